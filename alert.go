@@ -0,0 +1,97 @@
+package generator
+
+import (
+	"time"
+)
+
+// BackwardAlertPolicy 时钟回退频率告警策略：Window时间内发生Threshold次时钟回退时告警
+//   - 偶发的时钟回退属于正常现象，频繁回退通常意味着NTP配置错误，需要人工介入
+type BackwardAlertPolicy struct {
+	Threshold   int                                   //告警阈值(次数)
+	Window      time.Duration                         //统计窗口
+	OnAlert     func(count int, window time.Duration) //告警回调，在独立goroutine中执行
+	TripBreaker bool                                  //告警时熔断生成器，熔断期间Generate返回错误
+	Cooldown    time.Duration                         //熔断时长，0表示须调用ResetBreaker手动恢复
+}
+
+// backwardAlert 时钟回退频率统计
+type backwardAlert struct {
+	policy       BackwardAlertPolicy
+	events       []time.Time //窗口内的回退时间
+	trippedUntil time.Time   //熔断截止时间
+	tripped      bool        //是否处于熔断状态
+}
+
+// SetBackwardAlert 设置时钟回退频率告警，policy为nil表示关闭
+func (idGen *IDGenerator) SetBackwardAlert(policy *BackwardAlertPolicy) error {
+	if policy != nil && (policy.Threshold < 1 || policy.Window <= 0) {
+		return errorf(CodeAlertPolicy)
+	}
+
+	idGen.mutex.Lock()
+	defer idGen.mutex.Unlock()
+	if policy == nil {
+		idGen.backwardAlert = nil
+		return nil
+	}
+	idGen.backwardAlert = &backwardAlert{policy: *policy}
+	return nil
+}
+
+// ResetBreaker 解除熔断
+func (idGen *IDGenerator) ResetBreaker() {
+	idGen.mutex.Lock()
+	defer idGen.mutex.Unlock()
+	if idGen.backwardAlert != nil {
+		idGen.backwardAlert.tripped = false
+		idGen.backwardAlert.events = idGen.backwardAlert.events[:0]
+	}
+}
+
+// checkBreaker 检查生成器是否处于熔断状态，熔断时长按生成器的时钟计算，调用方须持有锁
+func (idGen *IDGenerator) checkBreaker() error {
+	alert := idGen.backwardAlert
+	if alert == nil || !alert.tripped {
+		return nil
+	}
+	if alert.policy.Cooldown == 0 {
+		return &BreakerOpenError{}
+	}
+	now := idGen.now()
+	if !now.Before(alert.trippedUntil) {
+		alert.tripped = false
+		return nil
+	}
+	return &BreakerOpenError{RetryAfter: alert.trippedUntil.Sub(now)}
+}
+
+// recordBackward 记录一次时钟回退，达到阈值时告警；统计窗口按生成器的时钟计算，调用方须持有锁
+func (idGen *IDGenerator) recordBackward() {
+	alert := idGen.backwardAlert
+	if alert == nil {
+		return
+	}
+
+	now := idGen.now()
+	expired := 0
+	for expired < len(alert.events) && now.Sub(alert.events[expired]) > alert.policy.Window {
+		expired++
+	}
+	alert.events = append(alert.events[expired:], now)
+	if len(alert.events) < alert.policy.Threshold {
+		return
+	}
+
+	count := len(alert.events)
+	alert.events = alert.events[:0]
+	if idGen.metrics != nil {
+		idGen.metrics.Counter(MetricBackwardAlert, 1)
+	}
+	if alert.policy.TripBreaker {
+		alert.tripped = true
+		alert.trippedUntil = now.Add(alert.policy.Cooldown)
+	}
+	if alert.policy.OnAlert != nil {
+		go alert.policy.OnAlert(count, alert.policy.Window)
+	}
+}
@@ -0,0 +1,108 @@
+package generator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jayecc/mtl-snowflake/fakeclock"
+)
+
+// TestBackwardAlert 时钟回退频率告警及熔断
+func TestBackwardAlert(t *testing.T) {
+	clock := fakeclock.New(time.Now())
+	idGen, _ := NewGeneratorWithSettings(0, Settings{TimeBit: 41, MachineIDBit: 7, TimelineBit: 3, SeqBit: 12, Epoch: DefaultEpoch, Clock: clock})
+
+	alerts := make(chan int, 1)
+	err := idGen.SetBackwardAlert(&BackwardAlertPolicy{
+		Threshold:   3,
+		Window:      time.Minute,
+		OnAlert:     func(count int, window time.Duration) { alerts <- count },
+		TripBreaker: true,
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	// 前进3ms后回退20ms
+	backward := func() error {
+		clock.Advance(3 * time.Millisecond)
+		clock.Advance(-20 * time.Millisecond)
+		_, err := idGen.Generate()
+		return err
+	}
+
+	idGen.Generate()
+	for i := 0; i < 2; i++ {
+		if err := backward(); err != nil {
+			t.Fatalf("【失败】-未达到阈值前应正常生成-%v", err)
+		}
+	}
+	select {
+	case <-alerts:
+		t.Fatalf("【失败】-未达到阈值不应告警")
+	default:
+	}
+
+	// 第3次回退触发告警并熔断
+	backward()
+	select {
+	case count := <-alerts:
+		if count != 3 {
+			t.Fatalf("【失败】-告警次数-got:%d-want:3", count)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("【失败】-达到阈值应告警")
+	}
+	if _, err := idGen.Generate(); err == nil {
+		t.Fatalf("【失败】-熔断期间应返回错误")
+	}
+
+	idGen.ResetBreaker()
+	if _, err := idGen.Generate(); err != nil {
+		t.Fatalf("【失败】-解除熔断后应正常生成-%v", err)
+	}
+
+	if err := idGen.SetBackwardAlert(&BackwardAlertPolicy{Threshold: 0, Window: time.Second}); err == nil {
+		t.Fatalf("【失败】-阈值为0应返回错误")
+	}
+}
+
+// TestBackwardAlertCooldown 熔断时长及统计窗口按生成器的时钟计算
+func TestBackwardAlertCooldown(t *testing.T) {
+	clock := fakeclock.New(time.Now())
+	idGen, _ := NewGeneratorWithSettings(0, Settings{TimeBit: 41, MachineIDBit: 7, TimelineBit: 3, SeqBit: 12, Epoch: DefaultEpoch, Clock: clock})
+	err := idGen.SetBackwardAlert(&BackwardAlertPolicy{Threshold: 2, Window: time.Minute, TripBreaker: true, Cooldown: time.Second})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	// 前进3ms后回退20ms
+	backward := func() error {
+		clock.Advance(3 * time.Millisecond)
+		clock.Advance(-20 * time.Millisecond)
+		_, err := idGen.Generate()
+		return err
+	}
+
+	// 两次回退间隔超过统计窗口，不触发熔断
+	idGen.Generate()
+	backward()
+	clock.Advance(2 * time.Minute)
+	idGen.Generate()
+	if err := backward(); err != nil {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "超出统计窗口", err, nil)
+	}
+
+	// 窗口内第2次回退触发熔断
+	backward()
+	_, err = idGen.Generate()
+	breaker, ok := err.(*BreakerOpenError)
+	if !ok || breaker.RetryAfter != time.Second {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "熔断剩余时长", err, time.Second)
+	}
+
+	clock.Advance(time.Second)
+	if _, err := idGen.Generate(); err != nil {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "熔断到期", err, nil)
+	}
+}
@@ -0,0 +1,185 @@
+package generator
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	defaultAnomalyTolerance = time.Second
+	defaultAnomalyDecay     = 0.01
+)
+
+// AnomalyKind 异常类型
+type AnomalyKind int
+
+const (
+	AnomalyUnknownMachine AnomalyKind = iota //机器ID不在已知机器中
+	AnomalyOldTime                           //机器生成的id时间明显早于其此前的id(超过容忍的乱序)
+	AnomalySeqReset                          //同一时间单位内序号重复出现，可能是机器ID冲突或进程在同一时间单位内重启
+	AnomalyFutureTime                        //id时间晚于当前时间(超过容忍的时钟误差)
+)
+
+// String 异常类型名称
+func (k AnomalyKind) String() string {
+	switch k {
+	case AnomalyUnknownMachine:
+		return "unknown_machine"
+	case AnomalyOldTime:
+		return "old_time"
+	case AnomalySeqReset:
+		return "seq_reset"
+	case AnomalyFutureTime:
+		return "future_time"
+	}
+	return fmt.Sprintf("AnomalyKind(%d)", int(k))
+}
+
+// anomalyMetrics 各异常类型上报的指标
+var anomalyMetrics = map[AnomalyKind]string{
+	AnomalyUnknownMachine: MetricAnomalyUnknownMachine,
+	AnomalyOldTime:        MetricAnomalyOldTime,
+	AnomalySeqReset:       MetricAnomalySeqReset,
+	AnomalyFutureTime:     MetricAnomalyFutureTime,
+}
+
+// Anomaly 检测到的异常
+type Anomaly struct {
+	Kind    AnomalyKind
+	ID      int64
+	Compose IDCompose
+	Score   float64 //该机器当前的异常评分
+}
+
+// AnomalyConfig 异常检测配置
+type AnomalyConfig struct {
+	KnownMachines []int64       //已知的机器ID，为空表示不检测未知机器
+	Tolerance     time.Duration //容忍的乱序及时钟误差，缺省1s
+	Decay         float64       //异常评分的平滑系数(0-1]，缺省0.01，越大对近期异常越敏感
+	Metrics       Metrics       //按异常类型上报计数
+}
+
+// AnomalyDetector 消费侧异常检测：从线上流量中的id被动监控各机器的生成情况
+//   - 每台机器维护一个异常评分：观察到的id中异常所占比例的指数加权平均，介于0-1之间
+//   - 只记录每台机器最新时间单位的序号，内存占用与机器数及单个时间单位的id数成正比
+type AnomalyDetector struct {
+	mutex     sync.Mutex
+	idGen     *IDGenerator
+	config    AnomalyConfig
+	tolerance int64 //容忍的乱序(时间单位)
+	known     map[int64]bool
+	machines  map[int64]*machineActivity
+}
+
+// machineActivity 机器的生成情况
+type machineActivity struct {
+	maxTime int64              //观察到的最大时间
+	tick    [2]int64           //最新时间单位(时间线, 时间)
+	seqs    map[int64]struct{} //最新时间单位内出现过的序号
+	score   float64
+}
+
+// NewAnomalyDetector 创建异常检测器
+func (idGen *IDGenerator) NewAnomalyDetector(config AnomalyConfig) (*AnomalyDetector, error) {
+	if config.Tolerance < 0 {
+		return nil, errorf(CodeNegative, "Tolerance")
+	}
+	if config.Tolerance == 0 {
+		config.Tolerance = defaultAnomalyTolerance
+	}
+	if config.Decay < 0 || config.Decay > 1 {
+		return nil, errorf(CodeDecayRange)
+	}
+	if config.Decay == 0 {
+		config.Decay = defaultAnomalyDecay
+	}
+
+	unit := idGen.settings.unit()
+	d := &AnomalyDetector{
+		idGen:     idGen,
+		config:    config,
+		tolerance: (int64(config.Tolerance) + unit - 1) / unit,
+		machines:  make(map[int64]*machineActivity),
+	}
+	if len(config.KnownMachines) > 0 {
+		d.known = make(map[int64]bool, len(config.KnownMachines))
+		for _, machineID := range config.KnownMachines {
+			d.known[machineID] = true
+		}
+	}
+	return d, nil
+}
+
+// Observe 观察一个id，返回检测到的异常
+func (d *AnomalyDetector) Observe(id int64) []Anomaly {
+	compose := *d.idGen.Decompose(id)
+	now := d.idGen.toOffsetTime(time.Now().UnixNano())
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	var kinds []AnomalyKind
+	if d.known != nil && !d.known[compose.MachineID] {
+		kinds = append(kinds, AnomalyUnknownMachine)
+	}
+	if compose.Time > now+d.tolerance {
+		kinds = append(kinds, AnomalyFutureTime)
+	}
+
+	m, exist := d.machines[compose.MachineID]
+	if !exist {
+		m = &machineActivity{maxTime: compose.Time, seqs: make(map[int64]struct{})}
+		d.machines[compose.MachineID] = m
+	}
+	if compose.Time < m.maxTime-d.tolerance {
+		kinds = append(kinds, AnomalyOldTime)
+	}
+	if compose.Time > m.maxTime {
+		m.maxTime = compose.Time
+	}
+
+	//只跟踪最新的时间单位
+	tick := [2]int64{compose.TimeLine, compose.Time}
+	switch {
+	case tick == m.tick:
+		if _, exist := m.seqs[compose.Seq]; exist {
+			kinds = append(kinds, AnomalySeqReset)
+		}
+		m.seqs[compose.Seq] = struct{}{}
+	case compose.Time >= m.tick[1]:
+		m.tick = tick
+		m.seqs = map[int64]struct{}{compose.Seq: {}}
+	}
+
+	//异常评分：异常比例的指数加权平均
+	var hit float64
+	if len(kinds) > 0 {
+		hit = 1
+	}
+	m.score += d.config.Decay * (hit - m.score)
+
+	if len(kinds) == 0 {
+		return nil
+	}
+	anomalies := make([]Anomaly, len(kinds))
+	for i, kind := range kinds {
+		anomalies[i] = Anomaly{Kind: kind, ID: id, Compose: compose, Score: m.score}
+		if d.config.Metrics != nil {
+			d.config.Metrics.Counter(anomalyMetrics[kind], 1)
+		}
+	}
+	return anomalies
+}
+
+// Scores 各机器的异常评分
+func (d *AnomalyDetector) Scores() map[int64]float64 {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	scores := make(map[int64]float64, len(d.machines))
+	for machineID, m := range d.machines {
+		scores[machineID] = m.score
+	}
+	return scores
+}
@@ -0,0 +1,74 @@
+package generator
+
+import (
+	"testing"
+	"time"
+)
+
+// TestAnomalyDetector 消费侧异常检测
+func TestAnomalyDetector(t *testing.T) {
+	idGen, _ := NewGenerator(1)
+	stranger, _ := NewGenerator(3)
+	now := idGen.toOffsetTime(time.Now().UnixNano())
+
+	testCases := []struct {
+		name string
+		ids  []int64
+		want []AnomalyKind
+	}{
+		{name: "正常流量", ids: []int64{idGen.compose(now, 0, 0), idGen.compose(now, 0, 1), idGen.compose(now+1, 0, 0)}},
+		{name: "容忍范围内乱序", ids: []int64{idGen.compose(now, 0, 0), idGen.compose(now-1, 0, 0)}},
+		{name: "未知机器", ids: []int64{stranger.compose(now, 0, 0)}, want: []AnomalyKind{AnomalyUnknownMachine}},
+		{name: "旧时间", ids: []int64{idGen.compose(now, 0, 0), idGen.compose(now-5000, 0, 0)}, want: []AnomalyKind{AnomalyOldTime}},
+		{name: "同一时间单位序号重复", ids: []int64{idGen.compose(now, 0, 0), idGen.compose(now, 0, 1), idGen.compose(now, 0, 0)}, want: []AnomalyKind{AnomalySeqReset}},
+		{name: "切换时间线后序号从0开始", ids: []int64{idGen.compose(now, 0, 0), idGen.compose(now, 1, 0)}},
+		{name: "未来时间", ids: []int64{idGen.compose(now+3600*1000, 0, 0)}, want: []AnomalyKind{AnomalyFutureTime}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			metrics := countingMetrics{}
+			detector, err := idGen.NewAnomalyDetector(AnomalyConfig{KnownMachines: []int64{1, 2}, Metrics: metrics})
+			if err != nil {
+				t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, err, nil)
+			}
+			var got []AnomalyKind
+			for _, id := range tc.ids {
+				for _, anomaly := range detector.Observe(id) {
+					got = append(got, anomaly.Kind)
+				}
+			}
+			if len(got) != len(tc.want) {
+				t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, got, tc.want)
+			}
+			for i := range got {
+				if got[i] != tc.want[i] || metrics[anomalyMetrics[got[i]]] != 1 {
+					t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, got, tc.want)
+				}
+			}
+		})
+	}
+}
+
+// TestAnomalyScore 异常评分随异常比例变化
+func TestAnomalyScore(t *testing.T) {
+	idGen, _ := NewGenerator(1)
+	now := idGen.toOffsetTime(time.Now().UnixNano())
+
+	detector, _ := idGen.NewAnomalyDetector(AnomalyConfig{Decay: 0.5})
+	for i := int64(0); i < 10; i++ {
+		detector.Observe(idGen.compose(now, 0, i))
+	}
+	if got := detector.Scores()[1]; got != 0 {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "正常流量评分", got, 0)
+	}
+	for i := 0; i < 10; i++ {
+		detector.Observe(idGen.compose(now, 0, 0))
+	}
+	if got := detector.Scores()[1]; got < 0.99 {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "持续异常评分", got, ">=0.99")
+	}
+
+	if _, err := idGen.NewAnomalyDetector(AnomalyConfig{Decay: 2}); err == nil {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "Decay超出范围", err, "error")
+	}
+}
@@ -0,0 +1,107 @@
+package arrowexport
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet/file"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+
+	generator "github.com/jayecc/mtl-snowflake"
+)
+
+// TestNewRecord id展开为分解列
+func TestNewRecord(t *testing.T) {
+	idGen, _ := generator.NewGenerator(7)
+	ids := make([]int64, 100)
+	for i := range ids {
+		id, _ := idGen.Generate()
+		ids[i] = int64(id)
+	}
+
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+	rec := NewRecord(mem, idGen, ids)
+	defer rec.Release()
+
+	if rec.NumRows() != int64(len(ids)) {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "行数", rec.NumRows(), len(ids))
+	}
+	checkColumns(t, idGen, ids, rec)
+}
+
+// TestParquetWriter 写入并读回Parquet
+func TestParquetWriter(t *testing.T) {
+	idGen, _ := generator.NewGenerator(3)
+	var ids []int64
+	for i := 0; i < 1000; i++ {
+		id, _ := idGen.Generate()
+		ids = append(ids, int64(id))
+	}
+
+	var buf bytes.Buffer
+	pw, err := NewParquetWriter(&buf, idGen)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	for _, batch := range [][]int64{ids[:600], ids[600:]} {
+		if err := pw.Write(batch); err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+	if err := pw.Close(); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	reader, err := file.NewParquetReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if reader.NumRowGroups() != 2 {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "row group数", reader.NumRowGroups(), 2)
+	}
+	fr, err := pqarrow.NewFileReader(reader, pqarrow.ArrowReadProperties{}, memory.DefaultAllocator)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	table, err := fr.ReadTable(context.Background())
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer table.Release()
+
+	tr := array.NewTableReader(table, int64(len(ids)))
+	defer tr.Release()
+	if !tr.Next() {
+		t.Fatal("【失败】-读回Parquet-没有数据")
+	}
+	checkColumns(t, idGen, ids, tr.Record())
+}
+
+// checkColumns 校验分解列与Decompose一致
+func checkColumns(t *testing.T, idGen *generator.IDGenerator, ids []int64, rec arrow.Record) {
+	idCol := rec.Column(0).(*array.Int64)
+	timeCol := rec.Column(1).(*array.Timestamp)
+	machineIDCol := rec.Column(2).(*array.Int64)
+	timelineCol := rec.Column(3).(*array.Int64)
+	seqCol := rec.Column(4).(*array.Int64)
+
+	for i, id := range ids {
+		want := idGen.Decompose(id)
+		got := generator.IDCompose{
+			MachineID: machineIDCol.Value(i),
+			TimeLine:  timelineCol.Value(i),
+			Seq:       seqCol.Value(i),
+		}
+		if idCol.Value(i) != id || got.MachineID != want.MachineID || got.TimeLine != want.TimeLine || got.Seq != want.Seq {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", "分解列", got, *want)
+		}
+		if gotTime := timeCol.Value(i).ToTime(arrow.Microsecond); !gotTime.Equal(idGen.Time(id)) {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", "生成时间", gotTime, idGen.Time(id))
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package generator
+
+import (
+	"time"
+)
+
+var (
+	// ErrBackfillTime 回填的时间不早于生成器的创建时间
+	ErrBackfillTime = newError(CodeBackfillTime)
+	// ErrBackfillExhausted 回填时间所在时间单位的id已用完
+	ErrBackfillExhausted = newError(CodeBackfillExhausted)
+)
+
+// GenerateAt 生成时间部分为t的id，用于数据回填、迁移等需要按历史时间排序的场景
+//   - 每个时间单位独立分配：依次使用各时间线的全部序号，每个时间单位最多生成(时间线数×序号数)个id，用完返回ErrBackfillExhausted
+//   - t须早于生成器的创建时间(ErrBackfillTime)，与该生成器实时生成的id不会重复；t早于基准时间时返回ErrBeforeEpoch
+//   - 其他进程可能已用同一机器ID在t生成过id，回填须使用专用的机器ID(如Coordinator.Reserve申请的批量任务机器ID)
+//   - 各时间单位已分配的数量保存在内存中，回填大量不同的时间单位时占用相应的内存；回填的id不影响Generate的进度与严格递增判断
+func (idGen *IDGenerator) GenerateAt(t time.Time) (int64, error) {
+	idGen.mutex.Lock()
+	defer idGen.mutex.Unlock()
+
+	if idGen.fenced != nil {
+		return 0, idGen.fenced
+	}
+	nanos := t.UnixNano()
+	if nanos < idGen.settings.Epoch {
+		return 0, ErrBeforeEpoch
+	}
+	curTime := idGen.toOffsetTime(nanos)
+	if curTime >= idGen.createdAt {
+		return 0, ErrBackfillTime
+	}
+
+	maxSeq := idGen.settings.presets.maxSeq
+	if idGen.shardSeqMax > 0 {
+		maxSeq = idGen.shardSeqMax
+	}
+	used := idGen.backfill[curTime]
+	timeline, seq := used/(maxSeq+1), used%(maxSeq+1)
+	if timeline > idGen.settings.presets.maxTimeline {
+		return 0, ErrBackfillExhausted
+	}
+	if idGen.backfill == nil {
+		idGen.backfill = make(map[int64]int64)
+	}
+	idGen.backfill[curTime] = used + 1
+	return idGen.compose(curTime, timeline, seq), nil
+}
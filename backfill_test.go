@@ -0,0 +1,66 @@
+package generator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jayecc/mtl-snowflake/fakeclock"
+)
+
+// TestGenerateAt 回填历史时间的id
+func TestGenerateAt(t *testing.T) {
+	start := time.Now().Truncate(time.Millisecond)
+	clock := fakeclock.New(start)
+	settings := Settings{TimeBit: 41, MachineIDBit: 19, TimelineBit: 1, SeqBit: 2, Epoch: DefaultSettings.Epoch, Clock: clock}
+	idGen, err := NewGeneratorWithSettings(5, settings)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	past := start.Add(-24 * time.Hour)
+	ids := map[int64]bool{}
+	type testCase struct {
+		name    string
+		t       time.Time
+		wantErr error
+	}
+	testCases := []testCase{
+		{name: "早于基准时间", t: time.Unix(0, settings.Epoch).Add(-time.Second), wantErr: ErrBeforeEpoch},
+		{name: "创建时间", t: start, wantErr: ErrBackfillTime},
+		{name: "未来", t: start.Add(time.Hour), wantErr: ErrBackfillTime},
+	}
+	//每个时间单位最多2条时间线×4个序号
+	for i := 0; i < 8; i++ {
+		testCases = append(testCases, testCase{name: "回填", t: past})
+	}
+	testCases = append(testCases,
+		testCase{name: "时间单位已用完", t: past, wantErr: ErrBackfillExhausted},
+		testCase{name: "其他时间单位", t: past.Add(time.Millisecond)},
+	)
+
+	for _, tc := range testCases {
+		id, err := idGen.GenerateAt(tc.t)
+		if err != tc.wantErr {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, err, tc.wantErr)
+		}
+		if err != nil {
+			continue
+		}
+		if ids[id] {
+			t.Fatalf("【失败】-%s-重复id:%d", tc.name, id)
+		}
+		ids[id] = true
+		if got := idGen.Time(id); !got.Equal(tc.t.Truncate(time.Millisecond)) {
+			t.Fatalf("【失败】-%s-时间-got:%v-want:%v", tc.name, got, tc.t)
+		}
+		if got := idGen.Decompose(id).MachineID; got != 5 {
+			t.Fatalf("【失败】-%s-机器ID-got:%v-want:%v", tc.name, got, 5)
+		}
+	}
+
+	//不影响实时生成
+	id, err := idGen.Generate()
+	if err != nil || !idGen.Time(int64(id)).Equal(start) || ids[int64(id)] {
+		t.Fatalf("【失败】-%s-got:%v,%v", "实时生成", id, err)
+	}
+}
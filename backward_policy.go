@@ -0,0 +1,108 @@
+package generator
+
+import (
+	"fmt"
+	"time"
+)
+
+// BackwardAction 发生时钟回退时的处理方式
+type BackwardAction int
+
+const (
+	BackwardSwitchTimeline BackwardAction = iota //切换到进度落后于当前时间的时间线，没有可切换的时间线时返回ErrTimelinesExhausted
+	BackwardWait                                 //等待时钟追回当前时间线进度，不消耗时间线
+	BackwardFail                                 //返回*ClockBackwardError(ErrBackwardRejected)，由调用方告警或重试
+)
+
+// String 处理方式名称
+func (a BackwardAction) String() string {
+	switch a {
+	case BackwardSwitchTimeline:
+		return "switch-timeline"
+	case BackwardWait:
+		return "wait"
+	case BackwardFail:
+		return "fail"
+	}
+	return fmt.Sprintf("BackwardAction(%d)", int(a))
+}
+
+// BackwardEvent 时钟回退的情况，供时钟回退策略决定处理方式
+type BackwardEvent struct {
+	Wait      time.Duration //时钟追回当前时间线进度所需的时长
+	Timeline  int64         //当前时间线
+	Available int           //可用于处理时钟回退的时间线数(不含当前时间线)
+	Jumped    bool          //是否检测到时钟跳变(SetPauseDetection)
+}
+
+// BackwardPolicy 时钟回退策略，决定发生时钟回退时等待、切换时间线还是返回错误
+//   - 在生成器锁内调用，实现须快速返回且不能阻塞
+type BackwardPolicy interface {
+	OnBackward(event BackwardEvent) BackwardAction
+}
+
+// BackwardPolicyFunc 函数形式的时钟回退策略
+type BackwardPolicyFunc func(event BackwardEvent) BackwardAction
+
+// OnBackward 调用f
+func (f BackwardPolicyFunc) OnBackward(event BackwardEvent) BackwardAction { return f(event) }
+
+// 内置的时钟回退策略
+var (
+	AlwaysWait   BackwardPolicy = fixedBackwardPolicy(BackwardWait)           //总是等待时钟追回，适用于不希望消耗时间线、能容忍延迟的场景
+	AlwaysSwitch BackwardPolicy = fixedBackwardPolicy(BackwardSwitchTimeline) //总是立即切换时间线，不产生等待
+	FailFast     BackwardPolicy = fixedBackwardPolicy(BackwardFail)           //总是返回错误，由调用方告警
+)
+
+type fixedBackwardPolicy BackwardAction
+
+func (p fixedBackwardPolicy) OnBackward(BackwardEvent) BackwardAction { return BackwardAction(p) }
+
+// WaitThenSwitch 追回所需时长不超过maxWait且未检测到时钟跳变时等待，否则切换时间线；未设置时钟回退策略时按MaxBackwardWait使用该策略
+func WaitThenSwitch(maxWait time.Duration) BackwardPolicy {
+	return BackwardPolicyFunc(func(event BackwardEvent) BackwardAction {
+		if event.Wait <= maxWait && !event.Jumped {
+			return BackwardWait
+		}
+		return BackwardSwitchTimeline
+	})
+}
+
+// SetBackwardPolicy 设置时钟回退策略，nil表示缺省：按SetMaxBackwardWait设置的上限等待，否则切换时间线
+//   - 也可通过Settings.BackwardPolicy在创建时指定
+func (idGen *IDGenerator) SetBackwardPolicy(policy BackwardPolicy) {
+	idGen.mutex.Lock()
+	defer idGen.mutex.Unlock()
+	idGen.backwardPolicy = policy
+}
+
+// backwardAction 按时钟回退策略决定处理方式，调用方须持有锁
+func (idGen *IDGenerator) backwardAction(wait time.Duration, jumped bool) BackwardAction {
+	policy := idGen.backwardPolicy
+	if policy == nil {
+		if wait <= idGen.maxBackwardWait && !jumped {
+			return BackwardWait
+		}
+		return BackwardSwitchTimeline
+	}
+	return policy.OnBackward(BackwardEvent{
+		Wait:      wait,
+		Timeline:  idGen.curTimeline,
+		Available: idGen.availableTimelines(),
+		Jumped:    jumped,
+	})
+}
+
+// waitCatchUp 等待时钟追回到当前时间线进度，返回等待后的时间，调用方须持有锁
+func (idGen *IDGenerator) waitCatchUp(progress int64, wait time.Duration) (int64, error) {
+	span := idGen.traceWait(SpanClockBackwardWait, wait)
+	curTime, err := idGen.waitUntil(progress)
+	endTrace(span, err)
+	if err != nil {
+		return 0, err
+	}
+	if idGen.metrics != nil {
+		idGen.metrics.Histogram(MetricWaitSeconds, wait.Seconds())
+	}
+	return curTime, nil
+}
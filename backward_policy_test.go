@@ -0,0 +1,64 @@
+package generator
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jayecc/mtl-snowflake/fakeclock"
+)
+
+// TestBackwardPolicy 各时钟回退策略的处理方式
+func TestBackwardPolicy(t *testing.T) {
+	var events []BackwardEvent
+	recorder := BackwardPolicyFunc(func(event BackwardEvent) BackwardAction {
+		events = append(events, event)
+		return BackwardFail
+	})
+
+	testCases := []struct {
+		name     string
+		policy   BackwardPolicy
+		backward time.Duration
+		timeline int64
+		err      error
+	}{
+		{name: "缺省切换时间线", policy: nil, backward: 10 * time.Millisecond, timeline: 1},
+		{name: "总是等待", policy: AlwaysWait, backward: 10 * time.Millisecond, timeline: 0},
+		{name: "总是切换", policy: AlwaysSwitch, backward: 500 * time.Microsecond, timeline: 1},
+		{name: "返回错误", policy: FailFast, backward: 10 * time.Millisecond, err: ErrBackwardRejected},
+		{name: "小幅回退等待", policy: WaitThenSwitch(5 * time.Millisecond), backward: 3 * time.Millisecond, timeline: 0},
+		{name: "大幅回退切换", policy: WaitThenSwitch(5 * time.Millisecond), backward: 10 * time.Millisecond, timeline: 1},
+		{name: "自定义", policy: recorder, backward: 10 * time.Millisecond, err: ErrBackwardRejected},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+			clock := fakeclock.New(start)
+			idGen, _ := NewGeneratorWithSettings(1, Settings{TimeBit: 41, MachineIDBit: 10, TimelineBit: 1, SeqBit: 11, Epoch: DefaultEpoch, Clock: clock, BackwardPolicy: tc.policy})
+			first, _ := idGen.Generate()
+			clock.Advance(-tc.backward)
+			id, err := idGen.Generate()
+			if tc.err != nil {
+				if !errors.Is(err, tc.err) || !errors.Is(err, ErrClockMovedBack) {
+					t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, err, tc.err)
+				}
+				if retry, ok := RetryAfter(err); !ok || retry <= 0 || retry > tc.backward {
+					t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, retry, tc.backward)
+				}
+				return
+			}
+			if err != nil || id == first {
+				t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, err, "生成成功")
+			}
+			if got := idGen.Decompose(int64(id)).TimeLine; got != tc.timeline {
+				t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, got, tc.timeline)
+			}
+		})
+	}
+
+	want := BackwardEvent{Wait: 10 * time.Millisecond, Timeline: 0, Available: 1}
+	if len(events) != 1 || events[0] != want {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "自定义策略收到的回退情况", events, want)
+	}
+}
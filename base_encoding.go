@@ -0,0 +1,101 @@
+package generator
+
+// 短字符串编码字母表
+const (
+	base62Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+	base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz" //Bitcoin字母表，去掉了易混淆的0、O、I、l
+)
+
+// baseCodec 短字符串编码
+type baseCodec struct {
+	name     string
+	alphabet string
+	index    [256]int8 //字符在字母表中的位置，-1表示非法字符
+}
+
+var (
+	base62Codec = newBaseCodec("Base62", base62Alphabet)
+	base58Codec = newBaseCodec("Base58", base58Alphabet)
+)
+
+// newBaseCodec 创建短字符串编码
+func newBaseCodec(name, alphabet string) *baseCodec {
+	c := &baseCodec{name: name, alphabet: alphabet}
+	for i := range c.index {
+		c.index[i] = -1
+	}
+	for i := 0; i < len(alphabet); i++ {
+		c.index[alphabet[i]] = int8(i)
+	}
+	return c
+}
+
+// encode 按无符号数编码，负数(最高位置位)同样可以还原
+func (c *baseCodec) encode(id int64) string {
+	var buf [11]byte //Base58编码uint64最多11位
+	base := uint64(len(c.alphabet))
+	v := uint64(id)
+	i := len(buf)
+	for {
+		i--
+		buf[i] = c.alphabet[v%base]
+		v /= base
+		if v == 0 {
+			break
+		}
+	}
+	return string(buf[i:])
+}
+
+// decode 解码，拒绝空串、非法字符、多余的前导零及超出64位的值
+func (c *baseCodec) decode(s string) (int64, error) {
+	if s == "" {
+		return 0, errorf(CodeEncodingEmpty, c.name)
+	}
+	if len(s) > 1 && s[0] == c.alphabet[0] {
+		return 0, errorf(CodeEncodingLeadingZero, c.name, s)
+	}
+	base := uint64(len(c.alphabet))
+	var v uint64
+	for i := 0; i < len(s); i++ {
+		digit := c.index[s[i]]
+		if digit < 0 {
+			return 0, errorf(CodeEncodingInvalidChar, c.name, s, s[i])
+		}
+		if v > (^uint64(0)-uint64(digit))/base {
+			return 0, errorf(CodeEncodingOverflow, c.name, s)
+		}
+		v = v*base + uint64(digit)
+	}
+	return int64(v), nil
+}
+
+// EncodeBase62 将id编码为Base62短字符串(字母表0-9A-Za-z)，可直接用于URL，默认结构的id为11位
+func EncodeBase62(id int64) string {
+	return base62Codec.encode(id)
+}
+
+// DecodeBase62 解码EncodeBase62的结果
+func DecodeBase62(s string) (int64, error) {
+	return base62Codec.decode(s)
+}
+
+// EncodeBase58 将id编码为Base58短字符串(Bitcoin字母表)，不含易混淆的0、O、I、l，适合人工输入
+func EncodeBase58(id int64) string {
+	return base58Codec.encode(id)
+}
+
+// DecodeBase58 解码EncodeBase58的结果
+func DecodeBase58(s string) (int64, error) {
+	return base58Codec.decode(s)
+}
+
+// Base62 同EncodeBase62
+func (id ID) Base62() string {
+	return EncodeBase62(int64(id))
+}
+
+// Base58 同EncodeBase58
+func (id ID) Base58() string {
+	return EncodeBase58(int64(id))
+}
@@ -0,0 +1,69 @@
+package generator
+
+import (
+	"math"
+	"testing"
+)
+
+// TestBaseEncoding Base62/Base58编码
+func TestBaseEncoding(t *testing.T) {
+	testCases := []struct {
+		name   string
+		id     int64
+		base62 string
+		base58 string
+	}{
+		{name: "零", id: 0, base62: "0", base58: "1"},
+		{name: "一位", id: 61, base62: "z", base58: "24"},
+		{name: "进位", id: 62, base62: "10", base58: "25"},
+		{name: "最大值", id: math.MaxInt64, base62: "AzL8n0Y58m7", base58: "NQm6nKp8qFC"},
+		{name: "负数", id: -1, base62: "LygHa16AHYF", base58: "jpXCZedGfVQ"},
+	}
+	for _, tc := range testCases {
+		if got := EncodeBase62(tc.id); got != tc.base62 {
+			t.Fatalf("【失败】-%s-Base62-got:%v-want:%v", tc.name, got, tc.base62)
+		}
+		if got := EncodeBase58(tc.id); got != tc.base58 {
+			t.Fatalf("【失败】-%s-Base58-got:%v-want:%v", tc.name, got, tc.base58)
+		}
+		if got, err := DecodeBase62(tc.base62); err != nil || got != tc.id {
+			t.Fatalf("【失败】-%s-DecodeBase62-got:%v,%v-want:%v", tc.name, got, err, tc.id)
+		}
+		if got, err := DecodeBase58(tc.base58); err != nil || got != tc.id {
+			t.Fatalf("【失败】-%s-DecodeBase58-got:%v,%v-want:%v", tc.name, got, err, tc.id)
+		}
+	}
+
+	idGen, _ := NewGenerator(1)
+	for i := 0; i < 1000; i++ {
+		id, _ := idGen.Generate()
+		if got, err := DecodeBase62(id.Base62()); err != nil || got != id.Int64() {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", "Base62还原", got, id)
+		}
+		if got, err := DecodeBase58(id.Base58()); err != nil || got != id.Int64() {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", "Base58还原", got, id)
+		}
+	}
+}
+
+// TestBaseDecodeError Base62/Base58解码错误
+func TestBaseDecodeError(t *testing.T) {
+	testCases := []struct {
+		name   string
+		decode func(string) (int64, error)
+		s      string
+	}{
+		{name: "Base62空串", decode: DecodeBase62, s: ""},
+		{name: "Base62非法字符", decode: DecodeBase62, s: "ab-c"},
+		{name: "Base62前导零", decode: DecodeBase62, s: "0z"},
+		{name: "Base62超出64位", decode: DecodeBase62, s: "LygHa16AHYG"},
+		{name: "Base58易混淆字符", decode: DecodeBase58, s: "2O"},
+		{name: "Base58前导零", decode: DecodeBase58, s: "12"},
+		{name: "Base58超出64位", decode: DecodeBase58, s: "jpXCZedGfVR"},
+	}
+	for _, tc := range testCases {
+		if _, err := tc.decode(tc.s); err == nil {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, err, "error")
+		}
+	}
+}
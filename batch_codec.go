@@ -0,0 +1,73 @@
+package generator
+
+import (
+	"encoding/binary"
+)
+
+// batchFormatV1 批量id二进制格式版本
+const batchFormatV1 byte = 1
+
+// EncodeBatch 将一批id编码为紧凑的二进制格式
+//   - 格式：版本(1字节) + 数量(uvarint) + 首个id(uvarint) + 相邻id之差(zigzag varint)
+//   - 同一时间单位内连续生成的id相差1，每个id只占1字节
+func EncodeBatch(ids []int64) []byte {
+	buf := make([]byte, 0, 1+binary.MaxVarintLen64*2+len(ids)*2)
+	buf = append(buf, batchFormatV1)
+	buf = appendUvarint(buf, uint64(len(ids)))
+	if len(ids) == 0 {
+		return buf
+	}
+
+	buf = appendUvarint(buf, uint64(ids[0]))
+	for i := 1; i < len(ids); i++ {
+		buf = appendVarint(buf, ids[i]-ids[i-1])
+	}
+	return buf
+}
+
+// DecodeBatch 解码EncodeBatch生成的数据
+func DecodeBatch(data []byte) ([]int64, error) {
+	if len(data) == 0 || data[0] != batchFormatV1 {
+		return nil, errorf(CodeBatchFormat)
+	}
+	data = data[1:]
+
+	count, n := binary.Uvarint(data)
+	if n <= 0 || count > uint64(len(data)) {
+		return nil, errorf(CodeBatchCorrupted)
+	}
+	data = data[n:]
+	ids := make([]int64, 0, count)
+	if count == 0 {
+		return ids, nil
+	}
+
+	first, n := binary.Uvarint(data)
+	if n <= 0 {
+		return nil, errorf(CodeBatchCorrupted)
+	}
+	data = data[n:]
+	ids = append(ids, int64(first))
+	for i := uint64(1); i < count; i++ {
+		delta, n := binary.Varint(data)
+		if n <= 0 {
+			return nil, errorf(CodeBatchCorrupted)
+		}
+		data = data[n:]
+		ids = append(ids, ids[i-1]+delta)
+	}
+	if len(data) != 0 {
+		return nil, errorf(CodeBatchCorrupted)
+	}
+	return ids, nil
+}
+
+func appendUvarint(buf []byte, v uint64) []byte {
+	var tmp [binary.MaxVarintLen64]byte
+	return append(buf, tmp[:binary.PutUvarint(tmp[:], v)]...)
+}
+
+func appendVarint(buf []byte, v int64) []byte {
+	var tmp [binary.MaxVarintLen64]byte
+	return append(buf, tmp[:binary.PutVarint(tmp[:], v)]...)
+}
@@ -0,0 +1,48 @@
+package generator
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestBatchCodec 批量id编解码
+func TestBatchCodec(t *testing.T) {
+	idGen, _ := NewGenerator(0)
+	generated := make([]int64, 0, 10000)
+	for i := 0; i < 10000; i++ {
+		id, _ := idGen.Generate()
+		generated = append(generated, int64(id))
+	}
+
+	testCases := []struct {
+		name string
+		ids  []int64
+	}{
+		{name: "空批次", ids: []int64{}},
+		{name: "单个id", ids: generated[:1]},
+		{name: "连续生成的id", ids: generated},
+		{name: "乱序id", ids: []int64{generated[5], generated[0], 1<<63 - 1, 0, generated[9999]}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			data := EncodeBatch(tc.ids)
+			got, err := DecodeBatch(data)
+			if err != nil || !reflect.DeepEqual(got, tc.ids) {
+				t.Fatalf("【失败】-%s-got:%v-want:%v-err:%v", tc.name, got, tc.ids, err)
+			}
+		})
+	}
+
+	// 连续生成的id压缩到原大小的20%以内
+	if size := len(EncodeBatch(generated)); size*5 > len(generated)*8 {
+		t.Fatalf("【失败】-压缩率不足-size:%d-raw:%d", size, len(generated)*8)
+	}
+
+	// 损坏的数据
+	data := EncodeBatch(generated[:10])
+	for _, bad := range [][]byte{nil, {2}, data[:len(data)-1], append(append([]byte(nil), data...), 0)} {
+		if _, err := DecodeBatch(bad); err == nil {
+			t.Fatalf("【失败】-损坏的数据应解码失败-%v", bad)
+		}
+	}
+}
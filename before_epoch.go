@@ -0,0 +1,32 @@
+package generator
+
+import (
+	"time"
+)
+
+// ErrBeforeEpoch 当前时间早于基准时间，无法生成id
+//   - 通常是服务器时钟被大幅调回导致，时钟恢复后生成器自动恢复
+var ErrBeforeEpoch = newError(CodeBeforeEpoch)
+
+// SetBeforeEpochHandler 设置时钟早于基准时间时的回调，nil表示不回调
+//   - 与普通的时钟回退区分，便于单独告警
+//   - 每次进入该状态时回调一次(生成成功后重新计算)，回调在独立goroutine中执行
+func (idGen *IDGenerator) SetBeforeEpochHandler(handler func(now time.Time)) {
+	idGen.mutex.Lock()
+	defer idGen.mutex.Unlock()
+	idGen.beforeEpochHandler = handler
+}
+
+// beforeEpoch 处理时钟早于基准时间，调用方须持有锁
+func (idGen *IDGenerator) beforeEpoch(now time.Time) error {
+	if idGen.metrics != nil {
+		idGen.metrics.Counter(MetricBeforeEpoch, 1)
+	}
+	if !idGen.isBeforeEpoch {
+		idGen.isBeforeEpoch = true
+		if handler := idGen.beforeEpochHandler; handler != nil {
+			go handler(now)
+		}
+	}
+	return ErrBeforeEpoch
+}
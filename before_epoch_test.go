@@ -0,0 +1,61 @@
+package generator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jayecc/mtl-snowflake/fakeclock"
+)
+
+// TestBeforeEpoch 时钟早于基准时间
+func TestBeforeEpoch(t *testing.T) {
+	clock := fakeclock.New(time.Now())
+	settings := *DefaultSettings
+	settings.Clock = clock
+	idGen, _ := NewGeneratorWithSettings(0, settings)
+	metrics := countingMetrics{}
+	idGen.SetMetrics(metrics)
+	calls := make(chan time.Time, 10)
+	idGen.SetBeforeEpochHandler(func(now time.Time) { calls <- now })
+
+	now := clock.Time()
+	beforeEpoch := time.Unix(0, settings.Epoch).Add(-time.Hour)
+	testCases := []struct {
+		name      string
+		now       time.Time //时钟时间
+		wantErr   error
+		wantCalls int
+	}{
+		{name: "正常生成", now: now, wantErr: nil, wantCalls: 0},
+		{name: "时钟早于基准时间", now: beforeEpoch, wantErr: ErrBeforeEpoch, wantCalls: 1},
+		{name: "持续早于基准时间不重复回调", now: beforeEpoch, wantErr: ErrBeforeEpoch, wantCalls: 0},
+		{name: "时钟恢复", now: now, wantErr: nil, wantCalls: 0},
+		{name: "再次早于基准时间", now: beforeEpoch, wantErr: ErrBeforeEpoch, wantCalls: 1},
+	}
+
+	for _, tc := range testCases {
+		clock.Set(tc.now)
+		if _, err := idGen.Generate(); err != tc.wantErr {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, err, tc.wantErr)
+		}
+		got := 0
+		for i := 0; i < tc.wantCalls; i++ {
+			select {
+			case <-calls:
+				got++
+			case <-time.After(time.Second):
+			}
+		}
+		select {
+		case <-calls:
+			got++
+		case <-time.After(10 * time.Millisecond):
+		}
+		if got != tc.wantCalls {
+			t.Fatalf("【失败】-%s-回调次数-got:%v-want:%v", tc.name, got, tc.wantCalls)
+		}
+	}
+	if got := metrics[MetricBeforeEpoch]; got != 3 {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "指标", got, 3)
+	}
+}
@@ -0,0 +1,233 @@
+// Package bench 在当前硬件上对比不同时间源、等待方式下Generate的性能，给出部署建议
+//   - 时间源：系统时钟(time.Now)、单调时钟锚定、粗粒度缓存时钟
+//   - 等待方式：休眠、混合、自旋
+package bench
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	generator "github.com/jayecc/mtl-snowflake"
+)
+
+const (
+	defaultDuration         = time.Second
+	defaultCoarseResolution = 100 * time.Microsecond
+	sampleEvery             = 16   //每隔多少次调用采样一次延迟
+	recommendTolerance      = 0.95 //吞吐达到最快组合的该比例即视为相当，优先推荐更简单的组合
+	clockReads              = 1 << 16
+)
+
+// Config 对比参数
+type Config struct {
+	Settings         *generator.Settings //id结构，缺省generator.DefaultSettings
+	Duration         time.Duration       //每种组合的运行时长，缺省1s
+	Goroutines       int                 //并发调用Generate的goroutine数，缺省GOMAXPROCS
+	CoarseResolution time.Duration       //粗粒度时钟的刷新间隔，缺省100µs
+}
+
+// Result 一种组合的结果
+type Result struct {
+	Clock        string               //时间源
+	Wait         generator.WaitPolicy //等待方式
+	IDs          int64                //生成的id数
+	IDsPerSecond float64              //吞吐
+	ClockRead    time.Duration        //单次读取时间源的耗时
+	P99          time.Duration        //Generate延迟的p99
+	Max          time.Duration        //Generate延迟的最大值
+}
+
+// Report 对比报告
+type Report struct {
+	Goroutines  int
+	Results     []Result
+	Recommended Result
+}
+
+// clockSource 时间源
+type clockSource struct {
+	name string
+	new  func(config Config) (generator.Clock, func(), error)
+}
+
+// clockSources 按推荐优先级(由简单到复杂)排列
+var clockSources = []clockSource{
+	{name: "system", new: func(Config) (generator.Clock, func(), error) {
+		return generator.SystemClock, func() {}, nil
+	}},
+	{name: "monotonic", new: func(Config) (generator.Clock, func(), error) {
+		return generator.NewMonotonicClock(), func() {}, nil
+	}},
+	{name: "coarse", new: func(config Config) (generator.Clock, func(), error) {
+		c, err := generator.NewCoarseClock(config.CoarseResolution)
+		if err != nil {
+			return nil, nil, err
+		}
+		return c, c.Stop, nil
+	}},
+}
+
+// waitPolicies 按推荐优先级(由省CPU到低延迟)排列
+var waitPolicies = []generator.WaitPolicy{generator.WaitSleep, generator.WaitHybrid, generator.WaitSpin}
+
+// Run 依次运行各组合，每种组合使用新的生成器
+func Run(config Config) (*Report, error) {
+	if config.Settings == nil {
+		config.Settings = generator.DefaultSettings
+	}
+	if config.Duration <= 0 {
+		config.Duration = defaultDuration
+	}
+	if config.Goroutines <= 0 {
+		config.Goroutines = runtime.GOMAXPROCS(0)
+	}
+	if config.CoarseResolution <= 0 {
+		config.CoarseResolution = defaultCoarseResolution
+	}
+
+	report := &Report{Goroutines: config.Goroutines}
+	for _, source := range clockSources {
+		for _, policy := range waitPolicies {
+			result, err := runOne(config, source, policy)
+			if err != nil {
+				return nil, err
+			}
+			report.Results = append(report.Results, result)
+		}
+	}
+	report.Recommended = recommend(report.Results)
+	return report, nil
+}
+
+// runOne 运行一种组合
+func runOne(config Config, source clockSource, policy generator.WaitPolicy) (Result, error) {
+	result := Result{Clock: source.name, Wait: policy}
+	clock, stop, err := source.new(config)
+	if err != nil {
+		return result, err
+	}
+	defer stop()
+
+	idGen, err := generator.NewGeneratorWithSettings(1, *config.Settings)
+	if err != nil {
+		return result, err
+	}
+	idGen.SetClock(clock)
+	if err := idGen.SetWaitPolicy(policy); err != nil {
+		return result, err
+	}
+
+	start := time.Now()
+	for i := 0; i < clockReads; i++ {
+		clock.Now()
+	}
+	result.ClockRead = time.Since(start) / clockReads
+
+	var (
+		wg       sync.WaitGroup
+		mutex    sync.Mutex
+		samples  []time.Duration
+		firstErr error
+	)
+	deadline := time.Now().Add(config.Duration)
+	start = time.Now()
+	for g := 0; g < config.Goroutines; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var n int64
+			var local []time.Duration
+			var err error
+			for time.Now().Before(deadline) {
+				if n%sampleEvery == 0 {
+					begin := time.Now()
+					_, err = idGen.Generate()
+					local = append(local, time.Since(begin))
+				} else {
+					_, err = idGen.Generate()
+				}
+				if err != nil {
+					break
+				}
+				n++
+			}
+			mutex.Lock()
+			defer mutex.Unlock()
+			result.IDs += n
+			samples = append(samples, local...)
+			if err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}()
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return result, firstErr
+	}
+
+	result.IDsPerSecond = float64(result.IDs) / time.Since(start).Seconds()
+	if len(samples) > 0 {
+		sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+		result.P99 = samples[len(samples)*99/100]
+		result.Max = samples[len(samples)-1]
+	}
+	return result, nil
+}
+
+// recommend 吞吐与最快组合相当的组合中选择最靠前(最简单、最省CPU)的一个
+func recommend(results []Result) Result {
+	var best float64
+	for _, r := range results {
+		if r.IDsPerSecond > best {
+			best = r.IDsPerSecond
+		}
+	}
+	for _, r := range results {
+		if r.IDsPerSecond >= best*recommendTolerance {
+			return r
+		}
+	}
+	return Result{}
+}
+
+// WriteTo 输出对比表及建议
+func (report *Report) WriteTo(w io.Writer) (int64, error) {
+	if len(report.Results) == 0 {
+		return 0, generator.NewError(generator.CodeNoBenchResult)
+	}
+	var total int64
+	printf := func(format string, args ...interface{}) error {
+		n, err := fmt.Fprintf(w, format, args...)
+		total += int64(n)
+		return err
+	}
+
+	if err := printf("goroutines: %d\n%-10s %-7s %14s %10s %10s %10s\n", report.Goroutines, "clock", "wait", "ids/s", "read", "p99", "max"); err != nil {
+		return total, err
+	}
+	for _, r := range report.Results {
+		if err := printf("%-10s %-7s %14.0f %10s %10s %10s\n", r.Clock, r.Wait, r.IDsPerSecond, r.ClockRead, r.P99, r.Max); err != nil {
+			return total, err
+		}
+	}
+	rec := report.Recommended
+	if err := printf("recommended: clock=%s wait=%s (%.0f ids/s)\n", rec.Clock, rec.Wait, rec.IDsPerSecond); err != nil {
+		return total, err
+	}
+	switch {
+	case rec.Wait == generator.WaitSpin:
+		err := printf("note: spin waiting keeps a CPU busy whenever the seq space is exhausted\n")
+		return total, err
+	case rec.Clock == "coarse":
+		err := printf("note: the coarse clock lags by up to its resolution; keep it well below the time unit\n")
+		return total, err
+	case rec.Clock == "monotonic":
+		err := printf("note: the monotonic clock drifts from wall time; recreate it periodically\n")
+		return total, err
+	}
+	return total, nil
+}
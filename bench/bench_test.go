@@ -0,0 +1,69 @@
+package bench
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	generator "github.com/jayecc/mtl-snowflake"
+)
+
+// TestRun 各组合均有结果，建议来自结果之一
+func TestRun(t *testing.T) {
+	report, err := Run(Config{Duration: 20 * time.Millisecond, Goroutines: 2})
+	if err != nil {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "运行", err, nil)
+	}
+	if got, want := len(report.Results), len(clockSources)*len(waitPolicies); got != want {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "组合数", got, want)
+	}
+	for _, r := range report.Results {
+		if r.IDs <= 0 || r.IDsPerSecond <= 0 {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", r.Clock+"/"+r.Wait.String(), r.IDs, ">0")
+		}
+	}
+
+	var buf bytes.Buffer
+	if _, err := report.WriteTo(&buf); err != nil || !strings.Contains(buf.String(), "recommended: clock="+report.Recommended.Clock) {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "输出", buf.String(), "recommended")
+	}
+	if _, err := new(Report).WriteTo(&buf); err == nil || err.Error() != "no benchmark results" {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "没有对比结果", err, "no benchmark results")
+	}
+}
+
+// TestRecommend 吞吐相当时优先推荐靠前的组合
+func TestRecommend(t *testing.T) {
+	testCases := []struct {
+		name    string
+		results []Result
+		want    string
+	}{
+		{name: "相当时取靠前", results: []Result{{Clock: "system", IDsPerSecond: 96}, {Clock: "coarse", IDsPerSecond: 100}}, want: "system"},
+		{name: "明显更快时取更快", results: []Result{{Clock: "system", IDsPerSecond: 50}, {Clock: "coarse", IDsPerSecond: 100}}, want: "coarse"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := recommend(tc.results); got.Clock != tc.want {
+				t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, got.Clock, tc.want)
+			}
+		})
+	}
+}
+
+// BenchmarkGenerate 各时间源下的单goroutine生成耗时
+func BenchmarkGenerate(b *testing.B) {
+	for _, source := range clockSources {
+		b.Run(source.name, func(b *testing.B) {
+			clock, stop, _ := source.new(Config{CoarseResolution: defaultCoarseResolution})
+			defer stop()
+			idGen, _ := generator.NewGenerator(1)
+			idGen.SetClock(clock)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				idGen.Generate()
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package generator
+
+import (
+	"sync/atomic"
+)
+
+// ReservedBlock ReserveBlock预留的一段id：同一机器、同一时间线上按生成顺序排列的n个id，可跨越多个时间单位
+//   - 预留后无需再访问生成器，Next通过原子操作取出，可并发使用
+//   - 与离线id块(IDBlock)不同，预留的id来自生成器的当前进度，与生成器此后生成的id不会重复
+type ReservedBlock struct {
+	base      int64  //除时间、序号外的部分(选择位、数据中心、机器ID、时间线、分片编号)
+	shiftTime uint64 //时间部分左移位数
+	shiftSeq  uint64 //序号左移位数
+	startTime int64  //第一个id的时间单位
+	startSeq  int64  //第一个id的序号
+	perUnit   int64  //每个时间单位使用的序号数
+	count     int64  //id数量
+	next      int64  //Next取出的下一个下标
+}
+
+// ReserveBlock 预留n个id，返回的块可离线分配而不再加锁
+//   - 从当前时间单位的下一个序号开始，序号用完后顺延到后续时间单位；生成器的进度推进到块的最后一个id，
+//     若块延伸到时钟之后，返回前等待时钟到达块的最后一个时间单位(设置ExhaustionBorrow时只等待到超前不超过maxDrift)，等待方式与Generate相同
+//   - 每个时间单位最多2^SeqBit个id，预留n个id约需n/2^SeqBit个时间单位
+//   - 不能与序号填充、序号随机化、序号空间划分同时使用
+func (idGen *IDGenerator) ReserveBlock(n int) (*ReservedBlock, error) {
+	if n < 1 {
+		return nil, errorf(CodeNotPositive, "n")
+	}
+
+	idGen.mutex.Lock()
+	defer idGen.mutex.Unlock()
+	if idGen.padding != nil || idGen.seqRandom != nil || idGen.reservation != nil {
+		return nil, errorf(CodeBlockConflict)
+	}
+	if err := idGen.checkRate(idGen.now(), n); err != nil {
+		return nil, err
+	}
+
+	//第一个id按Generate的逻辑生成，处理时钟回退、序号用完等情况
+	first, err := idGen.generateLocked()
+	if err != nil {
+		return nil, err
+	}
+	presets := idGen.settings.presets
+	startTime := idGen.timelineProgress[idGen.curTimeline]
+	perUnit := idGen.seqLimit(presets.maxSeq) + 1
+	block := &ReservedBlock{
+		base:      first&^presets.maskTime ^ idGen.seq<<presets.shiftSeq,
+		shiftTime: presets.shiftTimeBit,
+		shiftSeq:  presets.shiftSeq,
+		startTime: startTime,
+		startSeq:  idGen.seq,
+		perUnit:   perUnit,
+		count:     int64(n),
+	}
+
+	last := idGen.seq + int64(n) - 1
+	endTime := startTime + last/perUnit
+	if endTime > presets.maxTime {
+		return nil, errorf(CodeBlockOverflow, n)
+	}
+	if idGen.persist != nil {
+		if err := idGen.ensurePersisted(endTime); err != nil {
+			return nil, err
+		}
+	}
+	idGen.timelineProgress[idGen.curTimeline] = endTime
+	idGen.seq = last % perUnit
+	idGen.lastID = block.ID(int64(n) - 1)
+	if idGen.lastID > idGen.maxID {
+		idGen.maxID = idGen.lastID
+	}
+	idGen.spendRate(int64(n) - 1)
+	if idGen.metrics != nil {
+		idGen.metrics.Counter(MetricGenerated, int64(n)-1)
+	}
+
+	//等待时钟到达块的最后一个时间单位，此后的生成不会视为时钟回退
+	if wall := idGen.toOffsetTime(idGen.now().UnixNano()); endTime-idGen.maxDrift > wall {
+		if _, err := idGen.waitUntil(endTime - idGen.maxDrift); err != nil {
+			return nil, err
+		}
+	}
+	if idGen.maxDrift > 0 {
+		idGen.borrowedTime = endTime
+	}
+	return block, nil
+}
+
+// Len 块内id数量
+func (b *ReservedBlock) Len() int64 {
+	return b.count
+}
+
+// ID 块内第index个id，index须介于0-Len()-1之间
+func (b *ReservedBlock) ID(index int64) int64 {
+	pos := b.startSeq + index
+	return b.base | (b.startTime+pos/b.perUnit)<<b.shiftTime | (pos%b.perUnit)<<b.shiftSeq
+}
+
+// Next 按顺序取出下一个id，取完时返回false，可并发调用
+func (b *ReservedBlock) Next() (int64, bool) {
+	index := atomic.AddInt64(&b.next, 1) - 1
+	if index >= b.count {
+		return 0, false
+	}
+	return b.ID(index), true
+}
+
+// Remaining 尚未通过Next取出的id数量
+func (b *ReservedBlock) Remaining() int64 {
+	if rest := b.count - atomic.LoadInt64(&b.next); rest > 0 {
+		return rest
+	}
+	return 0
+}
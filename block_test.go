@@ -0,0 +1,107 @@
+package generator
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jayecc/mtl-snowflake/fakeclock"
+)
+
+// TestReserveBlock 预留id块
+func TestReserveBlock(t *testing.T) {
+	clock := fakeclock.New(time.Now().Truncate(time.Millisecond))
+	settings := *DefaultSettings
+	settings.Clock = clock
+	idGen, _ := NewGeneratorWithSettings(3, settings)
+	metrics := countingMetrics{}
+	idGen.SetMetrics(metrics)
+
+	before, _ := idGen.Generate()
+	start := clock.Time()
+	block, err := idGen.ReserveBlock(10000)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if block.Len() != 10000 {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "数量", block.Len(), 10000)
+	}
+
+	//块内id按生成顺序递增，跨越3个时间单位(第一个时间单位已用1个序号)
+	prev := int64(before)
+	for i := int64(0); i < block.Len(); i++ {
+		id := block.ID(i)
+		if id <= prev {
+			t.Fatalf("【失败】-第%d个-got:%v-want:>%v", i, id, prev)
+		}
+		if c := idGen.Decompose(id); c.MachineID != 3 {
+			t.Fatalf("【失败】-第%d个-机器ID-got:%v", i, c.MachineID)
+		}
+		prev = id
+	}
+	if got := idGen.Decompose(block.ID(block.Len()-1)).Time - idGen.Decompose(int64(before)).Time; got != 2 {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "跨越时间单位", got, 2)
+	}
+	if elapsed := clock.Time().Sub(start); elapsed != 2*time.Millisecond {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "等待时钟到达最后一个时间单位", elapsed, 2*time.Millisecond)
+	}
+
+	//生成器从块之后继续生成，不视为时钟回退
+	after, err := idGen.Generate()
+	if err != nil || int64(after) <= prev {
+		t.Fatalf("【失败】-%s-got:%v-want:>%v-err:%v", "块之后生成", after, prev, err)
+	}
+	if metrics[MetricClockBackward] != 0 || metrics[MetricGenerated] != 10002 {
+		t.Fatalf("【失败】-%s-回退:%v-生成:%v", "指标", metrics[MetricClockBackward], metrics[MetricGenerated])
+	}
+
+	//并发取出
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	seen := make(map[int64]bool)
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				id, ok := block.Next()
+				if !ok {
+					return
+				}
+				mutex.Lock()
+				seen[id] = true
+				mutex.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if len(seen) != 10000 || block.Remaining() != 0 {
+		t.Fatalf("【失败】-%s-got:%v,%v-want:%v", "并发取出", len(seen), block.Remaining(), 10000)
+	}
+
+	if _, err := idGen.ReserveBlock(0); err == nil {
+		t.Fatalf("【失败】-%s-got:%v-want:%s", "n为0", err, "错误")
+	}
+	idGen.SetPadding([]byte("secret"), 0.1)
+	if _, err := idGen.ReserveBlock(10); err == nil {
+		t.Fatalf("【失败】-%s-got:%v-want:%s", "序号填充", err, "错误")
+	}
+}
+
+// TestReserveBlockPool 生成器池分片预留的块保留分片编号
+func TestReserveBlockPool(t *testing.T) {
+	pool, err := NewGeneratorPool(1, *DefaultSettings, 4)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	shard := pool.shards[2]
+	block, err := shard.ReserveBlock(3000)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	for i := int64(0); i < block.Len(); i++ {
+		if got := shard.Decompose(block.ID(i)).Seq >> 10; got != 2 {
+			t.Fatalf("【失败】-第%d个-分片编号-got:%v-want:%v", i, got, 2)
+		}
+	}
+}
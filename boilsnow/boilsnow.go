@@ -0,0 +1,60 @@
+// Package boilsnow sqlboiler集成：在BeforeInsert钩子中为未指定id的记录填充id
+//   - 不依赖sqlboiler：钩子的执行器类型由类型参数指定，同时适用于volatiletech与aarondl两个版本的boil.ContextExecutor
+//   - 生成失败时钩子返回错误，Insert不执行并原样返回该错误(可用errors.Is判断ErrTimeOverflow等)
+//   - 生成器可被多个goroutine共享，钩子不额外加锁；生成器为nil时使用包级默认生成器(generator.Init)
+//
+// 如：
+//
+//	models.AddOrderHook(boil.BeforeInsertHook,
+//		boilsnow.BeforeInsert[boil.ContextExecutor](idGen, func(o *models.Order) *int64 { return &o.ID }))
+package boilsnow
+
+import (
+	"context"
+
+	generator "github.com/jayecc/mtl-snowflake"
+)
+
+// BeforeInsert 返回BeforeInsert钩子：id为0时生成id，已指定的id保持不变
+//   - E为钩子的执行器类型(boil.ContextExecutor)，T为模型类型，id返回模型中id字段的指针
+func BeforeInsert[E any, T any, I ~int64](idGen generator.Generator, id func(*T) *I) func(context.Context, E, *T) error {
+	return func(ctx context.Context, exec E, o *T) error {
+		field := id(o)
+		if *field != 0 {
+			return nil
+		}
+		v, err := generate(idGen)
+		if err != nil {
+			return err
+		}
+		*field = I(v)
+		return nil
+	}
+}
+
+// BeforeInsertString 同BeforeInsert，用于字符串类型的id，encode为nil时使用十进制
+func BeforeInsertString[E any, T any](idGen generator.Generator, id func(*T) *string, encode func(int64) string) func(context.Context, E, *T) error {
+	if encode == nil {
+		encode = func(v int64) string { return generator.ID(v).String() }
+	}
+	return func(ctx context.Context, exec E, o *T) error {
+		field := id(o)
+		if *field != "" {
+			return nil
+		}
+		v, err := generate(idGen)
+		if err != nil {
+			return err
+		}
+		*field = encode(int64(v))
+		return nil
+	}
+}
+
+// generate 使用idGen生成id，idGen为nil时使用包级默认生成器
+func generate(idGen generator.Generator) (generator.ID, error) {
+	if idGen == nil {
+		return generator.Generate()
+	}
+	return idGen.Generate()
+}
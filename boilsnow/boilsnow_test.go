@@ -0,0 +1,72 @@
+package boilsnow
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	generator "github.com/jayecc/mtl-snowflake"
+)
+
+// executor 模拟boil.ContextExecutor
+type executor interface{}
+
+type order struct {
+	ID   int64
+	Name string
+}
+
+type account struct {
+	ID generator.ID
+}
+
+type user struct {
+	ID string
+}
+
+type failingGenerator struct{}
+
+func (failingGenerator) Generate() (generator.ID, error) { return 0, generator.ErrTimeOverflow }
+
+// TestBeforeInsert 为未指定id的记录填充id，生成失败时返回错误
+func TestBeforeInsert(t *testing.T) {
+	idGen, _ := generator.NewGenerator(9)
+	ctx := context.Background()
+	hook := BeforeInsert[executor](idGen, func(o *order) *int64 { return &o.ID })
+
+	o := &order{}
+	if err := hook(ctx, nil, o); err != nil || idGen.Decompose(o.ID).MachineID != 9 {
+		t.Fatalf("【失败】-%s-got:%v,%v", "填充id", o.ID, err)
+	}
+	preset := &order{ID: 42}
+	if hook(ctx, nil, preset); preset.ID != 42 {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "已指定的id保持不变", preset.ID, 42)
+	}
+
+	a := &account{}
+	BeforeInsert[executor](idGen, func(a *account) *generator.ID { return &a.ID })(ctx, nil, a)
+	if a.ID <= 0 {
+		t.Fatalf("【失败】-%s-got:%v", "generator.ID", a.ID)
+	}
+
+	u := &user{}
+	BeforeInsertString[executor](idGen, func(u *user) *string { return &u.ID }, nil)(ctx, nil, u)
+	if id, err := generator.ParseID(u.ID); err != nil || id <= 0 {
+		t.Fatalf("【失败】-%s-got:%v", "字符串id", u.ID)
+	}
+	u = &user{}
+	BeforeInsertString[executor](idGen, func(u *user) *string { return &u.ID }, func(id int64) string { return generator.ID(id).Base62() })(ctx, nil, u)
+	if u.ID == "" {
+		t.Fatalf("【失败】-%s-got:%v", "自定义编码", u.ID)
+	}
+
+	o = &order{}
+	err := BeforeInsert[executor](failingGenerator{}, func(o *order) *int64 { return &o.ID })(ctx, nil, o)
+	if !errors.Is(err, generator.ErrTimeOverflow) || o.ID != 0 {
+		t.Fatalf("【失败】-%s-got:%v,%v-want:%v", "生成失败", o.ID, err, generator.ErrTimeOverflow)
+	}
+	err = BeforeInsert[executor](nil, func(o *order) *int64 { return &o.ID })(ctx, nil, o)
+	if !errors.Is(err, generator.ErrNotInitialized) {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "默认生成器未初始化", err, generator.ErrNotInitialized)
+	}
+}
@@ -0,0 +1,7 @@
+module github.com/jayecc/mtl-snowflake/boilsnow
+
+go 1.25.0
+
+require github.com/jayecc/mtl-snowflake v0.0.0
+
+replace github.com/jayecc/mtl-snowflake => ../
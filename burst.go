@@ -0,0 +1,42 @@
+package generator
+
+// SetBurstBudget 限制每个时间单位内使用的序号数，用完后等待下一个时间单位(溢出到下一个时间单位)，0表示不限制
+//   - 突发请求时不会占满一个时间单位的全部序号，同一机器ID的其他使用方(如按序号空间划分的调用方)不会因此被挤占
+//   - 开启序号空间划分(SetSeqReservation)时作用于每个调用方；开启序号填充时被跳过的序号同样计入
+//   - 因预算用完等待的次数通过MetricBurstSpilled上报
+func (idGen *IDGenerator) SetBurstBudget(idsPerTick int) error {
+	maxSeq := idGen.settings.presets.maxSeq
+	if idsPerTick < 0 || int64(idsPerTick) > maxSeq+1 {
+		return errorf(CodeIDsPerTickRange, maxSeq+1)
+	}
+
+	idGen.mutex.Lock()
+	defer idGen.mutex.Unlock()
+	idGen.burstBudget = int64(idsPerTick)
+	return nil
+}
+
+// seqLimit 序号空间mask内允许使用的最大序号，调用方须持有锁
+//   - 作为生成器池的分片时序号的高位为分片编号，只使用低位的序号
+func (idGen *IDGenerator) seqLimit(mask int64) int64 {
+	if idGen.shardSeqMax > 0 && idGen.shardSeqMax < mask {
+		mask = idGen.shardSeqMax
+	}
+	if idGen.burstBudget > 0 && idGen.burstBudget-1 < mask {
+		return idGen.burstBudget - 1
+	}
+	return mask
+}
+
+// nextSeqExhausted 推进序号，超出允许使用的最大序号时置为下一个时间单位的起始序号并返回true，调用方须持有锁
+func (idGen *IDGenerator) nextSeqExhausted(seq *int64, mask int64) bool {
+	limit := idGen.seqLimit(mask)
+	if *seq += idGen.seqStep(); *seq <= limit {
+		return false
+	}
+	*seq = idGen.firstSeq()
+	if idGen.burstBudget > 0 && limit == idGen.burstBudget-1 && idGen.metrics != nil {
+		idGen.metrics.Counter(MetricBurstSpilled, 1)
+	}
+	return true
+}
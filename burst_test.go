@@ -0,0 +1,103 @@
+package generator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jayecc/mtl-snowflake/fakeclock"
+)
+
+// TestBurstBudget 每个时间单位的序号预算
+func TestBurstBudget(t *testing.T) {
+	testCases := []struct {
+		name      string
+		generate  func(idGen *IDGenerator) []int64
+		wantSeqs  []int64
+		wantTimes []int64 //相对第一个id的时间单位
+	}{
+		{name: "Generate", generate: func(idGen *IDGenerator) []int64 {
+			ids := make([]int64, 7)
+			for i := range ids {
+				id, _ := idGen.Generate()
+				ids[i] = int64(id)
+			}
+			return ids
+		}, wantSeqs: []int64{0, 1, 2, 0, 1, 2, 0}, wantTimes: []int64{0, 0, 0, 1, 1, 1, 2}},
+		{name: "GenerateN", generate: func(idGen *IDGenerator) []int64 {
+			ids, _ := idGen.GenerateN(7)
+			return ids
+		}, wantSeqs: []int64{0, 1, 2, 0, 1, 2, 0}, wantTimes: []int64{0, 0, 0, 1, 1, 1, 2}},
+	}
+	for _, tc := range testCases {
+		clock := fakeclock.New(time.Now().Truncate(time.Millisecond))
+		settings := *DefaultSettings
+		settings.Clock = clock
+		idGen, _ := NewGeneratorWithSettings(0, settings)
+		metrics := countingMetrics{}
+		idGen.SetMetrics(metrics)
+		if err := idGen.SetBurstBudget(3); err != nil {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, err, nil)
+		}
+
+		ids := tc.generate(idGen)
+		if len(ids) != len(tc.wantSeqs) {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, len(ids), len(tc.wantSeqs))
+		}
+		start := idGen.Decompose(ids[0]).Time
+		for i, id := range ids {
+			c := idGen.Decompose(id)
+			if c.Seq != tc.wantSeqs[i] || c.Time-start != tc.wantTimes[i] {
+				t.Fatalf("【失败】-%s-第%d个-got:%v,%v-want:%v,%v", tc.name, i, c.Seq, c.Time-start, tc.wantSeqs[i], tc.wantTimes[i])
+			}
+		}
+		if got := metrics[MetricBurstSpilled]; got != 2 {
+			t.Fatalf("【失败】-%s-溢出次数-got:%v-want:%v", tc.name, got, 2)
+		}
+	}
+}
+
+// TestBurstBudgetReservation 序号空间划分时预算作用于每个调用方
+func TestBurstBudgetReservation(t *testing.T) {
+	clock := fakeclock.New(time.Now().Truncate(time.Millisecond))
+	settings := *DefaultSettings
+	settings.Clock = clock
+	idGen, _ := NewGeneratorWithSettings(0, settings)
+	idGen.SetSeqReservation(2)
+	caller, _ := idGen.RegisterCaller("batch")
+	idGen.SetBurstBudget(2)
+
+	var times []int64
+	for i := 0; i < 3; i++ {
+		id, err := idGen.GenerateFor(caller)
+		if err != nil {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", "生成", err, nil)
+		}
+		times = append(times, idGen.Decompose(id).Time)
+	}
+	if times[1] != times[0] || times[2] != times[0]+1 {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "调用方溢出", times, "前2个同一时间单位")
+	}
+	if got := idGen.CallerExhaustions()["batch"]; got != 1 {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "用完次数", got, 1)
+	}
+}
+
+// TestSetBurstBudget 预算范围
+func TestSetBurstBudget(t *testing.T) {
+	idGen, _ := NewGenerator(0)
+	testCases := []struct {
+		name    string
+		budget  int
+		wantErr bool
+	}{
+		{name: "关闭", budget: 0},
+		{name: "全部序号", budget: 4096},
+		{name: "负数", budget: -1, wantErr: true},
+		{name: "超过序号数", budget: 4097, wantErr: true},
+	}
+	for _, tc := range testCases {
+		if err := idGen.SetBurstBudget(tc.budget); (err != nil) != tc.wantErr {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, err, tc.wantErr)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package generator
+
+import (
+	"math/big"
+	"time"
+)
+
+// nanosPerYear 一年(365.2425天)的纳秒数
+const nanosPerYear = 365.2425 * 24 * float64(time.Hour)
+
+// Capacity id结构的容量
+type Capacity struct {
+	TimeUnit     time.Duration //时间单位
+	Years        float64       //自基准时间起可使用的年数
+	Until        time.Time     //时间部分用尽的时间
+	Datacenters  int64         //数据中心数
+	Machines     int64         //最多实例数(每个数据中心)
+	Timelines    int64         //时间线数
+	SeqPerUnit   int64         //单个实例每个时间单位最多生成的id数
+	IDsPerSecond float64       //单个实例每秒最多生成的id数
+
+	//时间线位置的取舍
+	Placement         Placement //时间线位置
+	TimeOrdered       bool      //不同时间线的id按生成时间排序，支持CutoffIDAt、PartitionBounds等按时间计算id范围的功能
+	MonotonicSwitch   bool      //切换到编号更大的时间线后id继续递增，严格递增模式下发生时钟回退可切换时间线而无需等待
+	MachineContiguous bool      //同一时间单位内同一机器的id连续
+}
+
+// Capacity 计算id结构的容量，用于规划位数分配及时间线位置
+func (settings *Settings) Capacity() Capacity {
+	unit := settings.unit()
+	units := int64(1) << settings.TimeBit
+
+	//按纳秒计算用尽时间，可能超出int64范围
+	nanos := new(big.Int).Mul(big.NewInt(units), big.NewInt(unit))
+	nanos.Add(nanos, big.NewInt(settings.Epoch))
+	sec, nsec := new(big.Int).DivMod(nanos, big.NewInt(int64(time.Second)), new(big.Int))
+
+	seqPerUnit := int64(1) << settings.SeqBit
+	return Capacity{
+		TimeUnit:     time.Duration(unit),
+		Years:        float64(units) * float64(unit) / nanosPerYear,
+		Until:        time.Unix(sec.Int64(), nsec.Int64()),
+		Datacenters:  int64(1) << settings.DatacenterBit,
+		Machines:     int64(1) << settings.MachineIDBit,
+		Timelines:    int64(1) << settings.TimelineBit,
+		SeqPerUnit:   seqPerUnit,
+		IDsPerSecond: float64(seqPerUnit) * float64(time.Second) / float64(unit),
+
+		Placement:         settings.Placement,
+		TimeOrdered:       settings.timeOrdered(),
+		MonotonicSwitch:   settings.Placement == TimelineAboveTime,
+		MachineContiguous: settings.Placement == TimelineBelowMachine,
+	}
+}
@@ -0,0 +1,72 @@
+package generator
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// TestCapacity id结构的容量
+func TestCapacity(t *testing.T) {
+	testCases := []struct {
+		name         string
+		settings     *Settings
+		years        float64
+		until        time.Time
+		machines     int64
+		seqPerUnit   int64
+		idsPerSecond float64
+	}{
+		{name: "默认配置", settings: DefaultSettings, years: 69.7, until: time.Date(2089, 9, 6, 15, 47, 35, 552e6, time.UTC), machines: 512, seqPerUnit: 4096, idsPerSecond: 4096e3},
+		{name: "秒级配置", settings: SecondSettings, years: 136.1, until: time.Date(2156, 2, 7, 6, 28, 16, 0, time.UTC), machines: 1024, seqPerUnit: 1 << 20, idsPerSecond: 1 << 20},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := tc.settings.Capacity()
+			if math.Abs(got.Years-tc.years) > 0.05 {
+				t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, got.Years, tc.years)
+			}
+			if !got.Until.Equal(tc.until) {
+				t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, got.Until.UTC(), tc.until)
+			}
+			if got.Placement != TimelineBelowMachine || !got.TimeOrdered || got.MonotonicSwitch || !got.MachineContiguous {
+				t.Fatalf("【失败】-%s-时间线位置-got:%+v", tc.name, got)
+			}
+			if got.Machines != tc.machines || got.Timelines != 2 || got.SeqPerUnit != tc.seqPerUnit || got.IDsPerSecond != tc.idsPerSecond {
+				t.Fatalf("【失败】-%s-got:%+v", tc.name, got)
+			}
+		})
+	}
+}
+
+// TestCapacityPlacement 时间线位置的取舍
+func TestCapacityPlacement(t *testing.T) {
+	testCases := []struct {
+		name                                            string
+		placement                                       Placement
+		timeOrdered, monotonicSwitch, machineContiguous bool
+	}{
+		{name: "时间线位于机器ID之下", placement: TimelineBelowMachine, timeOrdered: true, monotonicSwitch: false, machineContiguous: true},
+		{name: "时间线位于机器ID之上", placement: TimelineAboveMachine, timeOrdered: true, monotonicSwitch: false, machineContiguous: false},
+		{name: "时间线位于时间之上", placement: TimelineAboveTime, timeOrdered: false, monotonicSwitch: true, machineContiguous: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			settings := *DefaultSettings
+			settings.Placement = tc.placement
+			got := settings.Capacity()
+			if got.Placement != tc.placement || got.TimeOrdered != tc.timeOrdered ||
+				got.MonotonicSwitch != tc.monotonicSwitch || got.MachineContiguous != tc.machineContiguous {
+				t.Fatalf("【失败】-%s-got:%+v", tc.name, got)
+			}
+
+			//不按时间排序时不支持按时间计算分区范围
+			idGen, _ := NewGeneratorWithSettings(0, settings)
+			if _, _, err := idGen.PartitionBounds(0, time.Hour); (err == nil) != tc.timeOrdered {
+				t.Fatalf("【失败】-%s-分区范围-got:%v-want:%v", tc.name, err, tc.timeOrdered)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package generator
+
+import (
+	"context"
+	"runtime/pprof"
+	"time"
+)
+
+// SubsystemMetrics 可按子系统区分的指标上报
+//   - 生成器的指标上报实现该接口时，子生成器的指标通过Subsystem(name)返回的Metrics上报；未实现时与父生成器合并上报
+//   - 子生成器每次生成都会调用Subsystem，实现应缓存各子系统的Metrics
+type SubsystemMetrics interface {
+	Metrics
+	Subsystem(name string) Metrics
+}
+
+// ChildGenerator 子生成器，与父生成器共享机器ID、时间线与序号空间，生成的id与父生成器及其他子生成器互不重复
+//   - 同一进程的多个组件可共用一个机器ID，各组件的指标、回调、性能分析标签带有子系统名称
+//   - 父生成器的设置(时间源、序号空间划分、严格递增模式等)对子生成器同样生效
+type ChildGenerator struct {
+	parent             *IDGenerator
+	name               string
+	beforeEpochHandler func(now time.Time) //时钟早于基准时间时的回调，nil表示使用父生成器的回调
+}
+
+// Child 创建子系统name的子生成器
+func (idGen *IDGenerator) Child(name string) *ChildGenerator {
+	return &ChildGenerator{parent: idGen, name: name}
+}
+
+// Name 子系统名称
+func (child *ChildGenerator) Name() string {
+	return child.name
+}
+
+// Parent 父生成器
+func (child *ChildGenerator) Parent() *IDGenerator {
+	return child.parent
+}
+
+// SetBeforeEpochHandler 设置子生成器生成时时钟早于基准时间的回调，回调参数带有子系统名称，nil表示使用父生成器的回调
+//   - 是否处于该状态由父生成器与各子生成器共享，进入该状态时只回调触发的生成器一次
+func (child *ChildGenerator) SetBeforeEpochHandler(handler func(name string, now time.Time)) {
+	idGen := child.parent
+	idGen.mutex.Lock()
+	defer idGen.mutex.Unlock()
+	child.beforeEpochHandler = nil
+	if handler != nil {
+		name := child.name
+		child.beforeEpochHandler = func(now time.Time) { handler(name, now) }
+	}
+}
+
+// Generate 生成全局唯一id
+func (child *ChildGenerator) Generate() (ID, error) {
+	idGen := child.parent
+	idGen.mutex.Lock()
+	defer idGen.mutex.Unlock()
+	defer child.enter()()
+	id, err := idGen.generateLocked()
+	return ID(id), err
+}
+
+// GenerateCtx 生成全局唯一id，等待可通过ctx取消，同IDGenerator.GenerateCtx
+//   - 生成期间附加父生成器的性能分析标签及子系统名称标签
+func (child *ChildGenerator) GenerateCtx(ctx context.Context) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	idGen := child.parent
+	idGen.mutex.Lock()
+	defer idGen.mutex.Unlock()
+	defer child.enter()()
+
+	idGen.ctx = ctx
+	defer func() { idGen.ctx = nil }()
+
+	var id int64
+	var err error
+	pprof.Do(ctx, pprof.Labels(child.labelsLocked()...), func(context.Context) {
+		id, err = idGen.generateLocked()
+	})
+	return id, err
+}
+
+// LabelContext 在ctx上附加父生成器的性能分析标签及子系统名称标签
+func (child *ChildGenerator) LabelContext(ctx context.Context) context.Context {
+	idGen := child.parent
+	idGen.mutex.Lock()
+	labels := child.labelsLocked()
+	idGen.mutex.Unlock()
+	return pprof.WithLabels(ctx, pprof.Labels(labels...))
+}
+
+// profileLabels 性能分析标签，子生成器的后台goroutine同样带有子系统名称
+func (child *ChildGenerator) profileLabels() []string {
+	idGen := child.parent
+	idGen.mutex.Lock()
+	defer idGen.mutex.Unlock()
+	return child.labelsLocked()
+}
+
+// labelsLocked 父生成器的性能分析标签及子系统名称标签，调用方须持有父生成器的锁
+func (child *ChildGenerator) labelsLocked() []string {
+	labels := make([]string, 0, len(child.parent.labels)+2)
+	labels = append(labels, child.parent.labels...)
+	return append(labels, LabelSubsystem, child.name)
+}
+
+// enter 将父生成器的指标上报与回调切换为子生成器的，返回恢复函数，调用方须持有父生成器的锁
+func (child *ChildGenerator) enter() func() {
+	idGen := child.parent
+	metrics, handler := idGen.metrics, idGen.beforeEpochHandler
+	if subsystem, ok := metrics.(SubsystemMetrics); ok {
+		idGen.metrics = subsystem.Subsystem(child.name)
+	}
+	if child.beforeEpochHandler != nil {
+		idGen.beforeEpochHandler = child.beforeEpochHandler
+	}
+	return func() {
+		idGen.metrics, idGen.beforeEpochHandler = metrics, handler
+	}
+}
@@ -0,0 +1,170 @@
+package generator
+
+import (
+	"context"
+	"runtime/pprof"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jayecc/mtl-snowflake/fakeclock"
+)
+
+// subsystemMetrics 按子系统记录counter的指标实现
+type subsystemMetrics struct {
+	countingMetrics
+	subsystems map[string]countingMetrics
+}
+
+func (m *subsystemMetrics) Subsystem(name string) Metrics {
+	sub, exist := m.subsystems[name]
+	if !exist {
+		sub = countingMetrics{}
+		m.subsystems[name] = sub
+	}
+	return sub
+}
+
+// TestChild 子生成器
+func TestChild(t *testing.T) {
+	idGen, _ := NewGenerator(1)
+	orders, users := idGen.Child("orders"), idGen.Child("users")
+
+	//共享序号空间，并发生成不重复
+	const n = 2000
+	var mutex sync.Mutex
+	seen := make(map[int64]bool)
+	var wg sync.WaitGroup
+	for _, gen := range []Generator{idGen, orders, users} {
+		wg.Add(1)
+		go func(gen Generator) {
+			defer wg.Done()
+			for i := 0; i < n; i++ {
+				id, err := gen.Generate()
+				if err != nil {
+					t.Errorf("【失败】-%s-got:%v-want:%v", "生成", err, nil)
+					return
+				}
+				mutex.Lock()
+				if seen[int64(id)] {
+					t.Errorf("【失败】-%s-got:%v-want:%v", "id重复", id, "不重复")
+				}
+				seen[int64(id)] = true
+				mutex.Unlock()
+			}
+		}(gen)
+	}
+	wg.Wait()
+	if len(seen) != 3*n {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "id数", len(seen), 3*n)
+	}
+
+	id, _ := orders.Generate()
+	if got := idGen.Decompose(int64(id)).MachineID; got != 1 {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "机器ID", got, 1)
+	}
+	if orders.Name() != "orders" || orders.Parent() != idGen {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "名称", orders.Name(), "orders")
+	}
+}
+
+// TestChildMetrics 子生成器的指标按子系统上报
+func TestChildMetrics(t *testing.T) {
+	idGen, _ := NewGenerator(1)
+	metrics := &subsystemMetrics{countingMetrics: countingMetrics{}, subsystems: map[string]countingMetrics{}}
+	idGen.SetMetrics(metrics)
+	orders := idGen.Child("orders")
+
+	testCases := []struct {
+		name string
+		gen  Generator
+		n    int
+	}{
+		{name: "父生成器", gen: idGen, n: 3},
+		{name: "子生成器", gen: orders, n: 5},
+	}
+	for _, tc := range testCases {
+		for i := 0; i < tc.n; i++ {
+			if _, err := tc.gen.Generate(); err != nil {
+				t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, err, nil)
+			}
+		}
+	}
+	if got := metrics.countingMetrics[MetricGenerated]; got != 3 {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "父生成器指标", got, 3)
+	}
+	if got := metrics.subsystems["orders"][MetricGenerated]; got != 5 {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "子生成器指标", got, 5)
+	}
+
+	//未实现SubsystemMetrics时合并上报
+	plain := countingMetrics{}
+	idGen.SetMetrics(plain)
+	idGen.Generate()
+	orders.Generate()
+	if got := plain[MetricGenerated]; got != 2 {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "合并上报", got, 2)
+	}
+}
+
+// TestChildBeforeEpochHandler 子生成器的回调带有子系统名称
+func TestChildBeforeEpochHandler(t *testing.T) {
+	clock := fakeclock.New(time.Now())
+	settings := *DefaultSettings
+	settings.Clock = clock
+	idGen, _ := NewGeneratorWithSettings(0, settings)
+	parentCalls := make(chan time.Time, 10)
+	idGen.SetBeforeEpochHandler(func(now time.Time) { parentCalls <- now })
+	orders := idGen.Child("orders")
+	childCalls := make(chan string, 10)
+	orders.SetBeforeEpochHandler(func(name string, now time.Time) { childCalls <- name })
+
+	now := clock.Time()
+	beforeEpoch := time.Unix(0, settings.Epoch).Add(-time.Hour)
+	clock.Set(beforeEpoch)
+	if _, err := orders.Generate(); err != ErrBeforeEpoch {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "子生成器", err, ErrBeforeEpoch)
+	}
+	select {
+	case name := <-childCalls:
+		if name != "orders" {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", "子系统名称", name, "orders")
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "子生成器回调", "无", "回调")
+	}
+
+	//恢复后父生成器触发，使用父生成器的回调
+	clock.Set(now)
+	idGen.Generate()
+	clock.Set(beforeEpoch)
+	idGen.Generate()
+	select {
+	case <-parentCalls:
+	case <-time.After(time.Second):
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "父生成器回调", "无", "回调")
+	}
+	select {
+	case name := <-childCalls:
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "子生成器回调", name, "无")
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+// TestChildLabels 子生成器的性能分析标签
+func TestChildLabels(t *testing.T) {
+	idGen, _ := NewGenerator(1)
+	idGen.SetProfileLabels("order-id", "trade")
+	orders := idGen.Child("orders")
+
+	ctx := orders.LabelContext(context.Background())
+	wantLabels := map[string]string{LabelGenerator: "order-id", LabelBiz: "trade", LabelSubsystem: "orders"}
+	for key, want := range wantLabels {
+		if got, _ := pprof.Label(ctx, key); got != want {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", key, got, want)
+		}
+	}
+	if _, err := orders.GenerateCtx(context.Background()); err != nil {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "GenerateCtx", err, nil)
+	}
+}
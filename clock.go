@@ -0,0 +1,192 @@
+package generator
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Clock 时间源
+//   - Now返回当前unix时间(ns)
+//   - 使用自定义时间源时时钟跳变检测(SetPauseDetection)不生效：跳变检测依赖time.Now()附带的单调时钟读数
+type Clock interface {
+	Now() int64
+}
+
+// Sleeper 时间源可选实现的接口：生成器需要等待时调用Sleep而不是实际休眠
+//   - 供测试用的假时钟在Sleep中推进时间，使序号用完、时钟回退等场景可确定地复现
+type Sleeper interface {
+	Sleep(d time.Duration)
+}
+
+// SystemClock 系统时钟，每次读取time.Now()，生成器缺省使用
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() int64 { return time.Now().UnixNano() }
+
+// CoarseClock 粗粒度时钟：后台goroutine按固定间隔缓存当前时间，读取只需一次原子操作
+//   - 读到的时间最多落后一个间隔，间隔应明显小于时间单位
+//   - 不再使用时须调用Stop停止后台goroutine
+type CoarseClock struct {
+	now  int64
+	stop chan struct{}
+	once sync.Once
+}
+
+// NewCoarseClock 创建粗粒度时钟，resolution为缓存的刷新间隔
+func NewCoarseClock(resolution time.Duration) (*CoarseClock, error) {
+	if resolution <= 0 {
+		return nil, errorf(CodeNotPositive, "resolution")
+	}
+	c := &CoarseClock{now: time.Now().UnixNano(), stop: make(chan struct{})}
+	go func() {
+		ticker := time.NewTicker(resolution)
+		defer ticker.Stop()
+		for {
+			select {
+			case t := <-ticker.C:
+				atomic.StoreInt64(&c.now, t.UnixNano())
+			case <-c.stop:
+				return
+			}
+		}
+	}()
+	return c, nil
+}
+
+// Now 缓存的当前时间
+func (c *CoarseClock) Now() int64 {
+	return atomic.LoadInt64(&c.now)
+}
+
+// Stop 停止刷新
+func (c *CoarseClock) Stop() {
+	c.once.Do(func() { close(c.stop) })
+}
+
+// MonotonicClock 单调时钟锚定：以创建时的墙上时钟为锚点，此后按单调时钟走时
+//   - 不受NTP校正、手动调整系统时间的影响，不会回退
+//   - 长期运行会与墙上时钟产生漂移，应定期(如每天)重建
+type MonotonicClock struct {
+	anchor time.Time
+}
+
+// NewMonotonicClock 创建单调时钟锚定
+func NewMonotonicClock() *MonotonicClock {
+	return &MonotonicClock{anchor: time.Now()}
+}
+
+// Now 锚点加上单调时钟走时
+func (c *MonotonicClock) Now() int64 {
+	return c.anchor.UnixNano() + int64(time.Since(c.anchor))
+}
+
+// WaitPolicy 序号用完、时钟回退时的等待方式
+type WaitPolicy int
+
+const (
+	WaitSleep  WaitPolicy = iota //休眠，不占用CPU，唤醒可能有几十微秒到毫秒级的延迟(缺省)
+	WaitSpin                     //自旋(让出调度)直到时间到达，延迟最低，等待期间占满一个CPU
+	WaitHybrid                   //先休眠，最后spinThreshold内自旋
+)
+
+// spinThreshold 混合等待时自旋的时长
+const spinThreshold = 200 * time.Microsecond
+
+// String 等待方式名称
+func (p WaitPolicy) String() string {
+	switch p {
+	case WaitSleep:
+		return "sleep"
+	case WaitSpin:
+		return "spin"
+	case WaitHybrid:
+		return "hybrid"
+	}
+	return "unknown"
+}
+
+// SetClock 设置时间源，nil表示系统时钟，也可通过Settings.Clock在创建时指定
+//   - 应在生成第一个id之前设置；运行中更换时间源时，新时间源落后的部分按时钟回退处理
+func (idGen *IDGenerator) SetClock(clock Clock) {
+	idGen.mutex.Lock()
+	defer idGen.mutex.Unlock()
+	idGen.clock = clock
+	idGen.clockVersion++
+}
+
+// SetWaitPolicy 设置等待方式，也可通过Settings.WaitPolicy在创建时指定
+func (idGen *IDGenerator) SetWaitPolicy(policy WaitPolicy) error {
+	if policy < WaitSleep || policy > WaitHybrid {
+		return errorf(CodeUnknownWaitPolicy)
+	}
+	idGen.mutex.Lock()
+	defer idGen.mutex.Unlock()
+	idGen.waitPolicy = policy
+	return nil
+}
+
+// SetMaxBackwardWait 设置时钟小幅回退时等待时钟追回的上限，0表示不等待，直接切换时间线，也可通过Settings.MaxBackwardWait在创建时指定
+//   - 追回当前时间线进度所需的时长不超过d时按等待方式等待，不消耗时间线；超过d时切换时间线
+//   - 调大可减少NTP小幅校正对时间线的消耗，代价是回退时生成延迟出现尖峰
+func (idGen *IDGenerator) SetMaxBackwardWait(d time.Duration) error {
+	if d < 0 {
+		return errorf(CodeNegative, "d")
+	}
+	idGen.mutex.Lock()
+	defer idGen.mutex.Unlock()
+	idGen.maxBackwardWait = d
+	return nil
+}
+
+// now 读取时间源
+//   - 系统时钟返回的time.Time带有单调时钟读数，供时钟跳变检测使用
+func (idGen *IDGenerator) now() time.Time {
+	if idGen.clock == nil {
+		return time.Now()
+	}
+	return time.Unix(0, idGen.clock.Now())
+}
+
+// waitUntil 按等待方式等待直到时间到达target(时间单位)，返回等待后的时间，调用方须持有锁
+//   - 时间源粗粒度或等待期间时钟回退时醒来后可能仍未到达，继续等待而不是返回旧的时间
+//   - GenerateWithin限制等待额度时，按时间源计算已等待的时长，剩余等待超出额度时返回ErrWaitTimeout
+func (idGen *IDGenerator) waitUntil(target int64) (int64, error) {
+	policy := idGen.waitPolicy
+	if _, ok := idGen.clock.(Sleeper); ok && idGen.timer == nil {
+		policy = WaitSleep //自旋等待不会推进假时钟
+	}
+	timer := idGen.currentTimer()
+	var last int64
+	for {
+		now := idGen.now().UnixNano()
+		if idGen.budgeted && last != 0 && now > last {
+			idGen.waitBudget -= time.Duration(now - last)
+		}
+		last = now
+		curTime := idGen.toOffsetTime(now)
+		if curTime >= target {
+			return curTime, nil
+		}
+		remaining := time.Duration(idGen.toUnixNano(target) - now)
+		if idGen.budgeted && remaining > idGen.waitBudget {
+			return curTime, ErrWaitTimeout
+		}
+		switch {
+		case policy == WaitSpin, policy == WaitHybrid && remaining <= spinThreshold:
+			if idGen.ctx != nil && idGen.ctx.Err() != nil {
+				return curTime, idGen.ctx.Err()
+			}
+			timer.Yield()
+		case policy == WaitHybrid:
+			remaining -= spinThreshold
+			fallthrough
+		default:
+			if err := idGen.sleep(remaining); err != nil {
+				return curTime, err
+			}
+		}
+	}
+}
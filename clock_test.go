@@ -0,0 +1,139 @@
+package generator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jayecc/mtl-snowflake/fakeclock"
+)
+
+// TestClocks 时间源与系统时钟的偏差
+func TestClocks(t *testing.T) {
+	coarse, err := NewCoarseClock(time.Millisecond)
+	if err != nil {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "创建粗粒度时钟", err, nil)
+	}
+	defer coarse.Stop()
+	if _, err := NewCoarseClock(0); err == nil {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "刷新间隔为0", err, "error")
+	}
+
+	testCases := []struct {
+		name  string
+		clock Clock
+		drift time.Duration
+	}{
+		{name: "系统时钟", clock: SystemClock, drift: time.Millisecond},
+		{name: "粗粒度时钟", clock: coarse, drift: 20 * time.Millisecond},
+		{name: "单调时钟锚定", clock: NewMonotonicClock(), drift: time.Millisecond},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			time.Sleep(5 * time.Millisecond)
+			got := time.Duration(time.Now().UnixNano() - tc.clock.Now())
+			if got < -tc.drift || got > tc.drift {
+				t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, got, tc.drift)
+			}
+		})
+	}
+}
+
+// TestWaitPolicy 各等待方式及粗粒度时钟下序号用完时不产生重复id
+func TestWaitPolicy(t *testing.T) {
+	testCases := []struct {
+		name   string
+		policy WaitPolicy
+		coarse bool
+	}{
+		{name: "休眠", policy: WaitSleep},
+		{name: "自旋", policy: WaitSpin},
+		{name: "混合", policy: WaitHybrid},
+		{name: "粗粒度时钟休眠", policy: WaitSleep, coarse: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			idGen, _ := NewGeneratorWithSettings(1, Settings{TimeBit: 41, MachineIDBit: 10, TimelineBit: 8, SeqBit: 4, Epoch: DefaultSettings.Epoch})
+			if err := idGen.SetWaitPolicy(tc.policy); err != nil {
+				t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, err, nil)
+			}
+			if tc.coarse {
+				clock, _ := NewCoarseClock(3 * time.Millisecond)
+				defer clock.Stop()
+				idGen.SetClock(clock)
+			}
+			seen := make(map[int64]bool)
+			for i := 0; i < 200; i++ {
+				id, err := idGen.Generate()
+				if err != nil || seen[int64(id)] {
+					t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, err, "不重复")
+				}
+				seen[int64(id)] = true
+			}
+		})
+	}
+	idGen, _ := NewGenerator(1)
+	if err := idGen.SetWaitPolicy(WaitPolicy(9)); err == nil {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "未知的等待方式", err, "error")
+	}
+}
+
+// TestFakeClock 假时钟下序号用完确定地推进到下一个时间单位
+func TestFakeClock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := fakeclock.New(start)
+	idGen, _ := NewGeneratorWithSettings(1, Settings{TimeBit: 41, MachineIDBit: 10, TimelineBit: 8, SeqBit: 4, Epoch: DefaultEpoch, Clock: clock})
+	idGen.SetWaitPolicy(WaitSpin) //假时钟下自旋退化为休眠
+
+	for i := 0; i < 16*3; i++ {
+		id, _ := idGen.Generate()
+		got, want := idGen.Decompose(int64(id)), IDCompose{Time: idGen.toOffsetTime(start.UnixNano()) + int64(i/16), MachineID: 1, Seq: int64(i % 16),
+			Timestamp: time.Unix(0, start.UnixNano()+int64(i/16)*int64(time.Millisecond))}
+		if *got != want {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", "序号用完", *got, want)
+		}
+	}
+	if got, want := clock.Time(), start.Add(2*time.Millisecond); !got.Equal(want) {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "假时钟推进", got, want)
+	}
+}
+
+// TestMaxBackwardWait 时钟小幅回退不超过MaxBackwardWait时等待时钟追回，超过时切换时间线
+func TestMaxBackwardWait(t *testing.T) {
+	testCases := []struct {
+		name     string
+		maxWait  time.Duration
+		backward time.Duration
+		timeline int64
+	}{
+		{name: "缺省直接切换", backward: 500 * time.Microsecond, timeline: 1},
+		{name: "回退不超过上限时等待", maxWait: 5 * time.Millisecond, backward: 3 * time.Millisecond, timeline: 0},
+		{name: "回退超过上限时切换", maxWait: 5 * time.Millisecond, backward: 10 * time.Millisecond, timeline: 1},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+			clock := fakeclock.New(start)
+			idGen, err := NewGeneratorWithSettings(1, Settings{TimeBit: 41, MachineIDBit: 10, TimelineBit: 1, SeqBit: 11, Epoch: DefaultEpoch, Clock: clock, WaitPolicy: WaitHybrid, MaxBackwardWait: tc.maxWait})
+			if err != nil {
+				t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, err, nil)
+			}
+			first, _ := idGen.Generate()
+			clock.Advance(-tc.backward)
+			id, err := idGen.Generate()
+			if err != nil || id <= first && tc.timeline == 0 {
+				t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, err, "生成成功")
+			}
+			if got := idGen.Decompose(int64(id)).TimeLine; got != tc.timeline {
+				t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, got, tc.timeline)
+			}
+		})
+	}
+
+	idGen, _ := NewGenerator(1)
+	if err := idGen.SetMaxBackwardWait(-time.Millisecond); err == nil {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "上限为负数", err, "error")
+	}
+	if _, err := NewGeneratorWithSettings(1, Settings{TimeBit: 41, MachineIDBit: 10, TimelineBit: 1, SeqBit: 11, Epoch: DefaultEpoch, WaitPolicy: WaitPolicy(9)}); err == nil {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "未知的等待方式", err, "error")
+	}
+}
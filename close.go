@@ -0,0 +1,96 @@
+package generator
+
+import (
+	"context"
+	"io"
+)
+
+// ErrGeneratorClosed 生成器已关闭
+var ErrGeneratorClosed = newError(CodeGeneratorClosed)
+
+// RegisterCloser 注册随生成器关闭的资源，如机器ID分配器(machineid.Allocator)、水位发布(WatermarkPublisher)
+//   - Close时按注册的逆序关闭；生成器已关闭时立即关闭closer
+func (idGen *IDGenerator) RegisterCloser(closer io.Closer) error {
+	idGen.mutex.Lock()
+	if idGen.closed {
+		idGen.mutex.Unlock()
+		return closer.Close()
+	}
+	idGen.closers = append(idGen.closers, closer)
+	idGen.mutex.Unlock()
+	return nil
+}
+
+// Close 关闭生成器
+//   - 此后生成均返回ErrGeneratorClosed，RotateMachineID也不能恢复
+//   - 停止Stream及后台保存时间线状态的goroutine并等待其退出，保存最终的时间线状态(不含提前量)
+//   - 按注册的逆序关闭RegisterCloser注册的资源(如释放机器ID)，返回第一个错误
+//   - ctx取消时不再等待，返回ctx.Err()；未完成的部分保留，再次调用Close时继续等待、关闭
+//   - 全部完成后重复调用返回nil
+func (idGen *IDGenerator) Close(ctx context.Context) error {
+	idGen.mutex.Lock()
+	if !idGen.closed {
+		idGen.closed = true
+		idGen.fenced = ErrGeneratorClosed
+		close(idGen.closing)
+		//等待后台goroutine退出
+		stopped, p := make(chan struct{}), idGen.persist
+		idGen.stopped = stopped
+		go func() {
+			idGen.background.Wait()
+			if p != nil {
+				close(p.stop)
+				<-p.done
+			}
+			close(stopped)
+		}()
+	}
+	stopped := idGen.stopped
+	idGen.mutex.Unlock()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case idGen.closeTurn <- struct{}{}:
+		defer func() { <-idGen.closeTurn }()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	var err error
+	idGen.mutex.Lock()
+	p := idGen.persist
+	flush := p != nil && !idGen.stateFlushed
+	if flush {
+		p.lookahead = 0
+		idGen.stateFlushed = true
+	}
+	idGen.mutex.Unlock()
+	if flush {
+		err = idGen.FlushState()
+	}
+	for {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		idGen.mutex.Lock()
+		n := len(idGen.closers)
+		if n == 0 {
+			idGen.mutex.Unlock()
+			return err
+		}
+		closer := idGen.closers[n-1]
+		idGen.mutex.Unlock()
+
+		closeErr := closer.Close()
+		idGen.mutex.Lock()
+		idGen.closers = idGen.closers[:n-1]
+		idGen.mutex.Unlock()
+		if err == nil {
+			err = closeErr
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package generator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jayecc/mtl-snowflake/fakeclock"
+)
+
+// closerFunc 函数形式的io.Closer
+type closerFunc func() error
+
+func (f closerFunc) Close() error { return f() }
+
+// TestClose 关闭生成器
+func TestClose(t *testing.T) {
+	clock := fakeclock.New(time.Now())
+	persister := &memoryPersister{}
+	settings := *DefaultSettings
+	settings.Clock = clock
+	settings.StatePersister = persister
+	settings.PersistInterval = time.Hour
+	idGen, err := NewGeneratorWithSettings(1, settings)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	var order []int
+	for i := 0; i < 3; i++ {
+		i := i
+		idGen.RegisterCloser(closerFunc(func() error { order = append(order, i); return nil }))
+	}
+	stream := idGen.Stream(context.Background(), 4)
+	id := <-stream
+
+	if err := idGen.Close(context.Background()); err != nil {
+		t.Fatalf("【失败】-%s-%v", "关闭", err)
+	}
+	for range stream {
+	}
+
+	testCases := []struct {
+		name  string
+		check func() bool
+	}{
+		{name: "按注册的逆序关闭", check: func() bool { return len(order) == 3 && order[0] == 2 && order[2] == 0 }},
+		{name: "生成返回错误", check: func() bool { _, err := idGen.Generate(); return err == ErrGeneratorClosed }},
+		{name: "切换机器ID不能恢复", check: func() bool { _, err := idGen.RotateMachineID(2); return err == ErrGeneratorClosed }},
+		{name: "重复关闭", check: func() bool { return idGen.Close(context.Background()) == nil }},
+		{name: "关闭后注册立即关闭", check: func() bool {
+			closed := false
+			idGen.RegisterCloser(closerFunc(func() error { closed = true; return nil }))
+			return closed
+		}},
+		{name: "关闭后的Stream", check: func() bool { _, ok := <-idGen.Stream(context.Background(), 1); return !ok }},
+		{name: "保存最终状态不含提前量", check: func() bool {
+			state, _ := persister.Load()
+			return state.Progress[state.Timeline] >= idGen.Time(id).UnixNano() && state.Progress[state.Timeline] <= clock.Now()
+		}},
+	}
+	for _, tc := range testCases {
+		if !tc.check() {
+			t.Fatalf("【失败】-%s", tc.name)
+		}
+	}
+}
+
+// TestCloseRetry 首次Close超时后，再次Close继续关闭未关闭的资源
+func TestCloseRetry(t *testing.T) {
+	idGen, _ := NewGenerator(1)
+	released := 0
+	idGen.RegisterCloser(closerFunc(func() error { released++; return nil }))
+	idGen.RegisterCloser(closerFunc(func() error { time.Sleep(50 * time.Millisecond); return nil }))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := idGen.Close(ctx); err != context.DeadlineExceeded || released != 0 {
+		t.Fatalf("【失败】-%s-got:%v,%v-want:%v,%v", "首次关闭超时", err, released, context.DeadlineExceeded, 0)
+	}
+	if err := idGen.Close(context.Background()); err != nil || released != 1 {
+		t.Fatalf("【失败】-%s-got:%v,%v-want:%v,%v", "重试释放机器ID", err, released, nil, 1)
+	}
+	if err := idGen.Close(context.Background()); err != nil || released != 1 {
+		t.Fatalf("【失败】-%s-got:%v,%v-want:%v,%v", "重复关闭", err, released, nil, 1)
+	}
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"flag"
+
+	"github.com/jayecc/mtl-snowflake/bench"
+)
+
+// runBench mtl-snowflake bench [-duration 1s -goroutines 8 ...]
+func runBench(args []string) error {
+	fs := flag.NewFlagSet("bench", flag.ContinueOnError)
+	getSettings := settingsFlags(fs)
+	duration := fs.Duration("duration", 0, "每种组合的运行时长，缺省1s")
+	goroutines := fs.Int("goroutines", 0, "并发调用Generate的goroutine数，缺省GOMAXPROCS")
+	resolution := fs.Duration("coarse-resolution", 0, "粗粒度时钟的刷新间隔，缺省100µs")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	settings, err := getSettings()
+	if err != nil {
+		return err
+	}
+	report, err := bench.Run(bench.Config{
+		Settings:         &settings,
+		Duration:         *duration,
+		Goroutines:       *goroutines,
+		CoarseResolution: *resolution,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = report.WriteTo(stdout)
+	return err
+}
@@ -0,0 +1,11 @@
+package main
+
+import "testing"
+
+// TestBench 对比不同时间源、等待方式下的生成性能
+func TestBench(t *testing.T) {
+	runCases(t, "bench", []commandCase{
+		{name: "性能对比", args: []string{"-duration", "1ms", "-goroutines", "1"}, want: []string{"goroutines: 1\n", "system", "monotonic", "coarse"}},
+		{name: "未知参数", args: []string{"-unknown"}, wantErr: true, flagErr: true},
+	})
+}
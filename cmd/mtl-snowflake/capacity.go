@@ -0,0 +1,41 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+)
+
+// runCapacity mtl-snowflake capacity [-time-unit 1s -time-bit 32 ...]
+func runCapacity(args []string) error {
+	fs := flag.NewFlagSet("capacity", flag.ContinueOnError)
+	getSettings := settingsFlags(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	settings, err := getSettings()
+	if err != nil {
+		return err
+	}
+	if bits := settings.Bits(); bits != 63 {
+		return fmt.Errorf("VersionBit+TimeBit+DatacenterBit+MachineIDBit+TimelineBit+SeqBit(+标记位)为%d，须为63", bits)
+	}
+	c := settings.Capacity()
+	fmt.Fprintf(stdout, "time unit:      %s\n", c.TimeUnit)
+	fmt.Fprintf(stdout, "lifetime:       %.1f years (until %s)\n", c.Years, c.Until.UTC().Format(time.RFC3339))
+	if c.Datacenters > 1 {
+		fmt.Fprintf(stdout, "datacenters:    %d\n", c.Datacenters)
+		fmt.Fprintf(stdout, "machines:       %d (per datacenter)\n", c.Machines)
+	} else {
+		fmt.Fprintf(stdout, "machines:       %d\n", c.Machines)
+	}
+	fmt.Fprintf(stdout, "timelines:      %d\n", c.Timelines)
+	fmt.Fprintf(stdout, "seq per unit:   %d\n", c.SeqPerUnit)
+	fmt.Fprintf(stdout, "ids per second: %.0f (per machine)\n", c.IDsPerSecond)
+	fmt.Fprintf(stdout, "placement:      timeline %s\n", c.Placement)
+	fmt.Fprintf(stdout, "  time ordered across timelines:      %v\n", c.TimeOrdered)
+	fmt.Fprintf(stdout, "  increasing after switching upwards: %v\n", c.MonotonicSwitch)
+	fmt.Fprintf(stdout, "  machine ids contiguous per unit:    %v\n", c.MachineContiguous)
+	return nil
+}
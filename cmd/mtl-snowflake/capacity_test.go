@@ -0,0 +1,15 @@
+package main
+
+import "testing"
+
+// TestCapacity id结构的容量
+func TestCapacity(t *testing.T) {
+	runCases(t, "capacity", []commandCase{
+		{name: "默认结构的容量", want: []string{"time unit:      1ms\n", "machines:       512\n", "timelines:      2\n", "seq per unit:   4096\n"}},
+		{name: "数据中心", args: []string{"-datacenter-bit", "2", "-machine-bit", "7"}, want: []string{"datacenters:    4\n", "machines:       128 (per datacenter)\n"}},
+		{name: "秒级时间单位", args: []string{"-time-unit", "1s", "-time-bit", "32", "-machine-bit", "10", "-timeline-bit", "0", "-seq-bit", "21"}, want: []string{"time unit:      1s\n"}},
+		{name: "版本位", args: []string{"-version-bit", "1", "-time-bit", "40"}, want: []string{"seq per unit:   4096\n"}},
+		{name: "版本位计入位数和", args: []string{"-version-bit", "1"}, wantErr: true},
+		{name: "位数和不为63", args: []string{"-seq-bit", "10"}, wantErr: true},
+	})
+}
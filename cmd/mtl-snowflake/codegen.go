@@ -0,0 +1,35 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	generator "github.com/jayecc/mtl-snowflake"
+)
+
+// runCodegen mtl-snowflake codegen -lang java -class SnowflakeDecoder -package com.example.id
+func runCodegen(args []string) error {
+	fs := flag.NewFlagSet("codegen", flag.ContinueOnError)
+	getSettings := settingsFlags(fs)
+	lang := fs.String("lang", "java", "目标语言：java、python")
+	class := fs.String("class", "SnowflakeDecoder", "类名")
+	pkg := fs.String("package", "", "Java包名")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	settings, err := getSettings()
+	if err != nil {
+		return err
+	}
+	language, err := generator.ParseDecoderLanguage(*lang)
+	if err != nil {
+		return err
+	}
+	code, err := generator.GenerateDecoder(settings, generator.DecoderOptions{Language: language, Name: *class, Package: *pkg})
+	if err != nil {
+		return err
+	}
+	fmt.Fprint(stdout, code)
+	return nil
+}
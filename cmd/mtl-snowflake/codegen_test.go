@@ -0,0 +1,12 @@
+package main
+
+import "testing"
+
+// TestCodegen 生成解码器类
+func TestCodegen(t *testing.T) {
+	runCases(t, "codegen", []commandCase{
+		{name: "生成Java解码器", args: []string{"-class", "OrderID", "-package", "com.example.id"}, want: []string{"package com.example.id;", "OrderID"}},
+		{name: "生成Python解码器", args: []string{"-lang", "python"}, want: []string{"class SnowflakeDecoder"}},
+		{name: "不支持的语言", args: []string{"-lang", "cobol"}, wantErr: true},
+	})
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	generator "github.com/jayecc/mtl-snowflake"
+)
+
+// runDecompose mtl-snowflake decompose 1234567890 ...，未指定id时逐行读取标准输入
+func runDecompose(args []string) error {
+	fs := flag.NewFlagSet("decompose", flag.ContinueOnError)
+	getSettings := settingsFlags(fs)
+	asJSON := fs.Bool("json", false, "每行输出一个JSON对象")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	settings, err := getSettings()
+	if err != nil {
+		return err
+	}
+	decomposer, err := generator.NewDecomposer(settings)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(stdout)
+	defer w.Flush()
+	decompose := func(s string) error {
+		id, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+		if err != nil || id < 0 {
+			return fmt.Errorf("id格式错误：%q", s)
+		}
+		c := decomposer.Decompose(id)
+		if !*asJSON {
+			_, err = fmt.Fprintf(w, "%d %s\n", id, c)
+			return err
+		}
+		data, err := json.Marshal(c)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "{\"id\":\"%d\",%s\n", id, data[1:])
+		return err
+	}
+
+	if fs.NArg() > 0 {
+		for _, s := range fs.Args() {
+			if err := decompose(s); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return eachLine(stdin, decompose)
+}
+
+// eachLine 对每个非空行调用f
+func eachLine(in io.Reader, f func(line string) error) error {
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if err := f(line); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,22 @@
+package main
+
+import "testing"
+
+// TestDecompose 按id结构解析id
+//   - 默认配置下id 4194304007为 时间1000ms、机器ID0、时间线0、序号7
+func TestDecompose(t *testing.T) {
+	runCases(t, "decompose", []commandCase{
+		{name: "解析参数中的id", args: []string{"4194304007"},
+			want: []string{"4194304007 time=2020-01-01T00:00:01Z machine_id=0 timeline=0 seq=7\n"}},
+		{name: "按JSON输出", args: []string{"-json", "4194304007"},
+			want: []string{`{"id":"4194304007","time":1000,"timestamp":"2020-01-01T00:00:01Z","machine_id":0,"timeline":0,"seq":7}`}},
+		{name: "逐行解析标准输入", input: "4194304007\n\n4194328577\n",
+			want: []string{"seq=7\n", "machine_id=3 timeline=0 seq=1\n"}},
+		{name: "按指定结构解析", args: []string{"-machine-bit", "10", "-timeline-bit", "0", "4194328577"},
+			want: []string{"machine_id=6 timeline=0 seq=1\n"}},
+		{name: "id不是数字", args: []string{"abc"}, wantErr: true},
+		{name: "id为负数", input: "-1\n", wantErr: true},
+		{name: "无效的时间单位", args: []string{"-time-unit", "xyz", "1"}, wantErr: true, flagErr: true},
+		{name: "无效的id结构", args: []string{"-seq-bit", "13", "1"}, wantErr: true},
+	})
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	generator "github.com/jayecc/mtl-snowflake"
+)
+
+// exportChunk 每次GenerateN生成的数量
+const exportChunk = 4096
+
+// runExport mtl-snowflake export -n 1000000 -o ids.txt
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	getSettings := settingsFlags(fs)
+	n := fs.Int64("n", 1000, "生成数量")
+	machineID := fs.Int64("machine-id", 0, "机器ID")
+	format := fs.String("format", "decimal", "输出格式：decimal、base62、readable、readable-v2")
+	output := fs.String("o", "", "输出文件，缺省为标准输出")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	settings, err := getSettings()
+	if err != nil {
+		return err
+	}
+	if *n < 0 {
+		return errors.New("-n 不能为负数")
+	}
+	idGen, err := generator.NewGeneratorWithSettings(*machineID, settings)
+	if err != nil {
+		return err
+	}
+
+	var encode func(buf []byte, id int64) []byte
+	switch *format {
+	case "decimal":
+		encode = func(buf []byte, id int64) []byte {
+			return strconv.AppendInt(buf, id, 10)
+		}
+	case "base62":
+		encode = func(buf []byte, id int64) []byte {
+			return append(buf, generator.EncodeBase62(id)...)
+		}
+	case "readable":
+		encode = func(buf []byte, id int64) []byte {
+			return append(buf, idGen.ToReadable(id)...)
+		}
+	case "readable-v2":
+		encode = func(buf []byte, id int64) []byte {
+			return append(buf, idGen.ToReadableV2(id)...)
+		}
+	default:
+		return fmt.Errorf("不支持的输出格式：%s", *format)
+	}
+
+	var out io.Writer = stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		out = file
+	}
+
+	w := bufio.NewWriterSize(out, 1<<20)
+	buf := make([]byte, 0, 64)
+	for remaining := *n; remaining > 0; {
+		chunk := remaining
+		if chunk > exportChunk {
+			chunk = exportChunk
+		}
+		ids, err := idGen.GenerateN(int(chunk))
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			buf = append(encode(buf[:0], id), '\n')
+			if _, err := w.Write(buf); err != nil {
+				return err
+			}
+		}
+		remaining -= chunk
+	}
+	return w.Flush()
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	generator "github.com/jayecc/mtl-snowflake"
+)
+
+// TestExport 批量生成id
+func TestExport(t *testing.T) {
+	runCases(t, "export", []commandCase{
+		{name: "导出十进制id", args: []string{"-n", "3"}, want: []string{"\n"}},
+		{name: "导出v2可读格式", args: []string{"-n", "2", "-format", "readable-v2", "-machine-id", "5"}, want: []string{"-m005-t0-s0000\n", "-m005-t0-s0001\n"}},
+		{name: "不支持的输出格式", args: []string{"-format", "xml"}, wantErr: true},
+		{name: "数量为负数", args: []string{"-n", "-1"}, wantErr: true},
+		{name: "机器ID超限", args: []string{"-machine-id", "512"}, wantErr: true},
+	})
+}
+
+// TestExportFile 导出到文件，每行一个id且不重复
+func TestExportFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "export")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "ids.txt")
+
+	if out, err := execute("export", []string{"-n", "5000", "-o", path}, ""); err != nil || out != "" {
+		t.Fatalf("【失败】-%s-got:%q(%v)-want:%v", "导出到文件", out, err, "")
+	}
+	data, _ := ioutil.ReadFile(path)
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	seen := make(map[string]bool, len(lines))
+	for _, line := range lines {
+		if seen[line] {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", "出现重复的id", line, "不重复")
+		}
+		seen[line] = true
+	}
+	if len(lines) != 5000 {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "行数", len(lines), 5000)
+	}
+}
+
+// TestExportBase62 Base62格式分批生成，解码后递增
+func TestExportBase62(t *testing.T) {
+	out, err := execute("export", []string{"-n", "5000", "-format", "base62"}, "")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+	if len(lines) != 5000 {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "行数", len(lines), 5000)
+	}
+	var last int64
+	for _, line := range lines {
+		id, err := generator.DecodeBase62(line)
+		if err != nil || id <= last {
+			t.Fatalf("【失败】-%s-got:%v(%v)-want:>%v", line, id, err, last)
+		}
+		last = id
+	}
+}
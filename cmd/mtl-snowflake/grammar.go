@@ -0,0 +1,46 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	generator "github.com/jayecc/mtl-snowflake"
+)
+
+// runGrammar mtl-snowflake grammar [-encoding base62]
+func runGrammar(args []string) error {
+	fs := flag.NewFlagSet("grammar", flag.ContinueOnError)
+	getSettings := settingsFlags(fs)
+	encoding := fs.String("encoding", "", "只输出指定编码：decimal、base62、base58、readable、readable-v2，缺省全部输出")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	settings, err := getSettings()
+	if err != nil {
+		return err
+	}
+	idGen, err := generator.NewGeneratorWithSettings(0, settings)
+	if err != nil {
+		return err
+	}
+	encodings := []generator.IDEncoding{
+		generator.EncodingDecimal, generator.EncodingBase62, generator.EncodingBase58,
+		generator.EncodingReadable, generator.EncodingReadableV2,
+	}
+	if *encoding != "" {
+		e, err := generator.ParseIDEncoding(*encoding)
+		if err != nil {
+			return err
+		}
+		encodings = []generator.IDEncoding{e}
+	}
+	for _, e := range encodings {
+		g, err := idGen.Grammar(e)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(stdout, "%-12s length %d..%d  %s\n", e, g.MinLength, g.MaxLength, g.Pattern)
+	}
+	return nil
+}
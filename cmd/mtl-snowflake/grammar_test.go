@@ -0,0 +1,12 @@
+package main
+
+import "testing"
+
+// TestGrammar 各字符串编码的长度范围及正则表达式
+func TestGrammar(t *testing.T) {
+	runCases(t, "grammar", []commandCase{
+		{name: "指定编码的语法", args: []string{"-encoding", "base62"}, want: []string{"base62       length 1..11  ^(0|[1-9A-Za-z][0-9A-Za-z]{0,10})$\n"}},
+		{name: "全部编码的语法", want: []string{"decimal", "base62", "base58", "readable", "readable-v2"}},
+		{name: "不支持的编码", args: []string{"-encoding", "base64"}, wantErr: true},
+	})
+}
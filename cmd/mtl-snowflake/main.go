@@ -0,0 +1,93 @@
+// mtl-snowflake 命令行工具
+//
+//	mtl-snowflake <command> [flags]
+//
+// 各子命令均可通过 -time-bit/-datacenter-bit/-machine-bit/-timeline-bit/-seq-bit/-epoch/-time-unit/-timeline-placement/-flag-bit
+// 指定id结构，缺省为默认配置。
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// 子命令读写的标准输入输出，测试时替换
+var (
+	stdin  io.Reader = os.Stdin
+	stdout io.Writer = os.Stdout
+	stderr io.Writer = os.Stderr
+)
+
+// command 子命令
+type command struct {
+	usage string
+	run   func(args []string) error
+}
+
+var commands = map[string]command{
+	"bench":     {usage: "对比不同时间源、等待方式下的生成性能并给出建议", run: runBench},
+	"capacity":  {usage: "输出id结构的容量：可使用年限、实例数、每秒id数", run: runCapacity},
+	"codegen":   {usage: "生成Java/Python解码器类", run: runCodegen},
+	"decompose": {usage: "按id结构解析id的生成时间、机器ID、时间线、序号", run: runDecompose},
+	"export":    {usage: "批量生成id并输出到标准输出或文件", run: runExport},
+	"grammar":   {usage: "输出各字符串编码的长度范围及校验用正则表达式", run: runGrammar},
+	"migrate":   {usage: "生成serial/bigserial主键迁移到id的计划及回填SQL", run: runMigrate},
+	"range":     {usage: "输出时间窗口对应的id范围(含两端)", run: runRange},
+	"udf":       {usage: "生成解析id的SQL函数(MySQL/PostgreSQL/ClickHouse)", run: runUDF},
+	"verify":    {usage: "校验id文件：重复、结构错误、未来时间、逆序", run: runVerify},
+	"whois":     {usage: "根据机器登记表(JSON/CSV)查找id由哪个系统的哪台机器生成", run: runWhois},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		usage()
+		os.Exit(2)
+	}
+	if err := cmd.run(os.Args[2:]); err != nil {
+		if fe, ok := err.(flagError); ok {
+			//flag包已输出错误及用法
+			if fe.error == flag.ErrHelp {
+				os.Exit(0)
+			}
+			os.Exit(2)
+		}
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// flagError 参数解析错误
+type flagError struct {
+	error
+}
+
+// parseFlags 解析参数，出错时返回flagError
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	fs.SetOutput(stderr)
+	if err := fs.Parse(args); err != nil {
+		return flagError{err}
+	}
+	return nil
+}
+
+func usage() {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(os.Stderr, "usage: mtl-snowflake <command> [flags]")
+	fmt.Fprintln(os.Stderr, "commands:")
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-11s %s\n", name, commands[name].usage)
+	}
+}
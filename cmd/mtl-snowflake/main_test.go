@@ -0,0 +1,48 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+)
+
+// commandCase 子命令的测试用例
+type commandCase struct {
+	name    string
+	args    []string
+	input   string   //标准输入
+	want    []string //输出包含的内容
+	wantErr bool
+	flagErr bool //参数解析错误
+}
+
+// execute 执行子命令，返回标准输出
+func execute(name string, args []string, input string) (string, error) {
+	var out bytes.Buffer
+	stdin, stdout, stderr = strings.NewReader(input), &out, ioutil.Discard
+	defer func() { stdin, stdout, stderr = os.Stdin, os.Stdout, os.Stderr }()
+	err := commands[name].run(args)
+	return out.String(), err
+}
+
+// runCases 执行子命令的测试用例，校验输出及错误
+func runCases(t *testing.T, command string, testCases []commandCase) {
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := execute(command, tc.args, tc.input)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, err, tc.wantErr)
+			}
+			if _, ok := err.(flagError); ok != tc.flagErr {
+				t.Fatalf("【失败】-%s-参数解析错误-got:%v-want:%v", tc.name, err, tc.flagErr)
+			}
+			for _, want := range tc.want {
+				if !strings.Contains(got, want) {
+					t.Fatalf("【失败】-%s-got:%q-want:%q", tc.name, got, want)
+				}
+			}
+		})
+	}
+}
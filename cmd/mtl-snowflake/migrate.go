@@ -0,0 +1,54 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+
+	generator "github.com/jayecc/mtl-snowflake"
+)
+
+// runMigrate mtl-snowflake migrate -table orders -column id -min 1 -max 1000000
+func runMigrate(args []string) error {
+	fs := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	getSettings := settingsFlags(fs)
+	table := fs.String("table", "", "表名，可带schema")
+	column := fs.String("column", "id", "主键列名")
+	minSerial := fs.Int64("min", 1, "已有serial最小值")
+	maxSerial := fs.Int64("max", 0, "已有serial最大值")
+	cutover := fs.String("cutover", "", "切换时间(RFC3339)，缺省为当前时间")
+	reencode := fs.Bool("reencode", false, "使用重编码策略")
+	machineID := fs.Int64("machine-id", 0, "重编码时使用的预留机器ID")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	settings, err := getSettings()
+	if err != nil {
+		return err
+	}
+	req := generator.MigrationRequest{
+		Table:     *table,
+		Column:    *column,
+		MinSerial: *minSerial,
+		MaxSerial: *maxSerial,
+		Reencode:  *reencode,
+		MachineID: *machineID,
+	}
+	if *cutover != "" {
+		if req.Cutover, err = time.Parse(time.RFC3339, *cutover); err != nil {
+			return err
+		}
+	}
+	if req.MaxSerial == 0 {
+		return errors.New("须通过 -max 指定已有serial最大值")
+	}
+
+	plan, err := generator.PlanMigration(settings, req)
+	if err != nil {
+		return err
+	}
+	fmt.Fprint(stdout, plan.SQL)
+	return nil
+}
@@ -0,0 +1,16 @@
+package main
+
+import "testing"
+
+// TestMigrate 生成迁移计划
+func TestMigrate(t *testing.T) {
+	runCases(t, "migrate", []commandCase{
+		{name: "偏移策略", args: []string{"-table", "orders", "-max", "1000", "-cutover", "2024-01-01T00:00:00Z"},
+			want: []string{"-- serial range: [1, 1000] -> id range: [1, 1000]\n", "first generated id >= 529448671641600000\n", "BEGIN;"}},
+		{name: "未指定最大值", args: []string{"-table", "orders"}, wantErr: true},
+		{name: "切换时间格式错误", args: []string{"-table", "orders", "-max", "1000", "-cutover", "2024-01-01"}, wantErr: true},
+		{name: "无效的时间单位", args: []string{"-time-unit", "xyz"}, wantErr: true, flagErr: true},
+		{name: "无效的基准时间", args: []string{"-epoch", "2020-01-01", "-max", "1000"}, wantErr: true},
+		{name: "未知参数", args: []string{"-unknown"}, wantErr: true, flagErr: true},
+	})
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+
+	generator "github.com/jayecc/mtl-snowflake"
+)
+
+// runRange mtl-snowflake range --from 2024-01-01 --to 2024-02-01
+func runRange(args []string) error {
+	fs := flag.NewFlagSet("range", flag.ContinueOnError)
+	getSettings := settingsFlags(fs)
+	from := fs.String("from", "", "起始时间(含)，格式 2006-01-02 或 RFC3339")
+	to := fs.String("to", "", "结束时间(不含)，格式 2006-01-02 或 RFC3339")
+	column := fs.String("column", "", "指定列名时输出SQL条件，如 id BETWEEN min AND max")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	settings, err := getSettings()
+	if err != nil {
+		return err
+	}
+	fromTime, err := parseTime(*from)
+	if err != nil {
+		return err
+	}
+	toTime, err := parseTime(*to)
+	if err != nil {
+		return err
+	}
+	if !fromTime.Before(toTime) {
+		return errors.New("-from 必须早于 -to")
+	}
+
+	idGen, err := generator.NewGeneratorWithSettings(0, settings)
+	if err != nil {
+		return err
+	}
+	minID := idGen.CutoffIDAt(fromTime)
+	maxID := idGen.CutoffIDAt(toTime) - 1
+	if maxID < minID {
+		return errors.New("时间窗口内没有可用的id")
+	}
+
+	if *column != "" {
+		fmt.Fprintf(stdout, "%s BETWEEN %d AND %d\n", *column, minID, maxID)
+		return nil
+	}
+	fmt.Fprintln(stdout, minID, maxID)
+	return nil
+}
+
+// parseTime 解析日期(UTC)或RFC3339时间
+func parseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, errors.New("时间不能为空")
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
@@ -0,0 +1,17 @@
+package main
+
+import "testing"
+
+// TestRange 时间窗口对应的id范围
+func TestRange(t *testing.T) {
+	runCases(t, "range", []commandCase{
+		{name: "时间窗口的id范围", args: []string{"-from", "2024-01-01", "-to", "2024-01-02"},
+			want: []string{"529448671641600000 529811059507199999\n"}},
+		{name: "输出SQL条件", args: []string{"-from", "2024-01-01T00:00:00Z", "-to", "2024-01-02", "-column", "id"},
+			want: []string{"id BETWEEN 529448671641600000 AND 529811059507199999\n"}},
+		{name: "起始时间不早于结束时间", args: []string{"-from", "2024-01-02", "-to", "2024-01-01"}, wantErr: true},
+		{name: "未指定时间", args: []string{"-to", "2024-01-01"}, wantErr: true},
+		{name: "时间格式错误", args: []string{"-from", "2024/01/01", "-to", "2024-01-02"}, wantErr: true},
+		{name: "窗口早于基准时间", args: []string{"-from", "2010-01-01", "-to", "2010-01-02"}, wantErr: true},
+	})
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"flag"
+	"time"
+
+	generator "github.com/jayecc/mtl-snowflake"
+)
+
+// settingsFlags 注册id结构相关参数，返回的函数用于在解析参数后获取配置
+func settingsFlags(fs *flag.FlagSet) func() (generator.Settings, error) {
+	settings := *generator.DefaultSettings
+	fs.Uint64Var(&settings.TimeBit, "time-bit", settings.TimeBit, "时间位长度")
+	fs.Uint64Var(&settings.DatacenterBit, "datacenter-bit", settings.DatacenterBit, "数据中心位长度")
+	fs.Int64Var(&settings.DatacenterID, "datacenter", settings.DatacenterID, "数据中心ID")
+	fs.Uint64Var(&settings.MachineIDBit, "machine-bit", settings.MachineIDBit, "实例ID位长度")
+	fs.Uint64Var(&settings.TimelineBit, "timeline-bit", settings.TimelineBit, "时间线位长度")
+	fs.Uint64Var(&settings.SeqBit, "seq-bit", settings.SeqBit, "序号位长度")
+	fs.DurationVar(&settings.TimeUnit, "time-unit", settings.TimeUnit, "时间单位(如1us、1ms、10ms、1s)，缺省毫秒")
+	fs.Uint64Var(&settings.VersionBit, "version-bit", settings.VersionBit, "版本位长度")
+	fs.Int64Var(&settings.Version, "version", settings.Version, "id结构的版本号")
+	fs.BoolVar(&settings.FlagBit, "flag-bit", settings.FlagBit, "在最低位预留1位标记位")
+	placement := fs.String("timeline-placement", settings.Placement.String(), "时间线位置：below-machine、above-machine、above-time")
+	epoch := fs.String("epoch", time.Unix(0, settings.Epoch).UTC().Format(time.RFC3339), "基准时间(RFC3339)")
+
+	return func() (generator.Settings, error) {
+		t, err := time.Parse(time.RFC3339, *epoch)
+		if err != nil {
+			return settings, err
+		}
+		settings.Epoch = t.UnixNano()
+		if settings.Placement, err = generator.ParsePlacement(*placement); err != nil {
+			return settings, err
+		}
+		return settings, nil
+	}
+}
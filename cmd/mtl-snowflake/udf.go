@@ -0,0 +1,34 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	generator "github.com/jayecc/mtl-snowflake"
+)
+
+// runUDF mtl-snowflake udf -dialect postgres -prefix snowflake
+func runUDF(args []string) error {
+	fs := flag.NewFlagSet("udf", flag.ContinueOnError)
+	getSettings := settingsFlags(fs)
+	dialect := fs.String("dialect", "postgres", "SQL方言：mysql、postgres、clickhouse")
+	prefix := fs.String("prefix", "snowflake", "函数名前缀")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	settings, err := getSettings()
+	if err != nil {
+		return err
+	}
+	d, err := generator.ParseSQLDialect(*dialect)
+	if err != nil {
+		return err
+	}
+	sql, err := generator.GenerateSQLFunctions(settings, d, *prefix)
+	if err != nil {
+		return err
+	}
+	fmt.Fprint(stdout, sql)
+	return nil
+}
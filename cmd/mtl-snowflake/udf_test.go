@@ -0,0 +1,12 @@
+package main
+
+import "testing"
+
+// TestUDF 生成解析id的SQL函数
+func TestUDF(t *testing.T) {
+	runCases(t, "udf", []commandCase{
+		{name: "生成MySQL函数", args: []string{"-dialect", "mysql"}, want: []string{"CREATE FUNCTION snowflake_machine_id(id BIGINT)", "RETURN (id >> 13) & 511;"}},
+		{name: "指定函数名前缀", args: []string{"-prefix", "order_id"}, want: []string{"order_id_machine_id"}},
+		{name: "不支持的方言", args: []string{"-dialect", "oracle"}, wantErr: true},
+	})
+}
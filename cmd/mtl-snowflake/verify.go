@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	generator "github.com/jayecc/mtl-snowflake"
+)
+
+// runVerify mtl-snowflake verify ids.txt，发现问题时以非0状态码退出
+func runVerify(args []string) error {
+	fs := flag.NewFlagSet("verify", flag.ContinueOnError)
+	getSettings := settingsFlags(fs)
+	tolerance := fs.Duration("tolerance", time.Second, "允许的时钟误差，晚于当前时间+tolerance的id视为未来时间")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	settings, err := getSettings()
+	if err != nil {
+		return err
+	}
+	idGen, err := generator.NewGeneratorWithSettings(0, settings)
+	if err != nil {
+		return err
+	}
+
+	var in io.Reader = stdin
+	if fs.NArg() > 0 && fs.Arg(0) != "-" {
+		file, err := os.Open(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		in = file
+	}
+
+	verifier := idGen.NewVerifier(*tolerance)
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(line, 10, 64)
+		if err != nil {
+			verifier.AddViolation()
+			continue
+		}
+		verifier.Add(id)
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	report := verifier.Report()
+	fmt.Fprintln(stdout, report.String())
+	if !report.OK() {
+		return errors.New("校验未通过")
+	}
+	return nil
+}
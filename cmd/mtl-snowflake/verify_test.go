@@ -0,0 +1,35 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestVerify 校验id文件
+//   - 默认配置下id 4194304007为 时间1000ms、机器ID0、时间线0、序号7
+//   - 数据中心位1位、机器ID位8位时id 4196401159为 时间1000ms、数据中心ID1、机器ID0、时间线0、序号7
+func TestVerify(t *testing.T) {
+	dir, err := ioutil.TempDir("", "verify")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "ids.txt")
+	ioutil.WriteFile(path, []byte("4194304007\n4194304008\n"), 0644)
+
+	runCases(t, "verify", []commandCase{
+		{name: "校验通过", input: "4194304007\n4194304008\n",
+			want: []string{"total=2 machines=1 duplicates=0 layout_violations=0 future_timestamps=0 order_anomalies=0\n"}},
+		{name: "读取文件", args: []string{path}, want: []string{"total=2 machines=1 duplicates=0"}},
+		{name: "重复及格式错误", input: "4194304007\n4194304007\nabc\n",
+			want: []string{"duplicates=1", "layout_violations=1"}, wantErr: true},
+		{name: "逆序", input: "4194304008\n4194304007\n", want: []string{"order_anomalies=1"}, wantErr: true},
+		{name: "数据中心ID不符", args: []string{"-datacenter-bit", "1", "-machine-bit", "8"}, input: "4196401159\n",
+			want: []string{"layout_violations=1"}, wantErr: true},
+		{name: "指定数据中心ID", args: []string{"-datacenter-bit", "1", "-machine-bit", "8", "-datacenter", "1"}, input: "4196401159\n",
+			want: []string{"layout_violations=0"}},
+		{name: "文件不存在", args: []string{filepath.Join(dir, "missing.txt")}, wantErr: true},
+	})
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	generator "github.com/jayecc/mtl-snowflake"
+)
+
+// runWhois mtl-snowflake whois -registry machines.json [-csv legacy.csv -system legacy] id...
+func runWhois(args []string) error {
+	fs := flag.NewFlagSet("whois", flag.ContinueOnError)
+	getSettings := settingsFlags(fs)
+	registryPath := fs.String("registry", "", "JSON格式的登记表")
+	csvPath := fs.String("csv", "", "CSV格式的机器信息，使用 -system 及id结构参数登记为一个系统")
+	system := fs.String("system", "default", "CSV机器信息所属系统")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if *registryPath == "" && *csvPath == "" {
+		return errors.New("须指定 -registry 或 -csv")
+	}
+	registry := generator.NewMachineRegistry()
+	if *registryPath != "" {
+		file, err := os.Open(*registryPath)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		if err := registry.LoadJSON(file); err != nil {
+			return err
+		}
+	}
+	if *csvPath != "" {
+		settings, err := getSettings()
+		if err != nil {
+			return err
+		}
+		if err := registry.AddSystem(*system, settings); err != nil {
+			return err
+		}
+		file, err := os.Open(*csvPath)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		if err := registry.LoadCSV(*system, file); err != nil {
+			return err
+		}
+	}
+
+	for _, arg := range fs.Args() {
+		id, err := strconv.ParseInt(arg, 10, 64)
+		if err != nil {
+			return fmt.Errorf("id格式错误：%s", arg)
+		}
+		attributions := registry.WhoIs(id)
+		if len(attributions) == 0 {
+			fmt.Fprintf(stdout, "%d\tunknown\n", id)
+			continue
+		}
+		for _, a := range attributions {
+			fmt.Fprintf(stdout, "%d\tsystem=%s machine_id=%d host=%s owner=%s time=%s timeline=%d seq=%d\n",
+				id, a.System, a.Machine.MachineID, a.Machine.Host, a.Machine.Owner,
+				a.Time.UTC().Format(time.RFC3339Nano), a.Compose.TimeLine, a.Compose.Seq)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestWhois 根据机器登记表查找id的归属
+//   - 默认配置下id 4194328577为 时间1000ms、机器ID3、时间线0、序号1
+func TestWhois(t *testing.T) {
+	dir, err := ioutil.TempDir("", "whois")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer os.RemoveAll(dir)
+	machines := filepath.Join(dir, "machines.csv")
+	ioutil.WriteFile(machines, []byte("machine_id,host,owner\n3,app-03,order\n"), 0644)
+
+	runCases(t, "whois", []commandCase{
+		{name: "查找机器", args: []string{"-csv", machines, "-system", "legacy", "4194328577", "4194304007"},
+			want: []string{"4194328577\tsystem=legacy machine_id=3 host=app-03 owner=order time=2020-01-01T00:00:01Z timeline=0 seq=1\n", "4194304007\tunknown\n"}},
+		{name: "未指定登记表", args: []string{"4194328577"}, wantErr: true},
+		{name: "id格式错误", args: []string{"-csv", machines, "abc"}, wantErr: true},
+		{name: "登记表不存在", args: []string{"-registry", filepath.Join(dir, "missing.json"), "1"}, wantErr: true},
+	})
+}
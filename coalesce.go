@@ -0,0 +1,105 @@
+package generator
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// coalesceMaxRounds 合并者连续处理的最大批次数，超过后移交给等待者，避免单个调用方长时间阻塞
+const coalesceMaxRounds = 16
+
+// coalescer 并发请求合并：同一时刻只有一个调用方(合并者)持有生成器锁，
+// 合并者在一次加锁内依次为所有等待者生成连续序号的id，减少锁的交接次数
+type coalescer struct {
+	mutex     sync.Mutex
+	combining bool              //是否已有合并者
+	pending   []*coalesceWaiter //等待中的调用方
+	spare     []*coalesceWaiter //复用的等待队列
+}
+
+// coalesceWaiter 等待合并者分配id的调用方
+type coalesceWaiter struct {
+	id      int64
+	err     error
+	promote bool //是否接替成为合并者
+	done    chan struct{}
+}
+
+var coalesceWaiterPool = sync.Pool{
+	New: func() interface{} {
+		return &coalesceWaiter{done: make(chan struct{}, 1)}
+	},
+}
+
+// SetCoalescing 设置是否合并并发的Generate调用
+//   - 适用于大量goroutine在多核上并发生成id的场景，可减少生成器锁的交接次数
+//   - 等待者需挂起等待合并者分配id，收益取决于核数与并发度，启用前请通过BenchmarkGenParallel*基准测试确认
+func (idGen *IDGenerator) SetCoalescing(enabled bool) {
+	var value int32
+	if enabled {
+		value = 1
+	}
+	atomic.StoreInt32(&idGen.coalescing, value)
+}
+
+// generateCoalesced 合并模式下生成id
+func (idGen *IDGenerator) generateCoalesced() (int64, error) {
+	c := &idGen.coalescer
+	c.mutex.Lock()
+	if c.combining {
+		w := coalesceWaiterPool.Get().(*coalesceWaiter)
+		c.pending = append(c.pending, w)
+		c.mutex.Unlock()
+
+		<-w.done
+		id, err, promote := w.id, w.err, w.promote
+		w.promote = false
+		coalesceWaiterPool.Put(w)
+		if !promote {
+			return id, err
+		}
+	} else {
+		c.combining = true
+		c.mutex.Unlock()
+	}
+	return idGen.combine()
+}
+
+// combine 作为合并者生成自身及等待者的id
+func (idGen *IDGenerator) combine() (int64, error) {
+	c := &idGen.coalescer
+
+	idGen.mutex.Lock()
+	defer idGen.mutex.Unlock()
+
+	id, err := idGen.generateLocked()
+	for round := 0; ; round++ {
+		c.mutex.Lock()
+		batch := c.pending
+		if len(batch) == 0 {
+			c.combining = false
+			c.mutex.Unlock()
+			return id, err
+		}
+		if round == coalesceMaxRounds {
+			//移交给最早的等待者，由其继续合并
+			next := batch[0]
+			c.pending = batch[1:]
+			c.mutex.Unlock()
+			next.promote = true
+			next.done <- struct{}{}
+			return id, err
+		}
+		c.pending = c.spare[:0]
+		c.mutex.Unlock()
+
+		for _, w := range batch {
+			w.id, w.err = idGen.generateLocked()
+			w.done <- struct{}{}
+		}
+
+		c.mutex.Lock()
+		c.spare = batch[:0]
+		c.mutex.Unlock()
+	}
+}
@@ -0,0 +1,66 @@
+package generator
+
+import (
+	"sync"
+	"testing"
+)
+
+// TestCoalescing 合并并发请求时id全局唯一
+func TestCoalescing(t *testing.T) {
+	idGen, _ := NewGenerator(0)
+	idGen.SetCoalescing(true)
+
+	var ids sync.Map
+	var wg sync.WaitGroup
+	for g := 0; g < 64; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 5000; i++ {
+				id, err := idGen.Generate()
+				if err != nil {
+					t.Error(err.Error())
+					return
+				}
+				if _, exist := ids.LoadOrStore(id, nil); exist {
+					t.Errorf("出现重复的id:%d", id)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	// 关闭后恢复普通模式
+	idGen.SetCoalescing(false)
+	id, err := idGen.Generate()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if _, exist := ids.Load(id); exist {
+		t.Fatalf("【失败】-出现重复的id:%d", id)
+	}
+}
+
+// BenchmarkGenParallel 多goroutine并发性能测试
+func BenchmarkGenParallel(b *testing.B) {
+	idGen, _ := NewGeneratorWithSettings(0, Settings{TimeBit: 41, MachineIDBit: 0, TimelineBit: 1, SeqBit: 21, Epoch: DefaultEpoch})
+	b.SetParallelism(16)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			idGen.Generate()
+		}
+	})
+}
+
+// BenchmarkGenParallelCoalesced 多goroutine并发(合并请求)性能测试
+func BenchmarkGenParallelCoalesced(b *testing.B) {
+	idGen, _ := NewGeneratorWithSettings(0, Settings{TimeBit: 41, MachineIDBit: 0, TimelineBit: 1, SeqBit: 21, Epoch: DefaultEpoch})
+	idGen.SetCoalescing(true)
+	b.SetParallelism(16)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			idGen.Generate()
+		}
+	})
+}
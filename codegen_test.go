@@ -0,0 +1,64 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestGenerateDecoder 生成解码器代码
+func TestGenerateDecoder(t *testing.T) {
+	custom := Settings{TimeBit: 39, MachineIDBit: 8, TimelineBit: 2, SeqBit: 14, Epoch: DefaultEpoch}
+
+	testCases := []struct {
+		name     string
+		settings Settings
+		opts     DecoderOptions
+		contains []string
+		wantErr  bool
+	}{
+		{
+			name:     "Java默认配置",
+			settings: *DefaultSettings,
+			opts:     DecoderOptions{Language: LangJava, Package: "com.example.id"},
+			contains: []string{
+				"package com.example.id;",
+				"public final class SnowflakeDecoder {",
+				"return (id >>> 22) & 2199023255551L;",
+				"return (id >>> 13) & 511L;",
+				"return (id >>> 12) & 1L;",
+				"return id & 4095L;",
+				"EPOCH_NANOS = 1577836800000000000L;",
+				"TIME_UNIT_NANOS = 1000000L;",
+			},
+		},
+		{
+			name:     "Python自定义配置",
+			settings: custom,
+			opts:     DecoderOptions{Language: LangPython, Name: "OrderID"},
+			contains: []string{
+				"class OrderID:",
+				"return (id_ >> 24) & 549755813887",
+				"return (id_ >> 16) & 255",
+				"return (id_ >> 14) & 3",
+				"return id_ & 16383",
+				"EPOCH_NANOS = 1577836800000000000",
+			},
+		},
+		{name: "类名不合法", settings: *DefaultSettings, opts: DecoderOptions{Language: LangJava, Name: "a-b"}, wantErr: true},
+		{name: "包名不合法", settings: *DefaultSettings, opts: DecoderOptions{Language: LangJava, Package: "com..id"}, wantErr: true},
+		{name: "不支持的语言", settings: *DefaultSettings, opts: DecoderOptions{Language: DecoderLanguage(9)}, wantErr: true},
+		{name: "配置位数错误", settings: Settings{TimeBit: 41, MachineIDBit: 9, TimelineBit: 1, SeqBit: 10}, opts: DecoderOptions{Language: LangJava}, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		code, err := GenerateDecoder(tc.settings, tc.opts)
+		if (err != nil) != tc.wantErr {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, err, tc.wantErr)
+		}
+		for _, want := range tc.contains {
+			if !strings.Contains(code, want) {
+				t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, code, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package generator
+
+// Compare 按id中的时间、序号比较两个id的先后，a在前返回-1，a在后返回1，相同返回0
+//   - 时间、序号均相同的不同id(不同机器或时间线)再按时间线、机器ID排序，结果是确定的全序，但不代表生成的先后
+//   - 数值大小与Compare不一致的情况：时间线位于时间之上(TimelineAboveTime)时数值先按时间线排序；
+//     同一时间单位内数值先按机器ID(或时间线)排序，Compare先按序号排序
+//   - 发生时钟回退切换时间线后，新时间线上生成的id时间早于此前在原时间线上生成的id，Compare按id中的时间(生成时读到的时钟)排序，
+//     与实际生成的先后不同；需要与生成顺序一致时应开启严格递增模式(SetStrictMonotonic)并按数值比较
+func (idGen *IDGenerator) Compare(a, b int64) int {
+	x, y := idGen.Decompose(a), idGen.Decompose(b)
+	switch {
+	case x.Time != y.Time:
+		return compareInt64(x.Time, y.Time)
+	case x.Seq != y.Seq:
+		return compareInt64(x.Seq, y.Seq)
+	case x.TimeLine != y.TimeLine:
+		return compareInt64(x.TimeLine, y.TimeLine)
+	case x.MachineID != y.MachineID:
+		return compareInt64(x.MachineID, y.MachineID)
+	}
+	return compareInt64(a, b)
+}
+
+// compareInt64 比较两个整数
+func compareInt64(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,53 @@
+package generator
+
+import (
+	"sort"
+	"testing"
+)
+
+// TestCompare 按时间、序号比较id
+func TestCompare(t *testing.T) {
+	below, _ := NewGenerator(1)
+	settings := *DefaultSettings
+	settings.Placement = TimelineAboveTime
+	above, _ := NewGeneratorWithSettings(1, settings)
+
+	testCases := []struct {
+		name  string
+		idGen *IDGenerator
+		a     [4]int64 //时间、机器ID、时间线、序号
+		b     [4]int64
+		want  int
+	}{
+		{name: "时间", idGen: below, a: [4]int64{100, 1, 0, 5}, b: [4]int64{101, 1, 0, 0}, want: -1},
+		{name: "序号", idGen: below, a: [4]int64{100, 1, 0, 5}, b: [4]int64{100, 1, 0, 3}, want: 1},
+		{name: "相同", idGen: below, a: [4]int64{100, 1, 0, 5}, b: [4]int64{100, 1, 0, 5}, want: 0},
+		{name: "同一时间单位先按序号", idGen: below, a: [4]int64{100, 9, 0, 1}, b: [4]int64{100, 1, 1, 2}, want: -1},
+		{name: "时间线位于时间之上", idGen: above, a: [4]int64{100, 1, 1, 0}, b: [4]int64{101, 1, 0, 0}, want: -1},
+		{name: "时间、序号相同按时间线", idGen: above, a: [4]int64{100, 1, 1, 0}, b: [4]int64{100, 1, 0, 0}, want: 1},
+		{name: "时间、序号、时间线相同按机器ID", idGen: below, a: [4]int64{100, 2, 0, 0}, b: [4]int64{100, 3, 0, 0}, want: -1},
+	}
+	//按各部分组合id
+	build := func(idGen *IDGenerator, parts [4]int64) int64 {
+		presets := idGen.settings.presets
+		return parts[0]<<presets.shiftTimeBit | parts[1]<<presets.shiftMachineIDBit | parts[2]<<presets.shiftTimelineBit | parts[3]<<presets.shiftSeq
+	}
+	for _, tc := range testCases {
+		a, b := build(tc.idGen, tc.a), build(tc.idGen, tc.b)
+		if got := tc.idGen.Compare(a, b); got != tc.want {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, got, tc.want)
+		}
+		if got := tc.idGen.Compare(b, a); got != -tc.want {
+			t.Fatalf("【失败】-%s-交换后-got:%v-want:%v", tc.name, got, -tc.want)
+		}
+	}
+
+	//时间线位于时间之上时按数值排序与按时间排序不同
+	ids := []int64{above.compose(200, 1, 0), above.compose(100, 0, 0), above.compose(300, 0, 0)}
+	sort.Slice(ids, func(i, j int) bool { return above.Compare(ids[i], ids[j]) < 0 })
+	for i, want := range []int64{100, 200, 300} {
+		if got := above.Decompose(ids[i]).Time; got != want {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", "排序", got, want)
+		}
+	}
+}
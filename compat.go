@@ -0,0 +1,71 @@
+package generator
+
+import (
+	"time"
+)
+
+// 常见snowflake变体的解析器，用于分析外部系统生成的id
+var (
+	// TwitterSnowflake 时间(41位，毫秒，基准时间2010-11-04T01:42:54.657Z)|datacenter(5)|worker(5)|seq(12)
+	TwitterSnowflake = mustCompatDecomposer("twitter", time.Unix(0, 1288834974657*int64(time.Millisecond)), time.Millisecond,
+		LayoutField{FieldTime, 41}, LayoutField{"datacenter", 5}, LayoutField{"worker", 5}, LayoutField{FieldSeq, 12})
+	// DiscordSnowflake 时间(42位，毫秒，基准时间2015-01-01)|worker(5)|process(5)|seq(12)
+	DiscordSnowflake = mustCompatDecomposer("discord", time.Unix(0, 1420070400000*int64(time.Millisecond)), time.Millisecond,
+		LayoutField{FieldTime, 42}, LayoutField{"worker", 5}, LayoutField{"process", 5}, LayoutField{FieldSeq, 12})
+	// Sonyflake 时间(39位，10毫秒，基准时间2014-09-01)|seq(8)|machine(16)
+	Sonyflake = mustCompatDecomposer("sonyflake", time.Date(2014, 9, 1, 0, 0, 0, 0, time.UTC), 10*time.Millisecond,
+		LayoutField{FieldTime, 39}, LayoutField{FieldSeq, 8}, LayoutField{"machine", 16})
+	// InstagramID 时间(41位，毫秒，基准时间1314220021721)|shard(13)|seq(10)
+	InstagramID = mustCompatDecomposer("instagram", time.Unix(0, 1314220021721*int64(time.Millisecond)), time.Millisecond,
+		LayoutField{FieldTime, 41}, LayoutField{"shard", 13}, LayoutField{FieldSeq, 10})
+)
+
+// CompatDecomposer 按其他snowflake变体的id结构解析id，只解析不生成
+//   - 各字段位数之和可为63或64，为64时最高字段占用符号位，id按无符号数解析(如Discord的42位时间)
+type CompatDecomposer struct {
+	name   string
+	layout *Layout
+	epoch  int64 //基准时间(unix nano)
+	unit   int64 //时间单位(纳秒)
+}
+
+// NewCompatDecomposer 创建解析器，fields由高位到低位排列，须包含time、seq字段
+func NewCompatDecomposer(name string, epoch time.Time, unit time.Duration, fields ...LayoutField) (*CompatDecomposer, error) {
+	if unit <= 0 {
+		return nil, errorf(CodeNotPositive, "unit")
+	}
+	layout, err := newLayout(true, fields)
+	if err != nil {
+		return nil, err
+	}
+	return &CompatDecomposer{name: name, layout: layout, epoch: epoch.UnixNano(), unit: int64(unit)}, nil
+}
+
+// mustCompatDecomposer 创建预置的解析器
+func mustCompatDecomposer(name string, epoch time.Time, unit time.Duration, fields ...LayoutField) *CompatDecomposer {
+	d, err := NewCompatDecomposer(name, epoch, unit, fields...)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// Name 变体名称
+func (d *CompatDecomposer) Name() string {
+	return d.name
+}
+
+// Layout id结构
+func (d *CompatDecomposer) Layout() *Layout {
+	return d.layout
+}
+
+// Decompose 将id按字段名解析
+func (d *CompatDecomposer) Decompose(id int64) map[string]int64 {
+	return d.layout.Decompose(id)
+}
+
+// Time id的生成时间(精确到时间单位)
+func (d *CompatDecomposer) Time(id int64) time.Time {
+	return time.Unix(0, d.epoch+d.layout.Decompose(id)[FieldTime]*d.unit)
+}
@@ -0,0 +1,71 @@
+package generator
+
+import (
+	"testing"
+	"time"
+)
+
+// TestCompatDecomposer 解析常见snowflake变体的id
+func TestCompatDecomposer(t *testing.T) {
+	testCases := []struct {
+		name     string
+		d        *CompatDecomposer
+		id       int64
+		want     map[string]int64
+		wantTime time.Time
+	}{
+		{
+			name:     "discord文档示例",
+			d:        DiscordSnowflake,
+			id:       175928847299117063,
+			want:     map[string]int64{FieldTime: 41944705796, "worker": 1, "process": 0, FieldSeq: 7},
+			wantTime: time.Date(2016, 4, 30, 11, 18, 25, 796e6, time.UTC),
+		},
+		{
+			name:     "instagram博客示例",
+			d:        InstagramID,
+			id:       1387263000<<23 | 1341<<10 | 5001%1024,
+			want:     map[string]int64{FieldTime: 1387263000, "shard": 1341, FieldSeq: 905},
+			wantTime: time.Unix(0, (1314220021721+1387263000)*int64(time.Millisecond)),
+		},
+		{
+			name:     "twitter",
+			d:        TwitterSnowflake,
+			id:       1000<<22 | 3<<17 | 7<<12 | 42,
+			want:     map[string]int64{FieldTime: 1000, "datacenter": 3, "worker": 7, FieldSeq: 42},
+			wantTime: time.Date(2010, 11, 4, 1, 42, 55, 657e6, time.UTC),
+		},
+		{
+			name:     "sonyflake",
+			d:        Sonyflake,
+			id:       100<<24 | 5<<16 | 65535,
+			want:     map[string]int64{FieldTime: 100, FieldSeq: 5, "machine": 65535},
+			wantTime: time.Date(2014, 9, 1, 0, 0, 1, 0, time.UTC),
+		},
+		{
+			name:     "占用符号位",
+			d:        DiscordSnowflake,
+			id:       -1,
+			want:     map[string]int64{FieldTime: 1<<42 - 1, "worker": 31, "process": 31, FieldSeq: 4095},
+			wantTime: time.Unix(0, (1420070400000+1<<42-1)*int64(time.Millisecond)),
+		},
+	}
+	for _, tc := range testCases {
+		got := tc.d.Decompose(tc.id)
+		for name, want := range tc.want {
+			if got[name] != want {
+				t.Fatalf("【失败】-%s-%s-got:%v-want:%v", tc.name, name, got[name], want)
+			}
+		}
+		if at := tc.d.Time(tc.id); !at.Equal(tc.wantTime) {
+			t.Fatalf("【失败】-%s-时间-got:%v-want:%v", tc.name, at, tc.wantTime)
+		}
+	}
+
+	if _, err := NewCompatDecomposer("no-time", time.Unix(0, 0), time.Millisecond, LayoutField{"machine", 52}, LayoutField{FieldSeq, 12}); err == nil {
+		t.Fatalf("【失败】-%s-got:%v-want:%s", "缺少时间", err, "错误")
+	}
+	if _, err := NewLayout(LayoutField{FieldTime, 42}, LayoutField{"machine", 10}, LayoutField{FieldSeq, 12}); err == nil {
+		t.Fatalf("【失败】-%s-got:%v-want:%s", "NewLayout不允许64位", err, "错误")
+	}
+}
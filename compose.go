@@ -0,0 +1,65 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// String 各部分的key=value形式，如：time=2024-09-04T19:01:47.123Z machine_id=12 timeline=1 seq=42
+//   - 生成时间按UTC输出；数据中心ID不为0时在machine_id之前输出datacenter_id，版本号不为0时在最前输出version
+func (c *IDCompose) String() string {
+	version, datacenter := "", ""
+	if c.Version != 0 {
+		version = fmt.Sprintf("version=%d ", c.Version)
+	}
+	if c.DatacenterID != 0 {
+		datacenter = fmt.Sprintf("datacenter_id=%d ", c.DatacenterID)
+	}
+	return fmt.Sprintf("%stime=%s %smachine_id=%d timeline=%d seq=%d",
+		version, c.Timestamp.UTC().Format(time.RFC3339Nano), datacenter, c.MachineID, c.TimeLine, c.Seq)
+}
+
+// idComposeJSON IDCompose的JSON格式
+type idComposeJSON struct {
+	Version      int64     `json:"version,omitempty"`
+	Time         int64     `json:"time"`      //时间(自基准时间起的时间单位数)
+	Timestamp    time.Time `json:"timestamp"` //生成时间(RFC3339)
+	DatacenterID int64     `json:"datacenter_id,omitempty"`
+	MachineID    int64     `json:"machine_id"`
+	Timeline     int64     `json:"timeline"`
+	Seq          int64     `json:"seq"`
+}
+
+// MarshalJSON 序列化为JSON，版本号、数据中心ID为0时省略version、datacenter_id，如：
+//
+//	{"time":147865307123,"timestamp":"2024-09-04T19:01:47.123Z","machine_id":12,"timeline":1,"seq":42}
+func (c *IDCompose) MarshalJSON() ([]byte, error) {
+	return json.Marshal(idComposeJSON{
+		Version:      c.Version,
+		Time:         c.Time,
+		Timestamp:    c.Timestamp.UTC(),
+		DatacenterID: c.DatacenterID,
+		MachineID:    c.MachineID,
+		Timeline:     c.TimeLine,
+		Seq:          c.Seq,
+	})
+}
+
+// ToMap 各部分的map形式，key与JSON格式相同，用于结构化日志(如zap.Any、logrus.WithFields)
+func (c *IDCompose) ToMap() map[string]interface{} {
+	m := map[string]interface{}{
+		"time":       c.Time,
+		"timestamp":  c.Timestamp,
+		"machine_id": c.MachineID,
+		"timeline":   c.TimeLine,
+		"seq":        c.Seq,
+	}
+	if c.DatacenterID != 0 {
+		m["datacenter_id"] = c.DatacenterID
+	}
+	if c.Version != 0 {
+		m["version"] = c.Version
+	}
+	return m
+}
@@ -0,0 +1,43 @@
+package generator
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// TestIDComposeFormat IDCompose的字符串、JSON、map形式
+func TestIDComposeFormat(t *testing.T) {
+	idGen, _ := NewGenerator(12)
+	id := idGen.compose(123456789, 1, 42)
+	c := idGen.Decompose(id)
+	at := time.Unix(0, DefaultEpoch+123456789*int64(time.Millisecond))
+	if !c.Timestamp.Equal(at) {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "生成时间", c.Timestamp, at)
+	}
+
+	data, _ := json.Marshal(c)
+	withDatacenter := *c
+	withDatacenter.DatacenterID = 3
+	dcData, _ := json.Marshal(&withDatacenter)
+	testCases := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{name: "String", got: c.String(), want: "time=2020-01-02T10:17:36.789Z machine_id=12 timeline=1 seq=42"},
+		{name: "JSON", got: string(data), want: `{"time":123456789,"timestamp":"2020-01-02T10:17:36.789Z","machine_id":12,"timeline":1,"seq":42}`},
+		{name: "数据中心String", got: withDatacenter.String(), want: "time=2020-01-02T10:17:36.789Z datacenter_id=3 machine_id=12 timeline=1 seq=42"},
+		{name: "数据中心JSON", got: string(dcData), want: `{"time":123456789,"timestamp":"2020-01-02T10:17:36.789Z","datacenter_id":3,"machine_id":12,"timeline":1,"seq":42}`},
+	}
+	for _, tc := range testCases {
+		if tc.got != tc.want {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, tc.got, tc.want)
+		}
+	}
+
+	m := c.ToMap()
+	if m["machine_id"] != int64(12) || m["timeline"] != int64(1) || m["seq"] != int64(42) || m["time"] != int64(123456789) || !m["timestamp"].(time.Time).Equal(at) {
+		t.Fatalf("【失败】-%s-got:%v", "ToMap", m)
+	}
+}
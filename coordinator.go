@@ -0,0 +1,255 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrReservationExpired 批量任务的预留时间窗口已结束
+var ErrReservationExpired = newError(CodeReservationExpired)
+
+// LeaseKind 机器ID租约类型
+type LeaseKind int
+
+const (
+	LeaseOnline LeaseKind = iota //在线节点，需定期续约
+	LeaseBatch                   //批量任务，在预留时间窗口结束时自动过期，不能续约
+)
+
+// String 租约类型名称
+func (k LeaseKind) String() string {
+	switch k {
+	case LeaseOnline:
+		return "online"
+	case LeaseBatch:
+		return "batch"
+	}
+	return fmt.Sprintf("LeaseKind(%d)", int(k))
+}
+
+// Lease 机器ID租约
+type Lease struct {
+	MachineID int64
+	Holder    string //在线节点名称或批量任务名称
+	Kind      LeaseKind
+	Start     time.Time
+	Expires   time.Time
+}
+
+// AuditRecord 租约审计记录
+type AuditRecord struct {
+	Time   time.Time
+	Action string //acquire、renew、release、reserve、expire
+	Lease  Lease
+}
+
+// Coordinator 机器ID协调者：为在线节点和批量任务分配互不重叠的机器ID
+//   - 在线节点通过Acquire获取租约并定期Renew，租约过期后机器ID被回收
+//   - 批量任务通过Reserve申请专用的(机器ID, 时间窗口)，窗口内可在本地全速生成id，不与在线流量竞争
+//   - 过期或释放的机器ID等待一个时间单位后才会重新分配，避免与原持有者在同一时间单位内生成相同的id
+//   - 所有租约变化均记录审计，可通过SetAuditHandler实时输出
+type Coordinator struct {
+	mutex    sync.Mutex
+	settings Settings
+	leases   map[int64]*Lease
+	freed    map[int64]time.Time //机器ID -> 可重新分配的时间
+	audit    []AuditRecord
+	onAudit  func(AuditRecord)
+	now      func() time.Time
+}
+
+// NewCoordinator 创建机器ID协调者
+func NewCoordinator(settings Settings) (*Coordinator, error) {
+	if err := checkSettings(&settings, 0); err != nil {
+		return nil, err
+	}
+	settings.presets = calcPresets(&settings)
+	return &Coordinator{
+		settings: settings,
+		leases:   make(map[int64]*Lease),
+		freed:    make(map[int64]time.Time),
+		now:      time.Now,
+	}, nil
+}
+
+// SetAuditHandler 设置审计回调，在协调者锁内调用，实现须快速返回
+func (c *Coordinator) SetAuditHandler(handler func(AuditRecord)) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.onAudit = handler
+}
+
+// Audit 全部审计记录
+func (c *Coordinator) Audit() []AuditRecord {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return append([]AuditRecord(nil), c.audit...)
+}
+
+// Leases 当前有效的租约
+func (c *Coordinator) Leases() []Lease {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.expireLocked(c.now())
+
+	result := make([]Lease, 0, len(c.leases))
+	for machineID := int64(0); machineID <= c.settings.presets.maxMachineID; machineID++ {
+		if lease, exist := c.leases[machineID]; exist {
+			result = append(result, *lease)
+		}
+	}
+	return result
+}
+
+// Acquire 为在线节点分配机器ID，租约在ttl后过期
+func (c *Coordinator) Acquire(holder string, ttl time.Duration) (Lease, error) {
+	if ttl <= 0 {
+		return Lease{}, errorf(CodeNotPositive, "ttl")
+	}
+	return c.allocate(holder, LeaseOnline, ttl, "acquire")
+}
+
+// Renew 续约在线节点的租约
+func (c *Coordinator) Renew(machineID int64, holder string, ttl time.Duration) (Lease, error) {
+	if ttl <= 0 {
+		return Lease{}, errorf(CodeNotPositive, "ttl")
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := c.now()
+	c.expireLocked(now)
+	lease, exist := c.leases[machineID]
+	if !exist || lease.Holder != holder {
+		return Lease{}, errorf(CodeLeaseNotFound, machineID)
+	}
+	if lease.Kind != LeaseOnline {
+		return Lease{}, errorf(CodeReservationRenew)
+	}
+	lease.Expires = now.Add(ttl)
+	c.record(now, "renew", lease)
+	return *lease, nil
+}
+
+// Release 提前释放租约
+func (c *Coordinator) Release(machineID int64, holder string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := c.now()
+	c.expireLocked(now)
+	lease, exist := c.leases[machineID]
+	if !exist || lease.Holder != holder {
+		return errorf(CodeLeaseNotFound, machineID)
+	}
+	c.free(now, lease)
+	c.record(now, "release", lease)
+	return nil
+}
+
+// BatchReservation 批量任务的预留
+type BatchReservation struct {
+	Lease
+	gen *IDGenerator
+}
+
+// Reserve 为批量任务预留专用机器ID，时间窗口为[当前时间, 当前时间+window)
+func (c *Coordinator) Reserve(job string, window time.Duration) (*BatchReservation, error) {
+	if window <= 0 {
+		return nil, errorf(CodeNotPositive, "window")
+	}
+	lease, err := c.allocate(job, LeaseBatch, window, "reserve")
+	if err != nil {
+		return nil, err
+	}
+	gen, err := NewGeneratorWithSettings(lease.MachineID, c.settings)
+	if err != nil {
+		return nil, err
+	}
+	return &BatchReservation{Lease: lease, gen: gen}, nil
+}
+
+// Generate 在预留时间窗口内生成id
+func (r *BatchReservation) Generate() (ID, error) {
+	if !time.Now().Before(r.Expires) {
+		return 0, ErrReservationExpired
+	}
+	id, err := r.gen.generate()
+	if err == nil && !r.gen.Time(id).Before(r.Expires) {
+		return 0, ErrReservationExpired
+	}
+	return ID(id), err
+}
+
+// GenerateN 在预留时间窗口内批量生成id
+func (r *BatchReservation) GenerateN(n int) ([]int64, error) {
+	if !time.Now().Before(r.Expires) {
+		return nil, ErrReservationExpired
+	}
+	//生成期间等待序号可能越过窗口结束时间
+	ids, err := r.gen.GenerateN(n)
+	if err == nil && n > 0 && !r.gen.Time(ids[n-1]).Before(r.Expires) {
+		return nil, ErrReservationExpired
+	}
+	return ids, err
+}
+
+// allocate 分配空闲的机器ID
+func (c *Coordinator) allocate(holder string, kind LeaseKind, ttl time.Duration, action string) (Lease, error) {
+	if holder == "" {
+		return Lease{}, errorf(CodeOwnerEmpty)
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := c.now()
+	c.expireLocked(now)
+	for machineID := int64(0); machineID <= c.settings.presets.maxMachineID; machineID++ {
+		if _, used := c.leases[machineID]; used {
+			continue
+		}
+		if ready, exist := c.freed[machineID]; exist {
+			if now.Before(ready) {
+				continue
+			}
+			delete(c.freed, machineID)
+		}
+		lease := &Lease{MachineID: machineID, Holder: holder, Kind: kind, Start: now, Expires: now.Add(ttl)}
+		c.leases[machineID] = lease
+		c.record(now, action, lease)
+		return *lease, nil
+	}
+	return Lease{}, errorf(CodeNoFreeMachineID)
+}
+
+// expireLocked 回收过期的租约，调用方须持有锁
+func (c *Coordinator) expireLocked(now time.Time) {
+	var expired []*Lease
+	for _, lease := range c.leases {
+		if !now.Before(lease.Expires) {
+			expired = append(expired, lease)
+		}
+	}
+	sort.Slice(expired, func(i, j int) bool { return expired[i].MachineID < expired[j].MachineID })
+	for _, lease := range expired {
+		c.free(lease.Expires, lease)
+		c.record(now, "expire", lease)
+	}
+}
+
+// free 释放租约，机器ID在一个时间单位后可重新分配，调用方须持有锁
+func (c *Coordinator) free(at time.Time, lease *Lease) {
+	delete(c.leases, lease.MachineID)
+	c.freed[lease.MachineID] = at.Add(time.Duration(c.settings.unit()))
+}
+
+// record 记录审计，调用方须持有锁
+func (c *Coordinator) record(now time.Time, action string, lease *Lease) {
+	r := AuditRecord{Time: now, Action: action, Lease: *lease}
+	c.audit = append(c.audit, r)
+	if c.onAudit != nil {
+		c.onAudit(r)
+	}
+}
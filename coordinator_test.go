@@ -0,0 +1,95 @@
+package generator
+
+import (
+	"testing"
+	"time"
+)
+
+// TestCoordinator 机器ID分配、续约、释放及过期
+func TestCoordinator(t *testing.T) {
+	settings := Settings{TimeBit: 41, MachineIDBit: 2, TimelineBit: 1, SeqBit: 19, Epoch: DefaultEpoch}
+	c, err := NewCoordinator(settings)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	now := time.Now()
+	c.now = func() time.Time { return now }
+	var actions []string
+	c.SetAuditHandler(func(r AuditRecord) { actions = append(actions, r.Action) })
+
+	a, _ := c.Acquire("node-a", time.Minute)
+	b, _ := c.Acquire("node-b", time.Minute)
+	job, _ := c.Reserve("backfill", 10*time.Second)
+	if a.MachineID != 0 || b.MachineID != 1 || job.MachineID != 2 || job.Kind != LeaseBatch {
+		t.Fatalf("【失败】-%s-got:%v,%v,%v", "分配机器ID", a, b, job.Lease)
+	}
+	if _, err := c.Renew(job.MachineID, "backfill", time.Minute); err == nil {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "批量预留续约", err, "error")
+	}
+	if err := c.Release(b.MachineID, "node-a"); err == nil {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "释放他人租约", err, "error")
+	}
+
+	//释放后一个时间单位内不重新分配
+	c.Release(b.MachineID, "node-b")
+	if got, _ := c.Acquire("node-c", time.Minute); got.MachineID != 3 {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "释放后立即分配", got.MachineID, 3)
+	}
+	if _, err := c.Acquire("node-d", time.Minute); err == nil {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "没有空闲的机器ID", err, "error")
+	}
+	now = now.Add(time.Millisecond)
+	if got, _ := c.Acquire("node-d", time.Minute); got.MachineID != 1 {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "等待一个时间单位后分配", got.MachineID, 1)
+	}
+
+	//批量预留到期自动回收，在线节点续约后不过期
+	now = now.Add(30 * time.Second)
+	if _, err := c.Renew(a.MachineID, "node-a", time.Minute); err != nil {
+		t.Fatal(err.Error())
+	}
+	now = now.Add(40 * time.Second)
+	leases := c.Leases()
+	if len(leases) != 1 || leases[0].Holder != "node-a" {
+		t.Fatalf("【失败】-%s-got:%v", "过期回收", leases)
+	}
+
+	want := []string{"acquire", "acquire", "reserve", "release", "acquire", "acquire", "expire", "renew", "expire", "expire"}
+	if got := c.Audit(); len(got) != len(want) {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "审计记录", actions, want)
+	}
+	for i := range want {
+		if actions[i] != want[i] {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", "审计记录", actions, want)
+		}
+	}
+}
+
+// TestBatchReservation 批量任务在预留窗口内生成id
+func TestBatchReservation(t *testing.T) {
+	c, _ := NewCoordinator(*DefaultSettings)
+	c.Acquire("node-a", time.Minute)
+	r, err := c.Reserve("import", 50*time.Millisecond)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	ids, err := r.GenerateN(10000)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	for _, id := range ids {
+		compose := r.gen.Decompose(id)
+		if compose.MachineID != r.MachineID {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", "使用预留的机器ID", compose.MachineID, r.MachineID)
+		}
+		if got := r.gen.Time(id); got.Before(r.Start.Truncate(time.Millisecond)) || !got.Before(r.Expires) {
+			t.Fatalf("【失败】-%s-got:%v-want:[%v,%v)", "生成时间在窗口内", got, r.Start, r.Expires)
+		}
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if _, err := r.Generate(); err != ErrReservationExpired {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "窗口结束", err, ErrReservationExpired)
+	}
+}
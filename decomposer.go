@@ -0,0 +1,51 @@
+package generator
+
+import "time"
+
+// Decomposer 按id结构解析id，不需要创建生成器
+//   - 只校验id结构(各部分位数、时间单位、时间线位置)，不检查基准时间与当前时间，适用于日志处理、离线分析等只解析不生成的场景
+type Decomposer struct {
+	settings Settings
+	idGen    *IDGenerator //只用于复用解析逻辑，不生成id
+}
+
+// NewDecomposer 创建按settings解析id的Decomposer
+func NewDecomposer(settings Settings) (*Decomposer, error) {
+	if err := settings.checkLayout(); err != nil {
+		return nil, err
+	}
+	settings.presets = calcPresets(&settings)
+	return newDecomposer(settings), nil
+}
+
+// newDecomposer 按已计算presets的settings创建Decomposer
+func newDecomposer(settings Settings) *Decomposer {
+	d := &Decomposer{settings: settings}
+	d.idGen = &IDGenerator{settings: &d.settings}
+	return d
+}
+
+// DecomposeWith 按settings将id解析成time、seq等部分，id结构不合法时返回错误
+//   - 解析大量id时应创建Decomposer复用
+func DecomposeWith(settings Settings, id int64) (*IDCompose, error) {
+	d, err := NewDecomposer(settings)
+	if err != nil {
+		return nil, err
+	}
+	return d.Decompose(id), nil
+}
+
+// Decompose 将id解析成time、seq等部分
+func (d *Decomposer) Decompose(id int64) *IDCompose {
+	return d.idGen.Decompose(id)
+}
+
+// Time id的生成时间(精确到时间单位)
+func (d *Decomposer) Time(id int64) time.Time {
+	return d.idGen.Time(id)
+}
+
+// Settings 解析使用的id结构
+func (d *Decomposer) Settings() Settings {
+	return d.settings
+}
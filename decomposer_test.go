@@ -0,0 +1,49 @@
+package generator
+
+import (
+	"testing"
+	"time"
+)
+
+// TestDecomposeWith 不创建生成器解析id
+func TestDecomposeWith(t *testing.T) {
+	idGen, _ := NewGenerator(7)
+	id, _ := idGen.Generate()
+
+	future := *DefaultSettings
+	future.Epoch = time.Now().Add(time.Hour).UnixNano() //基准时间晚于当前时间，无法创建生成器
+
+	testCases := []struct {
+		name     string
+		settings Settings
+		wantErr  bool
+		want     *IDCompose
+	}{
+		{name: "默认配置", settings: *DefaultSettings, want: idGen.Decompose(int64(id))},
+		{name: "基准时间晚于当前时间", settings: future, want: idGen.Decompose(int64(id))},
+		{name: "位数错误", settings: Settings{TimeBit: 41, MachineIDBit: 9, TimelineBit: 1, SeqBit: 3}, wantErr: true},
+		{name: "时间单位错误", settings: Settings{TimeBit: 41, MachineIDBit: 9, TimelineBit: 1, SeqBit: 12, TimeUnit: 3 * time.Millisecond}, wantErr: true},
+	}
+	for _, tc := range testCases {
+		got, err := DecomposeWith(tc.settings, int64(id))
+		if (err != nil) != tc.wantErr {
+			t.Fatalf("【失败】-%s-err:%v", tc.name, err)
+		}
+		if err != nil {
+			continue
+		}
+		//基准时间不同时只有生成时间不同
+		got.Timestamp, tc.want.Timestamp = time.Time{}, time.Time{}
+		if *got != *tc.want {
+			t.Fatalf("【失败】-%s-got:%+v-want:%+v", tc.name, got, tc.want)
+		}
+	}
+
+	d, err := NewDecomposer(*DefaultSettings)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if got := d.Time(int64(id)); !got.Equal(idGen.Time(int64(id))) {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "时间", got, idGen.Time(int64(id)))
+	}
+}
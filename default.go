@@ -0,0 +1,66 @@
+package generator
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// 包级默认生成器的错误
+var (
+	ErrNotInitialized     = newError(CodeNotInitialized)     //未调用Init即使用包级Generate
+	ErrAlreadyInitialized = newError(CodeAlreadyInitialized) //重复调用Init
+)
+
+// 包级默认生成器，由Init创建
+var (
+	defaultOnce sync.Once
+	defaultGen  atomic.Value //*IDGenerator
+	defaultErr  error        //首次Init的错误，只在defaultOnce.Do之后读取
+)
+
+// Init 以默认配置创建包级默认生成器，简单的应用不必在各层之间传递*IDGenerator
+//   - 只生效一次：再次调用返回ErrAlreadyInitialized；首次调用失败时(如机器ID超出范围)再次调用返回相同的错误
+//   - 需要Hooks、Close等功能时通过Default获取生成器
+func Init(machineID int64) error {
+	return InitWithSettings(machineID, *DefaultSettings)
+}
+
+// InitWithSettings 以settings创建包级默认生成器，同Init
+func InitWithSettings(machineID int64, settings Settings) error {
+	first := false
+	defaultOnce.Do(func() {
+		first = true
+		var idGen *IDGenerator
+		if idGen, defaultErr = NewGeneratorWithSettings(machineID, settings); defaultErr == nil {
+			defaultGen.Store(idGen)
+		}
+	})
+	if first || defaultErr != nil {
+		return defaultErr
+	}
+	return ErrAlreadyInitialized
+}
+
+// Default 包级默认生成器，未初始化或初始化失败时返回nil
+func Default() *IDGenerator {
+	idGen, _ := defaultGen.Load().(*IDGenerator)
+	return idGen
+}
+
+// Generate 使用包级默认生成器生成id，未初始化或初始化失败时返回ErrNotInitialized
+func Generate() (ID, error) {
+	idGen := Default()
+	if idGen == nil {
+		return 0, ErrNotInitialized
+	}
+	return idGen.Generate()
+}
+
+// MustGenerate 同Generate，出错时panic
+func MustGenerate() ID {
+	id, err := Generate()
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
@@ -0,0 +1,56 @@
+package generator
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// resetDefault 清除包级默认生成器及首次Init的结果，供测试重复执行(go test -count)
+func resetDefault() {
+	defaultOnce = sync.Once{}
+	defaultGen = atomic.Value{}
+	defaultErr = nil
+}
+
+// TestDefault 包级默认生成器
+func TestDefault(t *testing.T) {
+	resetDefault()
+	defer resetDefault()
+	if _, err := Generate(); err != ErrNotInitialized {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "未初始化", err, ErrNotInitialized)
+	}
+	func() {
+		defer func() {
+			if r := recover(); r != ErrNotInitialized {
+				t.Fatalf("【失败】-%s-got:%v-want:%v", "未初始化MustGenerate", r, ErrNotInitialized)
+			}
+		}()
+		MustGenerate()
+	}()
+
+	testCases := []struct {
+		name      string
+		machineID int64
+		wantErr   error
+	}{
+		{name: "初始化", machineID: 3},
+		{name: "重复初始化", machineID: 4, wantErr: ErrAlreadyInitialized},
+	}
+	for _, tc := range testCases {
+		if err := Init(tc.machineID); err != tc.wantErr {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, err, tc.wantErr)
+		}
+	}
+
+	id, err := Generate()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if next := MustGenerate(); next <= id {
+		t.Fatalf("【失败】-%s-got:%v-want:>%v", "递增", next, id)
+	}
+	if got := Default().Decompose(int64(id)).MachineID; got != 3 {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "机器ID", got, 3)
+	}
+}
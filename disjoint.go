@@ -0,0 +1,57 @@
+package generator
+
+import (
+	"fmt"
+	"math"
+)
+
+// IDSpaceOverlapError 两个部署可能生成相同的id
+type IDSpaceOverlapError struct {
+	MachineA int64 //a部署中的机器ID
+	MachineB int64 //b部署中的机器ID
+	ID       int64 //两台机器都可能生成的一个id
+}
+
+func (e *IDSpaceOverlapError) Error() string {
+	return fmt.Sprintf("id空间重叠：a的机器%d与b的机器%d都可能生成id %d", e.MachineA, e.MachineB, e.ID)
+}
+
+// DisjointIDSpaces 判断按a、b配置部署的两个系统(各自使用machinesA、machinesB中的机器ID)是否不可能生成相同的id
+//   - 不重叠时返回nil；可能重叠时返回*IDSpaceOverlapError，给出一对机器及两者都可能生成的一个id
+//   - 每台机器可能生成的id为：符号位为0，版本、数据中心、机器ID部分固定，标记位(FlagBit)为0，时间、时间线、序号部分取遍各自的全部取值；
+//     两台机器的id空间不相交当且仅当两者都固定的某一位取值不同
+//   - 时间部分按全部取值比较：id中的时间是相对各自基准时间的偏移，基准时间不同并不能区分id，回填(GenerateAt)也可能生成任意时间的id
+//   - 配置或机器ID不合法时返回对应的错误
+func DisjointIDSpaces(a, b Settings, machinesA, machinesB []int64) error {
+	for _, machineID := range machinesA {
+		if err := checkSettings(&a, machineID); err != nil {
+			return err
+		}
+	}
+	for _, machineID := range machinesB {
+		if err := checkSettings(&b, machineID); err != nil {
+			return err
+		}
+	}
+
+	presetsA, presetsB := calcPresets(&a), calcPresets(&b)
+	for _, machineA := range machinesA {
+		maskA, valueA := idSpace(&a, presetsA, machineA)
+		for _, machineB := range machinesB {
+			maskB, valueB := idSpace(&b, presetsB, machineB)
+			if (valueA^valueB)&maskA&maskB == 0 {
+				return &IDSpaceOverlapError{MachineA: machineA, MachineB: machineB, ID: valueA | valueB}
+			}
+		}
+	}
+	return nil
+}
+
+// idSpace 机器可能生成的id中取值固定的位(mask)及其取值(value)，其余位可取任意值
+func idSpace(settings *Settings, p *presets, machineID int64) (mask, value int64) {
+	mask = math.MinInt64 | p.maskVersion | p.maskDatacenter | p.maskMachineID
+	if settings.FlagBit {
+		mask |= 1
+	}
+	return mask, p.version | p.datacenter | machineID<<p.shiftMachineIDBit
+}
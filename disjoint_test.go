@@ -0,0 +1,59 @@
+package generator
+
+import (
+	"testing"
+	"time"
+)
+
+// TestDisjointIDSpaces 两个部署的id空间是否不重叠
+func TestDisjointIDSpaces(t *testing.T) {
+	base := Settings{TimeBit: 41, MachineIDBit: 10, TimelineBit: 1, SeqBit: 11, Epoch: DefaultEpoch}
+	otherEpoch := base
+	otherEpoch.Epoch = time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC).UnixNano()
+	above := base
+	above.Placement = TimelineAboveMachine
+	flagged := Settings{TimeBit: 41, MachineIDBit: 10, TimelineBit: 1, SeqBit: 10, Epoch: DefaultEpoch, FlagBit: true}
+	narrow := Settings{TimeBit: 41, MachineIDBit: 9, TimelineBit: 1, SeqBit: 12, Epoch: DefaultEpoch}
+
+	testCases := []struct {
+		name      string
+		a, b      Settings
+		machinesA []int64
+		machinesB []int64
+		disjoint  bool
+	}{
+		{name: "相同配置不同机器", a: base, b: base, machinesA: []int64{0, 1, 2}, machinesB: []int64{3, 4}, disjoint: true},
+		{name: "相同配置相同机器", a: base, b: base, machinesA: []int64{0, 1}, machinesB: []int64{1}, disjoint: false},
+		{name: "基准时间不同不能区分", a: base, b: otherEpoch, machinesA: []int64{5}, machinesB: []int64{5}, disjoint: false},
+		{name: "时间线位置不同", a: base, b: above, machinesA: []int64{1}, machinesB: []int64{2}, disjoint: false},
+		{name: "机器ID位数不同", a: base, b: narrow, machinesA: []int64{512}, machinesB: []int64{0, 255, 511}, disjoint: true},
+		{name: "机器ID位数不同且重叠", a: base, b: narrow, machinesA: []int64{512, 2}, machinesB: []int64{0, 1}, disjoint: false},
+		{name: "标记位", a: flagged, b: flagged, machinesA: []int64{1}, machinesB: []int64{2}, disjoint: true},
+		{name: "标记位与无标记位", a: base, b: flagged, machinesA: []int64{1}, machinesB: []int64{1}, disjoint: false},
+		{name: "空机器列表", a: base, b: base, machinesA: nil, machinesB: []int64{1}, disjoint: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := DisjointIDSpaces(tc.a, tc.b, tc.machinesA, tc.machinesB)
+			if got := err == nil; got != tc.disjoint {
+				t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, err, tc.disjoint)
+			}
+			overlap, ok := err.(*IDSpaceOverlapError)
+			if err != nil && !ok {
+				t.Fatalf("【失败】-%s-got:%T-want:%v", tc.name, err, "*IDSpaceOverlapError")
+			}
+			if ok {
+				//重叠的id按两个配置解析分别属于对应的机器
+				genA, _ := NewGeneratorWithSettings(overlap.MachineA, tc.a)
+				genB, _ := NewGeneratorWithSettings(overlap.MachineB, tc.b)
+				if genA.Decompose(overlap.ID).MachineID != overlap.MachineA || genB.Decompose(overlap.ID).MachineID != overlap.MachineB {
+					t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, overlap, "两台机器都可能生成")
+				}
+			}
+		})
+	}
+
+	if err := DisjointIDSpaces(base, base, []int64{1024}, []int64{1}); err == nil {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "机器ID超出范围", err, "error")
+	}
+}
@@ -0,0 +1,122 @@
+// Package entsnow entgo集成：id字段类型、DefaultFunc及填充id的Hook、Mixin
+//
+// ent的DefaultFunc只能返回id，无法返回错误，因此：
+//   - Hook/Mixin在创建时填充id，生成失败时由Save返回错误(可用errors.Is判断ErrTimeOverflow等)，推荐使用
+//   - DefaultFunc生成失败时panic，只适用于配置了等待策略、不会因时钟回退返回错误的生成器
+//
+// 并发：生成器可被多个goroutine共享，Hook、DefaultFunc都不额外加锁；CreateBulk中每条记录分别生成id。
+// 生成器为nil时使用包级默认生成器(generator.Init)，便于在schema包中声明而无需持有生成器，
+// 未初始化时Hook返回generator.ErrNotInitialized，DefaultFunc panic。
+//
+// 如：
+//
+//	func (Order) Mixin() []ent.Mixin {
+//		return []ent.Mixin{entsnow.Mixin{}}
+//	}
+package entsnow
+
+import (
+	"context"
+	"fmt"
+
+	"entgo.io/ent"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/mixin"
+
+	generator "github.com/jayecc/mtl-snowflake"
+)
+
+// Field id字段：类型为generator.ID(BIGINT)，创建后不可修改
+//   - 生成的代码中id的类型为generator.ID，JSON序列化为字符串，避免JavaScript丢失精度
+func Field() ent.Field {
+	return field.Int64("id").GoType(generator.ID(0)).Immutable()
+}
+
+// DefaultFunc 用于field.Int64("id").DefaultFunc(entsnow.DefaultFunc(idGen))，生成失败时panic
+func DefaultFunc(idGen generator.Generator) func() int64 {
+	return func() int64 {
+		id, err := generate(idGen)
+		if err != nil {
+			panic(fmt.Sprintf("entsnow: %v", err))
+		}
+		return int64(id)
+	}
+}
+
+// DefaultID 同DefaultFunc，用于类型为generator.ID的id字段
+func DefaultID(idGen generator.Generator) func() generator.ID {
+	f := DefaultFunc(idGen)
+	return func() generator.ID {
+		return generator.ID(f())
+	}
+}
+
+// Hook 创建记录时为未指定id的记录填充id，生成失败时返回错误、不执行创建
+//   - 适用于id为int64或generator.ID的schema；id由数据库生成(未在schema中声明id字段)的schema不受影响
+func Hook(idGen generator.Generator) ent.Hook {
+	return func(next ent.Mutator) ent.Mutator {
+		return ent.MutateFunc(func(ctx context.Context, m ent.Mutation) (ent.Value, error) {
+			if m.Op().Is(ent.OpCreate) {
+				if err := setID(m, idGen); err != nil {
+					return nil, err
+				}
+			}
+			return next.Mutate(ctx, m)
+		})
+	}
+}
+
+// setID id未指定时生成并设置
+func setID(m ent.Mutation, idGen generator.Generator) error {
+	switch mutation := m.(type) {
+	case interface {
+		ID() (int64, bool)
+		SetID(int64)
+	}:
+		if _, exists := mutation.ID(); exists {
+			return nil
+		}
+		id, err := generate(idGen)
+		if err != nil {
+			return err
+		}
+		mutation.SetID(int64(id))
+	case interface {
+		ID() (generator.ID, bool)
+		SetID(generator.ID)
+	}:
+		if _, exists := mutation.ID(); exists {
+			return nil
+		}
+		id, err := generate(idGen)
+		if err != nil {
+			return err
+		}
+		mutation.SetID(id)
+	}
+	return nil
+}
+
+// generate 使用idGen生成id，idGen为nil时使用包级默认生成器
+func generate(idGen generator.Generator) (generator.ID, error) {
+	if idGen == nil {
+		return generator.Generate()
+	}
+	return idGen.Generate()
+}
+
+// Mixin 声明id字段(Field)并在创建时填充id(Hook)
+type Mixin struct {
+	mixin.Schema
+	Generator generator.Generator //nil表示包级默认生成器
+}
+
+// Fields id字段
+func (m Mixin) Fields() []ent.Field {
+	return []ent.Field{Field()}
+}
+
+// Hooks 填充id的Hook
+func (m Mixin) Hooks() []ent.Hook {
+	return []ent.Hook{Hook(m.Generator)}
+}
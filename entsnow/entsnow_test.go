@@ -0,0 +1,126 @@
+package entsnow
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"entgo.io/ent"
+
+	generator "github.com/jayecc/mtl-snowflake"
+)
+
+// int64Mutation 生成代码中id为int64的mutation
+type int64Mutation struct {
+	ent.Mutation
+	op ent.Op
+	id *int64
+}
+
+func (m *int64Mutation) Op() ent.Op { return m.op }
+
+func (m *int64Mutation) ID() (int64, bool) {
+	if m.id == nil {
+		return 0, false
+	}
+	return *m.id, true
+}
+
+func (m *int64Mutation) SetID(id int64) { m.id = &id }
+
+// idMutation 生成代码中id为generator.ID的mutation
+type idMutation struct {
+	ent.Mutation
+	id *generator.ID
+}
+
+func (m *idMutation) Op() ent.Op { return ent.OpCreate }
+
+func (m *idMutation) ID() (generator.ID, bool) {
+	if m.id == nil {
+		return 0, false
+	}
+	return *m.id, true
+}
+
+func (m *idMutation) SetID(id generator.ID) { m.id = &id }
+
+type failingGenerator struct{}
+
+func (failingGenerator) Generate() (generator.ID, error) { return 0, generator.ErrTimeOverflow }
+
+// mutate 通过Hook执行mutation，返回是否执行了后续的mutator
+func mutate(hook ent.Hook, m ent.Mutation) (bool, error) {
+	called := false
+	next := ent.MutateFunc(func(ctx context.Context, m ent.Mutation) (ent.Value, error) {
+		called = true
+		return nil, nil
+	})
+	_, err := hook(next).Mutate(context.Background(), m)
+	return called, err
+}
+
+// TestHook 创建时填充id，生成失败时返回错误
+func TestHook(t *testing.T) {
+	idGen, _ := generator.NewGenerator(5)
+	hook := Hook(idGen)
+
+	m := &int64Mutation{op: ent.OpCreate}
+	if called, err := mutate(hook, m); !called || err != nil || m.id == nil || idGen.Decompose(*m.id).MachineID != 5 {
+		t.Fatalf("【失败】-%s-got:%v,%v,%v", "int64 id", called, err, m.id)
+	}
+
+	preset := int64(42)
+	m = &int64Mutation{op: ent.OpCreate, id: &preset}
+	if mutate(hook, m); *m.id != 42 {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "已指定的id保持不变", *m.id, 42)
+	}
+
+	m = &int64Mutation{op: ent.OpUpdateOne}
+	if mutate(hook, m); m.id != nil {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "更新时不填充", *m.id, nil)
+	}
+
+	typed := &idMutation{}
+	if _, err := mutate(hook, typed); err != nil || typed.id == nil || *typed.id <= 0 {
+		t.Fatalf("【失败】-%s-got:%v,%v", "generator.ID id", err, typed.id)
+	}
+
+	m = &int64Mutation{op: ent.OpCreate}
+	called, err := mutate(Hook(failingGenerator{}), m)
+	if called || !errors.Is(err, generator.ErrTimeOverflow) {
+		t.Fatalf("【失败】-%s-got:%v,%v-want:%v", "生成失败", called, err, generator.ErrTimeOverflow)
+	}
+}
+
+// TestDefaultFunc 生成id，生成失败时panic
+func TestDefaultFunc(t *testing.T) {
+	idGen, _ := generator.NewGenerator(5)
+	if id := DefaultFunc(idGen)(); id <= 0 {
+		t.Fatalf("【失败】-%s-got:%v", "DefaultFunc", id)
+	}
+	if id := DefaultID(idGen)(); id <= 0 {
+		t.Fatalf("【失败】-%s-got:%v", "DefaultID", id)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", "生成失败", nil, "panic")
+		}
+	}()
+	DefaultFunc(failingGenerator{})()
+}
+
+// TestMixin id字段及Hook
+func TestMixin(t *testing.T) {
+	m := Mixin{}
+	if fields := m.Fields(); len(fields) != 1 || fields[0].Descriptor().Name != "id" {
+		t.Fatalf("【失败】-%s-got:%v", "字段", fields)
+	}
+	if len(m.Hooks()) != 1 {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "Hook", len(m.Hooks()), 1)
+	}
+	//未初始化包级默认生成器
+	if _, err := mutate(m.Hooks()[0], &int64Mutation{op: ent.OpCreate}); !errors.Is(err, generator.ErrNotInitialized) {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "默认生成器", err, generator.ErrNotInitialized)
+	}
+}
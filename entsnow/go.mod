@@ -0,0 +1,10 @@
+module github.com/jayecc/mtl-snowflake/entsnow
+
+go 1.25.0
+
+require (
+	entgo.io/ent v0.14.5
+	github.com/jayecc/mtl-snowflake v0.0.0
+)
+
+replace github.com/jayecc/mtl-snowflake => ../
@@ -0,0 +1,142 @@
+package generator
+
+import (
+	"time"
+)
+
+// EpochRotation 基准时间轮换
+//   - 以时间部分的最高位作为基准时间选择位：旧基准时间生成的id该位为0，新基准时间生成的id该位为1
+//   - 新旧基准时间的id结构相同，但都只能使用时间部分的低TimeBit-1位，因此须在旧基准时间用到时间部分最高位之前完成轮换
+//   - 选择位使新基准时间的id总是大于旧基准时间的id，重叠窗口结束后id仍保持趋势递增
+type EpochRotation struct {
+	Old     Settings      //轮换前配置
+	New     Settings      //轮换后配置，与Old仅Epoch不同
+	Start   time.Time     //开始轮换的时间，此后新上线的节点使用新基准时间
+	Overlap time.Duration //重叠窗口，窗口内新旧基准时间的id可能同时生成
+
+	oldPresets, newPresets *presets
+	selector               int64 //选择位
+}
+
+// NewEpochRotation 创建基准时间轮换
+func NewEpochRotation(oldSettings, newSettings Settings, start time.Time, overlap time.Duration) (*EpochRotation, error) {
+	old, cur := oldSettings, newSettings
+	if old.TimeBit != cur.TimeBit || old.DatacenterBit != cur.DatacenterBit || old.MachineIDBit != cur.MachineIDBit ||
+		old.TimelineBit != cur.TimelineBit || old.SeqBit != cur.SeqBit || old.unit() != cur.unit() ||
+		old.Placement != cur.Placement || old.FlagBit != cur.FlagBit || old.VersionBit != cur.VersionBit || old.Version != cur.Version {
+		return nil, errorf(CodeRotationLayout)
+	}
+	if !old.timeOrdered() {
+		return nil, errNotTimeOrdered
+	}
+	if err := old.checkBits(); err != nil {
+		return nil, err
+	}
+	if old.TimeBit < 2 {
+		return nil, errorf(CodeRotationTimeBit)
+	}
+	if cur.Epoch <= old.Epoch || cur.Epoch > start.UnixNano() {
+		return nil, errorf(CodeRotationEpoch)
+	}
+	if overlap < 0 {
+		return nil, errorf(CodeNegative, "overlap")
+	}
+
+	r := &EpochRotation{Old: old, New: cur, Start: start, Overlap: overlap}
+	r.oldPresets = rotationPresets(&r.Old)
+	r.newPresets = rotationPresets(&r.New)
+	r.selector = int64(1) << (r.oldPresets.shiftTimeBit + old.TimeBit - 1)
+
+	end := start.Add(overlap).UnixNano()
+	if (end-old.Epoch)/old.unit() > r.oldPresets.maxTime {
+		return nil, errorf(CodeRotationLate)
+	}
+	return r, nil
+}
+
+// rotationPresets 时间部分只使用低TimeBit-1位的预置参数
+func rotationPresets(settings *Settings) *presets {
+	p := calcPresets(settings)
+	p.maxTime >>= 1
+	p.maskTime = p.maxTime << p.shiftTimeBit
+	return p
+}
+
+// OldGenerator 创建使用旧基准时间的生成器，时间部分用到最高位之前返回错误，避免与新基准时间的id冲突
+func (r *EpochRotation) OldGenerator(machineID int64) (*IDGenerator, error) {
+	idGen, err := NewGeneratorWithSettings(machineID, r.Old)
+	if err != nil {
+		return nil, err
+	}
+	idGen.settings.presets = r.oldPresets
+	return idGen, nil
+}
+
+// NewGenerator 创建使用新基准时间的生成器
+func (r *EpochRotation) NewGenerator(machineID int64) (*IDGenerator, error) {
+	idGen, err := NewGeneratorWithSettings(machineID, r.New)
+	if err != nil {
+		return nil, err
+	}
+	idGen.settings.presets = r.newPresets
+	idGen.prefix = r.selector
+	return idGen, nil
+}
+
+// IsNewEpoch id是否由新基准时间生成
+func (r *EpochRotation) IsNewEpoch(id int64) bool {
+	return id&r.selector != 0
+}
+
+// Decompose 按选择位使用对应的基准时间解析id
+func (r *EpochRotation) Decompose(id int64) *IDCompose {
+	p, epoch := r.oldPresets, r.Old.Epoch
+	if r.IsNewEpoch(id) {
+		p, epoch = r.newPresets, r.New.Epoch
+	}
+	offset := (id & p.maskTime) >> p.shiftTimeBit
+	return &IDCompose{
+		Version:      (id & p.maskVersion) >> p.shiftVersionBit,
+		Time:         offset,
+		DatacenterID: (id & p.maskDatacenter) >> p.shiftDatacenterBit,
+		MachineID:    (id & p.maskMachineID) >> p.shiftMachineIDBit,
+		TimeLine:     (id & p.maskTimeline) >> p.shiftTimelineBit,
+		Seq:          (id & p.maskSeq) >> p.shiftSeq,
+		Timestamp:    time.Unix(0, epoch+offset*r.Old.unit()),
+	}
+}
+
+// Time id的生成时间
+func (r *EpochRotation) Time(id int64) time.Time {
+	return r.Decompose(id).Timestamp
+}
+
+// Sortable 两个id的数值大小关系是否与生成时间先后一致
+//   - 只有重叠窗口内新旧基准时间各自生成的id之间可能不一致：旧基准时间生成的id总是更小
+func (r *EpochRotation) Sortable(a, b int64) bool {
+	if r.IsNewEpoch(a) == r.IsNewEpoch(b) {
+		return true
+	}
+	if a > b {
+		a, b = b, a
+	}
+	//a为旧基准时间的id，b为新基准时间的id
+	return !r.Time(a).After(r.Time(b))
+}
+
+// VerifySortability 校验一组按数值排序的id，返回生成时间与数值顺序不一致且不在重叠窗口内的id
+func (r *EpochRotation) VerifySortability(sortedIDs []int64) []int64 {
+	windowEnd := r.Start.Add(r.Overlap)
+	var violations []int64
+	var latest time.Time
+	for _, id := range sortedIDs {
+		t := r.Time(id)
+		if t.Before(latest) && (t.Before(r.Start) || !t.Before(windowEnd)) {
+			violations = append(violations, id)
+		}
+		if t.After(latest) {
+			latest = t
+		}
+	}
+	return violations
+}
@@ -0,0 +1,79 @@
+package generator
+
+import (
+	"sort"
+	"testing"
+	"time"
+)
+
+// TestEpochRotation 基准时间轮换
+func TestEpochRotation(t *testing.T) {
+	now := time.Now()
+	oldSettings := *DefaultSettings
+	newSettings := *DefaultSettings
+	newSettings.Epoch = now.Add(-time.Hour).UnixNano()
+
+	rotation, err := NewEpochRotation(oldSettings, newSettings, now.Add(-time.Minute), time.Hour)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	oldGen, err := rotation.OldGenerator(1)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	newGen, err := rotation.NewGenerator(2)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	oldID, _ := oldGen.Generate()
+	newID, _ := newGen.Generate()
+	if rotation.IsNewEpoch(int64(oldID)) || !rotation.IsNewEpoch(int64(newID)) {
+		t.Fatalf("【失败】-选择位错误")
+	}
+	if newID <= oldID {
+		t.Fatalf("【失败】-新基准时间的id应大于旧基准时间的id")
+	}
+
+	testCases := []struct {
+		name      string
+		id        int64
+		machineID int64
+	}{
+		{name: "解析旧基准时间的id", id: int64(oldID), machineID: 1},
+		{name: "解析新基准时间的id", id: int64(newID), machineID: 2},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := rotation.Decompose(tc.id).MachineID; got != tc.machineID {
+				t.Fatalf("【失败】-%s-machineID-got:%d-want:%d", tc.name, got, tc.machineID)
+			}
+			if d := time.Since(rotation.Time(tc.id)); d < 0 || d > time.Second {
+				t.Fatalf("【失败】-%s-生成时间偏差过大:%v", tc.name, d)
+			}
+		})
+	}
+
+	// 重叠窗口内旧基准时间后生成的id小于新基准时间先生成的id
+	time.Sleep(2 * time.Millisecond)
+	lateOldID, _ := oldGen.Generate()
+	if rotation.Sortable(int64(lateOldID), int64(newID)) {
+		t.Fatalf("【失败】-重叠窗口内的id顺序应不一致")
+	}
+	ids := []int64{int64(newID), int64(oldID), int64(lateOldID)}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	if violations := rotation.VerifySortability(ids); len(violations) != 0 {
+		t.Fatalf("【失败】-重叠窗口内的不一致不应视为违规-%v", violations)
+	}
+	noOverlap, _ := NewEpochRotation(oldSettings, newSettings, now.Add(-time.Minute), 0)
+	if violations := noOverlap.VerifySortability(ids); len(violations) != 1 {
+		t.Fatalf("【失败】-重叠窗口外的不一致应视为违规-%v", violations)
+	}
+
+	// 旧基准时间已用到时间部分最高位
+	ancient := *DefaultSettings
+	ancient.Epoch = now.AddDate(-40, 0, 0).UnixNano()
+	if _, err := NewEpochRotation(ancient, newSettings, now, time.Hour); err == nil {
+		t.Fatalf("【失败】-旧基准时间已用到时间部分最高位应返回错误")
+	}
+}
@@ -0,0 +1,95 @@
+package generator
+
+import "time"
+
+// 生成器返回的错误
+//   - 带有结构化字段的错误为*ClockBackwardError、*BreakerOpenError、*MachineIDError、*RateLimitError，
+//     通过Unwrap返回对应的哨兵错误，可使用errors.Is/errors.As判断；不使用errors包时可通过RetryAfter读取重试间隔
+//   - ErrTimeOverflow、ErrBeforeEpoch、ErrNotMonotonic、ErrFenced、ErrWaitTimeout等保持直接返回，可用==比较
+//   - 错误信息默认为英文，可通过SetTranslator切换语言(如ChineseMessages)；ErrorCodeOf返回与语言无关的错误码
+var (
+	ErrClockMovedBack     = newError(CodeClockMovedBack)     //时钟回退导致生成失败，*ClockBackwardError匹配该错误
+	ErrTimelinesExhausted = newError(CodeTimelinesExhausted) //时钟回退后没有可切换的时间线
+	ErrBreakerOpen        = newError(CodeBreakerOpen)        //时钟回退频率告警触发熔断
+	ErrInvalidMachineID   = newError(CodeInvalidMachineID)   //机器ID超出MachineIDBit所能表示的范围
+	ErrBackwardRejected   = newError(CodeBackwardRejected)   //时钟回退策略(BackwardPolicy)选择返回错误
+	ErrRateLimited        = newError(CodeRateLimited)        //超过SetRateLimit设置的速率，*RateLimitError匹配该错误
+)
+
+// ClockBackwardError 时钟回退导致生成失败
+type ClockBackwardError struct {
+	Backward   time.Duration //时钟落后于当前时间线进度的时长
+	RetryAfter time.Duration //预计时钟追回、出现可用时间线所需的时长
+	Err        error         //具体原因，如ErrTimelinesExhausted
+}
+
+func (e *ClockBackwardError) Error() string {
+	return message(CodeClockBackwardDetail, e.Err, e.Backward, e.RetryAfter)
+}
+
+// Unwrap 具体原因
+func (e *ClockBackwardError) Unwrap() error { return e.Err }
+
+// Is 匹配ErrClockMovedBack
+func (e *ClockBackwardError) Is(target error) bool { return target == ErrClockMovedBack }
+
+// BreakerOpenError 生成器处于熔断状态
+type BreakerOpenError struct {
+	RetryAfter time.Duration //距熔断结束的时长，0表示须调用ResetBreaker手动恢复
+}
+
+func (e *BreakerOpenError) Error() string {
+	if e.RetryAfter == 0 {
+		return message(CodeBreakerManualDetail, ErrBreakerOpen)
+	}
+	return message(CodeBreakerRetryDetail, ErrBreakerOpen, e.RetryAfter)
+}
+
+// Unwrap ErrBreakerOpen
+func (e *BreakerOpenError) Unwrap() error { return ErrBreakerOpen }
+
+// MachineIDError 机器ID超出范围
+type MachineIDError struct {
+	MachineID int64 //指定的机器ID
+	Max       int64 //允许的最大机器ID(2^MachineIDBit-1)
+}
+
+func (e *MachineIDError) Error() string {
+	return message(CodeMachineIDDetail, e.Max, e.MachineID)
+}
+
+// Unwrap ErrInvalidMachineID
+func (e *MachineIDError) Unwrap() error { return ErrInvalidMachineID }
+
+// RateLimitError 超过生成速率上限
+type RateLimitError struct {
+	RetryAfter time.Duration //令牌补充到足够数量所需的时长
+}
+
+func (e *RateLimitError) Error() string {
+	return message(CodeRateLimitDetail, ErrRateLimited, e.RetryAfter)
+}
+
+// Unwrap ErrRateLimited
+func (e *RateLimitError) Unwrap() error { return ErrRateLimited }
+
+// RetryAfter 错误带有的建议重试间隔，不包含该信息时返回false
+//   - 沿Unwrap链查找*ClockBackwardError、*RateLimitError、*BreakerOpenError(熔断须手动恢复时返回false)
+func RetryAfter(err error) (time.Duration, bool) {
+	for err != nil {
+		switch e := err.(type) {
+		case *ClockBackwardError:
+			return e.RetryAfter, true
+		case *RateLimitError:
+			return e.RetryAfter, true
+		case *BreakerOpenError:
+			return e.RetryAfter, e.RetryAfter > 0
+		}
+		wrapper, ok := err.(interface{ Unwrap() error })
+		if !ok {
+			return 0, false
+		}
+		err = wrapper.Unwrap()
+	}
+	return 0, false
+}
@@ -0,0 +1,108 @@
+package generator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jayecc/mtl-snowflake/fakeclock"
+)
+
+// TestClockBackwardError 时钟回退后没有可切换的时间线
+func TestClockBackwardError(t *testing.T) {
+	clock := fakeclock.New(time.Now().Truncate(time.Millisecond))
+	settings := *DefaultSettings
+	settings.Clock = clock
+	idGen, _ := NewGeneratorWithSettings(0, settings)
+
+	idGen.Generate()
+	clock.Advance(-10 * time.Millisecond) //切换到时间线1
+	if _, err := idGen.Generate(); err != nil {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "切换时间线", err, nil)
+	}
+	clock.Advance(-10 * time.Millisecond) //时间线0已使用，时间线1进度领先
+	_, err := idGen.Generate()
+	e, ok := err.(*ClockBackwardError)
+	if !ok {
+		t.Fatalf("【失败】-%s-got:%T-want:%v", "错误类型", err, "*ClockBackwardError")
+	}
+	testCases := []struct {
+		name string
+		got  interface{}
+		want interface{}
+	}{
+		{name: "回退时长", got: e.Backward, want: 10 * time.Millisecond},
+		{name: "重试间隔", got: e.RetryAfter, want: 10 * time.Millisecond},
+		{name: "具体原因", got: e.Unwrap(), want: ErrTimelinesExhausted},
+		{name: "匹配ErrClockMovedBack", got: e.Is(ErrClockMovedBack), want: true},
+	}
+	for _, tc := range testCases {
+		if tc.got != tc.want {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, tc.got, tc.want)
+		}
+	}
+	if retry, ok := RetryAfter(err); !ok || retry != e.RetryAfter {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "RetryAfter", retry, e.RetryAfter)
+	}
+
+	//等待RetryAfter后恢复
+	clock.Advance(e.RetryAfter)
+	if _, err := idGen.Generate(); err != nil {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "恢复", err, nil)
+	}
+}
+
+// TestBreakerOpenError 熔断
+func TestBreakerOpenError(t *testing.T) {
+	testCases := []struct {
+		name      string
+		cooldown  time.Duration
+		wantRetry bool
+	}{
+		{name: "自动恢复", cooldown: time.Minute, wantRetry: true},
+		{name: "手动恢复", cooldown: 0, wantRetry: false},
+	}
+	for _, tc := range testCases {
+		clock := fakeclock.New(time.Now())
+		settings := *DefaultSettings
+		settings.Clock = clock
+		idGen, _ := NewGeneratorWithSettings(0, settings)
+		idGen.SetBackwardAlert(&BackwardAlertPolicy{Threshold: 1, Window: time.Minute, TripBreaker: true, Cooldown: tc.cooldown})
+		idGen.Generate()
+		clock.Advance(-10 * time.Millisecond)
+		idGen.Generate()
+
+		_, err := idGen.Generate()
+		e, ok := err.(*BreakerOpenError)
+		if !ok || e.Unwrap() != ErrBreakerOpen {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, err, ErrBreakerOpen)
+		}
+		retry, ok := RetryAfter(err)
+		if ok != tc.wantRetry || retry > tc.cooldown || tc.wantRetry && retry <= 0 {
+			t.Fatalf("【失败】-%s-got:%v,%v-want:%v", tc.name, retry, ok, tc.wantRetry)
+		}
+	}
+}
+
+// TestMachineIDError 机器ID超出范围
+func TestMachineIDError(t *testing.T) {
+	idGen, _ := NewGenerator(1)
+	_, createErr := NewGenerator(-1)
+	_, rotateErr := idGen.RotateMachineID(512)
+	testCases := []struct {
+		name string
+		err  error
+		want MachineIDError
+	}{
+		{name: "创建", err: createErr, want: MachineIDError{MachineID: -1, Max: 511}},
+		{name: "切换", err: rotateErr, want: MachineIDError{MachineID: 512, Max: 511}},
+	}
+	for _, tc := range testCases {
+		e, ok := tc.err.(*MachineIDError)
+		if !ok || *e != tc.want || e.Unwrap() != ErrInvalidMachineID {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, tc.err, tc.want)
+		}
+		if _, ok := RetryAfter(tc.err); ok {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, ok, false)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package generator
+
+import "math"
+
+// IssuanceEstimate id发放数量估计
+type IssuanceEstimate struct {
+	Count      float64           //估计的发放数量
+	LowerBound int64             //确定的下界：各(机器ID,时间线,时间单位)内观测到的最大序号+1之和
+	SampleRate float64           //估计的抽样比例
+	Samples    int               //窗口内的样本数(去重后)
+	Units      int               //样本覆盖的(机器ID,时间线,时间单位)数
+	PerMachine map[int64]float64 //各机器估计的发放数量
+}
+
+// EstimateCount 根据抽样的id估计[minID, maxID]窗口内发放的id数量，可配合CutoffIDAt按时间窗口计算minID、maxID
+//   - 同一机器同一时间线的每个时间单位内，序号从0开始连续递增，最大序号+1即该时间单位的发放数下界
+//   - 时间单位内按German tank方法估计：N≈m(1+1/k)-1，m为最大序号+1，k为该时间单位内的样本数
+//   - 样本应从窗口内的id中均匀随机抽取；未被抽中的时间单位按Horvitz-Thompson方法补偿，抽样比例越低误差越大
+//   - 样本包含窗口内全部id时，结果是精确值
+//   - 开启序号填充(SetPadding)时结果偏大，持有密钥可通过PaddingIndex获得准确值
+func (idGen *IDGenerator) EstimateCount(minID, maxID int64, sample []int64) *IssuanceEstimate {
+	type unitKey struct{ machineID, timeline, time int64 }
+	type unitStat struct{ maxSeq, samples int64 }
+
+	est := &IssuanceEstimate{PerMachine: make(map[int64]float64)}
+	seen := make(map[int64]struct{}, len(sample))
+	units := make(map[unitKey]*unitStat)
+	for _, id := range sample {
+		if id < minID || id > maxID || id < 0 {
+			continue
+		}
+		if _, exist := seen[id]; exist {
+			continue
+		}
+		seen[id] = struct{}{}
+
+		compose := idGen.Decompose(id)
+		key := unitKey{compose.MachineID, compose.TimeLine, compose.Time}
+		stat, exist := units[key]
+		if !exist {
+			stat = &unitStat{maxSeq: -1}
+			units[key] = stat
+		}
+		stat.samples++
+		if compose.Seq > stat.maxSeq {
+			stat.maxSeq = compose.Seq
+		}
+	}
+	est.Samples = len(seen)
+	est.Units = len(units)
+	if est.Samples == 0 {
+		return est
+	}
+
+	//各时间单位的发放数估计
+	unitCounts := make(map[unitKey]float64, len(units))
+	var total float64
+	for key, stat := range units {
+		m := stat.maxSeq + 1
+		n := float64(m)*(1+1/float64(stat.samples)) - 1
+		if n < float64(m) {
+			n = float64(m)
+		}
+		unitCounts[key] = n
+		total += n
+		est.LowerBound += m
+	}
+
+	//抽样比例，及每个时间单位至少被抽中一个样本的概率
+	est.SampleRate = math.Min(1, float64(est.Samples)/total)
+	for key, n := range unitCounts {
+		weighted := n
+		if est.SampleRate < 1 {
+			weighted = n / (1 - math.Pow(1-est.SampleRate, n))
+		}
+		est.Count += weighted
+		est.PerMachine[key.machineID] += weighted
+	}
+	return est
+}
@@ -0,0 +1,74 @@
+package generator
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// TestEstimateCount 发放数量估计
+func TestEstimateCount(t *testing.T) {
+	var ids []int64
+	perMachine := map[int64]int{0: 30000, 1: 10000, 2: 5000}
+	for machineID, n := range perMachine {
+		idGen, _ := NewGenerator(machineID)
+		for i := 0; i < n; i++ {
+			id, _ := idGen.Generate()
+			ids = append(ids, int64(id))
+		}
+	}
+	idGen, _ := NewGenerator(0)
+	total := float64(len(ids))
+
+	sample := func(rate float64) []int64 {
+		r := rand.New(rand.NewSource(1))
+		var s []int64
+		for _, id := range ids {
+			if r.Float64() < rate {
+				s = append(s, id)
+			}
+		}
+		return s
+	}
+
+	testCases := []struct {
+		name      string
+		sample    []int64
+		tolerance float64 //允许的相对误差
+	}{
+		{name: "全量样本", sample: ids, tolerance: 0},
+		{name: "抽样10%", sample: sample(0.1), tolerance: 0.1},
+		{name: "抽样1%", sample: sample(0.01), tolerance: 0.25},
+	}
+
+	for _, tc := range testCases {
+		est := idGen.EstimateCount(0, math.MaxInt64, tc.sample)
+		if math.Abs(est.Count-total)/total > tc.tolerance+1e-9 {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, est.Count, total)
+		}
+		if est.LowerBound > int64(total) {
+			t.Fatalf("【失败】-%s-下界-got:%v-want:<=%v", tc.name, est.LowerBound, total)
+		}
+		for machineID, n := range perMachine {
+			if got := est.PerMachine[machineID]; math.Abs(got-float64(n))/float64(n) > tc.tolerance*2+1e-9 {
+				t.Fatalf("【失败】-%s-机器%d-got:%v-want:%v", tc.name, machineID, got, n)
+			}
+		}
+	}
+
+	//窗口外的id不参与估计
+	sorted := append([]int64(nil), ids...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	minID := idGen.CutoffIDAt(idGen.Time(sorted[len(sorted)/3]))
+	maxID := idGen.CutoffIDAt(idGen.Time(sorted[len(sorted)*2/3])) - 1
+	want := 0
+	for _, id := range ids {
+		if id >= minID && id <= maxID {
+			want++
+		}
+	}
+	if est := idGen.EstimateCount(minID, maxID, ids); est.Count != float64(want) {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "窗口过滤", est.Count, want)
+	}
+}
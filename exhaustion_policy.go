@@ -0,0 +1,72 @@
+package generator
+
+import (
+	"fmt"
+	"time"
+)
+
+// SeqExhaustionPolicy 当前时间单位的序号用完时的处理方式
+type SeqExhaustionPolicy int
+
+const (
+	ExhaustionWait   SeqExhaustionPolicy = iota //等待下一个时间单位(默认)
+	ExhaustionBorrow                            //借用下一个时间单位继续生成，不等待；时间线进度超前时钟不超过maxDrift，超出时等待
+)
+
+// String 处理方式名称
+func (p SeqExhaustionPolicy) String() string {
+	switch p {
+	case ExhaustionWait:
+		return "wait"
+	case ExhaustionBorrow:
+		return "borrow"
+	}
+	return fmt.Sprintf("SeqExhaustionPolicy(%d)", int(p))
+}
+
+// SetSeqExhaustionPolicy 设置序号用完时的处理方式
+//   - ExhaustionWait：等待下一个时间单位，maxDrift须为0
+//   - ExhaustionBorrow：直接推进时间线进度，使用下一个时间单位的序号，突发请求不会阻塞；代价是id的时间可能超前实际时间，最多maxDrift。
+//     时钟追上借用的时间单位前，后续生成继续使用借用的时间单位，不视为时钟回退；maxDrift须不小于一个时间单位
+//   - 借用的次数通过MetricSeqBorrowed上报；序号空间划分(SetSeqReservation)的调用方仍等待下一个时间单位
+func (idGen *IDGenerator) SetSeqExhaustionPolicy(policy SeqExhaustionPolicy, maxDrift time.Duration) error {
+	unit := time.Duration(idGen.settings.unit())
+	switch policy {
+	case ExhaustionWait:
+		if maxDrift != 0 {
+			return errorf(CodeExhaustionWaitDrift)
+		}
+	case ExhaustionBorrow:
+		if maxDrift < unit {
+			return errorf(CodeMaxDriftUnit, unit)
+		}
+	default:
+		return errorf(CodeUnknownExhaustionPolicy)
+	}
+
+	idGen.mutex.Lock()
+	defer idGen.mutex.Unlock()
+	idGen.maxDrift = int64(maxDrift / unit)
+	return nil
+}
+
+// borrowing 时间线进度由借用而来且时钟尚未追上(超前不超过maxDrift)，调用方须持有锁
+func (idGen *IDGenerator) borrowing(curTime, progress int64) bool {
+	return idGen.maxDrift > 0 && curTime >= 0 && curTime < progress &&
+		progress == idGen.borrowedTime && progress-curTime <= idGen.maxDrift
+}
+
+// nextTime 当前时间单位的序号已用完，按处理方式借用下一个时间单位或等待，返回使用的时间，调用方须持有锁
+func (idGen *IDGenerator) nextTime(curTime int64) (int64, error) {
+	if idGen.maxDrift > 0 && curTime < idGen.settings.presets.maxTime {
+		if wall := idGen.toOffsetTime(idGen.now().UnixNano()); curTime+1-wall <= idGen.maxDrift {
+			idGen.borrowedTime = curTime + 1
+			idGen.borrowedCount++
+			if idGen.metrics != nil {
+				idGen.metrics.Counter(MetricSeqBorrowed, 1)
+			}
+			return curTime + 1, nil
+		}
+	}
+	return idGen.waitNextTime(curTime)
+}
@@ -0,0 +1,77 @@
+package generator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jayecc/mtl-snowflake/fakeclock"
+)
+
+// TestSeqExhaustionBorrow 序号用完时借用下一个时间单位
+func TestSeqExhaustionBorrow(t *testing.T) {
+	clock := fakeclock.New(time.Now().Truncate(time.Millisecond))
+	settings := *DefaultSettings
+	settings.Clock = clock
+	idGen, _ := NewGeneratorWithSettings(0, settings)
+	metrics := countingMetrics{}
+	idGen.SetMetrics(metrics)
+	idGen.SetBurstBudget(3)
+	if err := idGen.SetSeqExhaustionPolicy(ExhaustionBorrow, 2*time.Millisecond); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	//时钟不动：借用两个时间单位后超前达到上限，等待下一个时间单位
+	start := clock.Time()
+	wantTimes := []int64{0, 0, 0, 1, 1, 1, 2, 2, 2, 3}
+	var first int64
+	for i, want := range wantTimes {
+		id, err := idGen.Generate()
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if i == 0 {
+			first = idGen.Decompose(int64(id)).Time
+		}
+		if got := idGen.Decompose(int64(id)).Time - first; got != want {
+			t.Fatalf("【失败】-第%d个-时间-got:%v-want:%v", i, got, want)
+		}
+	}
+	if got := metrics[MetricSeqBorrowed]; got != 2 {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "借用次数", got, 2)
+	}
+	if got := idGen.Stats().SeqBorrowed; got != 2 {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "Stats借用次数", got, 2)
+	}
+	if metrics[MetricClockBackward] != 0 || metrics[MetricTimelineSwitch] != 0 {
+		t.Fatalf("【失败】-%s-回退:%v-切换:%v", "借用不应视为时钟回退", metrics[MetricClockBackward], metrics[MetricTimelineSwitch])
+	}
+	if elapsed := clock.Time().Sub(start); elapsed != 3*time.Millisecond {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "等待时长", elapsed, 3*time.Millisecond)
+	}
+
+	//借用后时钟回退超过maxDrift，按时钟回退处理
+	clock.Advance(-10 * time.Millisecond)
+	if _, err := idGen.Generate(); err != nil {
+		t.Fatal(err.Error())
+	}
+	if metrics[MetricTimelineSwitch] != 1 {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "超过maxDrift切换时间线", metrics[MetricTimelineSwitch], 1)
+	}
+
+	testCases := []struct {
+		name     string
+		policy   SeqExhaustionPolicy
+		maxDrift time.Duration
+		wantErr  bool
+	}{
+		{name: "maxDrift小于时间单位", policy: ExhaustionBorrow, maxDrift: time.Microsecond, wantErr: true},
+		{name: "等待不能指定maxDrift", policy: ExhaustionWait, maxDrift: time.Millisecond, wantErr: true},
+		{name: "不支持的处理方式", policy: SeqExhaustionPolicy(9), maxDrift: 0, wantErr: true},
+		{name: "恢复等待", policy: ExhaustionWait, maxDrift: 0, wantErr: false},
+	}
+	for _, tc := range testCases {
+		if err := idGen.SetSeqExhaustionPolicy(tc.policy, tc.maxDrift); (err != nil) != tc.wantErr {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, err, tc.wantErr)
+		}
+	}
+}
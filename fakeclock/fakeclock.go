@@ -0,0 +1,46 @@
+// Package fakeclock 供测试使用的手动控制时间源
+//   - 实现generator.Clock及generator.Sleeper：生成器等待时推进时间而不是实际休眠
+//   - 通过Set、Advance可确定地模拟时钟回退、跳变，不必修改生成器的基准时间
+package fakeclock
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Clock 手动控制的时间源，可并发使用
+type Clock struct {
+	now int64 //unix nano
+}
+
+// New 创建时间为t的时钟
+func New(t time.Time) *Clock {
+	return &Clock{now: t.UnixNano()}
+}
+
+// Now 当前时间(unix nano)
+func (c *Clock) Now() int64 {
+	return atomic.LoadInt64(&c.now)
+}
+
+// Time 当前时间
+func (c *Clock) Time() time.Time {
+	return time.Unix(0, c.Now())
+}
+
+// Set 将时钟设置为t，早于当前时间即模拟时钟回退
+func (c *Clock) Set(t time.Time) {
+	atomic.StoreInt64(&c.now, t.UnixNano())
+}
+
+// Advance 时钟前进d，d为负数即模拟时钟回退
+func (c *Clock) Advance(d time.Duration) {
+	atomic.AddInt64(&c.now, int64(d))
+}
+
+// Sleep 时钟前进d，立即返回
+func (c *Clock) Sleep(d time.Duration) {
+	if d > 0 {
+		c.Advance(d)
+	}
+}
@@ -0,0 +1,33 @@
+package fakeclock
+
+import (
+	"testing"
+	"time"
+)
+
+// TestClock 手动控制时间
+func TestClock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name string
+		op   func(c *Clock)
+		want time.Time
+	}{
+		{name: "初始时间", op: func(c *Clock) {}, want: start},
+		{name: "前进", op: func(c *Clock) { c.Advance(time.Second) }, want: start.Add(time.Second)},
+		{name: "回退", op: func(c *Clock) { c.Advance(-time.Second) }, want: start.Add(-time.Second)},
+		{name: "设置", op: func(c *Clock) { c.Set(start.Add(time.Hour)) }, want: start.Add(time.Hour)},
+		{name: "休眠推进时间", op: func(c *Clock) { c.Sleep(time.Millisecond) }, want: start.Add(time.Millisecond)},
+		{name: "休眠负数不回退", op: func(c *Clock) { c.Sleep(-time.Millisecond) }, want: start},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := New(start)
+			tc.op(c)
+			if got := c.Time(); !got.Equal(tc.want) || c.Now() != tc.want.UnixNano() {
+				t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package generator
+
+import (
+	"sync/atomic"
+)
+
+// Generator id生成器
+type Generator interface {
+	Generate() (ID, error)
+}
+
+// EmergencyGenerator 应急生成器
+//   - 唯一性依赖外部系统(如Redis)的可用性与持久性，保证弱于IDGenerator
+//   - 只能作为降级链中的后备，不能作为主生成器
+type EmergencyGenerator interface {
+	Generator
+	Emergency() //标记方法
+}
+
+// FallbackChain 降级链：依次尝试各生成器，返回第一个成功生成的id
+type FallbackChain struct {
+	generators []Generator
+	active     int64 //最近一次成功生成id的生成器下标
+}
+
+// NewFallbackChain 创建降级链，primary不能是应急生成器
+func NewFallbackChain(primary Generator, fallbacks ...Generator) (*FallbackChain, error) {
+	if primary == nil {
+		return nil, errorf(CodeNilPrimary)
+	}
+	if _, ok := primary.(EmergencyGenerator); ok {
+		return nil, errorf(CodeEmergencyPrimary)
+	}
+	for _, gen := range fallbacks {
+		if gen == nil {
+			return nil, errorf(CodeNilFallback)
+		}
+	}
+	return &FallbackChain{generators: append([]Generator{primary}, fallbacks...)}, nil
+}
+
+// Generate 依次尝试各生成器，全部失败时返回最后一个错误
+func (chain *FallbackChain) Generate() (ID, error) {
+	var lastErr error
+	for index, gen := range chain.generators {
+		id, err := gen.Generate()
+		if err == nil {
+			atomic.StoreInt64(&chain.active, int64(index))
+			return id, nil
+		}
+		lastErr = err
+	}
+	return 0, lastErr
+}
+
+// Active 最近一次成功生成id的生成器下标，0表示主生成器
+func (chain *FallbackChain) Active() int {
+	return int(atomic.LoadInt64(&chain.active))
+}
+
+// Degraded 最近一次是否由后备生成器生成
+func (chain *FallbackChain) Degraded() bool {
+	return chain.Active() != 0
+}
@@ -0,0 +1,127 @@
+package generator
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeRedis 内存实现的RedisClient
+type fakeRedis struct {
+	mutex    sync.Mutex
+	now      time.Time
+	counters map[string]int64
+	err      error
+}
+
+func (r *fakeRedis) Time() (time.Time, error) {
+	return r.now, r.err
+}
+
+func (r *fakeRedis) Incr(key string) (int64, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.err != nil {
+		return 0, r.err
+	}
+	r.counters[key]++
+	return r.counters[key], nil
+}
+
+func (r *fakeRedis) Expire(key string, ttl time.Duration) error {
+	return r.err
+}
+
+// failingGenerator 总是失败的生成器
+type failingGenerator struct{}
+
+func (failingGenerator) Generate() (ID, error) {
+	return 0, errors.New("clock broken")
+}
+
+// TestFallbackChain 降级链
+func TestFallbackChain(t *testing.T) {
+	redis := &fakeRedis{now: time.Now(), counters: make(map[string]int64)}
+	emergency, err := NewRedisEmergencyGenerator(redis, "order", 511, *DefaultSettings)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if _, err := NewFallbackChain(emergency); err == nil {
+		t.Fatalf("【失败】-应急生成器不能作为主生成器")
+	}
+
+	idGen, _ := NewGenerator(0)
+	chain, err := NewFallbackChain(idGen, emergency)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if _, err := chain.Generate(); err != nil || chain.Degraded() {
+		t.Fatalf("【失败】-主生成器可用时不应降级-err:%v", err)
+	}
+
+	chain, _ = NewFallbackChain(failingGenerator{}, emergency)
+	ids := make(map[int64]bool)
+	for i := 0; i < 4096; i++ {
+		id, err := chain.Generate()
+		if err != nil || !chain.Degraded() {
+			t.Fatalf("【失败】-应降级到应急生成器-err:%v", err)
+		}
+		if ids[int64(id)] {
+			t.Fatalf("【失败】-出现重复的id:%d", id)
+		}
+		ids[int64(id)] = true
+		if compose := idGen.Decompose(int64(id)); compose.MachineID != 511 {
+			t.Fatalf("【失败】-应急id的机器ID错误:%d", compose.MachineID)
+		}
+	}
+
+	//同一时间单位内的序号用完
+	for i := 0; i < 4096; i++ {
+		chain.Generate()
+	}
+	if _, err := chain.Generate(); err == nil {
+		t.Fatalf("【失败】-序号用完后应返回错误")
+	}
+
+	//Redis不可用
+	redis.err = errors.New("connection refused")
+	if _, err := chain.Generate(); err == nil {
+		t.Fatalf("【失败】-全部生成器失败时应返回错误")
+	}
+}
+
+// TestRedisEmergencyPlacement 序号溢出到时间线位时不影响机器ID及时间，与时间线位置无关
+func TestRedisEmergencyPlacement(t *testing.T) {
+	for placement := TimelineBelowMachine; placement <= TimelineAboveTime; placement++ {
+		settings := *DefaultSettings
+		settings.TimeBit = 41
+		settings.MachineIDBit = 16
+		settings.TimelineBit = 2
+		settings.SeqBit = 4
+		settings.Placement = placement
+		redis := &fakeRedis{now: time.Now(), counters: make(map[string]int64)}
+		emergency, err := NewRedisEmergencyGenerator(redis, "order", 4, settings)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		idGen, _ := NewGeneratorWithSettings(0, settings)
+		want := idGen.Decompose(idGen.MinIDForTime(redis.now)).Time
+		ids := make(map[int64]bool)
+		for i := 0; i < 1<<(settings.TimelineBit+settings.SeqBit); i++ {
+			id, err := emergency.Generate()
+			if err != nil {
+				t.Fatalf("【失败】-%s-got:%v-want:%v", placement, err, nil)
+			}
+			compose := idGen.Decompose(int64(id))
+			if compose.MachineID != 4 || compose.Time != want || ids[int64(id)] {
+				t.Fatalf("【失败】-%s-got:%v,%v-want:%v,%v", placement, compose.MachineID, compose.Time, 4, want)
+			}
+			ids[int64(id)] = true
+		}
+		if _, err := emergency.Generate(); err == nil {
+			t.Fatalf("【失败】-%s-序号用完后应返回错误", placement)
+		}
+	}
+}
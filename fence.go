@@ -0,0 +1,23 @@
+package generator
+
+// ErrFenced 生成器已失效
+var ErrFenced = newError(CodeFenced)
+
+// Fence 使生成器失效，此后生成均返回err(为nil时返回ErrFenced)
+//   - 用于机器ID租约丢失等不能再使用当前机器ID的情况，避免两个节点以相同的机器ID生成
+//   - 通过RotateMachineID切换到新的机器ID后恢复
+func (idGen *IDGenerator) Fence(err error) {
+	if err == nil {
+		err = ErrFenced
+	}
+	idGen.mutex.Lock()
+	defer idGen.mutex.Unlock()
+	idGen.fenced = err
+}
+
+// Fenced 生成器失效的原因，未失效时返回nil
+func (idGen *IDGenerator) Fenced() error {
+	idGen.mutex.Lock()
+	defer idGen.mutex.Unlock()
+	return idGen.fenced
+}
@@ -0,0 +1,41 @@
+package generator
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestFence 生成器失效及恢复
+func TestFence(t *testing.T) {
+	lost := errors.New("租约丢失")
+	testCases := []struct {
+		name    string
+		fence   error
+		wantErr error
+	}{
+		{name: "指定原因", fence: lost, wantErr: lost},
+		{name: "缺省原因", fence: nil, wantErr: ErrFenced},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			idGen, _ := NewGenerator(1)
+			idGen.Fence(tc.fence)
+			if _, err := idGen.Generate(); err != tc.wantErr || idGen.Fenced() != tc.wantErr {
+				t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, err, tc.wantErr)
+			}
+			if _, err := idGen.GenerateN(10); err != tc.wantErr {
+				t.Fatalf("【失败】-%s-批量-got:%v-want:%v", tc.name, err, tc.wantErr)
+			}
+
+			//切换到相同机器ID不恢复，切换到新机器ID后恢复
+			idGen.RotateMachineID(1)
+			if _, err := idGen.Generate(); err != tc.wantErr {
+				t.Fatalf("【失败】-%s-相同机器ID-got:%v-want:%v", tc.name, err, tc.wantErr)
+			}
+			idGen.RotateMachineID(2)
+			if id, err := idGen.Generate(); err != nil || idGen.Decompose(int64(id)).MachineID != 2 {
+				t.Fatalf("【失败】-%s-新机器ID-got:%v-want:%v", tc.name, err, nil)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package generator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// ErrFingerprintMismatch id结构的指纹不一致，*FingerprintMismatchError匹配该错误
+var ErrFingerprintMismatch = newError(CodeFingerprintMismatch)
+
+// FingerprintMismatchError 解析方的id结构与生成方不一致
+type FingerprintMismatchError struct {
+	Expected string //生成方公布的指纹
+	Actual   string //解析方配置的指纹
+}
+
+func (e *FingerprintMismatchError) Error() string {
+	return message(CodeFingerprintDetail, ErrFingerprintMismatch, e.Expected, e.Actual)
+}
+
+// Unwrap ErrFingerprintMismatch
+func (e *FingerprintMismatchError) Unwrap() error { return ErrFingerprintMismatch }
+
+// Fingerprint id结构的指纹：各部分位数、标记位、时间线位置、基准时间、时间单位及版本的哈希(16位十六进制)
+//   - 结构相同的配置指纹相同，与机器ID、数据中心ID、时钟、等待策略等运行参数无关；TimeUnit为0与1毫秒视为相同
+//   - 生成方可将指纹随配置或接口公布，解析方通过VerifyFingerprint确认使用的是同一id结构，避免结构不一致时静默得到错误的解析结果
+func (settings *Settings) Fingerprint() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "time=%d;datacenter=%d;machine=%d;timeline=%d;seq=%d;flag=%t;placement=%s;epoch=%d;unit=%d",
+		settings.TimeBit, settings.DatacenterBit, settings.MachineIDBit, settings.TimelineBit, settings.SeqBit,
+		settings.FlagBit, settings.Placement, settings.Epoch, settings.unit())
+	if settings.VersionBit != 0 {
+		fmt.Fprintf(&b, ";version=%d/%d", settings.Version, settings.VersionBit)
+	}
+	sum := sha256.Sum256([]byte(b.String()))
+	return hex.EncodeToString(sum[:8])
+}
+
+// VerifyFingerprint 校验id结构与生成方公布的指纹一致，不一致时返回*FingerprintMismatchError
+//   - 指纹不区分大小写
+func (settings *Settings) VerifyFingerprint(fingerprint string) error {
+	actual := settings.Fingerprint()
+	if !strings.EqualFold(strings.TrimSpace(fingerprint), actual) {
+		return &FingerprintMismatchError{Expected: fingerprint, Actual: actual}
+	}
+	return nil
+}
+
+// Fingerprint 生成器id结构的指纹，见Settings.Fingerprint
+func (idGen *IDGenerator) Fingerprint() string {
+	return idGen.settings.Fingerprint()
+}
+
+// VerifyFingerprint 校验解析使用的id结构与生成方公布的指纹一致
+func (d *Decomposer) VerifyFingerprint(fingerprint string) error {
+	return d.settings.VerifyFingerprint(fingerprint)
+}
@@ -0,0 +1,68 @@
+package generator
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestFingerprint 结构相同的配置指纹相同，任一结构参数不同则指纹不同
+func TestFingerprint(t *testing.T) {
+	base := *DefaultSettings
+	fp := base.Fingerprint()
+	if len(fp) != 16 {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "指纹长度", len(fp), 16)
+	}
+
+	same := base
+	same.TimeUnit = time.Millisecond
+	same.DatacenterID = 0
+	same.MaxBackwardWait = time.Second
+	if got := same.Fingerprint(); got != fp {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "运行参数不影响指纹", got, fp)
+	}
+
+	testCases := []struct {
+		name   string
+		modify func(s *Settings)
+	}{
+		{name: "序号位数", modify: func(s *Settings) { s.SeqBit--; s.MachineIDBit++ }},
+		{name: "时间线位数", modify: func(s *Settings) { s.TimelineBit--; s.SeqBit++ }},
+		{name: "数据中心位数", modify: func(s *Settings) { s.DatacenterBit = 2; s.MachineIDBit -= 2 }},
+		{name: "基准时间", modify: func(s *Settings) { s.Epoch += int64(time.Millisecond) }},
+		{name: "时间单位", modify: func(s *Settings) { s.TimeUnit = 10 * time.Millisecond }},
+		{name: "时间线位置", modify: func(s *Settings) { s.Placement = TimelineAboveMachine }},
+		{name: "标记位", modify: func(s *Settings) { s.FlagBit = true; s.SeqBit-- }},
+	}
+	for _, tc := range testCases {
+		settings := base
+		tc.modify(&settings)
+		if got := settings.Fingerprint(); got == fp {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, got, "不同的指纹")
+		}
+	}
+}
+
+// TestVerifyFingerprint 解析方校验与生成方的id结构一致
+func TestVerifyFingerprint(t *testing.T) {
+	idGen, _ := NewGeneratorWithSettings(1, *DefaultSettings)
+	fp := idGen.Fingerprint()
+
+	d, _ := NewDecomposer(*DefaultSettings)
+	if err := d.VerifyFingerprint(fp); err != nil {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "结构相同", err, nil)
+	}
+
+	other, _ := NewDecomposer(*CentisecondSettings)
+	err := other.VerifyFingerprint(fp)
+	e, ok := err.(*FingerprintMismatchError)
+	if !ok || e.Expected != fp || e.Actual != CentisecondSettings.Fingerprint() {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "结构不同", err, "*FingerprintMismatchError")
+	}
+	if !errors.Is(err, ErrFingerprintMismatch) {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "匹配ErrFingerprintMismatch", false, true)
+	}
+	if code, _ := ErrorCodeOf(err); code != CodeFingerprintMismatch {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "错误码", code, CodeFingerprintMismatch)
+	}
+}
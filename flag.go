@@ -0,0 +1,24 @@
+package generator
+
+// WithFlag 设置id的标记位(最低位)，用于标记派生的key(如墓碑记录、系统记录)
+//   - 须在配置中预留标记位(Settings.FlagBit)，生成的id标记位总是0；未预留时原样返回
+//   - 标记位位于最低位，设置后不改变与其他id的先后顺序，同一id设置标记位后紧随其后
+func (idGen *IDGenerator) WithFlag(id int64) int64 {
+	if !idGen.settings.FlagBit {
+		return id
+	}
+	return id | 1
+}
+
+// WithoutFlag 清除id的标记位，得到原始id；未预留标记位时原样返回
+func (idGen *IDGenerator) WithoutFlag(id int64) int64 {
+	if !idGen.settings.FlagBit {
+		return id
+	}
+	return id &^ 1
+}
+
+// HasFlag id是否设置了标记位；未预留标记位时总是返回false
+func (idGen *IDGenerator) HasFlag(id int64) bool {
+	return idGen.settings.FlagBit && id&1 != 0
+}
@@ -0,0 +1,92 @@
+package generator
+
+import (
+	"sort"
+	"strings"
+	"testing"
+)
+
+// flagSettings 预留标记位的配置
+var flagSettings = Settings{TimeBit: 41, MachineIDBit: 9, TimelineBit: 1, SeqBit: 11, Epoch: DefaultEpoch, FlagBit: true}
+
+// TestFlagBit 标记位
+func TestFlagBit(t *testing.T) {
+	idGen, err := NewGeneratorWithSettings(3, flagSettings)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	//序号用完后等待下一个时间单位，id不重复且标记位为0
+	ids, err := idGen.GenerateN(5000)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	seen := make(map[int64]bool)
+	for i, id := range ids {
+		if seen[id] {
+			t.Fatalf("【失败】-%s-出现重复的id:%d", "生成", id)
+		}
+		seen[id] = true
+		if idGen.HasFlag(id) {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", "生成的id标记位", true, false)
+		}
+		if compose := idGen.Decompose(id); compose.MachineID != 3 || (i > 0 && compose.Seq == 0 && idGen.Decompose(ids[i-1]).Seq != 2047) {
+			t.Fatalf("【失败】-%s-got:%v", "解构", compose)
+		}
+	}
+
+	//设置标记位后保持原有顺序
+	flagged := make([]int64, 0, 2*len(ids))
+	for _, id := range ids {
+		flagged = append(flagged, idGen.WithFlag(id), id)
+	}
+	sorted := append([]int64(nil), flagged...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	for i := range sorted {
+		want := ids[i/2]
+		if idGen.WithoutFlag(sorted[i]) != want || idGen.HasFlag(sorted[i]) != (i%2 == 1) {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", "排序", sorted[i], want)
+		}
+		if got := *idGen.Decompose(sorted[i]); got != *idGen.Decompose(want) {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", "标记后解构", got, *idGen.Decompose(want))
+		}
+	}
+
+	//未预留标记位时原样返回
+	plain, _ := NewGenerator(0)
+	id, _ := plain.Generate()
+	if plain.WithFlag(int64(id)) != int64(id) || plain.HasFlag(int64(id)|1) {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "未预留标记位", plain.WithFlag(int64(id)), id)
+	}
+}
+
+// TestFlagBitSettings 预留标记位的配置校验及解码代码
+func TestFlagBitSettings(t *testing.T) {
+	settings := flagSettings
+	settings.SeqBit = 12
+	if _, err := NewGeneratorWithSettings(0, settings); err == nil {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "位数之和超过63", err, "error")
+	}
+
+	testCases := []struct {
+		name     string
+		generate func() (string, error)
+		want     string
+	}{
+		{name: "MySQL", generate: func() (string, error) { return GenerateSQLFunctions(flagSettings, DialectMySQL, "sf") }, want: "(id >> 1) & 2047"},
+		{name: "ClickHouse", generate: func() (string, error) { return GenerateSQLFunctions(flagSettings, DialectClickHouse, "sf") }, want: "bitAnd(bitShiftRight(toInt64(id), 1), 2047)"},
+		{name: "Java", generate: func() (string, error) { return GenerateDecoder(flagSettings, DecoderOptions{Language: LangJava}) }, want: "(id >>> 1) & 2047L"},
+		{name: "Python", generate: func() (string, error) { return GenerateDecoder(flagSettings, DecoderOptions{Language: LangPython}) }, want: "def has_flag(id_):"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.generate()
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+			if !strings.Contains(got, tc.want) {
+				t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, got, tc.want)
+			}
+		})
+	}
+}
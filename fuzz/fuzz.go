@@ -0,0 +1,273 @@
+// Package fuzz 面向id结构的模糊测试入口，可用于go-fuzz、libFuzzer等
+//
+// 包级函数FuzzDecompose、FuzzParseReadable、FuzzMigrate从输入的前几个字节推导id结构(位数、时间单位、时间线位置、标记位、数据中心位)，
+// 覆盖各种结构的边界情况；使用自定义Settings时通过NewTarget创建针对该结构的入口：
+//
+//	var target, _ = fuzz.NewTarget(settings)
+//
+//	func Fuzz(data []byte) int { return target.Decompose(data) }
+//
+// 发现问题时panic，返回值遵循go-fuzz约定：1表示输入有价值，0表示一般，-1表示不加入语料。
+package fuzz
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"time"
+
+	generator "github.com/jayecc/mtl-snowflake"
+	"github.com/jayecc/mtl-snowflake/fakeclock"
+)
+
+const (
+	layoutBytes    = 4  //推导id结构使用的字节数
+	maxTimelineBit = 10 //推导的时间线位数上限，生成器按时间线数分配进度
+)
+
+// timeUnits 推导的时间单位，0表示毫秒
+var timeUnits = [8]time.Duration{0, time.Second, 10 * time.Millisecond, time.Microsecond,
+	100 * time.Microsecond, 100 * time.Millisecond, 5 * time.Millisecond, 250 * time.Millisecond}
+
+// Target 针对指定Settings的模糊测试入口
+type Target struct {
+	idGen    *generator.IDGenerator
+	settings generator.Settings
+}
+
+// NewTarget 创建针对settings的模糊测试入口
+//   - settings未指定Clock时使用固定在基准时间的假时钟，使结果不依赖当前时间
+func NewTarget(settings generator.Settings) (*Target, error) {
+	if settings.Clock == nil {
+		settings.Clock = fakeclock.New(time.Unix(0, settings.Epoch))
+	}
+	idGen, err := generator.NewGeneratorWithSettings(0, settings)
+	if err != nil {
+		return nil, err
+	}
+	return &Target{idGen: idGen, settings: settings}, nil
+}
+
+// FuzzDecompose 由输入推导id结构后执行Target.Decompose
+func FuzzDecompose(data []byte) int {
+	target, rest := layoutTarget(data)
+	if target == nil {
+		return -1
+	}
+	return target.Decompose(rest)
+}
+
+// FuzzParseReadable 由输入推导id结构后执行Target.ParseReadable
+func FuzzParseReadable(data []byte) int {
+	target, rest := layoutTarget(data)
+	if target == nil {
+		return -1
+	}
+	return target.ParseReadable(rest)
+}
+
+// FuzzMigrate 由输入推导id结构后执行Target.Migrate
+func FuzzMigrate(data []byte) int {
+	target, rest := layoutTarget(data)
+	if target == nil {
+		return -1
+	}
+	return target.Migrate(rest)
+}
+
+// layoutTarget 由输入的前layoutBytes个字节推导id结构
+//   - data[0]：bit0标记位，bit1-2时间线位置，bit3-5时间单位(timeUnits)，bit6-7数据中心位数(0-3)
+//   - data[1-3]：时间、机器ID、时间线(不超过maxTimelineBit)的位数，其余为序号位数
+//   - 数据中心ID固定为1，输入的id覆盖其他数据中心
+func layoutTarget(data []byte) (*Target, []byte) {
+	if len(data) < layoutBytes {
+		return nil, nil
+	}
+	settings := generator.Settings{Epoch: generator.DefaultEpoch}
+	settings.FlagBit = data[0]&1 == 1
+	settings.Placement = generator.Placement((data[0] >> 1 & 3) % 3)
+	settings.TimeUnit = timeUnits[data[0]>>3&7]
+	remaining := uint64(63)
+	if settings.FlagBit {
+		remaining--
+	}
+	settings.DatacenterBit = uint64(data[0] >> 6)
+	if settings.DatacenterBit > 0 {
+		settings.DatacenterID = 1
+	}
+	remaining -= settings.DatacenterBit
+	settings.TimeBit = uint64(data[1]) % (remaining + 1)
+	remaining -= settings.TimeBit
+	settings.MachineIDBit = uint64(data[2]) % (remaining + 1)
+	remaining -= settings.MachineIDBit
+	timelineBit := remaining
+	if timelineBit > maxTimelineBit {
+		timelineBit = maxTimelineBit
+	}
+	settings.TimelineBit = uint64(data[3]) % (timelineBit + 1)
+	settings.SeqBit = remaining - settings.TimelineBit
+
+	target, err := NewTarget(settings)
+	if err != nil {
+		return nil, nil
+	}
+	return target, data[layoutBytes:]
+}
+
+// Decompose 将输入的前8个字节作为id，校验：
+//   - 各部分不超过对应位数的最大值
+//   - 按独立计算的移位重新组合后与id一致(不含标记位)
+//   - 可读格式解析后与原可读格式一致，且除时间外各部分不变
+//   - v2可读格式解析后与id一致
+func (target *Target) Decompose(data []byte) int {
+	if len(data) < 8 {
+		return -1
+	}
+	id := int64(binary.BigEndian.Uint64(data) & math.MaxInt64)
+	s := target.settings
+	c := target.idGen.Decompose(id)
+	if c.Time > maxOf(s.TimeBit) || c.DatacenterID > maxOf(s.DatacenterBit) || c.MachineID > maxOf(s.MachineIDBit) || c.TimeLine > maxOf(s.TimelineBit) || c.Seq > maxOf(s.SeqBit) ||
+		c.Time < 0 || c.DatacenterID < 0 || c.MachineID < 0 || c.TimeLine < 0 || c.Seq < 0 {
+		panic(fmt.Sprintf("id %d 解析结果超出位数范围：%+v", id, *c))
+	}
+
+	want := id
+	if s.FlagBit {
+		want &^= 1
+	}
+	if got := recompose(s, c); got != want {
+		panic(fmt.Sprintf("id %d 解析后重新组合为 %d", id, got))
+	}
+
+	readable := target.idGen.ToReadable(id)
+	parsed, err := target.idGen.ParseReadable(readable)
+	if err != nil {
+		panic(fmt.Sprintf("id %d 的可读格式 %s 无法解析：%v", id, readable, err))
+	}
+	if got := target.idGen.ToReadable(parsed); got != readable {
+		panic(fmt.Sprintf("可读格式 %s 解析后为 %s", readable, got))
+	}
+	if p := target.idGen.Decompose(parsed); p.DatacenterID != c.DatacenterID || p.MachineID != c.MachineID || p.TimeLine != c.TimeLine || p.Seq != c.Seq {
+		panic(fmt.Sprintf("id %d 的可读格式解析后各部分不一致：%+v", id, *p))
+	}
+
+	readable = target.idGen.ToReadableV2(id)
+	if parsed, err = target.idGen.ParseReadableV2(readable); err != nil || parsed != id {
+		panic(fmt.Sprintf("id %d 的v2可读格式 %s 解析为 %d：%v", id, readable, parsed, err))
+	}
+	return 1
+}
+
+// ParseReadable 将输入分别作为v1、v2可读格式解析，成功时校验重新输出的可读格式与输入一致
+func (target *Target) ParseReadable(data []byte) int {
+	readable := string(data)
+	formats := []struct {
+		parse  func(string) (int64, error)
+		format func(int64) string
+	}{
+		{parse: target.idGen.ParseReadable, format: target.idGen.ToReadable},
+		{parse: target.idGen.ParseReadableV2, format: target.idGen.ToReadableV2},
+	}
+	result := 0
+	for _, f := range formats {
+		id, err := f.parse(readable)
+		if err != nil {
+			continue
+		}
+		if id < 0 {
+			panic(fmt.Sprintf("可读格式 %s 解析为负数 %d", readable, id))
+		}
+		if got := f.format(id); got != readable {
+			panic(fmt.Sprintf("可读格式 %s 解析为 %d，重新输出为 %s", readable, id, got))
+		}
+		result = 1
+	}
+	return result
+}
+
+// Migrate 由输入构造迁移参数，计划生成成功时校验：
+//   - 首尾及若干中间值迁移后为正、严格递增，且小于切换后可能生成的最小id
+//   - 重编码策略迁移后的id解析出的机器ID为迁移专用机器ID，时间早于切换时间
+func (target *Target) Migrate(data []byte) int {
+	if len(data) < 25 {
+		return -1
+	}
+	s := target.settings
+	unit := int64(time.Millisecond)
+	if s.TimeUnit != 0 {
+		unit = int64(s.TimeUnit)
+	}
+	minSerial := int64(binary.BigEndian.Uint64(data[0:8])&math.MaxInt64) | 1
+	span := int64(binary.BigEndian.Uint64(data[8:16]) & math.MaxInt64 % uint64(math.MaxInt64-minSerial+1))
+	cutover := int64(binary.BigEndian.Uint64(data[16:24]) & math.MaxInt64 % uint64(maxOf(s.TimeBit)+1))
+	req := generator.MigrationRequest{
+		Table:     "t",
+		Column:    "id",
+		MinSerial: minSerial,
+		MaxSerial: minSerial + span,
+		Cutover:   time.Unix(0, s.Epoch+cutover*unit),
+		Reencode:  data[24]&1 == 1,
+		MachineID: int64(data[24]>>1) & maxOf(s.MachineIDBit),
+	}
+	plan, err := generator.PlanMigration(s, req)
+	if err != nil {
+		return 0
+	}
+
+	serials := []int64{req.MinSerial, req.MinSerial + span/3, req.MinSerial + span/2, req.MaxSerial - 1, req.MaxSerial}
+	var lastSerial, last int64
+	for _, serial := range serials {
+		if serial < req.MinSerial || serial == lastSerial {
+			continue
+		}
+		id, err := plan.Map(serial)
+		if err != nil {
+			panic(fmt.Sprintf("迁移计划 %+v 无法映射 %d：%v", req, serial, err))
+		}
+		if id < 1 || id >= plan.FirstNewID || (lastSerial > 0 && id <= last) {
+			panic(fmt.Sprintf("迁移计划 %+v 将 %d 映射为 %d(上一个 %d，切换后最小id %d)", req, serial, id, last, plan.FirstNewID))
+		}
+		if plan.Strategy == generator.MigrationReencode {
+			c := target.idGen.Decompose(id)
+			if c.MachineID != req.MachineID || c.Time >= cutover {
+				panic(fmt.Sprintf("迁移计划 %+v 将 %d 重编码为 %d：%+v", req, serial, id, *c))
+			}
+		}
+		lastSerial, last = serial, id
+	}
+	return 1
+}
+
+// maxOf bits位所能表示的最大值
+func maxOf(bits uint64) int64 {
+	return int64(1)<<bits - 1
+}
+
+// recompose 按id结构独立计算各部分的移位并组合，作为生成器实现的对照
+func recompose(s generator.Settings, c *generator.IDCompose) int64 {
+	shift := uint64(0)
+	if s.FlagBit {
+		shift = 1
+	}
+	id := c.Seq << shift
+	shift += s.SeqBit
+
+	//数据中心ID紧邻机器ID之上，与机器ID作为一个整体排列
+	nodeBit := s.DatacenterBit + s.MachineIDBit
+	node := c.DatacenterID<<s.MachineIDBit | c.MachineID
+	var order []uint64 //由低到高：节点(数据中心ID及机器ID)、时间线、时间的位数
+	var parts []int64
+	switch s.Placement {
+	case generator.TimelineAboveMachine:
+		order, parts = []uint64{nodeBit, s.TimelineBit, s.TimeBit}, []int64{node, c.TimeLine, c.Time}
+	case generator.TimelineAboveTime:
+		order, parts = []uint64{nodeBit, s.TimeBit, s.TimelineBit}, []int64{node, c.Time, c.TimeLine}
+	default:
+		order, parts = []uint64{s.TimelineBit, nodeBit, s.TimeBit}, []int64{c.TimeLine, node, c.Time}
+	}
+	for i, bits := range order {
+		id |= parts[i] << shift
+		shift += bits
+	}
+	return id
+}
@@ -0,0 +1,97 @@
+package fuzz
+
+import (
+	"encoding/binary"
+	"math/rand"
+	"testing"
+	"time"
+
+	generator "github.com/jayecc/mtl-snowflake"
+)
+
+// randomInputs 固定种子的随机输入，覆盖各种id结构
+func randomInputs(n, size int) [][]byte {
+	r := rand.New(rand.NewSource(1))
+	inputs := make([][]byte, n)
+	for i := range inputs {
+		inputs[i] = make([]byte, size)
+		r.Read(inputs[i])
+	}
+	return inputs
+}
+
+// TestFuzzTargets 各入口在随机输入下不panic，且能覆盖到有效的id结构
+func TestFuzzTargets(t *testing.T) {
+	testCases := []struct {
+		name string
+		fuzz func(data []byte) int
+		size int
+	}{
+		{name: "FuzzDecompose", fuzz: FuzzDecompose, size: layoutBytes + 8},
+		{name: "FuzzMigrate", fuzz: FuzzMigrate, size: layoutBytes + 25},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			valid := 0
+			for _, data := range randomInputs(20000, tc.size) {
+				if tc.fuzz(data) == 1 {
+					valid++
+				}
+			}
+			if valid == 0 {
+				t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, valid, ">0")
+			}
+		})
+	}
+}
+
+// TestTarget 针对默认配置的入口
+func TestTarget(t *testing.T) {
+	target, err := NewTarget(*generator.DefaultSettings)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	idGen, _ := generator.NewGenerator(5)
+	id, _ := idGen.Generate()
+	data := make([]byte, 8)
+	binary.BigEndian.PutUint64(data, uint64(id))
+
+	testCases := []struct {
+		name string
+		got  int
+		want int
+	}{
+		{name: "解析生成的id", got: target.Decompose(data), want: 1},
+		{name: "输入过短", got: target.Decompose(data[:4]), want: -1},
+		{name: "解析可读格式", got: target.ParseReadable([]byte(idGen.ToReadable(int64(id)))), want: 1},
+		{name: "非法可读格式", got: target.ParseReadable([]byte("2020x")), want: 0},
+		{name: "月份超出范围", got: target.ParseReadable([]byte("2020130100000000000000000")), want: 0},
+		{name: "解析v2可读格式", got: target.ParseReadable([]byte(idGen.ToReadableV2(int64(id)))), want: 1},
+	}
+	for _, tc := range testCases {
+		if tc.got != tc.want {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, tc.got, tc.want)
+		}
+	}
+
+	//秒级结构下的可读格式
+	target, _ = NewTarget(*generator.SecondSettings)
+	readable := time.Unix(0, generator.DefaultEpoch).Add(time.Hour).Format("20060102150405") + "0000001234"
+	if got := target.ParseReadable([]byte(readable)); got != 1 {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "秒级可读格式", got, 1)
+	}
+
+	//其他数据中心的id
+	settings := generator.Settings{TimeBit: 41, DatacenterBit: 3, DatacenterID: 1, MachineIDBit: 7, TimelineBit: 1, SeqBit: 11, Epoch: generator.DefaultEpoch}
+	target, _ = NewTarget(settings)
+	settings.DatacenterID = 2
+	other, _ := generator.NewGeneratorWithSettings(5, settings)
+	id, _ = other.Generate()
+	binary.BigEndian.PutUint64(data, uint64(id))
+	if got := target.Decompose(data); got != 1 {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "其他数据中心", got, 1)
+	}
+	if got := target.ParseReadable([]byte(other.ToReadableV2(int64(id)))); got != 1 {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "其他数据中心的v2可读格式", got, 1)
+	}
+}
@@ -0,0 +1,32 @@
+package generator
+
+import (
+	"context"
+	"runtime/pprof"
+)
+
+// GenerateCtx 生成全局唯一id，序号用完或时钟回退需要等待时可通过ctx取消
+//   - 等待被取消时返回ctx.Err()，生成器状态保持不变，不会因取消产生重复id
+//   - 获取生成器锁的等待不可取消(通常不超过一个时间单位)
+//   - 设置了性能分析标签(SetProfileLabels)时，生成期间附加到调用方goroutine，保留ctx中已有的标签
+func (idGen *IDGenerator) GenerateCtx(ctx context.Context) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	idGen.mutex.Lock()
+	defer idGen.mutex.Unlock()
+
+	idGen.ctx = ctx
+	defer func() { idGen.ctx = nil }()
+	if len(idGen.labels) == 0 {
+		return idGen.generateLocked()
+	}
+
+	var id int64
+	var err error
+	pprof.Do(ctx, pprof.Labels(idGen.labels...), func(context.Context) {
+		id, err = idGen.generateLocked()
+	})
+	return id, err
+}
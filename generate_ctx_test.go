@@ -0,0 +1,81 @@
+package generator
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestGenerateCtx 取消生成时的等待
+func TestGenerateCtx(t *testing.T) {
+	//秒级时间单位、每秒4个序号，序号用完后需等待到下一秒
+	settings := Settings{TimeBit: 32, MachineIDBit: 28, TimelineBit: 1, SeqBit: 2, Epoch: DefaultEpoch, TimeUnit: time.Second}
+	idGen, _ := NewGeneratorWithSettings(0, settings)
+	idGen.SetProfileLabels("order", "trade")
+	time.Sleep(time.Until(time.Now().Truncate(time.Second).Add(time.Second)))
+
+	ids := make(map[int64]bool)
+	for i := 0; i < 4; i++ {
+		id, err := idGen.GenerateCtx(context.Background())
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		ids[id] = true
+	}
+
+	testCases := []struct {
+		name    string
+		ctx     func() (context.Context, context.CancelFunc)
+		wantErr error
+	}{
+		{name: "已取消", ctx: func() (context.Context, context.CancelFunc) {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			return ctx, cancel
+		}, wantErr: context.Canceled},
+		{name: "序号用完等待超时", ctx: func() (context.Context, context.CancelFunc) {
+			return context.WithTimeout(context.Background(), 20*time.Millisecond)
+		}, wantErr: context.DeadlineExceeded},
+		{name: "再次等待超时", ctx: func() (context.Context, context.CancelFunc) {
+			return context.WithTimeout(context.Background(), 20*time.Millisecond)
+		}, wantErr: context.DeadlineExceeded},
+	}
+	for _, tc := range testCases {
+		ctx, cancel := tc.ctx()
+		start := time.Now()
+		_, err := idGen.GenerateCtx(ctx)
+		cancel()
+		if err != tc.wantErr {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, err, tc.wantErr)
+		}
+		if elapsed := time.Since(start); elapsed > 200*time.Millisecond {
+			t.Fatalf("【失败】-%s-等待时长-got:%v", tc.name, elapsed)
+		}
+	}
+
+	//取消后继续生成不会产生重复的id
+	for i := 0; i < 4; i++ {
+		id, err := idGen.Generate()
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if ids[int64(id)] {
+			t.Fatalf("【失败】-%s-出现重复的id:%d", "取消后继续生成", id)
+		}
+		ids[int64(id)] = true
+	}
+}
+
+// TestGenerateCtxStrictMonotonic 取消严格递增模式下等待时钟追回
+func TestGenerateCtxStrictMonotonic(t *testing.T) {
+	idGen, _ := NewGenerator(0)
+	idGen.SetStrictMonotonic(true, 10*time.Second)
+	idGen.Generate()
+	idGen.settings.Epoch += int64(time.Second)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := idGen.GenerateCtx(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "等待时钟追回超时", err, context.DeadlineExceeded)
+	}
+}
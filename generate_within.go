@@ -0,0 +1,35 @@
+package generator
+
+import (
+	"time"
+)
+
+// ErrWaitTimeout GenerateWithin的等待额度不足，实现了Timeout() bool，可按net.Error的方式识别
+var ErrWaitTimeout error = waitTimeoutError{}
+
+// waitTimeoutError 等待额度不足
+type waitTimeoutError struct{}
+
+func (waitTimeoutError) Error() string { return message(CodeWaitTimeout) }
+
+func (waitTimeoutError) errorCode() ErrorCode { return CodeWaitTimeout }
+
+// Timeout 超时错误
+func (waitTimeoutError) Timeout() bool { return true }
+
+// GenerateWithin 生成全局唯一id，序号用完、时钟回退等内部等待的总时长不超过d，否则返回ErrWaitTimeout
+//   - 预计等待超出剩余额度时立即返回，不会先等待再失败；d为0表示不允许等待
+//   - 获取生成器锁的时间不计入额度；返回ErrWaitTimeout时生成器状态保持不变，不会因此产生重复id
+//   - 不需要完整的ctx传递即可为有延迟要求的服务提供等待上限，需要取消时使用GenerateCtx
+func (idGen *IDGenerator) GenerateWithin(d time.Duration) (int64, error) {
+	if d < 0 {
+		return 0, errorf(CodeNegative, "d")
+	}
+
+	idGen.mutex.Lock()
+	defer idGen.mutex.Unlock()
+
+	idGen.budgeted, idGen.waitBudget = true, d
+	defer func() { idGen.budgeted, idGen.waitBudget = false, 0 }()
+	return idGen.generateLocked()
+}
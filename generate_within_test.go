@@ -0,0 +1,70 @@
+package generator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jayecc/mtl-snowflake/fakeclock"
+)
+
+// TestGenerateWithin 限定内部等待的总时长
+func TestGenerateWithin(t *testing.T) {
+	//每个时间单位4个序号，时钟位于时间单位起点
+	clock := fakeclock.New(time.Now().Truncate(time.Millisecond))
+	settings := Settings{TimeBit: 41, MachineIDBit: 19, TimelineBit: 1, SeqBit: 2, Epoch: DefaultEpoch, Clock: clock}
+	idGen, err := NewGeneratorWithSettings(0, settings)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if err := idGen.SetStrictMonotonic(true, time.Second); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	ids := make(map[int64]bool)
+	testCases := []struct {
+		name    string
+		prepare func()
+		d       time.Duration
+		wantErr error
+	}{
+		{name: "无需等待", prepare: func() {}, d: 0, wantErr: nil},
+		{name: "序号用完不允许等待", prepare: func() {
+			for i := 0; i < 3; i++ {
+				idGen.Generate()
+			}
+		}, d: 0, wantErr: ErrWaitTimeout},
+		{name: "序号用完额度不足", prepare: func() {}, d: 500 * time.Microsecond, wantErr: ErrWaitTimeout},
+		{name: "序号用完额度足够", prepare: func() {}, d: 2 * time.Millisecond, wantErr: nil},
+		{name: "时钟回退额度不足", prepare: func() { clock.Advance(-3 * time.Millisecond) }, d: time.Millisecond, wantErr: ErrWaitTimeout},
+		{name: "时钟回退额度足够", prepare: func() {}, d: 10 * time.Millisecond, wantErr: nil},
+	}
+	for _, tc := range testCases {
+		tc.prepare()
+		before := clock.Now()
+		id, err := idGen.GenerateWithin(tc.d)
+		if err != tc.wantErr {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, err, tc.wantErr)
+		}
+		if waited := time.Duration(clock.Now() - before); waited > tc.d {
+			t.Fatalf("【失败】-%s-等待时长-got:%v-want:<=%v", tc.name, waited, tc.d)
+		}
+		if err == nil {
+			if ids[id] {
+				t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, id, "不重复")
+			}
+			ids[id] = true
+		}
+	}
+
+	if timeout, ok := ErrWaitTimeout.(interface{ Timeout() bool }); !ok || !timeout.Timeout() {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "Timeout", ok, true)
+	}
+	if _, err := idGen.GenerateWithin(-time.Second); err == nil {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "负数", err, "error")
+	}
+	//不限额度的生成不受影响
+	clock.Advance(-3 * time.Millisecond)
+	if _, err := idGen.Generate(); err != nil {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "Generate", err, nil)
+	}
+}
@@ -0,0 +1,166 @@
+// Package gormsnow GORM插件：创建记录时为主键字段自动填充id
+//   - 在gorm:create之前执行，只填充值为零值的字段，已指定id的记录保持不变
+//   - 整数字段(int64、uint64、generator.ID等)直接写入id；字符串字段写入Encode编码后的id，缺省为十进制
+//   - 批量创建(切片、数组)时通过GenerateN一次生成，生成器未实现GenerateN时逐个生成
+//   - 主键字段须关闭自增，如 `gorm:"primaryKey;autoIncrement:false"`，否则数据库可能忽略填充的id
+//
+// 如：
+//
+//	db.Use(gormsnow.New(idGen))
+package gormsnow
+
+import (
+	"reflect"
+	"strconv"
+	"sync"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+
+	generator "github.com/jayecc/mtl-snowflake"
+)
+
+// callbackName 注册的create回调名称
+const callbackName = "gormsnow:assign_id"
+
+// Plugin 为主键字段填充id的GORM插件
+type Plugin struct {
+	Field  string             //填充的字段名，缺省为模型的主键字段
+	Encode func(int64) string //字符串字段的编码方式，nil表示十进制，也可使用generator.ID(id).Base62()等
+
+	idGen generator.Generator
+	mutex sync.RWMutex
+	model map[reflect.Type]generator.Generator //按模型指定的生成器
+}
+
+var _ gorm.Plugin = (*Plugin)(nil)
+
+// New 创建使用idGen生成id的插件，idGen可以是*generator.IDGenerator、降级链等任意Generator
+func New(idGen generator.Generator) *Plugin {
+	return &Plugin{idGen: idGen, model: make(map[reflect.Type]generator.Generator)}
+}
+
+// Name 插件名称
+func (p *Plugin) Name() string {
+	return "gormsnow"
+}
+
+// Initialize 注册create回调
+func (p *Plugin) Initialize(db *gorm.DB) error {
+	return db.Callback().Create().Before("gorm:create").Register(callbackName, p.assign)
+}
+
+// UseFor 为model类型的记录使用单独的生成器，如不同的表使用不同的id结构或机器ID
+//   - model为模型的零值或指针，如 &Order{}；idGen为nil时恢复使用共享的生成器
+func (p *Plugin) UseFor(model interface{}, idGen generator.Generator) {
+	t := modelType(model)
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if idGen == nil {
+		delete(p.model, t)
+		return
+	}
+	p.model[t] = idGen
+}
+
+// generatorFor 模型使用的生成器
+func (p *Plugin) generatorFor(t reflect.Type) generator.Generator {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	if idGen, ok := p.model[t]; ok {
+		return idGen
+	}
+	return p.idGen
+}
+
+// modelType 去掉指针后的类型
+func modelType(model interface{}) reflect.Type {
+	t := reflect.TypeOf(model)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+// assign 为待创建记录中值为零值的字段填充id，出错时通过db.AddError中止创建
+func (p *Plugin) assign(db *gorm.DB) {
+	if db.Error != nil || db.Statement.Schema == nil {
+		return
+	}
+	field := db.Statement.Schema.PrioritizedPrimaryField
+	if p.Field != "" {
+		field = db.Statement.Schema.LookUpField(p.Field)
+	}
+	if field == nil {
+		return
+	}
+	switch field.FieldType.Kind() {
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64, reflect.String:
+	default:
+		return
+	}
+
+	ctx := db.Statement.Context
+	rv := db.Statement.ReflectValue
+	var targets []reflect.Value
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			elem := reflect.Indirect(rv.Index(i))
+			if elem.Kind() != reflect.Struct {
+				continue
+			}
+			if _, zero := field.ValueOf(ctx, elem); zero {
+				targets = append(targets, elem)
+			}
+		}
+	case reflect.Struct:
+		if _, zero := field.ValueOf(ctx, rv); zero {
+			targets = append(targets, rv)
+		}
+	}
+	if len(targets) == 0 {
+		return
+	}
+
+	ids, err := generate(p.generatorFor(db.Statement.Schema.ModelType), len(targets))
+	if err != nil {
+		db.AddError(err)
+		return
+	}
+	for i, target := range targets {
+		if err := field.Set(ctx, target, p.value(field, ids[i])); err != nil {
+			db.AddError(err)
+			return
+		}
+	}
+}
+
+// value 按字段类型转换id
+func (p *Plugin) value(field *schema.Field, id int64) interface{} {
+	if field.FieldType.Kind() != reflect.String {
+		return id
+	}
+	if p.Encode != nil {
+		return p.Encode(id)
+	}
+	return strconv.FormatInt(id, 10)
+}
+
+// generate 生成n个id，生成器实现了GenerateN时一次生成
+func generate(idGen generator.Generator, n int) ([]int64, error) {
+	if batch, ok := idGen.(interface {
+		GenerateN(n int) ([]int64, error)
+	}); ok && n > 1 {
+		return batch.GenerateN(n)
+	}
+	ids := make([]int64, n)
+	for i := range ids {
+		id, err := idGen.Generate()
+		if err != nil {
+			return nil, err
+		}
+		ids[i] = int64(id)
+	}
+	return ids, nil
+}
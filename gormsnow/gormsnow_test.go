@@ -0,0 +1,116 @@
+package gormsnow
+
+import (
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/utils/tests"
+
+	generator "github.com/jayecc/mtl-snowflake"
+)
+
+type order struct {
+	ID   int64 `gorm:"primaryKey;autoIncrement:false"`
+	Name string
+}
+
+type account struct {
+	ID   string `gorm:"primaryKey"`
+	Name string
+}
+
+type event struct {
+	ID generator.ID `gorm:"primaryKey;autoIncrement:false"`
+}
+
+type failingGenerator struct{}
+
+func (failingGenerator) Generate() (generator.ID, error) { return 0, errors.New("生成失败") }
+
+// newDB 不连接数据库的GORM实例，只执行回调并构造SQL
+func newDB(t *testing.T, p *Plugin) *gorm.DB {
+	db, err := gorm.Open(tests.DummyDialector{}, &gorm.Config{DryRun: true})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if err := db.Use(p); err != nil {
+		t.Fatal(err.Error())
+	}
+	return db
+}
+
+// TestAssign 创建时填充零值的主键
+func TestAssign(t *testing.T) {
+	idGen, _ := generator.NewGenerator(7)
+	p := New(idGen)
+	db := newDB(t, p)
+
+	o := order{Name: "a"}
+	if err := db.Create(&o).Error; err != nil {
+		t.Fatal(err.Error())
+	}
+	if o.ID <= 0 || idGen.Decompose(o.ID).MachineID != 7 {
+		t.Fatalf("【失败】-%s-got:%v", "整数主键", o.ID)
+	}
+
+	preset := order{ID: 42}
+	db.Create(&preset)
+	if preset.ID != 42 {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "已指定的id保持不变", preset.ID, 42)
+	}
+
+	orders := []order{{Name: "b"}, {ID: 1}, {Name: "c"}}
+	db.Create(&orders)
+	if orders[0].ID <= 0 || orders[1].ID != 1 || orders[2].ID <= orders[0].ID {
+		t.Fatalf("【失败】-%s-got:%+v", "批量创建", orders)
+	}
+
+	a := account{}
+	db.Create(&a)
+	if id, err := generator.ParseID(a.ID); err != nil || id <= 0 {
+		t.Fatalf("【失败】-%s-got:%v", "字符串主键", a.ID)
+	}
+	p.Encode = func(id int64) string { return generator.ID(id).Base62() }
+	b := account{}
+	db.Create(&b)
+	if len(b.ID) == 0 || len(b.ID) >= len(a.ID) {
+		t.Fatalf("【失败】-%s-got:%v", "自定义编码", b.ID)
+	}
+
+	e := event{}
+	db.Create(&e)
+	if e.ID <= 0 {
+		t.Fatalf("【失败】-%s-got:%v", "generator.ID主键", e.ID)
+	}
+}
+
+// TestUseFor 按模型使用单独的生成器，生成失败时中止创建
+func TestUseFor(t *testing.T) {
+	shared, _ := generator.NewGenerator(1)
+	orders, _ := generator.NewGenerator(2)
+	p := New(shared)
+	p.UseFor(&order{}, orders)
+	db := newDB(t, p)
+
+	o, e := order{}, event{}
+	db.Create(&o)
+	db.Create(&e)
+	if got := orders.Decompose(o.ID).MachineID; got != 2 {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "模型的生成器", got, 2)
+	}
+	if got := shared.Decompose(e.ID.Int64()).MachineID; got != 1 {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "共享的生成器", got, 1)
+	}
+
+	p.UseFor(order{}, failingGenerator{})
+	if err := db.Create(&order{}).Error; err == nil {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "生成失败", err, "error")
+	}
+	p.UseFor(&order{}, nil)
+	o = order{}
+	db.Create(&o)
+	if got := shared.Decompose(o.ID).MachineID; got != 1 {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "恢复共享的生成器", got, 1)
+	}
+}
@@ -0,0 +1,152 @@
+package generator
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// IDEncoding id的字符串形式
+type IDEncoding int
+
+const (
+	EncodingDecimal    IDEncoding = iota //十进制
+	EncodingBase62                       //EncodeBase62
+	EncodingBase58                       //EncodeBase58
+	EncodingReadable                     //ToReadable
+	EncodingReadableV2                   //ToReadableV2
+)
+
+// String 编码名称
+func (e IDEncoding) String() string {
+	switch e {
+	case EncodingDecimal:
+		return "decimal"
+	case EncodingBase62:
+		return "base62"
+	case EncodingBase58:
+		return "base58"
+	case EncodingReadable:
+		return "readable"
+	case EncodingReadableV2:
+		return "readable-v2"
+	}
+	return fmt.Sprintf("IDEncoding(%d)", int(e))
+}
+
+// ParseIDEncoding 解析编码名称：decimal、base62、base58、readable、readable-v2
+func ParseIDEncoding(name string) (IDEncoding, error) {
+	switch strings.ToLower(name) {
+	case "decimal":
+		return EncodingDecimal, nil
+	case "base62":
+		return EncodingBase62, nil
+	case "base58":
+		return EncodingBase58, nil
+	case "readable":
+		return EncodingReadable, nil
+	case "readable-v2":
+		return EncodingReadableV2, nil
+	}
+	return 0, errorf(CodeUnsupportedEncoding, name)
+}
+
+// IDGrammar 字符串形式id的语法，供API网关、前端等在请求到达服务前拒绝格式错误的id
+//   - Pattern为带锚点的正则表达式，只使用RE2与JavaScript共有的语法，可直接用于前端校验
+//   - 十进制、Base62、Base58长度不固定，正则表达式无法限制数值范围，Match额外校验不超过int64
+//   - 可读格式长度固定，MinLength等于MaxLength；正则表达式只校验形式，不校验日期是否有效
+type IDGrammar struct {
+	Encoding  IDEncoding
+	MinLength int
+	MaxLength int
+	Pattern   string
+
+	re *regexp.Regexp
+}
+
+// Grammar 当前配置下编码encoding的语法
+func (idGen *IDGenerator) Grammar(encoding IDEncoding) (IDGrammar, error) {
+	g := IDGrammar{Encoding: encoding}
+	switch encoding {
+	case EncodingDecimal, EncodingBase62, EncodingBase58:
+		//各部分位数之和固定为63，id的取值范围为[0, MaxInt64]
+		alphabet, first := "0-9", "1-9"
+		switch encoding {
+		case EncodingBase62:
+			alphabet, first = "0-9A-Za-z", "1-9A-Za-z"
+		case EncodingBase58:
+			alphabet, first = "1-9A-HJ-NP-Za-km-z", "2-9A-HJ-NP-Za-km-z"
+		}
+		g.MinLength = 1
+		g.MaxLength = len(idGen.encode(encoding, int64(^uint64(0)>>1)))
+		g.Pattern = fmt.Sprintf("^(%s|[%s][%s]{0,%d})$", idGen.encode(encoding, 0), first, alphabet, g.MaxLength-1)
+	case EncodingReadable:
+		subWidth, _, _, _ := idGen.readableV2Widths()
+		inTimeDigit := len(strconv.FormatInt(int64(1)<<(63-idGen.settings.TimeBit)-1, 10))
+		g.MinLength = len(readableLayout) + subWidth + inTimeDigit
+		g.MaxLength = g.MinLength
+		g.Pattern = fmt.Sprintf("^[0-9]{%d}$", g.MinLength)
+	case EncodingReadableV2:
+		subWidth, machineWidth, timelineWidth, seqWidth := idGen.readableV2Widths()
+		versionWidth, datacenterWidth := idGen.readableV2NodeWidths()
+		var b strings.Builder
+		b.WriteString("^")
+		if versionWidth > 0 {
+			fmt.Fprintf(&b, "v[0-9]{%d}-", versionWidth)
+			g.MinLength += len("v-") + versionWidth
+		}
+		b.WriteString("[0-9]{8}T[0-9]{6}")
+		g.MinLength += len(readableV2Layout)
+		if subWidth > 0 {
+			fmt.Fprintf(&b, `\.[0-9]{%d}`, subWidth)
+			g.MinLength += 1 + subWidth
+		}
+		if datacenterWidth > 0 {
+			fmt.Fprintf(&b, "-d[0-9]{%d}", datacenterWidth)
+			g.MinLength += len("-d") + datacenterWidth
+		}
+		fmt.Fprintf(&b, "-m[0-9]{%d}-t[0-9]{%d}-s[0-9]{%d}", machineWidth, timelineWidth, seqWidth)
+		g.MinLength += 3*len("-m") + machineWidth + timelineWidth + seqWidth
+		if idGen.settings.FlagBit {
+			b.WriteString("-f[01]")
+			g.MinLength += len("-f0")
+		}
+		b.WriteString("$")
+		g.MaxLength = g.MinLength
+		g.Pattern = b.String()
+	default:
+		return IDGrammar{}, errorf(CodeUnsupportedEncoding, encoding)
+	}
+	g.re = regexp.MustCompile(g.Pattern)
+	return g, nil
+}
+
+// encode 按编码输出id，只用于数字形式的编码
+func (idGen *IDGenerator) encode(encoding IDEncoding, id int64) string {
+	switch encoding {
+	case EncodingBase62:
+		return EncodeBase62(id)
+	case EncodingBase58:
+		return EncodeBase58(id)
+	}
+	return strconv.FormatInt(id, 10)
+}
+
+// Match s是否符合语法：长度、正则表达式，数字形式的编码还须不超过int64
+func (g IDGrammar) Match(s string) bool {
+	if len(s) < g.MinLength || len(s) > g.MaxLength || g.re == nil || !g.re.MatchString(s) {
+		return false
+	}
+	var err error
+	var id int64
+	switch g.Encoding {
+	case EncodingDecimal:
+		id, err = strconv.ParseInt(s, 10, 64)
+	case EncodingBase62:
+		id, err = DecodeBase62(s)
+	case EncodingBase58:
+		id, err = DecodeBase58(s)
+	}
+	return err == nil && id >= 0
+}
@@ -0,0 +1,102 @@
+package generator
+
+import (
+	"strconv"
+	"testing"
+)
+
+// TestGrammar 各配置、各编码生成的id均符合语法
+func TestGrammar(t *testing.T) {
+	flagged := Settings{TimeBit: 41, MachineIDBit: 9, TimelineBit: 1, SeqBit: 11, Epoch: DefaultEpoch, FlagBit: true}
+	node := Settings{VersionBit: 2, Version: 1, TimeBit: 41, DatacenterBit: 3, DatacenterID: 1, MachineIDBit: 6, TimelineBit: 1, SeqBit: 10, Epoch: DefaultEpoch}
+	testCases := []struct {
+		name     string
+		settings Settings
+	}{
+		{name: "默认配置", settings: *DefaultSettings},
+		{name: "秒级时间单位", settings: *SecondSettings},
+		{name: "标记位", settings: flagged},
+		{name: "版本号及数据中心", settings: node},
+	}
+	encode := map[IDEncoding]func(idGen *IDGenerator, id int64) string{
+		EncodingDecimal:    func(idGen *IDGenerator, id int64) string { return strconv.FormatInt(id, 10) },
+		EncodingBase62:     func(idGen *IDGenerator, id int64) string { return EncodeBase62(id) },
+		EncodingBase58:     func(idGen *IDGenerator, id int64) string { return EncodeBase58(id) },
+		EncodingReadable:   func(idGen *IDGenerator, id int64) string { return idGen.ToReadable(id) },
+		EncodingReadableV2: func(idGen *IDGenerator, id int64) string { return idGen.ToReadableV2(id) },
+	}
+	for _, tc := range testCases {
+		idGen, _ := NewGeneratorWithSettings(3, tc.settings)
+		presets := idGen.settings.presets
+		ids := []int64{idGen.compose(0, 0, 0), idGen.compose(presets.maxTime, 1, 1)}
+		//其他数据中心的id
+		ids = append(ids, idGen.compose(1, 1, 1)&^presets.maskDatacenter|presets.maxDatacenter<<presets.shiftDatacenterBit)
+		for i := 0; i < 100; i++ {
+			id, _ := idGen.Generate()
+			ids = append(ids, int64(id))
+		}
+		for encoding, f := range encode {
+			g, err := idGen.Grammar(encoding)
+			if err != nil {
+				t.Fatalf("【失败】-%s-%s-got:%v-want:%v", tc.name, encoding, err, nil)
+			}
+			for _, id := range ids {
+				s := f(idGen, id)
+				if !g.Match(s) {
+					t.Fatalf("【失败】-%s-%s-got:%v-want:%v(%s)", tc.name, encoding, s, "匹配", g.Pattern)
+				}
+			}
+		}
+	}
+}
+
+// TestGrammarReject 拒绝格式错误的id
+func TestGrammarReject(t *testing.T) {
+	idGen, _ := NewGenerator(3)
+	testCases := []struct {
+		name     string
+		encoding IDEncoding
+		s        string
+		want     bool
+	}{
+		{name: "十进制最大值", encoding: EncodingDecimal, s: "9223372036854775807", want: true},
+		{name: "十进制超出int64", encoding: EncodingDecimal, s: "9223372036854775808", want: false},
+		{name: "十进制前导零", encoding: EncodingDecimal, s: "0123", want: false},
+		{name: "十进制负数", encoding: EncodingDecimal, s: "-1", want: false},
+		{name: "十进制空串", encoding: EncodingDecimal, s: "", want: false},
+		{name: "Base62超出int64", encoding: EncodingBase62, s: "LygHa16AHYF", want: false},
+		{name: "Base62非法字符", encoding: EncodingBase62, s: "abc_", want: false},
+		{name: "Base58易混淆字符", encoding: EncodingBase58, s: "2O", want: false},
+		{name: "可读格式长度错误", encoding: EncodingReadable, s: "2020010100000000000000", want: false},
+		{name: "可读格式v2缺少序号", encoding: EncodingReadableV2, s: "20240904T190147.123-m012-t1", want: false},
+		{name: "可读格式v2", encoding: EncodingReadableV2, s: "20240904T190147.123-m012-t1-s0042", want: true},
+		{name: "可读格式v2宽度错误", encoding: EncodingReadableV2, s: "20240904T190147.123-m12-t1-s0042", want: false},
+	}
+	for _, tc := range testCases {
+		g, _ := idGen.Grammar(tc.encoding)
+		if got := g.Match(tc.s); got != tc.want {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, got, tc.want)
+		}
+	}
+
+	lengths := []struct {
+		encoding IDEncoding
+		min, max int
+	}{
+		{encoding: EncodingDecimal, min: 1, max: 19},
+		{encoding: EncodingBase62, min: 1, max: 11},
+		{encoding: EncodingReadableV2, min: 33, max: 33},
+	}
+	for _, tc := range lengths {
+		g, _ := idGen.Grammar(tc.encoding)
+		if g.MinLength != tc.min || g.MaxLength != tc.max {
+			t.Fatalf("【失败】-%s-got:%v,%v-want:%v,%v", tc.encoding, g.MinLength, g.MaxLength, tc.min, tc.max)
+		}
+	}
+	if _, err := idGen.Grammar(IDEncoding(99)); err == nil {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "未知编码", err, "error")
+	}
+	if e, err := ParseIDEncoding("readable-v2"); err != nil || e != EncodingReadableV2 {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "ParseIDEncoding", e, EncodingReadableV2)
+	}
+}
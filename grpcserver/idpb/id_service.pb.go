@@ -0,0 +1,559 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        v5.29.3
+// source: id_service.proto
+
+package idpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GenerateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GenerateRequest) Reset() {
+	*x = GenerateRequest{}
+	mi := &file_id_service_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GenerateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerateRequest) ProtoMessage() {}
+
+func (x *GenerateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_id_service_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerateRequest.ProtoReflect.Descriptor instead.
+func (*GenerateRequest) Descriptor() ([]byte, []int) {
+	return file_id_service_proto_rawDescGZIP(), []int{0}
+}
+
+type GenerateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GenerateResponse) Reset() {
+	*x = GenerateResponse{}
+	mi := &file_id_service_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GenerateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerateResponse) ProtoMessage() {}
+
+func (x *GenerateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_id_service_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerateResponse.ProtoReflect.Descriptor instead.
+func (*GenerateResponse) Descriptor() ([]byte, []int) {
+	return file_id_service_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GenerateResponse) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type GenerateBatchRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 生成的数量
+	Count int32 `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	// 每批返回的数量，不大于0时使用服务端的默认值
+	ChunkSize     int32 `protobuf:"varint,2,opt,name=chunk_size,json=chunkSize,proto3" json:"chunk_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GenerateBatchRequest) Reset() {
+	*x = GenerateBatchRequest{}
+	mi := &file_id_service_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GenerateBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerateBatchRequest) ProtoMessage() {}
+
+func (x *GenerateBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_id_service_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerateBatchRequest.ProtoReflect.Descriptor instead.
+func (*GenerateBatchRequest) Descriptor() ([]byte, []int) {
+	return file_id_service_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GenerateBatchRequest) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *GenerateBatchRequest) GetChunkSize() int32 {
+	if x != nil {
+		return x.ChunkSize
+	}
+	return 0
+}
+
+type GenerateBatchResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ids           []int64                `protobuf:"varint,1,rep,packed,name=ids,proto3" json:"ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GenerateBatchResponse) Reset() {
+	*x = GenerateBatchResponse{}
+	mi := &file_id_service_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GenerateBatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerateBatchResponse) ProtoMessage() {}
+
+func (x *GenerateBatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_id_service_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerateBatchResponse.ProtoReflect.Descriptor instead.
+func (*GenerateBatchResponse) Descriptor() ([]byte, []int) {
+	return file_id_service_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GenerateBatchResponse) GetIds() []int64 {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+type DecomposeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DecomposeRequest) Reset() {
+	*x = DecomposeRequest{}
+	mi := &file_id_service_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DecomposeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DecomposeRequest) ProtoMessage() {}
+
+func (x *DecomposeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_id_service_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DecomposeRequest.ProtoReflect.Descriptor instead.
+func (*DecomposeRequest) Descriptor() ([]byte, []int) {
+	return file_id_service_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *DecomposeRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type DecomposeResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// 时间(自基准时间起的时间单位数)
+	Time int64 `protobuf:"varint,2,opt,name=time,proto3" json:"time,omitempty"`
+	// 生成时间
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	MachineId     int64                  `protobuf:"varint,4,opt,name=machine_id,json=machineId,proto3" json:"machine_id,omitempty"`
+	Timeline      int64                  `protobuf:"varint,5,opt,name=timeline,proto3" json:"timeline,omitempty"`
+	Seq           int64                  `protobuf:"varint,6,opt,name=seq,proto3" json:"seq,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DecomposeResponse) Reset() {
+	*x = DecomposeResponse{}
+	mi := &file_id_service_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DecomposeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DecomposeResponse) ProtoMessage() {}
+
+func (x *DecomposeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_id_service_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DecomposeResponse.ProtoReflect.Descriptor instead.
+func (*DecomposeResponse) Descriptor() ([]byte, []int) {
+	return file_id_service_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *DecomposeResponse) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *DecomposeResponse) GetTime() int64 {
+	if x != nil {
+		return x.Time
+	}
+	return 0
+}
+
+func (x *DecomposeResponse) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *DecomposeResponse) GetMachineId() int64 {
+	if x != nil {
+		return x.MachineId
+	}
+	return 0
+}
+
+func (x *DecomposeResponse) GetTimeline() int64 {
+	if x != nil {
+		return x.Timeline
+	}
+	return 0
+}
+
+func (x *DecomposeResponse) GetSeq() int64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+type HealthRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HealthRequest) Reset() {
+	*x = HealthRequest{}
+	mi := &file_id_service_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HealthRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HealthRequest) ProtoMessage() {}
+
+func (x *HealthRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_id_service_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HealthRequest.ProtoReflect.Descriptor instead.
+func (*HealthRequest) Descriptor() ([]byte, []int) {
+	return file_id_service_proto_rawDescGZIP(), []int{6}
+}
+
+type HealthResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 是否可以生成id
+	Serving bool `protobuf:"varint,1,opt,name=serving,proto3" json:"serving,omitempty"`
+	// 不可生成的原因
+	Reason          string `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	MachineId       int64  `protobuf:"varint,3,opt,name=machine_id,json=machineId,proto3" json:"machine_id,omitempty"`
+	CurrentTimeline int64  `protobuf:"varint,4,opt,name=current_timeline,json=currentTimeline,proto3" json:"current_timeline,omitempty"`
+	// 可用于处理时钟回退的时间线数(不含当前时间线)
+	AvailableTimelines int64 `protobuf:"varint,5,opt,name=available_timelines,json=availableTimelines,proto3" json:"available_timelines,omitempty"`
+	// 时间部分用尽前剩余的秒数
+	RemainingSeconds float64 `protobuf:"fixed64,6,opt,name=remaining_seconds,json=remainingSeconds,proto3" json:"remaining_seconds,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *HealthResponse) Reset() {
+	*x = HealthResponse{}
+	mi := &file_id_service_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HealthResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HealthResponse) ProtoMessage() {}
+
+func (x *HealthResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_id_service_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HealthResponse.ProtoReflect.Descriptor instead.
+func (*HealthResponse) Descriptor() ([]byte, []int) {
+	return file_id_service_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *HealthResponse) GetServing() bool {
+	if x != nil {
+		return x.Serving
+	}
+	return false
+}
+
+func (x *HealthResponse) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *HealthResponse) GetMachineId() int64 {
+	if x != nil {
+		return x.MachineId
+	}
+	return 0
+}
+
+func (x *HealthResponse) GetCurrentTimeline() int64 {
+	if x != nil {
+		return x.CurrentTimeline
+	}
+	return 0
+}
+
+func (x *HealthResponse) GetAvailableTimelines() int64 {
+	if x != nil {
+		return x.AvailableTimelines
+	}
+	return 0
+}
+
+func (x *HealthResponse) GetRemainingSeconds() float64 {
+	if x != nil {
+		return x.RemainingSeconds
+	}
+	return 0
+}
+
+var File_id_service_proto protoreflect.FileDescriptor
+
+const file_id_service_proto_rawDesc = "" +
+	"\n" +
+	"\x10id_service.proto\x12\x0fmtlsnowflake.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\x11\n" +
+	"\x0fGenerateRequest\"\"\n" +
+	"\x10GenerateResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"K\n" +
+	"\x14GenerateBatchRequest\x12\x14\n" +
+	"\x05count\x18\x01 \x01(\x05R\x05count\x12\x1d\n" +
+	"\n" +
+	"chunk_size\x18\x02 \x01(\x05R\tchunkSize\")\n" +
+	"\x15GenerateBatchResponse\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\x03R\x03ids\"\"\n" +
+	"\x10DecomposeRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"\xbe\x01\n" +
+	"\x11DecomposeResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04time\x18\x02 \x01(\x03R\x04time\x128\n" +
+	"\ttimestamp\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x1d\n" +
+	"\n" +
+	"machine_id\x18\x04 \x01(\x03R\tmachineId\x12\x1a\n" +
+	"\btimeline\x18\x05 \x01(\x03R\btimeline\x12\x10\n" +
+	"\x03seq\x18\x06 \x01(\x03R\x03seq\"\x0f\n" +
+	"\rHealthRequest\"\xea\x01\n" +
+	"\x0eHealthResponse\x12\x18\n" +
+	"\aserving\x18\x01 \x01(\bR\aserving\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\x12\x1d\n" +
+	"\n" +
+	"machine_id\x18\x03 \x01(\x03R\tmachineId\x12)\n" +
+	"\x10current_timeline\x18\x04 \x01(\x03R\x0fcurrentTimeline\x12/\n" +
+	"\x13available_timelines\x18\x05 \x01(\x03R\x12availableTimelines\x12+\n" +
+	"\x11remaining_seconds\x18\x06 \x01(\x01R\x10remainingSeconds2\xdd\x02\n" +
+	"\tIDService\x12O\n" +
+	"\bGenerate\x12 .mtlsnowflake.v1.GenerateRequest\x1a!.mtlsnowflake.v1.GenerateResponse\x12`\n" +
+	"\rGenerateBatch\x12%.mtlsnowflake.v1.GenerateBatchRequest\x1a&.mtlsnowflake.v1.GenerateBatchResponse0\x01\x12R\n" +
+	"\tDecompose\x12!.mtlsnowflake.v1.DecomposeRequest\x1a\".mtlsnowflake.v1.DecomposeResponse\x12I\n" +
+	"\x06Health\x12\x1e.mtlsnowflake.v1.HealthRequest\x1a\x1f.mtlsnowflake.v1.HealthResponseB1Z/github.com/jayecc/mtl-snowflake/grpcserver/idpbb\x06proto3"
+
+var (
+	file_id_service_proto_rawDescOnce sync.Once
+	file_id_service_proto_rawDescData []byte
+)
+
+func file_id_service_proto_rawDescGZIP() []byte {
+	file_id_service_proto_rawDescOnce.Do(func() {
+		file_id_service_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_id_service_proto_rawDesc), len(file_id_service_proto_rawDesc)))
+	})
+	return file_id_service_proto_rawDescData
+}
+
+var file_id_service_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_id_service_proto_goTypes = []any{
+	(*GenerateRequest)(nil),       // 0: mtlsnowflake.v1.GenerateRequest
+	(*GenerateResponse)(nil),      // 1: mtlsnowflake.v1.GenerateResponse
+	(*GenerateBatchRequest)(nil),  // 2: mtlsnowflake.v1.GenerateBatchRequest
+	(*GenerateBatchResponse)(nil), // 3: mtlsnowflake.v1.GenerateBatchResponse
+	(*DecomposeRequest)(nil),      // 4: mtlsnowflake.v1.DecomposeRequest
+	(*DecomposeResponse)(nil),     // 5: mtlsnowflake.v1.DecomposeResponse
+	(*HealthRequest)(nil),         // 6: mtlsnowflake.v1.HealthRequest
+	(*HealthResponse)(nil),        // 7: mtlsnowflake.v1.HealthResponse
+	(*timestamppb.Timestamp)(nil), // 8: google.protobuf.Timestamp
+}
+var file_id_service_proto_depIdxs = []int32{
+	8, // 0: mtlsnowflake.v1.DecomposeResponse.timestamp:type_name -> google.protobuf.Timestamp
+	0, // 1: mtlsnowflake.v1.IDService.Generate:input_type -> mtlsnowflake.v1.GenerateRequest
+	2, // 2: mtlsnowflake.v1.IDService.GenerateBatch:input_type -> mtlsnowflake.v1.GenerateBatchRequest
+	4, // 3: mtlsnowflake.v1.IDService.Decompose:input_type -> mtlsnowflake.v1.DecomposeRequest
+	6, // 4: mtlsnowflake.v1.IDService.Health:input_type -> mtlsnowflake.v1.HealthRequest
+	1, // 5: mtlsnowflake.v1.IDService.Generate:output_type -> mtlsnowflake.v1.GenerateResponse
+	3, // 6: mtlsnowflake.v1.IDService.GenerateBatch:output_type -> mtlsnowflake.v1.GenerateBatchResponse
+	5, // 7: mtlsnowflake.v1.IDService.Decompose:output_type -> mtlsnowflake.v1.DecomposeResponse
+	7, // 8: mtlsnowflake.v1.IDService.Health:output_type -> mtlsnowflake.v1.HealthResponse
+	5, // [5:9] is the sub-list for method output_type
+	1, // [1:5] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_id_service_proto_init() }
+func file_id_service_proto_init() {
+	if File_id_service_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_id_service_proto_rawDesc), len(file_id_service_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   8,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_id_service_proto_goTypes,
+		DependencyIndexes: file_id_service_proto_depIdxs,
+		MessageInfos:      file_id_service_proto_msgTypes,
+	}.Build()
+	File_id_service_proto = out.File
+	file_id_service_proto_goTypes = nil
+	file_id_service_proto_depIdxs = nil
+}
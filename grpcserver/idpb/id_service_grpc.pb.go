@@ -0,0 +1,251 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: id_service.proto
+
+package idpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	IDService_Generate_FullMethodName      = "/mtlsnowflake.v1.IDService/Generate"
+	IDService_GenerateBatch_FullMethodName = "/mtlsnowflake.v1.IDService/GenerateBatch"
+	IDService_Decompose_FullMethodName     = "/mtlsnowflake.v1.IDService/Decompose"
+	IDService_Health_FullMethodName        = "/mtlsnowflake.v1.IDService/Health"
+)
+
+// IDServiceClient is the client API for IDService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// IDService id生成服务
+type IDServiceClient interface {
+	// Generate 生成一个id
+	Generate(ctx context.Context, in *GenerateRequest, opts ...grpc.CallOption) (*GenerateResponse, error)
+	// GenerateBatch 批量生成count个id，按chunk_size分批流式返回
+	GenerateBatch(ctx context.Context, in *GenerateBatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[GenerateBatchResponse], error)
+	// Decompose 解析id的各部分
+	Decompose(ctx context.Context, in *DecomposeRequest, opts ...grpc.CallOption) (*DecomposeResponse, error)
+	// Health 生成器健康状况
+	Health(ctx context.Context, in *HealthRequest, opts ...grpc.CallOption) (*HealthResponse, error)
+}
+
+type iDServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewIDServiceClient(cc grpc.ClientConnInterface) IDServiceClient {
+	return &iDServiceClient{cc}
+}
+
+func (c *iDServiceClient) Generate(ctx context.Context, in *GenerateRequest, opts ...grpc.CallOption) (*GenerateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GenerateResponse)
+	err := c.cc.Invoke(ctx, IDService_Generate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *iDServiceClient) GenerateBatch(ctx context.Context, in *GenerateBatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[GenerateBatchResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &IDService_ServiceDesc.Streams[0], IDService_GenerateBatch_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[GenerateBatchRequest, GenerateBatchResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type IDService_GenerateBatchClient = grpc.ServerStreamingClient[GenerateBatchResponse]
+
+func (c *iDServiceClient) Decompose(ctx context.Context, in *DecomposeRequest, opts ...grpc.CallOption) (*DecomposeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DecomposeResponse)
+	err := c.cc.Invoke(ctx, IDService_Decompose_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *iDServiceClient) Health(ctx context.Context, in *HealthRequest, opts ...grpc.CallOption) (*HealthResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HealthResponse)
+	err := c.cc.Invoke(ctx, IDService_Health_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// IDServiceServer is the server API for IDService service.
+// All implementations must embed UnimplementedIDServiceServer
+// for forward compatibility.
+//
+// IDService id生成服务
+type IDServiceServer interface {
+	// Generate 生成一个id
+	Generate(context.Context, *GenerateRequest) (*GenerateResponse, error)
+	// GenerateBatch 批量生成count个id，按chunk_size分批流式返回
+	GenerateBatch(*GenerateBatchRequest, grpc.ServerStreamingServer[GenerateBatchResponse]) error
+	// Decompose 解析id的各部分
+	Decompose(context.Context, *DecomposeRequest) (*DecomposeResponse, error)
+	// Health 生成器健康状况
+	Health(context.Context, *HealthRequest) (*HealthResponse, error)
+	mustEmbedUnimplementedIDServiceServer()
+}
+
+// UnimplementedIDServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedIDServiceServer struct{}
+
+func (UnimplementedIDServiceServer) Generate(context.Context, *GenerateRequest) (*GenerateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Generate not implemented")
+}
+func (UnimplementedIDServiceServer) GenerateBatch(*GenerateBatchRequest, grpc.ServerStreamingServer[GenerateBatchResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method GenerateBatch not implemented")
+}
+func (UnimplementedIDServiceServer) Decompose(context.Context, *DecomposeRequest) (*DecomposeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Decompose not implemented")
+}
+func (UnimplementedIDServiceServer) Health(context.Context, *HealthRequest) (*HealthResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Health not implemented")
+}
+func (UnimplementedIDServiceServer) mustEmbedUnimplementedIDServiceServer() {}
+func (UnimplementedIDServiceServer) testEmbeddedByValue()                   {}
+
+// UnsafeIDServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to IDServiceServer will
+// result in compilation errors.
+type UnsafeIDServiceServer interface {
+	mustEmbedUnimplementedIDServiceServer()
+}
+
+func RegisterIDServiceServer(s grpc.ServiceRegistrar, srv IDServiceServer) {
+	// If the following call pancis, it indicates UnimplementedIDServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&IDService_ServiceDesc, srv)
+}
+
+func _IDService_Generate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GenerateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IDServiceServer).Generate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IDService_Generate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IDServiceServer).Generate(ctx, req.(*GenerateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IDService_GenerateBatch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GenerateBatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(IDServiceServer).GenerateBatch(m, &grpc.GenericServerStream[GenerateBatchRequest, GenerateBatchResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type IDService_GenerateBatchServer = grpc.ServerStreamingServer[GenerateBatchResponse]
+
+func _IDService_Decompose_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DecomposeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IDServiceServer).Decompose(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IDService_Decompose_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IDServiceServer).Decompose(ctx, req.(*DecomposeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IDService_Health_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HealthRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IDServiceServer).Health(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IDService_Health_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IDServiceServer).Health(ctx, req.(*HealthRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// IDService_ServiceDesc is the grpc.ServiceDesc for IDService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var IDService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "mtlsnowflake.v1.IDService",
+	HandlerType: (*IDServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Generate",
+			Handler:    _IDService_Generate_Handler,
+		},
+		{
+			MethodName: "Decompose",
+			Handler:    _IDService_Decompose_Handler,
+		},
+		{
+			MethodName: "Health",
+			Handler:    _IDService_Health_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "GenerateBatch",
+			Handler:       _IDService_GenerateBatch_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "id_service.proto",
+}
@@ -0,0 +1,135 @@
+// Package grpcserver 以gRPC提供id生成服务，服务定义见idpb/id_service.proto，idpb同时包含生成的Go客户端
+//   - 生成失败返回codes.Unavailable，参数错误返回codes.InvalidArgument
+//   - GenerateBatch按批流式返回，大批量生成时客户端可边接收边处理
+package grpcserver
+
+//go:generate protoc -I idpb --go_out=idpb --go_opt=paths=source_relative --go-grpc_out=idpb --go-grpc_opt=paths=source_relative id_service.proto
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	generator "github.com/jayecc/mtl-snowflake"
+	"github.com/jayecc/mtl-snowflake/grpcserver/idpb"
+)
+
+const (
+	DefaultMaxBatch  = 100000 //GenerateBatch单次允许生成的最大数量
+	DefaultChunkSize = 1000   //GenerateBatch每批返回的数量
+)
+
+// Server IDService的实现
+type Server struct {
+	idpb.UnimplementedIDServiceServer
+	idGen     *generator.IDGenerator
+	MaxBatch  int //单次批量生成的最大数量，不大于0时使用DefaultMaxBatch
+	ChunkSize int //客户端未指定时每批返回的数量，不大于0时使用DefaultChunkSize
+}
+
+var _ idpb.IDServiceServer = (*Server)(nil)
+
+// New 创建使用idGen生成id的Server
+func New(idGen *generator.IDGenerator) *Server {
+	return &Server{idGen: idGen}
+}
+
+// Register 将Server注册到s
+func (srv *Server) Register(s *grpc.Server) {
+	idpb.RegisterIDServiceServer(s, srv)
+}
+
+// Generate 生成一个id
+func (srv *Server) Generate(ctx context.Context, req *idpb.GenerateRequest) (*idpb.GenerateResponse, error) {
+	id, err := srv.idGen.GenerateCtx(ctx)
+	if err != nil {
+		return nil, generateError(err)
+	}
+	return &idpb.GenerateResponse{Id: id}, nil
+}
+
+// GenerateBatch 批量生成count个id，按chunk_size分批返回，客户端取消时停止生成
+func (srv *Server) GenerateBatch(req *idpb.GenerateBatchRequest, stream idpb.IDService_GenerateBatchServer) error {
+	maxBatch := srv.MaxBatch
+	if maxBatch <= 0 {
+		maxBatch = DefaultMaxBatch
+	}
+	if req.Count <= 0 || int(req.Count) > maxBatch {
+		return status.Errorf(codes.InvalidArgument, "count须为1~%d", maxBatch)
+	}
+	chunkSize := int(req.ChunkSize)
+	if chunkSize <= 0 {
+		chunkSize = srv.ChunkSize
+	}
+	if chunkSize <= 0 {
+		chunkSize = DefaultChunkSize
+	}
+
+	for remaining := int(req.Count); remaining > 0; remaining -= chunkSize {
+		if err := stream.Context().Err(); err != nil {
+			return status.FromContextError(err).Err()
+		}
+		n := chunkSize
+		if remaining < n {
+			n = remaining
+		}
+		ids, err := srv.idGen.GenerateN(n)
+		if err != nil {
+			return generateError(err)
+		}
+		if err := stream.Send(&idpb.GenerateBatchResponse{Ids: ids}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Decompose 解析id的各部分
+func (srv *Server) Decompose(ctx context.Context, req *idpb.DecomposeRequest) (*idpb.DecomposeResponse, error) {
+	if req.Id < 0 {
+		return nil, status.Error(codes.InvalidArgument, "id不能为负数")
+	}
+	c := srv.idGen.Decompose(req.Id)
+	return &idpb.DecomposeResponse{
+		Id:        req.Id,
+		Time:      c.Time,
+		Timestamp: timestamppb.New(c.Timestamp),
+		MachineId: c.MachineID,
+		Timeline:  c.TimeLine,
+		Seq:       c.Seq,
+	}, nil
+}
+
+// Health 生成器健康状况：生成器失效(Fence、Close)或时间部分已用尽时serving为false
+func (srv *Server) Health(ctx context.Context, req *idpb.HealthRequest) (*idpb.HealthResponse, error) {
+	stats := srv.idGen.Stats()
+	resp := &idpb.HealthResponse{
+		Serving:            true,
+		MachineId:          stats.MachineID,
+		CurrentTimeline:    stats.CurrentTimeline,
+		AvailableTimelines: int64(stats.AvailableTimelines),
+		RemainingSeconds:   stats.Remaining.Seconds(),
+	}
+	if err := srv.idGen.Fenced(); err != nil {
+		resp.Serving, resp.Reason = false, err.Error()
+	} else if stats.Remaining <= 0 {
+		resp.Serving, resp.Reason = false, generator.ErrTimeOverflow.Error()
+	}
+	return resp, nil
+}
+
+// generateError 生成失败转换为codes.Unavailable，可重试的错误在描述中附带建议的重试间隔
+//   - 客户端取消或超时转换为codes.Canceled、codes.DeadlineExceeded
+func generateError(err error) error {
+	if err == context.Canceled || err == context.DeadlineExceeded {
+		return status.FromContextError(err).Err()
+	}
+	if retryAfter, ok := generator.RetryAfter(err); ok && retryAfter > 0 {
+		return status.Errorf(codes.Unavailable, "%s(retry after %s)", err, retryAfter.Round(time.Millisecond))
+	}
+	return status.Error(codes.Unavailable, err.Error())
+}
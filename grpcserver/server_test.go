@@ -0,0 +1,140 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	generator "github.com/jayecc/mtl-snowflake"
+	"github.com/jayecc/mtl-snowflake/grpcserver/idpb"
+)
+
+// newClient 在内存连接上启动服务，返回客户端
+func newClient(t *testing.T, srv *Server) idpb.IDServiceClient {
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer()
+	srv.Register(s)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	t.Cleanup(func() { conn.Close() })
+	return idpb.NewIDServiceClient(conn)
+}
+
+// TestServer 生成、解析、健康检查
+func TestServer(t *testing.T) {
+	idGen, _ := generator.NewGenerator(12)
+	client := newClient(t, New(idGen))
+	ctx := context.Background()
+
+	generated, err := client.Generate(ctx, &idpb.GenerateRequest{})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	got, err := client.Decompose(ctx, &idpb.DecomposeRequest{Id: generated.Id})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	want := idGen.Decompose(generated.Id)
+	testCases := []struct {
+		name string
+		got  interface{}
+		want interface{}
+	}{
+		{name: "时间", got: got.Time, want: want.Time},
+		{name: "生成时间", got: got.Timestamp.AsTime().UnixNano(), want: want.Timestamp.UnixNano()},
+		{name: "机器ID", got: got.MachineId, want: int64(12)},
+		{name: "时间线", got: got.Timeline, want: want.TimeLine},
+		{name: "序号", got: got.Seq, want: want.Seq},
+	}
+	for _, tc := range testCases {
+		if tc.got != tc.want {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, tc.got, tc.want)
+		}
+	}
+	if _, err := client.Decompose(ctx, &idpb.DecomposeRequest{Id: -1}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "解析负数", status.Code(err), codes.InvalidArgument)
+	}
+
+	health, err := client.Health(ctx, &idpb.HealthRequest{})
+	if err != nil || !health.Serving || health.MachineId != 12 || health.RemainingSeconds <= 0 {
+		t.Fatalf("【失败】-%s-got:%v,%v", "健康", health, err)
+	}
+	idGen.Fence(errors.New("租约已失效"))
+	health, _ = client.Health(ctx, &idpb.HealthRequest{})
+	if health.Serving || health.Reason != "租约已失效" {
+		t.Fatalf("【失败】-%s-got:%v", "失效", health)
+	}
+	if _, err := client.Generate(ctx, &idpb.GenerateRequest{}); status.Code(err) != codes.Unavailable {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "失效后生成", status.Code(err), codes.Unavailable)
+	}
+}
+
+// TestGenerateBatch 按批流式返回
+func TestGenerateBatch(t *testing.T) {
+	idGen, _ := generator.NewGenerator(12)
+	srv := New(idGen)
+	srv.MaxBatch = 5000
+	client := newClient(t, srv)
+
+	testCases := []struct {
+		name       string
+		req        *idpb.GenerateBatchRequest
+		wantChunks []int
+		wantCode   codes.Code
+	}{
+		{name: "默认每批数量", req: &idpb.GenerateBatchRequest{Count: 2500}, wantChunks: []int{1000, 1000, 500}},
+		{name: "指定每批数量", req: &idpb.GenerateBatchRequest{Count: 10, ChunkSize: 4}, wantChunks: []int{4, 4, 2}},
+		{name: "数量为0", req: &idpb.GenerateBatchRequest{Count: 0}, wantCode: codes.InvalidArgument},
+		{name: "数量过大", req: &idpb.GenerateBatchRequest{Count: 5001}, wantCode: codes.InvalidArgument},
+	}
+	for _, tc := range testCases {
+		stream, err := client.GenerateBatch(context.Background(), tc.req)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		var chunks []int
+		var last int64
+		for {
+			resp, err := stream.Recv()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				if status.Code(err) != tc.wantCode {
+					t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, status.Code(err), tc.wantCode)
+				}
+				break
+			}
+			for _, id := range resp.Ids {
+				if id <= last {
+					t.Fatalf("【失败】-%s-id未递增-got:%v-last:%v", tc.name, id, last)
+				}
+				last = id
+			}
+			chunks = append(chunks, len(resp.Ids))
+		}
+		if len(chunks) != len(tc.wantChunks) {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, chunks, tc.wantChunks)
+		}
+		for i := range chunks {
+			if chunks[i] != tc.wantChunks[i] {
+				t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, chunks, tc.wantChunks)
+			}
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package generator
+
+import (
+	"time"
+)
+
+// Hooks 生成器事件回调，用于记录日志或告警，未设置的回调不调用
+//   - 回调在独立goroutine中执行，不阻塞生成，也可以在回调中调用生成器
+type Hooks struct {
+	OnClockBackward    func(backward time.Duration, timeline int64) //检测到时钟回退，backward为回退时长，timeline为回退时所在的时间线
+	OnTimelineSwitch   func(from, to int64)                         //因时钟回退切换时间线
+	OnSeqExhausted     func(wait time.Duration)                     //当前时间单位的序号已用完，wait为等待下一个时间单位的时长
+	NearOverflowMargin time.Duration                                //时间部分剩余可用时长(见ExhaustionTime)小于该值(如1年)时调用OnTimeNearOverflow
+	OnTimeNearOverflow func(remaining time.Duration)                //时间部分即将用尽，设置时及生成时检查，只调用一次(切换到新基准时间后重新计算)
+	OnMachineIDRotate  func(from, to int64)                         //通过RotateMachineID切换了机器ID，此时可将旧机器ID归还给分配方
+}
+
+// SetHooks 设置事件回调，nil表示不回调
+func (idGen *IDGenerator) SetHooks(hooks *Hooks) error {
+	if hooks != nil && hooks.NearOverflowMargin < 0 {
+		return errorf(CodeNegative, "NearOverflowMargin")
+	}
+
+	idGen.mutex.Lock()
+	defer idGen.mutex.Unlock()
+	idGen.nearOverflowFired = false
+	if hooks == nil {
+		idGen.hooks = nil
+		return nil
+	}
+	copied := *hooks
+	idGen.hooks = &copied
+	idGen.hookNearOverflow(idGen.now())
+	return nil
+}
+
+// hookClockBackward 时钟回退回调，调用方须持有锁
+func (idGen *IDGenerator) hookClockBackward(backward time.Duration) {
+	if idGen.hooks != nil && idGen.hooks.OnClockBackward != nil {
+		go idGen.hooks.OnClockBackward(backward, idGen.curTimeline)
+	}
+}
+
+// hookTimelineSwitch 切换时间线回调，调用方须持有锁
+func (idGen *IDGenerator) hookTimelineSwitch(from, to int64) {
+	if idGen.hooks != nil && idGen.hooks.OnTimelineSwitch != nil {
+		go idGen.hooks.OnTimelineSwitch(from, to)
+	}
+}
+
+// hookSeqExhausted 序号用完回调，调用方须持有锁
+func (idGen *IDGenerator) hookSeqExhausted(wait time.Duration) {
+	if idGen.hooks != nil && idGen.hooks.OnSeqExhausted != nil {
+		go idGen.hooks.OnSeqExhausted(wait)
+	}
+}
+
+// hookMachineIDRotate 切换机器ID回调，调用方须持有锁
+func (idGen *IDGenerator) hookMachineIDRotate(from, to int64) {
+	if idGen.hooks != nil && idGen.hooks.OnMachineIDRotate != nil {
+		go idGen.hooks.OnMachineIDRotate(from, to)
+	}
+}
+
+// nearOverflowPending 是否需要检查时间部分即将用尽，调用方须持有锁
+func (idGen *IDGenerator) nearOverflowPending() bool {
+	hooks := idGen.hooks
+	return hooks != nil && hooks.OnTimeNearOverflow != nil && hooks.NearOverflowMargin > 0 && !idGen.nearOverflowFired
+}
+
+// hookNearOverflow 时间部分剩余可用时长不足时回调，调用方须持有锁
+func (idGen *IDGenerator) hookNearOverflow(now time.Time) {
+	if !idGen.nearOverflowPending() {
+		return
+	}
+	remaining := time.Duration(idGen.exhaustionNano() - now.UnixNano())
+	if remaining < idGen.hooks.NearOverflowMargin {
+		idGen.nearOverflowFired = true
+		go idGen.hooks.OnTimeNearOverflow(remaining)
+	}
+}
@@ -0,0 +1,80 @@
+package generator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jayecc/mtl-snowflake/fakeclock"
+)
+
+// TestHooks 事件回调
+func TestHooks(t *testing.T) {
+	clock := fakeclock.New(time.Now().Truncate(time.Millisecond))
+	settings := Settings{TimeBit: 41, MachineIDBit: 19, TimelineBit: 1, SeqBit: 2, Epoch: DefaultSettings.Epoch, Clock: clock}
+	idGen, err := NewGeneratorWithSettings(0, settings)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	events := make(chan string, 10)
+	remain := time.Duration(idGen.toUnixNano(idGen.settings.presets.maxTime+1) - clock.Now())
+	if err := idGen.SetHooks(&Hooks{
+		OnClockBackward:    func(backward time.Duration, timeline int64) { events <- "backward" },
+		OnTimelineSwitch:   func(from, to int64) { events <- "switch" },
+		OnSeqExhausted:     func(wait time.Duration) { events <- "exhausted" },
+		NearOverflowMargin: remain + time.Hour,
+		OnTimeNearOverflow: func(remaining time.Duration) { events <- "overflow" },
+	}); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	testCases := []struct {
+		name   string
+		action func()
+		want   []string
+	}{
+		{name: "时间部分即将用尽", action: func() {}, want: []string{"overflow"}},
+		{name: "不重复回调", action: func() {}, want: nil},
+		{name: "序号用完", action: func() {
+			for i := 0; i < 3; i++ {
+				idGen.Generate()
+			}
+		}, want: []string{"exhausted"}},
+		{name: "时钟回退", action: func() { clock.Advance(-time.Second) }, want: []string{"backward", "switch"}},
+	}
+
+	for _, tc := range testCases {
+		tc.action()
+		if _, err := idGen.Generate(); err != nil {
+			t.Fatalf("【失败】-%s-%v", tc.name, err)
+		}
+		got := map[string]int{}
+		for range tc.want {
+			select {
+			case event := <-events:
+				got[event]++
+			case <-time.After(time.Second):
+			}
+		}
+		select {
+		case event := <-events:
+			got[event]++
+		case <-time.After(10 * time.Millisecond):
+		}
+		want := map[string]int{}
+		for _, event := range tc.want {
+			want[event]++
+		}
+		if len(got) != len(want) {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, got, want)
+		}
+		for event, n := range want {
+			if got[event] != n {
+				t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, got, want)
+			}
+		}
+	}
+
+	if err := idGen.SetHooks(&Hooks{NearOverflowMargin: -1}); err == nil {
+		t.Fatalf("【失败】-%s-got:nil-want:error", "负数余量")
+	}
+}
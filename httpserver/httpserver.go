@@ -0,0 +1,192 @@
+// Package httpserver 以HTTP接口提供id生成服务，可作为sidecar部署，供不便直接引入本库的语言调用
+//   - GET /id              生成一个id：{"id":"123"}
+//   - GET /ids?n=100       批量生成n个id：{"ids":["123","124"]}
+//   - GET /decompose/{id}  解析id的各部分
+//   - GET /stats           生成器运行状态
+//
+// id均以字符串输出，避免JavaScript等按双精度浮点数解析时丢失精度；出错时返回{"error":"..."}
+package httpserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	generator "github.com/jayecc/mtl-snowflake"
+)
+
+// DefaultMaxBatch /ids单次允许生成的最大数量
+const DefaultMaxBatch = 1000
+
+// Handler id生成服务的http.Handler
+type Handler struct {
+	idGen    *generator.IDGenerator
+	mux      *http.ServeMux
+	MaxBatch int //单次批量生成的最大数量，不大于0时使用DefaultMaxBatch
+}
+
+var _ http.Handler = (*Handler)(nil)
+
+// New 创建使用idGen生成id的Handler，可直接作为http.Server的Handler，或通过http.StripPrefix挂载到已有路由下
+func New(idGen *generator.IDGenerator) *Handler {
+	h := &Handler{idGen: idGen, mux: http.NewServeMux()}
+	h.mux.HandleFunc("/id", h.generate)
+	h.mux.HandleFunc("/ids", h.generateN)
+	h.mux.HandleFunc("/decompose/", h.decompose)
+	h.mux.HandleFunc("/stats", h.stats)
+	return h
+}
+
+// ServeHTTP 只接受GET请求
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		writeError(w, http.StatusMethodNotAllowed, "只支持GET请求")
+		return
+	}
+	h.mux.ServeHTTP(w, r)
+}
+
+// idResponse GET /id
+type idResponse struct {
+	ID generator.ID `json:"id"`
+}
+
+// idsResponse GET /ids
+type idsResponse struct {
+	IDs []generator.ID `json:"ids"`
+}
+
+// decomposeResponse GET /decompose/{id}
+type decomposeResponse struct {
+	ID           generator.ID `json:"id"`
+	Version      int64        `json:"version,omitempty"`
+	Time         int64        `json:"time"`      //时间(自基准时间起的时间单位数)
+	Timestamp    time.Time    `json:"timestamp"` //生成时间(RFC3339)
+	DatacenterID int64        `json:"datacenter_id,omitempty"`
+	MachineID    int64        `json:"machine_id"`
+	Timeline     int64        `json:"timeline"`
+	Seq          int64        `json:"seq"`
+}
+
+// statsResponse GET /stats
+type statsResponse struct {
+	MachineID          int64       `json:"machine_id"`
+	CurrentTimeline    int64       `json:"current_timeline"`
+	TimelineProgress   []time.Time `json:"timeline_progress"`
+	AvailableTimelines int         `json:"available_timelines"`
+	RemainingSeconds   float64     `json:"remaining_seconds"`
+	SeqUsed            int64       `json:"seq_used"`
+	SeqCapacity        int64       `json:"seq_capacity"`
+	ClockBackwards     int64       `json:"clock_backwards"`
+	TimelineSwitches   int64       `json:"timeline_switches"`
+	SeqExhausted       int64       `json:"seq_exhausted"`
+}
+
+// errorResponse 出错时的响应
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// generate GET /id
+func (h *Handler) generate(w http.ResponseWriter, r *http.Request) {
+	id, err := h.idGen.Generate()
+	if err != nil {
+		writeGenerateError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, idResponse{ID: id})
+}
+
+// generateN GET /ids?n=100，n缺省为1
+func (h *Handler) generateN(w http.ResponseWriter, r *http.Request) {
+	maxBatch := h.MaxBatch
+	if maxBatch <= 0 {
+		maxBatch = DefaultMaxBatch
+	}
+	n := 1
+	if s := r.URL.Query().Get("n"); s != "" {
+		v, err := strconv.Atoi(s)
+		if err != nil || v <= 0 || v > maxBatch {
+			writeError(w, http.StatusBadRequest, "n须为1~"+strconv.Itoa(maxBatch)+"的整数")
+			return
+		}
+		n = v
+	}
+
+	ids, err := h.idGen.GenerateN(n)
+	if err != nil {
+		writeGenerateError(w, err)
+		return
+	}
+	resp := idsResponse{IDs: make([]generator.ID, len(ids))}
+	for i, id := range ids {
+		resp.IDs[i] = generator.ID(id)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// decompose GET /decompose/{id}
+func (h *Handler) decompose(w http.ResponseWriter, r *http.Request) {
+	id, err := generator.ParseID(strings.TrimPrefix(r.URL.Path, "/decompose/"))
+	if err != nil || id < 0 {
+		writeError(w, http.StatusBadRequest, "id须为非负的十进制整数")
+		return
+	}
+	c := h.idGen.Decompose(id.Int64())
+	writeJSON(w, http.StatusOK, decomposeResponse{
+		ID:           id,
+		Version:      c.Version,
+		Time:         c.Time,
+		Timestamp:    c.Timestamp.UTC(),
+		DatacenterID: c.DatacenterID,
+		MachineID:    c.MachineID,
+		Timeline:     c.TimeLine,
+		Seq:          c.Seq,
+	})
+}
+
+// stats GET /stats
+func (h *Handler) stats(w http.ResponseWriter, r *http.Request) {
+	stats := h.idGen.Stats()
+	progress := make([]time.Time, len(stats.TimelineProgress))
+	for i, t := range stats.TimelineProgress {
+		progress[i] = t.UTC()
+	}
+	writeJSON(w, http.StatusOK, statsResponse{
+		MachineID:          stats.MachineID,
+		CurrentTimeline:    stats.CurrentTimeline,
+		TimelineProgress:   progress,
+		AvailableTimelines: stats.AvailableTimelines,
+		RemainingSeconds:   stats.Remaining.Seconds(),
+		SeqUsed:            stats.SeqUsed,
+		SeqCapacity:        stats.SeqCapacity,
+		ClockBackwards:     stats.ClockBackwards,
+		TimelineSwitches:   stats.TimelineSwitches,
+		SeqExhausted:       stats.SeqExhausted,
+	})
+}
+
+// writeGenerateError 生成失败返回503，可重试的错误附带Retry-After
+func writeGenerateError(w http.ResponseWriter, err error) {
+	if retryAfter, ok := generator.RetryAfter(err); ok && retryAfter > 0 {
+		seconds := int64((retryAfter + time.Second - 1) / time.Second)
+		w.Header().Set("Retry-After", strconv.FormatInt(seconds, 10))
+	}
+	writeError(w, http.StatusServiceUnavailable, err.Error())
+}
+
+// writeError 以JSON返回错误信息
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, errorResponse{Error: msg})
+}
+
+// writeJSON 以JSON返回响应
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,122 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	generator "github.com/jayecc/mtl-snowflake"
+)
+
+// TestHandler 各接口的状态码及响应
+func TestHandler(t *testing.T) {
+	idGen, _ := generator.NewGenerator(12)
+	h := New(idGen)
+	h.MaxBatch = 10
+	id, _ := idGen.Generate()
+	idStr := generator.ID(id).String()
+
+	testCases := []struct {
+		name       string
+		method     string
+		path       string
+		wantStatus int
+		wantKeys   []string
+	}{
+		{name: "生成", method: http.MethodGet, path: "/id", wantStatus: http.StatusOK, wantKeys: []string{"id"}},
+		{name: "批量生成", method: http.MethodGet, path: "/ids?n=5", wantStatus: http.StatusOK, wantKeys: []string{"ids"}},
+		{name: "批量生成缺省数量", method: http.MethodGet, path: "/ids", wantStatus: http.StatusOK, wantKeys: []string{"ids"}},
+		{name: "批量生成数量过大", method: http.MethodGet, path: "/ids?n=11", wantStatus: http.StatusBadRequest, wantKeys: []string{"error"}},
+		{name: "批量生成数量错误", method: http.MethodGet, path: "/ids?n=abc", wantStatus: http.StatusBadRequest, wantKeys: []string{"error"}},
+		{name: "解析", method: http.MethodGet, path: "/decompose/" + idStr, wantStatus: http.StatusOK, wantKeys: []string{"id", "time", "timestamp", "machine_id", "timeline", "seq"}},
+		{name: "解析错误的id", method: http.MethodGet, path: "/decompose/abc", wantStatus: http.StatusBadRequest, wantKeys: []string{"error"}},
+		{name: "解析负数", method: http.MethodGet, path: "/decompose/-1", wantStatus: http.StatusBadRequest, wantKeys: []string{"error"}},
+		{name: "状态", method: http.MethodGet, path: "/stats", wantStatus: http.StatusOK, wantKeys: []string{"machine_id", "remaining_seconds", "timeline_progress"}},
+		{name: "不支持的方法", method: http.MethodPost, path: "/id", wantStatus: http.StatusMethodNotAllowed, wantKeys: []string{"error"}},
+		{name: "不存在的路径", method: http.MethodGet, path: "/unknown", wantStatus: http.StatusNotFound},
+	}
+	for _, tc := range testCases {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(tc.method, tc.path, nil))
+		if w.Code != tc.wantStatus {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, w.Code, tc.wantStatus)
+		}
+		if tc.wantKeys == nil {
+			continue
+		}
+		var body map[string]json.RawMessage
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("【失败】-%s-响应格式错误-%s", tc.name, w.Body.String())
+		}
+		for _, key := range tc.wantKeys {
+			if _, ok := body[key]; !ok {
+				t.Fatalf("【失败】-%s-缺少%s-%s", tc.name, key, w.Body.String())
+			}
+		}
+	}
+}
+
+// TestHandlerResponse id以字符串输出，解析结果与Decompose一致
+func TestHandlerResponse(t *testing.T) {
+	idGen, _ := generator.NewGenerator(12)
+	h := New(idGen)
+
+	get := func(path string, v interface{}) {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if err := json.Unmarshal(w.Body.Bytes(), v); err != nil {
+			t.Fatalf("【失败】-%s-%s", path, w.Body.String())
+		}
+	}
+
+	var single map[string]interface{}
+	get("/id", &single)
+	if _, ok := single["id"].(string); !ok {
+		t.Fatalf("【失败】-%s-got:%T-want:%s", "id类型", single["id"], "string")
+	}
+
+	var batch struct {
+		IDs []generator.ID `json:"ids"`
+	}
+	get("/ids?n=100", &batch)
+	if len(batch.IDs) != 100 {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "批量生成数量", len(batch.IDs), 100)
+	}
+	for i := 1; i < len(batch.IDs); i++ {
+		if batch.IDs[i] <= batch.IDs[i-1] {
+			t.Fatalf("【失败】-%s-got:%v-want:>%v", "批量生成递增", batch.IDs[i], batch.IDs[i-1])
+		}
+	}
+
+	id := batch.IDs[0]
+	var got map[string]interface{}
+	get("/decompose/"+id.String(), &got)
+	c := idGen.Decompose(id.Int64())
+	want := map[string]interface{}{
+		"id":         id.String(),
+		"time":       float64(c.Time),
+		"timestamp":  c.Timestamp.UTC().Format("2006-01-02T15:04:05.999999999Z07:00"),
+		"machine_id": float64(12),
+		"timeline":   float64(c.TimeLine),
+		"seq":        float64(c.Seq),
+	}
+	for key, v := range want {
+		if got[key] != v {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", key, got[key], v)
+		}
+	}
+}
+
+// TestHandlerGenerateError 生成失败返回503
+func TestHandlerGenerateError(t *testing.T) {
+	idGen, _ := generator.NewGenerator(12)
+	idGen.Fence(errors.New("租约已失效"))
+	w := httptest.NewRecorder()
+	New(idGen).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/id", nil))
+	if w.Code != http.StatusServiceUnavailable || !strings.Contains(w.Body.String(), "error") {
+		t.Fatalf("【失败】-%s-got:%v,%s-want:%v", "生成失败", w.Code, w.Body.String(), http.StatusServiceUnavailable)
+	}
+}
@@ -0,0 +1,769 @@
+package generator
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// ErrorCode 运行时错误的稳定标识，不随语言变化，可用于日志检索、告警规则及错误码映射
+type ErrorCode string
+
+// 哨兵错误对应的错误码
+const (
+	CodeClockMovedBack      ErrorCode = "clock_moved_back"
+	CodeTimelinesExhausted  ErrorCode = "timelines_exhausted"
+	CodeBreakerOpen         ErrorCode = "breaker_open"
+	CodeInvalidMachineID    ErrorCode = "invalid_machine_id"
+	CodeBackwardRejected    ErrorCode = "backward_rejected"
+	CodeRateLimited         ErrorCode = "rate_limited"
+	CodeTimeOverflow        ErrorCode = "time_overflow"
+	CodeBeforeEpoch         ErrorCode = "before_epoch"
+	CodeNotMonotonic        ErrorCode = "not_monotonic"
+	CodeFenced              ErrorCode = "fenced"
+	CodeWaitTimeout         ErrorCode = "wait_timeout"
+	CodeGeneratorClosed     ErrorCode = "generator_closed"
+	CodeBackfillTime        ErrorCode = "backfill_time"
+	CodeBackfillExhausted   ErrorCode = "backfill_exhausted"
+	CodeReservationExpired  ErrorCode = "reservation_expired"
+	CodeNotInitialized      ErrorCode = "not_initialized"
+	CodeAlreadyInitialized  ErrorCode = "already_initialized"
+	CodeFingerprintMismatch ErrorCode = "fingerprint_mismatch"
+	CodeUnknownVersion      ErrorCode = "unknown_version"
+	CodeClockBackwardDetail ErrorCode = "clock_backward_detail" //*ClockBackwardError的格式，参数：原因、回退时长、预计恢复时长
+	CodeBreakerManualDetail ErrorCode = "breaker_manual_detail" //须手动恢复的*BreakerOpenError的格式，参数：ErrBreakerOpen
+	CodeBreakerRetryDetail  ErrorCode = "breaker_retry_detail"  //*BreakerOpenError的格式，参数：ErrBreakerOpen、恢复时长
+	CodeMachineIDDetail     ErrorCode = "machine_id_detail"     //*MachineIDError的格式，参数：最大机器ID、实际机器ID
+	CodeRateLimitDetail     ErrorCode = "rate_limit_detail"     //*RateLimitError的格式，参数：ErrRateLimited、重试间隔
+	CodeFingerprintDetail   ErrorCode = "fingerprint_detail"    //*FingerprintMismatchError的格式，参数：ErrFingerprintMismatch、期望的指纹、实际的指纹
+)
+
+// 其他运行时错误的错误码，Error()按参数填充信息模板
+const (
+	CodeNegative                   ErrorCode = "negative"     //参数：参数名
+	CodeNotPositive                ErrorCode = "not_positive" //参数：参数名
+	CodeNil                        ErrorCode = "nil"          //参数：参数名
+	CodeEmptyPath                  ErrorCode = "empty_path"
+	CodeNegativeID                 ErrorCode = "negative_id"
+	CodeUnknownWaitPolicy          ErrorCode = "unknown_wait_policy"
+	CodeAlertPolicy                ErrorCode = "alert_policy"
+	CodeDecayRange                 ErrorCode = "decay_range"
+	CodeEncodingEmpty              ErrorCode = "encoding_empty"        //参数：编码名称
+	CodeEncodingLeadingZero        ErrorCode = "encoding_leading_zero" //参数：编码名称、字符串
+	CodeEncodingInvalidChar        ErrorCode = "encoding_invalid_char" //参数：编码名称、字符串、字符
+	CodeEncodingOverflow           ErrorCode = "encoding_overflow"     //参数：编码名称、字符串
+	CodeBatchFormat                ErrorCode = "batch_format"
+	CodeBatchCorrupted             ErrorCode = "batch_corrupted"
+	CodeBlockConflict              ErrorCode = "block_conflict"
+	CodeBlockOverflow              ErrorCode = "block_overflow"     //参数：id数量
+	CodeIDsPerTickRange            ErrorCode = "ids_per_tick_range" //参数：最大值
+	CodeLeaseNotFound              ErrorCode = "lease_not_found"    //参数：机器ID
+	CodeReservationRenew           ErrorCode = "reservation_renew"
+	CodeOwnerEmpty                 ErrorCode = "owner_empty"
+	CodeNoFreeMachineID            ErrorCode = "no_free_machine_id"
+	CodeRotationLayout             ErrorCode = "rotation_layout"
+	CodeRotationTimeBit            ErrorCode = "rotation_time_bit"
+	CodeRotationEpoch              ErrorCode = "rotation_epoch"
+	CodeRotationLate               ErrorCode = "rotation_late"
+	CodeExhaustionWaitDrift        ErrorCode = "exhaustion_wait_drift"
+	CodeMaxDriftUnit               ErrorCode = "max_drift_unit" //参数：时间单位
+	CodeUnknownExhaustionPolicy    ErrorCode = "unknown_exhaustion_policy"
+	CodeNilPrimary                 ErrorCode = "nil_primary"
+	CodeEmergencyPrimary           ErrorCode = "emergency_primary"
+	CodeNilFallback                ErrorCode = "nil_fallback"
+	CodeUnsupportedEncoding        ErrorCode = "unsupported_encoding" //参数：编码
+	CodeInvalidID                  ErrorCode = "invalid_id"           //参数：字符串
+	CodeNullID                     ErrorCode = "null_id"
+	CodeIDConvert                  ErrorCode = "id_convert" //参数：源值
+	CodeFieldNameEmpty             ErrorCode = "field_name_empty"
+	CodeFieldDuplicate             ErrorCode = "field_duplicate" //参数：字段名
+	CodeFieldBits                  ErrorCode = "field_bits"      //参数：字段名
+	CodeFieldBitsSum               ErrorCode = "field_bits_sum"  //参数：位数之和
+	CodeFieldMissing               ErrorCode = "field_missing"   //参数：字段名
+	CodeFieldUnknown               ErrorCode = "field_unknown"   //参数：字段名
+	CodeFieldValue                 ErrorCode = "field_value"     //参数：字段名、最大值、实际值
+	CodeFieldGenerated             ErrorCode = "field_generated" //参数：字段名
+	CodeRegistryVersionBit         ErrorCode = "registry_version_bit"
+	CodeRegistryVersionBitMismatch ErrorCode = "registry_version_bit_mismatch" //参数：注册表的版本位长度、实际值
+	CodeVersionRegistered          ErrorCode = "version_registered"            //参数：版本号
+	CodeNoDefaultRegistry          ErrorCode = "no_default_registry"
+	CodeMachineIPBits              ErrorCode = "machine_ip_bits"
+	CodeNoPrivateIP                ErrorCode = "no_private_ip"
+	CodeMigrationTable             ErrorCode = "migration_table"
+	CodeMigrationSerialRange       ErrorCode = "migration_serial_range"
+	CodeMigrationCutover           ErrorCode = "migration_cutover"
+	CodeMigrationTooMany           ErrorCode = "migration_too_many"
+	CodeMigrationSpan              ErrorCode = "migration_span"
+	CodeMigrationOutOfRange        ErrorCode = "migration_out_of_range" //参数：原值、最小原值、最大原值
+	CodeMigrationNegative          ErrorCode = "migration_negative"
+	CodeMigrationInsufficient      ErrorCode = "migration_insufficient"
+	CodeMigrationConflict          ErrorCode = "migration_conflict" //参数：迁移后的最大id、切换后的最小id
+	CodeMonotonicReservation       ErrorCode = "monotonic_reservation"
+	CodeReadableLength             ErrorCode = "readable_length" //参数：长度
+	CodeReadableDigits             ErrorCode = "readable_digits"
+	CodeReadableLocalTime          ErrorCode = "readable_local_time"
+	CodeReadableTimeRange          ErrorCode = "readable_time_range"
+	CodeReadableSubsecond          ErrorCode = "readable_subsecond"
+	CodeReadableRest               ErrorCode = "readable_rest"
+	CodeReadableBeforeEpoch        ErrorCode = "readable_before_epoch"
+	CodeReadableTimeOverflow       ErrorCode = "readable_time_overflow"
+	CodeObfuscateKey               ErrorCode = "obfuscate_key"
+	CodeOverflowRotation           ErrorCode = "overflow_rotation"
+	CodeOverflowOldSettings        ErrorCode = "overflow_old_settings"
+	CodeOverflowTooLate            ErrorCode = "overflow_too_late"
+	CodeOverflowCallback           ErrorCode = "overflow_callback"
+	CodeUnknownOverflowAction      ErrorCode = "unknown_overflow_action"
+	CodePaddingRatio               ErrorCode = "padding_ratio"
+	CodePaddingKey                 ErrorCode = "padding_key"
+	CodePaddingRandom              ErrorCode = "padding_random"
+	CodePaddingReservation         ErrorCode = "padding_reservation"
+	CodePoolShard                  ErrorCode = "pool_shard"
+	CodePoolPersister              ErrorCode = "pool_persister"
+	CodePoolShards                 ErrorCode = "pool_shards" //参数：最大分片数
+	CodePrefetchSize               ErrorCode = "prefetch_size"
+	CodePrefetchClosed             ErrorCode = "prefetch_closed"
+	CodeRateLimitBurst             ErrorCode = "rate_limit_burst"    //参数：burst
+	CodeReadableV2Parts            ErrorCode = "readable_v2_parts"   //参数：部分数量
+	CodeReadableV2Version          ErrorCode = "readable_v2_version" //参数：版本号、配置的版本号
+	CodeReadableV2Time             ErrorCode = "readable_v2_time"    //参数：格式
+	CodeReadableV2TimeRange        ErrorCode = "readable_v2_time_range"
+	CodeReadableV2Field            ErrorCode = "readable_v2_field"       //参数：前缀字母、前缀字母、宽度
+	CodeReadableV2FieldRange       ErrorCode = "readable_v2_field_range" //参数：前缀字母、最大值
+	CodeRedisTimeRange             ErrorCode = "redis_time_range"
+	CodeEmergencyExhausted         ErrorCode = "emergency_exhausted" //参数：INCR的值
+	CodeCallerBits                 ErrorCode = "caller_bits"         //参数：SeqBit
+	CodeRandomReservation          ErrorCode = "random_reservation"
+	CodeReservationDisabled        ErrorCode = "reservation_disabled"
+	CodeCallerNameEmpty            ErrorCode = "caller_name_empty"
+	CodeCallersFull                ErrorCode = "callers_full"
+	CodeCallerUnknown              ErrorCode = "caller_unknown"   //参数：调用方
+	CodeMaxOffsetRange             ErrorCode = "max_offset_range" //参数：最大值
+	CodeMaxStepRange               ErrorCode = "max_step_range"   //参数：最大值
+	CodeTimelineRange              ErrorCode = "timeline_range"   //参数：最大时间线
+	CodeStartTimelineLate          ErrorCode = "start_timeline_late"
+	CodeStateTimelines             ErrorCode = "state_timelines"
+	CodeEmptyKeyPrefix             ErrorCode = "empty_key_prefix"
+	CodeUUIDFormat                 ErrorCode = "uuid_format"   //参数：类型名称、字符串
+	CodeTimeUUIDBits               ErrorCode = "timeuuid_bits" //参数：位数上限
+	CodeTimeUUIDVersion            ErrorCode = "timeuuid_version"
+	CodeTimeUUIDMulticast          ErrorCode = "timeuuid_multicast"
+	CodeTimeUUIDTime               ErrorCode = "timeuuid_time"
+	CodeTimeUUIDNode               ErrorCode = "timeuuid_node"
+	CodeULIDLength                 ErrorCode = "ulid_length"   //参数：字符串
+	CodeULIDChar                   ErrorCode = "ulid_char"     //参数：字符串、字符
+	CodeULIDOverflow               ErrorCode = "ulid_overflow" //参数：字符串
+	CodeULIDNotID                  ErrorCode = "ulid_not_id"
+	CodeULIDLayout                 ErrorCode = "ulid_layout"
+	CodeUUIDv7Invalid              ErrorCode = "uuidv7_invalid"
+	CodeUUIDv7Layout               ErrorCode = "uuidv7_layout"
+	CodeBeforeUnixEpoch            ErrorCode = "before_unix_epoch"
+	CodeTimeRange                  ErrorCode = "time_range"
+	CodeTimeBeforeEpoch            ErrorCode = "time_before_epoch"
+	CodeTimeBitsOverflow           ErrorCode = "time_bits_overflow"
+	CodeLowBitsOverflow            ErrorCode = "low_bits_overflow"
+	CodeVaultSignature             ErrorCode = "vault_signature"
+	CodeVaultLayout                ErrorCode = "vault_layout"
+	CodeVaultIndex                 ErrorCode = "vault_index"      //参数：下标、id数量
+	CodeVaultMachineID             ErrorCode = "vault_machine_id" //参数：机器ID
+	CodeVaultSettings              ErrorCode = "vault_settings"
+	CodeVaultOverlap               ErrorCode = "vault_overlap" //参数：机器ID、时间线、两个id块的时间范围
+	CodeVaultUnsupported           ErrorCode = "vault_unsupported"
+	CodeVaultTimeline              ErrorCode = "vault_timeline"
+	CodeVaultKey                   ErrorCode = "vault_key"
+	CodeVaultOverflow              ErrorCode = "vault_overflow"
+	CodeSystemNameEmpty            ErrorCode = "system_name_empty"
+	CodeSystemRegistered           ErrorCode = "system_registered" //参数：系统名称
+	CodeSystemUnknown              ErrorCode = "system_unknown"    //参数：系统名称
+	CodeSystemField                ErrorCode = "system_field"      //参数：配置项、系统名称、原因
+	CodeCSVEmpty                   ErrorCode = "csv_empty"
+	CodeCSVMachineID               ErrorCode = "csv_machine_id"
+	CodeCSVMachineIDValue          ErrorCode = "csv_machine_id_value" //参数：行号、原因
+	CodeCSVLine                    ErrorCode = "csv_line"             //参数：行号、原因
+	CodeUnknownPlacement           ErrorCode = "unknown_placement"    //参数：名称
+	CodeBitsSumFlag                ErrorCode = "bits_sum_flag"
+	CodeBitsSum                    ErrorCode = "bits_sum"
+	CodePlacementUnsupported       ErrorCode = "placement_unsupported"
+	CodeVersionRange               ErrorCode = "version_range" //参数：最大版本号、实际值
+	CodeNotTimeOrdered             ErrorCode = "not_time_ordered"
+	CodeVersionMismatch            ErrorCode = "version_mismatch"
+	CodeTimeUnit                   ErrorCode = "time_unit" //参数：时间单位
+	CodeTimeBitsTooMany            ErrorCode = "time_bits_too_many"
+	CodeEpochInFuture              ErrorCode = "epoch_in_future"
+	CodeDatacenterRange            ErrorCode = "datacenter_range"    //参数：最大数据中心ID、实际值
+	CodeMachineIDEnvName           ErrorCode = "machine_id_env_name" //参数：来源
+	CodeMachineIDSource            ErrorCode = "machine_id_source"   //参数：来源
+	CodeMachineIDSourceMissing     ErrorCode = "machine_id_source_missing"
+	CodeEnvEmpty                   ErrorCode = "env_empty"                //参数：变量名
+	CodeEnvMachineID               ErrorCode = "env_machine_id"           //参数：变量名、值
+	CodeSettingsFormat             ErrorCode = "settings_format"          //参数：路径
+	CodeSettingsFile               ErrorCode = "settings_file"            //参数：路径、原因
+	CodeSettingValueType           ErrorCode = "setting_value_type"       //参数：配置项
+	CodeYAMLNested                 ErrorCode = "yaml_nested"              //参数：行号
+	CodeYAMLColon                  ErrorCode = "yaml_colon"               //参数：行号
+	CodeYAMLValue                  ErrorCode = "yaml_value"               //参数：行号、配置项
+	CodeYAMLDuplicate              ErrorCode = "yaml_duplicate"           //参数：行号、配置项
+	CodeSettingUnknown             ErrorCode = "setting_unknown"          //参数：配置项
+	CodeSettingBits                ErrorCode = "setting_bits"             //参数：配置项、值
+	CodeSettingInteger             ErrorCode = "setting_integer"          //参数：配置项、值
+	CodeSettingEpoch               ErrorCode = "setting_epoch"            //参数：值
+	CodeSettingTimeUnit            ErrorCode = "setting_time_unit"        //参数：值
+	CodeSettingFlagBit             ErrorCode = "setting_flag_bit"         //参数：值
+	CodeSelfTestEpoch              ErrorCode = "self_test_epoch"          //参数：Epoch
+	CodeSelfTestTimeRange          ErrorCode = "self_test_time_range"     //参数：用尽的时间、剩余时长
+	CodeSelfTestClockStuck         ErrorCode = "self_test_clock_stuck"    //参数：间隔、两次读取的时间
+	CodeSelfTestGenerate           ErrorCode = "self_test_generate"       //参数：原因
+	CodeSelfTestMachineID          ErrorCode = "self_test_machine_id"     //参数：id、解析出的机器ID、机器ID
+	CodeSelfTestDatacenter         ErrorCode = "self_test_datacenter"     //参数：id、解析出的数据中心ID、数据中心ID
+	CodeSelfTestRecompose          ErrorCode = "self_test_recompose"      //参数：id、重新组合的id
+	CodeSelfTestTime               ErrorCode = "self_test_time"           //参数：id、id的时间、时钟
+	CodeSelfTestReadable           ErrorCode = "self_test_readable"       //参数：id、可读格式、还原的id、原因
+	CodeSelfTestNotIncreasing      ErrorCode = "self_test_not_increasing" //参数：前一个id、后一个id
+	CodeUnsupportedLanguage        ErrorCode = "unsupported_language"     //参数：语言
+	CodeClassName                  ErrorCode = "class_name"
+	CodeJavaPackage                ErrorCode = "java_package"
+	CodeUnsupportedDialect         ErrorCode = "unsupported_dialect" //参数：方言
+	CodeFunctionPrefix             ErrorCode = "function_prefix"
+	CodeEpochMicrosecond           ErrorCode = "epoch_microsecond"
+	CodeUDFVersion                 ErrorCode = "udf_version"
+	CodeAllocatorClosed            ErrorCode = "allocator_closed"
+	CodeLeaseLost                  ErrorCode = "lease_lost"
+	CodeMachineIDTaken             ErrorCode = "machine_id_taken" //参数：机器ID
+	CodeHeartbeatTTL               ErrorCode = "heartbeat_ttl"
+	CodeMachineIDBitRange          ErrorCode = "machine_id_bit_range" //参数：最大位数
+	CodeEnvValue                   ErrorCode = "env_value"            //参数：变量名、值、原因
+	CodeOrdinalRange               ErrorCode = "ordinal_range"        //参数：序号、偏移、最大机器ID
+	CodeOrdinalParse               ErrorCode = "ordinal_parse"
+	CodeExpvarExists               ErrorCode = "expvar_exists" //参数：名称
+	CodeNoBenchResult              ErrorCode = "no_bench_result"
+)
+
+// Translator 错误信息翻译器，按错误码返回对应语言的错误信息
+//   - args为信息模板的参数，含义见各错误码的说明；返回空字符串时使用英文
+type Translator interface {
+	Translate(code ErrorCode, args ...interface{}) string
+}
+
+// Messages 按错误码索引的信息模板，使用fmt.Sprintf填充参数，未包含的错误码返回空字符串
+type Messages map[ErrorCode]string
+
+// Translate 实现Translator
+func (m Messages) Translate(code ErrorCode, args ...interface{}) string {
+	format, ok := m[code]
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// EnglishMessages 英文错误信息(默认)
+var EnglishMessages = Messages{
+	CodeClockMovedBack:             "clock moved backwards",
+	CodeTimelinesExhausted:         "clock moved backwards too often, adjust the clock sync policy or add more timelines",
+	CodeBreakerOpen:                "clock moved backwards too often, generator breaker is open, check the server clock sync",
+	CodeInvalidMachineID:           "machineID out of range",
+	CodeBackwardRejected:           "clock moved backwards, generation rejected by backward policy",
+	CodeRateLimited:                "generation rate limit exceeded",
+	CodeTimeOverflow:               "time offset exceeds the maximum, use more time bits or a later epoch",
+	CodeBeforeEpoch:                "current time is earlier than the epoch, check the server clock or set an earlier epoch",
+	CodeNotMonotonic:               "clock moved backwards beyond the wait limit, ids can not be kept strictly increasing",
+	CodeFenced:                     "generator is fenced, the machine ID may be in use by another node",
+	CodeWaitTimeout:                "waiting to generate id exceeded the time limit",
+	CodeGeneratorClosed:            "generator is closed",
+	CodeBackfillTime:               "backfill time must be earlier than the generator creation time",
+	CodeBackfillExhausted:          "ids of the backfill time unit are exhausted",
+	CodeReservationExpired:         "reservation window has ended, request a new one",
+	CodeNotInitialized:             "default generator is not initialized, call Init first",
+	CodeAlreadyInitialized:         "default generator is already initialized",
+	CodeFingerprintMismatch:        "id layout fingerprint mismatch, the layout differs from the producer",
+	CodeUnknownVersion:             "no layout registered for the id version",
+	CodeClockBackwardDetail:        "%s (clock moved back %s, expected to recover in %s)",
+	CodeBreakerManualDetail:        "%s (manual reset required)",
+	CodeBreakerRetryDetail:         "%s (recovers in %s)",
+	CodeMachineIDDetail:            "machineID must be between 0-%d (2^MachineIDBit-1), got %d",
+	CodeRateLimitDetail:            "%s (retry in %s)",
+	CodeFingerprintDetail:          "%s (expected %s, got %s)",
+	CodeNegative:                   "%s must not be negative",
+	CodeNotPositive:                "%s must be greater than 0",
+	CodeNil:                        "%s must not be nil",
+	CodeEmptyPath:                  "file path must not be empty",
+	CodeNegativeID:                 "id must not be negative",
+	CodeUnknownWaitPolicy:          "unknown wait policy",
+	CodeAlertPolicy:                "alert threshold and window must be greater than 0",
+	CodeDecayRange:                 "Decay must be between 0-1",
+	CodeEncodingEmpty:              "%s string must not be empty",
+	CodeEncodingLeadingZero:        "%s string %q has redundant leading zeros",
+	CodeEncodingInvalidChar:        "%s string %q contains invalid character %q",
+	CodeEncodingOverflow:           "%s string exceeds 64 bits: %s",
+	CodeBatchFormat:                "unsupported id batch format",
+	CodeBatchCorrupted:             "id batch data is corrupted",
+	CodeBlockConflict:              "block reservation can not be used with seq padding, seq randomization or seq reservation",
+	CodeBlockOverflow:              "reserving %d ids exceeds the range of the time bits",
+	CodeIDsPerTickRange:            "idsPerTick must be between 0-%d (2^SeqBit)",
+	CodeLeaseNotFound:              "lease of machine ID %d does not exist or has expired",
+	CodeReservationRenew:           "batch reservations can not be renewed",
+	CodeOwnerEmpty:                 "owner name must not be empty",
+	CodeNoFreeMachineID:            "no free machine ID",
+	CodeRotationLayout:             "old and new settings must have the same layout and differ only in Epoch",
+	CodeRotationTimeBit:            "TimeBit must not be less than 2",
+	CodeRotationEpoch:              "the new epoch must be later than the old epoch and not later than the rotation start",
+	CodeRotationLate:               "the old epoch reaches the top time bit before the overlap ends, rotate earlier",
+	CodeExhaustionWaitDrift:        "maxDrift must be 0 for ExhaustionWait",
+	CodeMaxDriftUnit:               "maxDrift must be at least one time unit (%s)",
+	CodeUnknownExhaustionPolicy:    "unsupported SeqExhaustionPolicy",
+	CodeNilPrimary:                 "primary generator must not be nil",
+	CodeEmergencyPrimary:           "an emergency generator can only be a fallback in the chain",
+	CodeNilFallback:                "fallback generator must not be nil",
+	CodeUnsupportedEncoding:        "unsupported encoding: %s",
+	CodeInvalidID:                  "invalid id: %q",
+	CodeNullID:                     "id can not be NULL, use *ID for nullable columns",
+	CodeIDConvert:                  "can not convert %T to id",
+	CodeFieldNameEmpty:             "field name must not be empty",
+	CodeFieldDuplicate:             "duplicate field %s",
+	CodeFieldBits:                  "bits of field %s must be between 1-63",
+	CodeFieldBitsSum:               "the field bits must add up to 63, got %d",
+	CodeFieldMissing:               "missing field %s",
+	CodeFieldUnknown:               "unknown field %s",
+	CodeFieldValue:                 "value of field %s must be between 0-%d, got %d",
+	CodeFieldGenerated:             "field %s is filled by the generator and can not be specified",
+	CodeRegistryVersionBit:         "versionBit must be between 1-16",
+	CodeRegistryVersionBitMismatch: "VersionBit must be %d, got %d",
+	CodeVersionRegistered:          "version %d is already registered",
+	CodeNoDefaultRegistry:          "default registry is not set, call SetDefaultRegistry first",
+	CodeMachineIPBits:              "bits must be between 1-32",
+	CodeNoPrivateIP:                "no private IPv4 address found",
+	CodeMigrationTable:             "table and column names must not be empty",
+	CodeMigrationSerialRange:       "invalid serial range, 1<=MinSerial<=MaxSerial is required",
+	CodeMigrationCutover:           "cutover time is out of the configured time range",
+	CodeMigrationTooMany:           "too many serials to fit in the time units before the cutover, postpone the cutover or add seq bits",
+	CodeMigrationSpan:              "the serial span exceeds the minimum id after the cutover, can not migrate without collisions",
+	CodeMigrationOutOfRange:        "%d is out of the migration range [%d,%d]",
+	CodeMigrationNegative:          "migrated ids must be positive",
+	CodeMigrationInsufficient:      "the migrated id range can not hold all serials",
+	CodeMigrationConflict:          "the max migrated id (%d) conflicts with ids generated after the cutover (>=%d)",
+	CodeMonotonicReservation:       "monotonic mode can not be used with seq reservation",
+	CodeReadableLength:             "readable format must be %d digits",
+	CodeReadableDigits:             "readable format must contain only digits",
+	CodeReadableLocalTime:          "the time does not exist in the local time zone",
+	CodeReadableTimeRange:          "readable time is out of range",
+	CodeReadableSubsecond:          "readable sub-second part is out of range",
+	CodeReadableRest:               "readable remaining part is out of range",
+	CodeReadableBeforeEpoch:        "readable time is earlier than the epoch",
+	CodeReadableTimeOverflow:       "readable time exceeds the range of the time bits",
+	CodeObfuscateKey:               "obfuscation key must not be empty",
+	CodeOverflowRotation:           "OverflowSwitchEpoch requires Rotation",
+	CodeOverflowOldSettings:        "the generator must use the old settings of Rotation",
+	CodeOverflowTooLate:            "the generator has reached the top time bit and can not switch to the new epoch",
+	CodeOverflowCallback:           "OverflowCallback requires OnOverflow",
+	CodeUnknownOverflowAction:      "unsupported OverflowAction",
+	CodePaddingRatio:               "padding ratio must be between 0-0.9",
+	CodePaddingKey:                 "padding key must not be empty",
+	CodePaddingRandom:              "seq padding can not be used with seq randomization",
+	CodePaddingReservation:         "seq padding can not be used with seq reservation",
+	CodePoolShard:                  "pool shards can not enable seq padding, seq reservation or machine ID rotation",
+	CodePoolPersister:              "generator pools do not support StatePersister",
+	CodePoolShards:                 "shards must not exceed %d (2^(SeqBit-1))",
+	CodePrefetchSize:               "MinSize must not be greater than MaxSize",
+	CodePrefetchClosed:             "prefetch buffer is closed",
+	CodeRateLimitBurst:             "n must not exceed the rate limit burst (%d)",
+	CodeReadableV2Parts:            "readable v2 format must have %d parts separated by '-'",
+	CodeReadableV2Version:          "readable v2 version %d differs from the configured Version (%d)",
+	CodeReadableV2Time:             "readable v2 time part must be %s",
+	CodeReadableV2TimeRange:        "readable v2 time is out of range",
+	CodeReadableV2Field:            "readable v2 %c part must be %c followed by %d digits",
+	CodeReadableV2FieldRange:       "readable v2 %c part is out of range (max %d)",
+	CodeRedisTimeRange:             "Redis server time is out of the configured time range",
+	CodeEmergencyExhausted:         "emergency sequence of the current time unit is exhausted (%d)",
+	CodeCallerBits:                 "callerBits must be less than SeqBit (%d)",
+	CodeRandomReservation:          "seq randomization can not be used with seq reservation",
+	CodeReservationDisabled:        "seq reservation is not enabled",
+	CodeCallerNameEmpty:            "caller name must not be empty",
+	CodeCallersFull:                "too many callers, increase callerBits",
+	CodeCallerUnknown:              "caller %d is not registered",
+	CodeMaxOffsetRange:             "maxOffset must be between 0-%d (2^SeqBit-1)",
+	CodeMaxStepRange:               "maxStep must be between 0-%d (2^SeqBit)",
+	CodeTimelineRange:              "timeline must be between 0-%d (2^TimelineBit-1)",
+	CodeStartTimelineLate:          "the generator has already generated ids, the start timeline can not be set",
+	CodeStateTimelines:             "the saved timeline state does not match the configured number of timelines",
+	CodeEmptyKeyPrefix:             "key prefix must not be empty",
+	CodeUUIDFormat:                 "invalid %s: %s",
+	CodeTimeUUIDBits:               "MachineIDBit+TimelineBit+SeqBit exceeds %d bits, can not convert to timeuuid",
+	CodeTimeUUIDVersion:            "not a valid timeuuid (version 1)",
+	CodeTimeUUIDMulticast:          "timeuuid was not converted from an id (multicast bit not set)",
+	CodeTimeUUIDTime:               "timeuuid timestamp exceeds the configured time range or precision",
+	CodeTimeUUIDNode:               "timeuuid node exceeds the configured bits",
+	CodeULIDLength:                 "ULID must be 26 characters: %s",
+	CodeULIDChar:                   "ULID %q contains invalid character %q",
+	CodeULIDOverflow:               "ULID exceeds 128 bits: %s",
+	CodeULIDNotID:                  "ULID was not converted from an id",
+	CodeULIDLayout:                 "ULID was not converted from an id of the current settings",
+	CodeUUIDv7Invalid:              "not a valid UUIDv7",
+	CodeUUIDv7Layout:               "UUIDv7 was not converted from an id of the current settings",
+	CodeBeforeUnixEpoch:            "id time is earlier than the unix epoch",
+	CodeTimeRange:                  "time is out of range",
+	CodeTimeBeforeEpoch:            "time is earlier than the epoch",
+	CodeTimeBitsOverflow:           "time exceeds the range of the time bits",
+	CodeLowBitsOverflow:            "low bits exceed the configured bits",
+	CodeVaultSignature:             "id block signature verification failed",
+	CodeVaultLayout:                "id block content does not match the id layout",
+	CodeVaultIndex:                 "index %d is out of the id block range [0,%d)",
+	CodeVaultMachineID:             "machine ID (%d) of the id block is assigned to an online node",
+	CodeVaultSettings:              "id block settings differ",
+	CodeVaultOverlap:               "id blocks overlap: machine ID %d timeline %d time units [%d,%d) and [%d,%d)",
+	CodeVaultUnsupported:           "id blocks do not support DatacenterBit or VersionBit",
+	CodeVaultTimeline:              "timeline exceeds the TimelineBit range",
+	CodeVaultKey:                   "signing key must not be empty",
+	CodeVaultOverflow:              "id block exceeds the range of the time bits",
+	CodeSystemNameEmpty:            "system name must not be empty",
+	CodeSystemRegistered:           "system %s is already registered",
+	CodeSystemUnknown:              "system %s is not registered",
+	CodeSystemField:                "invalid %s of system %s: %s",
+	CodeCSVEmpty:                   "CSV is empty",
+	CodeCSVMachineID:               "CSV has no machine_id column",
+	CodeCSVMachineIDValue:          "CSV line %d: invalid machine_id: %s",
+	CodeCSVLine:                    "CSV line %d: %s",
+	CodeUnknownPlacement:           "unsupported timeline placement: %s",
+	CodeBitsSumFlag:                "VersionBit+TimeBit+DatacenterBit+MachineIDBit+TimelineBit+SeqBit+1(flag bit) != 63",
+	CodeBitsSum:                    "VersionBit+TimeBit+DatacenterBit+MachineIDBit+TimelineBit+SeqBit != 63",
+	CodePlacementUnsupported:       "unsupported timeline placement",
+	CodeVersionRange:               "Version must be between 0-%d (2^VersionBit-1), got %d",
+	CodeNotTimeOrdered:             "ids are not time ordered when the timeline is above the time (TimelineAboveTime), operation not supported",
+	CodeVersionMismatch:            "id version differs from the configured Version",
+	CodeTimeUnit:                   "TimeUnit (%s) must be a multiple of 1µs that divides 1s, e.g. 1µs, 100µs, 1ms, 10ms, 1s",
+	CodeTimeBitsTooMany:            "too many time bits, the time range exceeds unix nano for the time unit",
+	CodeEpochInFuture:              "epoch must not be later than the current time",
+	CodeDatacenterRange:            "DatacenterID must be between 0-%d (2^DatacenterBit-1), got %d",
+	CodeMachineIDEnvName:           "machine ID source is missing the env variable name: %s",
+	CodeMachineIDSource:            "unsupported machine ID source: %s, must be a number, private-ip or env:NAME",
+	CodeMachineIDSourceMissing:     "machine ID source is not configured",
+	CodeEnvEmpty:                   "env variable %s is empty",
+	CodeEnvMachineID:               "env variable %s=%s is not a valid machine ID",
+	CodeSettingsFormat:             "unsupported settings file format: %s, must be .json, .yaml or .yml",
+	CodeSettingsFile:               "%s: %v",
+	CodeSettingValueType:           "value of setting %s must be a number, string or boolean",
+	CodeYAMLNested:                 "line %d: only flat key: value is supported",
+	CodeYAMLColon:                  "line %d: missing colon in key: value",
+	CodeYAMLValue:                  "line %d: setting %s has no value",
+	CodeYAMLDuplicate:              "line %d: duplicate setting %s",
+	CodeSettingUnknown:             "unknown setting: %s",
+	CodeSettingBits:                "setting %s must be an integer between 0-63, got %s",
+	CodeSettingInteger:             "setting %s must be an integer, got %s",
+	CodeSettingEpoch:               "setting epoch must be RFC3339 (e.g. 2020-01-01T00:00:00Z), got %s",
+	CodeSettingTimeUnit:            "setting time_unit must be a duration (e.g. 1ms, 10ms, 1s), got %s",
+	CodeSettingFlagBit:             "setting flag_bit must be true or false, got %s",
+	CodeSelfTestEpoch:              "self test failed: Epoch (%d) is less than a year after 1970, seconds or milliseconds may have been used, Epoch must be unix nanoseconds",
+	CodeSelfTestTimeRange:          "self test failed: the id time part runs out at %s (%s remaining), use more time bits or a later epoch",
+	CodeSelfTestClockStuck:         "self test failed: the clock is not moving, two reads %s apart returned %d and %d",
+	CodeSelfTestGenerate:           "self test failed: generating id: %v",
+	CodeSelfTestMachineID:          "self test failed: id %d decodes to machine ID %d, want %d",
+	CodeSelfTestDatacenter:         "self test failed: id %d decodes to datacenter ID %d, want %d",
+	CodeSelfTestRecompose:          "self test failed: id %d recomposes to %d, the layout does not match the settings",
+	CodeSelfTestTime:               "self test failed: time %[2]s of id %[1]d does not match the clock %[3]s, check Epoch and TimeUnit",
+	CodeSelfTestReadable:           "self test failed: readable form of id %d (%s) decodes to %d (%v)",
+	CodeSelfTestNotIncreasing:      "self test failed: ids are not increasing, %d was followed by %d",
+	CodeUnsupportedLanguage:        "unsupported language: %s",
+	CodeClassName:                  "class name must contain only letters, digits and underscores and must not start with a digit",
+	CodeJavaPackage:                "invalid Java package name",
+	CodeUnsupportedDialect:         "unsupported SQL dialect: %s",
+	CodeFunctionPrefix:             "function prefix must contain only letters, digits and underscores and must not start with a digit",
+	CodeEpochMicrosecond:           "epoch must be accurate to the microsecond",
+	CodeUDFVersion:                 "SQL functions do not support VersionBit, decode each version separately",
+	CodeAllocatorClosed:            "machine ID allocator is closed",
+	CodeLeaseLost:                  "etcd lease of the machine ID is lost",
+	CodeMachineIDTaken:             "machine ID %d is taken by another instance or has expired",
+	CodeHeartbeatTTL:               "Heartbeat must be less than TTL",
+	CodeMachineIDBitRange:          "MachineIDBit must not exceed %d",
+	CodeEnvValue:                   "env variable %s=%s: %v",
+	CodeOrdinalRange:               "pod ordinal %d plus offset %d is out of the machine ID range 0-%d (2^MachineIDBit-1)",
+	CodeOrdinalParse:               "cannot parse the StatefulSet pod ordinal",
+	CodeExpvarExists:               "expvar %s already exists",
+	CodeNoBenchResult:              "no benchmark results",
+}
+
+// ChineseMessages 中文错误信息，通过SetTranslator(ChineseMessages)启用
+var ChineseMessages = Messages{
+	CodeClockMovedBack:             "时钟回退",
+	CodeTimelinesExhausted:         "时钟回退太频繁，请调整服务器时钟同步策略或增加时间线数量",
+	CodeBreakerOpen:                "时钟回退过于频繁，生成器已熔断，请检查服务器时钟同步",
+	CodeInvalidMachineID:           "machineID超出范围",
+	CodeBackwardRejected:           "时钟回退，按时钟回退策略拒绝生成",
+	CodeRateLimited:                "超过生成速率上限",
+	CodeTimeOverflow:               "当前时间偏移量已超过最大限制，请设置更多的时间位数或设置一个更近的基准时间",
+	CodeBeforeEpoch:                "当前时间早于基准时间(Epoch)，请检查服务器时钟或设置一个更早的基准时间",
+	CodeNotMonotonic:               "时钟回退超过等待上限，无法保证id严格递增",
+	CodeFenced:                     "生成器已失效，机器ID可能已被其他节点使用",
+	CodeWaitTimeout:                "生成id所需的等待超出了限定时长",
+	CodeGeneratorClosed:            "生成器已关闭",
+	CodeBackfillTime:               "回填的时间须早于生成器的创建时间",
+	CodeBackfillExhausted:          "回填时间所在时间单位的id已用完",
+	CodeReservationExpired:         "预留时间窗口已结束，请重新申请",
+	CodeNotInitialized:             "默认生成器未初始化，请先调用Init",
+	CodeAlreadyInitialized:         "默认生成器已初始化",
+	CodeFingerprintMismatch:        "id结构指纹不一致，与生成方的id结构不同",
+	CodeUnknownVersion:             "id的版本号未注册对应的id结构",
+	CodeClockBackwardDetail:        "%s(时钟回退%s，预计%s后恢复)",
+	CodeBreakerManualDetail:        "%s(须手动恢复)",
+	CodeBreakerRetryDetail:         "%s(%s后恢复)",
+	CodeMachineIDDetail:            "machineID 必须介于0-%d(2^MachineIDBit-1)之间，实际为%d",
+	CodeRateLimitDetail:            "%s(%s后可重试)",
+	CodeFingerprintDetail:          "%s(期望%s，实际%s)",
+	CodeNegative:                   "%s不能为负数",
+	CodeNotPositive:                "%s必须大于0",
+	CodeNil:                        "%s不能为空",
+	CodeEmptyPath:                  "文件路径不能为空",
+	CodeNegativeID:                 "id不能为负数",
+	CodeUnknownWaitPolicy:          "未知的等待方式",
+	CodeAlertPolicy:                "告警阈值和统计窗口必须大于0",
+	CodeDecayRange:                 "Decay必须介于0-1之间",
+	CodeEncodingEmpty:              "%s字符串不能为空",
+	CodeEncodingLeadingZero:        "%s字符串%q有多余的前导零",
+	CodeEncodingInvalidChar:        "%s字符串%q包含非法字符%q",
+	CodeEncodingOverflow:           "%s字符串超出64位：%s",
+	CodeBatchFormat:                "不支持的批量id格式",
+	CodeBatchCorrupted:             "批量id数据已损坏",
+	CodeBlockConflict:              "预留id块不能与序号填充、序号随机化、序号空间划分同时使用",
+	CodeBlockOverflow:              "预留%d个id超出时间位数所能表示的范围",
+	CodeIDsPerTickRange:            "idsPerTick须介于0-%d(2^SeqBit)之间",
+	CodeLeaseNotFound:              "机器ID %d 的租约不存在或已过期",
+	CodeReservationRenew:           "批量任务的预留不能续约",
+	CodeOwnerEmpty:                 "持有者名称不能为空",
+	CodeNoFreeMachineID:            "没有空闲的机器ID",
+	CodeRotationLayout:             "新旧配置的id结构必须相同，仅Epoch不同",
+	CodeRotationTimeBit:            "TimeBit不能小于2",
+	CodeRotationEpoch:              "新基准时间必须晚于旧基准时间且不晚于开始轮换的时间",
+	CodeRotationLate:               "重叠窗口结束前旧基准时间已用到时间部分最高位，请提前轮换",
+	CodeExhaustionWaitDrift:        "ExhaustionWait的maxDrift须为0",
+	CodeMaxDriftUnit:               "maxDrift须不小于一个时间单位(%s)",
+	CodeUnknownExhaustionPolicy:    "不支持的SeqExhaustionPolicy",
+	CodeNilPrimary:                 "主生成器不能为空",
+	CodeEmergencyPrimary:           "应急生成器只能作为降级链中的后备",
+	CodeNilFallback:                "后备生成器不能为空",
+	CodeUnsupportedEncoding:        "不支持的编码：%s",
+	CodeInvalidID:                  "id格式错误：%q",
+	CodeNullID:                     "id不能为NULL，可空的列请使用*ID",
+	CodeIDConvert:                  "不支持将%T转换为id",
+	CodeFieldNameEmpty:             "字段名不能为空",
+	CodeFieldDuplicate:             "字段%s重复",
+	CodeFieldBits:                  "字段%s的位数须介于1-63之间",
+	CodeFieldBitsSum:               "各字段位数之和须为63，实际为%d",
+	CodeFieldMissing:               "缺少字段%s",
+	CodeFieldUnknown:               "未知的字段%s",
+	CodeFieldValue:                 "字段%s的取值须介于0-%d之间，实际为%d",
+	CodeFieldGenerated:             "字段%s由生成器填充，不能指定取值",
+	CodeRegistryVersionBit:         "versionBit须介于1-16之间",
+	CodeRegistryVersionBitMismatch: "VersionBit须为%d，实际为%d",
+	CodeVersionRegistered:          "版本%d已注册",
+	CodeNoDefaultRegistry:          "未设置默认注册表，请先调用SetDefaultRegistry",
+	CodeMachineIPBits:              "bits必须介于1-32之间",
+	CodeNoPrivateIP:                "没有找到私有IPv4地址",
+	CodeMigrationTable:             "表名和列名不能为空",
+	CodeMigrationSerialRange:       "serial取值范围错误，须满足 1<=MinSerial<=MaxSerial",
+	CodeMigrationCutover:           "切换时间超出当前配置的时间范围",
+	CodeMigrationTooMany:           "原值数量过多，切换时间之前的时间单位不足以容纳，请推迟切换时间或增加序号位数",
+	CodeMigrationSpan:              "原值跨度超过切换后最小id，无法无冲突迁移",
+	CodeMigrationOutOfRange:        "%d 不在迁移范围[%d,%d]内",
+	CodeMigrationNegative:          "迁移后的id必须为正数",
+	CodeMigrationInsufficient:      "迁移后的id范围不足以容纳全部原值",
+	CodeMigrationConflict:          "迁移后的最大id(%d)与切换后生成的id(>=%d)冲突",
+	CodeMonotonicReservation:       "严格递增模式不能与序号空间划分同时使用",
+	CodeReadableLength:             "可读格式长度应为%d位",
+	CodeReadableDigits:             "可读格式只能包含数字",
+	CodeReadableLocalTime:          "本地时区不存在该时间",
+	CodeReadableTimeRange:          "可读格式的时间超出范围",
+	CodeReadableSubsecond:          "可读格式秒以下部分超出范围",
+	CodeReadableRest:               "可读格式剩余部分超出范围",
+	CodeReadableBeforeEpoch:        "可读格式的时间早于基准时间",
+	CodeReadableTimeOverflow:       "可读格式的时间超出时间位数所能表示的范围",
+	CodeObfuscateKey:               "混淆密钥不能为空",
+	CodeOverflowRotation:           "OverflowSwitchEpoch须指定Rotation",
+	CodeOverflowOldSettings:        "生成器须使用Rotation的旧配置",
+	CodeOverflowTooLate:            "生成器已用到时间部分最高位，无法切换到新基准时间",
+	CodeOverflowCallback:           "OverflowCallback须指定OnOverflow",
+	CodeUnknownOverflowAction:      "不支持的OverflowAction",
+	CodePaddingRatio:               "填充比例必须介于0-0.9之间",
+	CodePaddingKey:                 "填充密钥不能为空",
+	CodePaddingRandom:              "序号填充不能与序号随机化同时使用",
+	CodePaddingReservation:         "序号填充不能与序号空间划分同时使用",
+	CodePoolShard:                  "生成器池的分片不能开启序号填充、序号空间划分或切换机器ID",
+	CodePoolPersister:              "生成器池不支持时间线状态持久化(StatePersister)",
+	CodePoolShards:                 "分片数不能超过%d(2^(SeqBit-1))",
+	CodePrefetchSize:               "MinSize不能大于MaxSize",
+	CodePrefetchClosed:             "预取缓冲已关闭",
+	CodeRateLimitBurst:             "n不能超过速率限制的burst(%d)",
+	CodeReadableV2Parts:            "v2可读格式应包含%d个以'-'分隔的部分",
+	CodeReadableV2Version:          "v2可读格式的版本号%d与当前配置的Version(%d)不同",
+	CodeReadableV2Time:             "v2可读格式的时间部分应为%s",
+	CodeReadableV2TimeRange:        "v2可读格式的时间超出范围",
+	CodeReadableV2Field:            "v2可读格式的%c部分应为%c加%d位数字",
+	CodeReadableV2FieldRange:       "v2可读格式的%c部分超出范围(最大%d)",
+	CodeRedisTimeRange:             "Redis服务器时间超出当前配置的时间范围",
+	CodeEmergencyExhausted:         "当前时间单位的应急序号已用完(%d)",
+	CodeCallerBits:                 "callerBits必须小于SeqBit(%d)",
+	CodeRandomReservation:          "序号随机化不能与序号空间划分同时使用",
+	CodeReservationDisabled:        "未开启序号空间划分",
+	CodeCallerNameEmpty:            "调用方名称不能为空",
+	CodeCallersFull:                "调用方数量已达上限，请增加callerBits",
+	CodeCallerUnknown:              "调用方%d未登记",
+	CodeMaxOffsetRange:             "maxOffset须介于0-%d(2^SeqBit-1)之间",
+	CodeMaxStepRange:               "maxStep须介于0-%d(2^SeqBit)之间",
+	CodeTimelineRange:              "timeline 必须介于0-%d(2^TimelineBit-1)之间",
+	CodeStartTimelineLate:          "生成器已生成过id，不能再设置初始时间线",
+	CodeStateTimelines:             "保存的时间线状态与配置的时间线数不符",
+	CodeEmptyKeyPrefix:             "key前缀不能为空",
+	CodeUUIDFormat:                 "%s格式错误：%s",
+	CodeTimeUUIDBits:               "MachineIDBit+TimelineBit+SeqBit 超过%d位，无法转换为timeuuid",
+	CodeTimeUUIDVersion:            "不是合法的timeuuid(version 1)",
+	CodeTimeUUIDMulticast:          "timeuuid不是由id转换而来(未设置多播位)",
+	CodeTimeUUIDTime:               "timeuuid的时间戳超出当前配置的时间范围或精度",
+	CodeTimeUUIDNode:               "timeuuid的node字段超出当前配置的位数",
+	CodeULIDLength:                 "ULID长度应为26位：%s",
+	CodeULIDChar:                   "ULID%q包含非法字符%q",
+	CodeULIDOverflow:               "ULID超出128位：%s",
+	CodeULIDNotID:                  "ULID不是由id转换而来",
+	CodeULIDLayout:                 "ULID不是由当前配置的id转换而来",
+	CodeUUIDv7Invalid:              "不是合法的UUIDv7",
+	CodeUUIDv7Layout:               "UUIDv7不是由当前配置的id转换而来",
+	CodeBeforeUnixEpoch:            "id的时间早于unix零点",
+	CodeTimeRange:                  "时间超出范围",
+	CodeTimeBeforeEpoch:            "时间早于基准时间",
+	CodeTimeBitsOverflow:           "时间超出时间位数所能表示的范围",
+	CodeLowBitsOverflow:            "低位超出当前配置的位数",
+	CodeVaultSignature:             "id块签名校验失败",
+	CodeVaultLayout:                "id块内容不符合id结构",
+	CodeVaultIndex:                 "下标%d超出id块范围[0,%d)",
+	CodeVaultMachineID:             "id块使用的机器ID(%d)已分配给在线节点",
+	CodeVaultSettings:              "id块的配置不一致",
+	CodeVaultOverlap:               "id块重叠：机器ID %d 时间线 %d 时间单位 [%d,%d) 与 [%d,%d)",
+	CodeVaultUnsupported:           "id块不支持数据中心位(DatacenterBit)及版本位(VersionBit)",
+	CodeVaultTimeline:              "时间线超出TimelineBit范围",
+	CodeVaultKey:                   "签名密钥不能为空",
+	CodeVaultOverflow:              "id块超出时间位数所能表示的范围",
+	CodeSystemNameEmpty:            "系统名称不能为空",
+	CodeSystemRegistered:           "系统%s已登记",
+	CodeSystemUnknown:              "系统%s未登记",
+	CodeSystemField:                "系统%[2]s的%[1]s错误：%[3]s",
+	CodeCSVEmpty:                   "CSV内容为空",
+	CodeCSVMachineID:               "CSV缺少machine_id列",
+	CodeCSVMachineIDValue:          "CSV第%d行machine_id错误：%s",
+	CodeCSVLine:                    "CSV第%d行：%s",
+	CodeUnknownPlacement:           "不支持的时间线位置：%s",
+	CodeBitsSumFlag:                "VersionBit+TimeBit+DatacenterBit+MachineIDBit+TimelineBit+SeqBit+1(标记位) !=63",
+	CodeBitsSum:                    "VersionBit+TimeBit+DatacenterBit+MachineIDBit+TimelineBit+SeqBit !=63",
+	CodePlacementUnsupported:       "不支持的时间线位置",
+	CodeVersionRange:               "Version 必须介于0-%d(2^VersionBit-1)之间，实际为%d",
+	CodeNotTimeOrdered:             "时间线位于时间之上(TimelineAboveTime)时id不按时间排序，不支持该操作",
+	CodeVersionMismatch:            "id的版本号与当前配置的Version不同",
+	CodeTimeUnit:                   "TimeUnit(%s)须为1微秒的整数倍且能整除1秒，如1µs、100µs、1ms、10ms、1s",
+	CodeTimeBitsTooMany:            "时间位数过多，按当前时间单位超出可表示的时间范围(unix nano)",
+	CodeEpochInFuture:              "基准时间epoch须不晚于当前时间",
+	CodeDatacenterRange:            "DatacenterID 必须介于0-%d(2^DatacenterBit-1)之间，实际为%d",
+	CodeMachineIDEnvName:           "机器ID来源缺少环境变量名：%s",
+	CodeMachineIDSource:            "不支持的机器ID来源：%s，须为数字、private-ip或env:NAME",
+	CodeMachineIDSourceMissing:     "未配置机器ID来源",
+	CodeEnvEmpty:                   "环境变量%s为空",
+	CodeEnvMachineID:               "环境变量%s=%s不是合法的机器ID",
+	CodeSettingsFormat:             "不支持的配置文件格式：%s，须为.json、.yaml或.yml",
+	CodeSettingsFile:               "%s：%v",
+	CodeSettingValueType:           "配置项%s的值须为数字、字符串或布尔值",
+	CodeYAMLNested:                 "第%d行：仅支持单层的key: value",
+	CodeYAMLColon:                  "第%d行：缺少key: value中的冒号",
+	CodeYAMLValue:                  "第%d行：配置项%s缺少值",
+	CodeYAMLDuplicate:              "第%d行：重复的配置项%s",
+	CodeSettingUnknown:             "未知的配置项：%s",
+	CodeSettingBits:                "配置项%s须为0-63之间的整数，实际为%s",
+	CodeSettingInteger:             "配置项%s须为整数，实际为%s",
+	CodeSettingEpoch:               "配置项epoch须为RFC3339格式(如2020-01-01T00:00:00Z)，实际为%s",
+	CodeSettingTimeUnit:            "配置项time_unit须为时长(如1ms、10ms、1s)，实际为%s",
+	CodeSettingFlagBit:             "配置项flag_bit须为true或false，实际为%s",
+	CodeSelfTestEpoch:              "自检失败：Epoch(%d)距1970年不足一年，可能误用了秒或毫秒，Epoch应为unix纳秒",
+	CodeSelfTestTimeRange:          "自检失败：id时间部分将在%s用尽(剩余%s)，请设置更多的时间位数或更近的基准时间",
+	CodeSelfTestClockStuck:         "自检失败：时钟未走动，间隔%s两次读取的时间为%d、%d",
+	CodeSelfTestGenerate:           "自检失败：生成id出错：%v",
+	CodeSelfTestMachineID:          "自检失败：id %d 解析出的机器ID为%d，应为%d",
+	CodeSelfTestDatacenter:         "自检失败：id %d 解析出的数据中心ID为%d，应为%d",
+	CodeSelfTestRecompose:          "自检失败：id %d 按配置重新组合为%d，id结构与配置不一致",
+	CodeSelfTestTime:               "自检失败：id %d 的时间%s与时钟%s不符，请检查Epoch与TimeUnit",
+	CodeSelfTestReadable:           "自检失败：id %d 的可读格式%s还原为%d(%v)",
+	CodeSelfTestNotIncreasing:      "自检失败：id未递增，%d之后生成了%d",
+	CodeUnsupportedLanguage:        "不支持的语言：%s",
+	CodeClassName:                  "类名只能包含字母、数字和下划线，且不能以数字开头",
+	CodeJavaPackage:                "Java包名不合法",
+	CodeUnsupportedDialect:         "不支持的SQL方言：%s",
+	CodeFunctionPrefix:             "函数名前缀只能包含字母、数字和下划线，且不能以数字开头",
+	CodeEpochMicrosecond:           "基准时间须精确到微秒",
+	CodeUDFVersion:                 "SQL函数不支持版本位(VersionBit)，请按版本分别解析",
+	CodeAllocatorClosed:            "机器ID分配器已关闭",
+	CodeLeaseLost:                  "机器ID的etcd租约已丢失",
+	CodeMachineIDTaken:             "机器ID %d 已被其他实例占用或已过期",
+	CodeHeartbeatTTL:               "续约间隔须小于租约时长",
+	CodeMachineIDBitRange:          "MachineIDBit不能超过%d",
+	CodeEnvValue:                   "环境变量%s=%s：%v",
+	CodeOrdinalRange:               "pod序号%d加偏移%d超出机器ID范围0-%d(2^MachineIDBit-1)",
+	CodeOrdinalParse:               "无法解析StatefulSet pod序号",
+	CodeExpvarExists:               "expvar %s 已存在",
+	CodeNoBenchResult:              "没有对比结果",
+}
+
+// translatorHolder atomic.Value要求存入的值类型一致
+type translatorHolder struct {
+	t Translator
+}
+
+var translator atomic.Value
+
+// SetTranslator 设置运行时错误信息的翻译器，nil恢复为英文
+//   - 只影响Error()返回的文本，错误码及errors.Is的判断结果不变；可在运行中随时切换
+func SetTranslator(t Translator) {
+	translator.Store(translatorHolder{t: t})
+}
+
+// message 按当前翻译器生成错误信息，翻译器未设置或未覆盖该错误码时使用英文
+func message(code ErrorCode, args ...interface{}) string {
+	if holder, ok := translator.Load().(translatorHolder); ok && holder.t != nil {
+		if s := holder.t.Translate(code, args...); s != "" {
+			return s
+		}
+	}
+	return EnglishMessages.Translate(code, args...)
+}
+
+// Error 带错误码的运行时错误，Error()按SetTranslator设置的语言返回
+//   - 哨兵错误均为该类型且没有参数，可通过errors.Is判断；其他错误通过ErrorCodeOf或errors.As读取Code
+type Error struct {
+	Code ErrorCode
+	args []interface{} //信息模板的参数
+}
+
+// newError 创建哨兵错误
+func newError(code ErrorCode) error {
+	return &Error{Code: code}
+}
+
+// errorf 创建带参数的运行时错误
+func errorf(code ErrorCode, args ...interface{}) error {
+	return &Error{Code: code, args: args}
+}
+
+// NewError 创建带错误码的运行时错误，供子包(如machineid、metrics)使用，Error()同样按SetTranslator设置的语言返回
+//   - 无参数时可作为哨兵错误，通过errors.Is判断
+func NewError(code ErrorCode, args ...interface{}) error {
+	return &Error{Code: code, args: args}
+}
+
+func (e *Error) Error() string { return message(e.Code, e.args...) }
+
+func (e *Error) errorCode() ErrorCode { return e.Code }
+
+// ErrorCodeOf 错误对应的错误码，沿Unwrap链查找，不是本包的运行时错误时返回false
+//   - *ClockBackwardError返回具体原因的错误码，如CodeTimelinesExhausted
+func ErrorCodeOf(err error) (ErrorCode, bool) {
+	for err != nil {
+		if coded, ok := err.(interface{ errorCode() ErrorCode }); ok {
+			return coded.errorCode(), true
+		}
+		wrapper, ok := err.(interface{ Unwrap() error })
+		if !ok {
+			return "", false
+		}
+		err = wrapper.Unwrap()
+	}
+	return "", false
+}
@@ -0,0 +1,81 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// TestErrorMessages 默认英文，可切换为中文或自定义翻译
+func TestErrorMessages(t *testing.T) {
+	defer SetTranslator(nil)
+	backward := &ClockBackwardError{Backward: time.Millisecond, RetryAfter: 2 * time.Millisecond, Err: ErrTimelinesExhausted}
+	testCases := []struct {
+		name       string
+		translator Translator
+		err        error
+		want       string
+	}{
+		{name: "默认英文", err: ErrGeneratorClosed, want: "generator is closed"},
+		{name: "英文结构化错误", err: &RateLimitError{RetryAfter: time.Second}, want: "generation rate limit exceeded (retry in 1s)"},
+		{name: "英文嵌套原因", err: backward, want: fmt.Sprintf("%s (clock moved back 1ms, expected to recover in 2ms)", EnglishMessages[CodeTimelinesExhausted])},
+		{name: "英文超时", err: ErrWaitTimeout, want: "waiting to generate id exceeded the time limit"},
+		{name: "中文", translator: ChineseMessages, err: ErrGeneratorClosed, want: "生成器已关闭"},
+		{name: "中文结构化错误", translator: ChineseMessages, err: &BreakerOpenError{}, want: ChineseMessages[CodeBreakerOpen] + "(须手动恢复)"},
+		{name: "中文机器ID", translator: ChineseMessages, err: &MachineIDError{MachineID: 9, Max: 7}, want: "machineID 必须介于0-7(2^MachineIDBit-1)之间，实际为9"},
+		{name: "自定义翻译", translator: Messages{CodeFenced: "fenced!"}, err: ErrFenced, want: "fenced!"},
+		{name: "未覆盖的错误码使用英文", translator: Messages{CodeFenced: "fenced!"}, err: ErrGeneratorClosed, want: "generator is closed"},
+		{name: "英文带参数", err: errorf(CodeReadableV2Parts, 4), want: "readable v2 format must have 4 parts separated by '-'"},
+		{name: "中文带参数", translator: ChineseMessages, err: errorf(CodeReadableV2Parts, 4), want: "v2可读格式应包含4个以'-'分隔的部分"},
+		{name: "子包创建的错误", err: NewError(CodeExpvarExists, "idgen"), want: "expvar idgen already exists"},
+		{name: "子包创建的错误中文", translator: ChineseMessages, err: NewError(CodeLeaseLost), want: "机器ID的etcd租约已丢失"},
+		{name: "中文参数顺序", translator: ChineseMessages, err: errorf(CodeSystemField, "time_unit", "order", "bad"), want: "系统order的time_unit错误：bad"},
+	}
+	for _, tc := range testCases {
+		SetTranslator(tc.translator)
+		if got := tc.err.Error(); got != tc.want {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, got, tc.want)
+		}
+	}
+}
+
+// TestChineseMessagesComplete 中文信息覆盖全部错误码
+func TestChineseMessagesComplete(t *testing.T) {
+	for code := range EnglishMessages {
+		if _, ok := ChineseMessages[code]; !ok {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", "中文信息缺少错误码", code, "存在")
+		}
+	}
+	if len(ChineseMessages) != len(EnglishMessages) {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "错误码数量", len(ChineseMessages), len(EnglishMessages))
+	}
+}
+
+// TestErrorCodeOf 沿Unwrap链读取错误码，与语言无关
+func TestErrorCodeOf(t *testing.T) {
+	testCases := []struct {
+		name   string
+		err    error
+		want   ErrorCode
+		wantOk bool
+	}{
+		{name: "哨兵错误", err: ErrTimeOverflow, want: CodeTimeOverflow, wantOk: true},
+		{name: "包装的哨兵错误", err: fmt.Errorf("生成失败：%w", ErrFenced), want: CodeFenced, wantOk: true},
+		{name: "时钟回退", err: &ClockBackwardError{Err: ErrTimelinesExhausted}, want: CodeTimelinesExhausted, wantOk: true},
+		{name: "熔断", err: &BreakerOpenError{}, want: CodeBreakerOpen, wantOk: true},
+		{name: "等待超时", err: ErrWaitTimeout, want: CodeWaitTimeout, wantOk: true},
+		{name: "带参数的错误", err: func() error { _, err := new(IDGenerator).GenerateN(-1); return err }(), want: CodeNegative, wantOk: true},
+		{name: "其他错误", err: errors.New("other"), want: "", wantOk: false},
+		{name: "nil", err: nil, want: "", wantOk: false},
+	}
+	for _, tc := range testCases {
+		got, ok := ErrorCodeOf(tc.err)
+		if got != tc.want || ok != tc.wantOk {
+			t.Fatalf("【失败】-%s-got:%v,%v-want:%v,%v", tc.name, got, ok, tc.want, tc.wantOk)
+		}
+	}
+	if !errors.Is(&RateLimitError{}, ErrRateLimited) {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "errors.Is", false, true)
+	}
+}
@@ -0,0 +1,89 @@
+package generator
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"strconv"
+)
+
+// ID 生成器生成的id
+//   - String输出十进制
+//   - JSON序列化为字符串，避免JavaScript按双精度浮点数解析时丢失53位以上的精度；反序列化同时接受字符串和数字
+//   - 实现database/sql的Scanner/Valuer，按BIGINT读写
+type ID int64
+
+// ParseID 解析十进制id
+func ParseID(s string) (ID, error) {
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, errorf(CodeInvalidID, s)
+	}
+	return ID(v), nil
+}
+
+// Int64 转换为int64
+func (id ID) Int64() int64 {
+	return int64(id)
+}
+
+// String 十进制表示
+func (id ID) String() string {
+	return strconv.FormatInt(int64(id), 10)
+}
+
+// MarshalJSON 序列化为JSON字符串
+func (id ID) MarshalJSON() ([]byte, error) {
+	b := make([]byte, 0, 22)
+	b = append(b, '"')
+	b = strconv.AppendInt(b, int64(id), 10)
+	return append(b, '"'), nil
+}
+
+// UnmarshalJSON 从JSON字符串或数字反序列化，null保持不变
+func (id *ID) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	s := string(data)
+	if len(data) >= 2 && data[0] == '"' && data[len(data)-1] == '"' {
+		s = string(data[1 : len(data)-1])
+	}
+	v, err := ParseID(s)
+	if err != nil {
+		return err
+	}
+	*id = v
+	return nil
+}
+
+// Scan 实现sql.Scanner，支持BIGINT及字符串类型的列
+func (id *ID) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case int64:
+		*id = ID(v)
+		return nil
+	case []byte:
+		parsed, err := ParseID(string(v))
+		if err != nil {
+			return err
+		}
+		*id = parsed
+		return nil
+	case string:
+		parsed, err := ParseID(v)
+		if err != nil {
+			return err
+		}
+		*id = parsed
+		return nil
+	case nil:
+		return errorf(CodeNullID)
+	default:
+		return errorf(CodeIDConvert, src)
+	}
+}
+
+// Value 实现driver.Valuer，按int64写入
+func (id ID) Value() (driver.Value, error) {
+	return int64(id), nil
+}
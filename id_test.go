@@ -0,0 +1,104 @@
+package generator
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// TestIDJSON ID的JSON序列化
+func TestIDJSON(t *testing.T) {
+	testCases := []struct {
+		name string
+		id   ID
+		want string
+	}{
+		{name: "零", id: 0, want: `"0"`},
+		{name: "超过53位", id: 1<<62 + 1, want: `"4611686018427387905"`},
+		{name: "负数", id: -1, want: `"-1"`},
+	}
+	for _, tc := range testCases {
+		data, err := json.Marshal(tc.id)
+		if err != nil || string(data) != tc.want {
+			t.Fatalf("【失败】-%s-got:%s-want:%v", tc.name, data, tc.want)
+		}
+		var got ID
+		if err := json.Unmarshal(data, &got); err != nil || got != tc.id {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, got, tc.id)
+		}
+	}
+
+	unmarshalCases := []struct {
+		name    string
+		data    string
+		want    ID
+		wantErr bool
+	}{
+		{name: "字符串", data: `"123"`, want: 123},
+		{name: "数字", data: `123`, want: 123},
+		{name: "null保持不变", data: `null`, want: 7},
+		{name: "非数字", data: `"abc"`, want: 7, wantErr: true},
+		{name: "空字符串", data: `""`, want: 7, wantErr: true},
+		{name: "浮点数", data: `1.5`, want: 7, wantErr: true},
+	}
+	for _, tc := range unmarshalCases {
+		got := ID(7)
+		err := json.Unmarshal([]byte(tc.data), &got)
+		if (err != nil) != tc.wantErr || got != tc.want {
+			t.Fatalf("【失败】-%s-got:%v,%v-want:%v", tc.name, got, err, tc.want)
+		}
+	}
+
+	//作为结构体字段
+	var v struct {
+		ID ID `json:"id"`
+	}
+	v.ID = 9007199254740993
+	data, _ := json.Marshal(v)
+	if string(data) != `{"id":"9007199254740993"}` {
+		t.Fatalf("【失败】-%s-got:%s-want:%v", "结构体字段", data, `{"id":"9007199254740993"}`)
+	}
+}
+
+// TestIDSQL ID的Scanner/Valuer
+func TestIDSQL(t *testing.T) {
+	testCases := []struct {
+		name    string
+		src     interface{}
+		want    ID
+		wantErr bool
+	}{
+		{name: "int64", src: int64(123), want: 123},
+		{name: "[]byte", src: []byte("456"), want: 456},
+		{name: "string", src: "789", want: 789},
+		{name: "NULL", src: nil, wantErr: true},
+		{name: "不支持的类型", src: 1.5, wantErr: true},
+		{name: "非数字", src: "abc", wantErr: true},
+	}
+	for _, tc := range testCases {
+		var got ID
+		err := got.Scan(tc.src)
+		if (err != nil) != tc.wantErr || got != tc.want {
+			t.Fatalf("【失败】-%s-got:%v,%v-want:%v", tc.name, got, err, tc.want)
+		}
+	}
+
+	if v, err := ID(123).Value(); err != nil || v != int64(123) {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "Value", v, 123)
+	}
+}
+
+// TestGenerateID Generate返回ID类型
+func TestGenerateID(t *testing.T) {
+	idGen, _ := NewGenerator(1)
+	id, err := idGen.Generate()
+	if err != nil {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "生成", err, nil)
+	}
+	if got := idGen.Decompose(id.Int64()).MachineID; got != 1 {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "机器ID", got, 1)
+	}
+	parsed, err := ParseID(id.String())
+	if err != nil || parsed != id {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "ParseID", parsed, id)
+	}
+}
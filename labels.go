@@ -0,0 +1,61 @@
+package generator
+
+import (
+	"context"
+	"runtime/pprof"
+)
+
+// 性能分析标签
+const (
+	LabelGenerator = "snowflake_generator" //生成器名称
+	LabelBiz       = "snowflake_biz"       //业务标识
+	LabelSubsystem = "snowflake_subsystem" //子生成器的子系统名称
+)
+
+// SetProfileLabels 设置性能分析(pprof)标签，多个生成器的服务中可区分各生成器的CPU开销
+//   - 生成器相关的后台goroutine(如Prefetcher的补充)带有该标签，须在创建前设置
+//   - GenerateCtx生成期间自动附加该标签；Generate在调用方goroutine中执行，标签由调用方通过LabelContext+pprof.Do附加
+func (idGen *IDGenerator) SetProfileLabels(name, biz string) {
+	idGen.mutex.Lock()
+	defer idGen.mutex.Unlock()
+	idGen.labels = nil
+	if name != "" {
+		idGen.labels = append(idGen.labels, LabelGenerator, name)
+	}
+	if biz != "" {
+		idGen.labels = append(idGen.labels, LabelBiz, biz)
+	}
+}
+
+// profileLabels 性能分析标签
+func (idGen *IDGenerator) profileLabels() []string {
+	idGen.mutex.Lock()
+	defer idGen.mutex.Unlock()
+	return idGen.labels
+}
+
+// LabelContext 在ctx上附加生成器的性能分析标签，如：
+//
+//	pprof.Do(idGen.LabelContext(ctx), pprof.Labels(), func(ctx context.Context) { idGen.Generate() })
+func (idGen *IDGenerator) LabelContext(ctx context.Context) context.Context {
+	if labels := idGen.profileLabels(); len(labels) > 0 {
+		return pprof.WithLabels(ctx, pprof.Labels(labels...))
+	}
+	return ctx
+}
+
+// profileLabeler 带有性能分析标签的生成器
+type profileLabeler interface {
+	profileLabels() []string
+}
+
+// goLabeled 启动后台goroutine，gen带有性能分析标签时附加到该goroutine
+func goLabeled(gen interface{}, f func()) {
+	labeler, ok := gen.(profileLabeler)
+	if !ok || len(labeler.profileLabels()) == 0 {
+		go f()
+		return
+	}
+	labels := pprof.Labels(labeler.profileLabels()...)
+	go pprof.Do(context.Background(), labels, func(context.Context) { f() })
+}
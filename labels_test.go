@@ -0,0 +1,41 @@
+package generator
+
+import (
+	"bytes"
+	"context"
+	"runtime/pprof"
+	"strings"
+	"testing"
+)
+
+// TestProfileLabels 性能分析标签
+func TestProfileLabels(t *testing.T) {
+	idGen, _ := NewGenerator(1)
+	idGen.SetProfileLabels("order", "trade")
+
+	//后台goroutine带有标签
+	p, err := NewPrefetcher(idGen, PrefetchConfig{})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer p.Close()
+	p.Next()
+
+	var buf bytes.Buffer
+	pprof.Lookup("goroutine").WriteTo(&buf, 1)
+	for _, want := range []string{`"snowflake_generator":"order"`, `"snowflake_biz":"trade"`} {
+		if !strings.Contains(buf.String(), want) {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", "后台goroutine标签", buf.String(), want)
+		}
+	}
+
+	//LabelContext
+	ctx := idGen.LabelContext(context.Background())
+	if got, _ := pprof.Label(ctx, LabelGenerator); got != "order" {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "LabelContext", got, "order")
+	}
+	idGen.SetProfileLabels("", "")
+	if ctx := idGen.LabelContext(context.Background()); ctx != context.Background() {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "清除标签", ctx, context.Background())
+	}
+}
@@ -0,0 +1,183 @@
+package generator
+
+import (
+	"time"
+)
+
+// Layout中由生成器填充的字段，其余字段为取值固定的静态字段(如地域、租户、机器ID)
+const (
+	FieldTime     = "time"     //时间，必选
+	FieldTimeline = "timeline" //时间线，可选
+	FieldSeq      = "seq"      //序号，必选
+)
+
+// LayoutField id结构中的一个字段
+type LayoutField struct {
+	Name string //字段名
+	Bits uint64 //位数
+}
+
+// Layout 按字段名自定义的id结构，字段由高位到低位排列
+//   - time、seq、timeline由生成器填充，其余字段为静态字段，取值在创建LayoutGenerator时指定
+//   - 各字段位数之和须为63；字段顺序任意，time位于最高位时id按时间排序
+//
+// 如：
+//
+//	time(41)|region(3)|tenant(4)|machine(6)|timeline(1)|seq(8)
+type Layout struct {
+	fields []LayoutField
+	shifts map[string]uint64
+	index  map[string]int
+}
+
+// NewLayout 按由高位到低位的顺序声明字段，创建id结构
+func NewLayout(fields ...LayoutField) (*Layout, error) {
+	return newLayout(false, fields)
+}
+
+// newLayout 创建id结构，allowSignBit为true时允许各字段位数之和为64(最高字段占用符号位，按无符号数解析)
+func newLayout(allowSignBit bool, fields []LayoutField) (*Layout, error) {
+	l := &Layout{fields: append([]LayoutField(nil), fields...), shifts: make(map[string]uint64), index: make(map[string]int)}
+	var bits uint64
+	for i, f := range fields {
+		if f.Name == "" {
+			return nil, errorf(CodeFieldNameEmpty)
+		}
+		if _, exist := l.index[f.Name]; exist {
+			return nil, errorf(CodeFieldDuplicate, f.Name)
+		}
+		if f.Bits == 0 || f.Bits > 63 {
+			return nil, errorf(CodeFieldBits, f.Name)
+		}
+		l.index[f.Name] = i
+		bits += f.Bits
+	}
+	if bits != 63 && !(allowSignBit && bits == 64) {
+		return nil, errorf(CodeFieldBitsSum, bits)
+	}
+	for _, name := range []string{FieldTime, FieldSeq} {
+		if _, exist := l.index[name]; !exist {
+			return nil, errorf(CodeFieldMissing, name)
+		}
+	}
+
+	var shift uint64
+	for i := len(fields) - 1; i >= 0; i-- {
+		l.shifts[fields[i].Name] = shift
+		shift += fields[i].Bits
+	}
+	return l, nil
+}
+
+// Fields 由高位到低位的字段
+func (l *Layout) Fields() []LayoutField {
+	return append([]LayoutField(nil), l.fields...)
+}
+
+// bits 字段的位数，不存在时为0
+func (l *Layout) bits(name string) uint64 {
+	if i, exist := l.index[name]; exist {
+		return l.fields[i].Bits
+	}
+	return 0
+}
+
+// Compose 按各字段的取值组合id，未指定的字段为0，取值超出字段位数时返回错误
+func (l *Layout) Compose(values map[string]int64) (int64, error) {
+	var id int64
+	for name, v := range values {
+		i, exist := l.index[name]
+		if !exist {
+			return 0, errorf(CodeFieldUnknown, name)
+		}
+		if max := int64(1)<<l.fields[i].Bits - 1; v < 0 || v > max {
+			return 0, errorf(CodeFieldValue, name, max, v)
+		}
+		id |= v << l.shifts[name]
+	}
+	return id, nil
+}
+
+// Decompose 将id按字段名解析
+func (l *Layout) Decompose(id int64) map[string]int64 {
+	values := make(map[string]int64, len(l.fields))
+	for _, f := range l.fields {
+		values[f.Name] = id >> l.shifts[f.Name] & (int64(1)<<f.Bits - 1)
+	}
+	return values
+}
+
+// LayoutGenerator 按自定义id结构生成id
+//   - 时间、时间线、序号由内部的IDGenerator维护，时钟回退处理、等待策略、Hooks等与IDGenerator相同
+//   - 内部生成器的id结构为 时间|静态字段|时间线|序号(静态字段取值为0)，生成后按Layout重新排列
+type LayoutGenerator struct {
+	layout *Layout
+	idGen  *IDGenerator
+	static int64 //静态字段组合后的值
+}
+
+// NewLayoutGenerator 创建按layout生成id的生成器
+//   - values 为各静态字段的取值，同一业务的不同实例须保证静态字段的组合不同；不能指定time、timeline、seq
+//   - settings 提供Epoch、TimeUnit、Clock等，其中的各部分位数、Placement、FlagBit、DatacenterBit由layout决定，忽略settings中的设置
+func NewLayoutGenerator(layout *Layout, values map[string]int64, settings Settings) (*LayoutGenerator, error) {
+	for _, name := range []string{FieldTime, FieldTimeline, FieldSeq} {
+		if _, exist := values[name]; exist {
+			return nil, errorf(CodeFieldGenerated, name)
+		}
+	}
+	static, err := layout.Compose(values)
+	if err != nil {
+		return nil, err
+	}
+
+	settings.TimeBit = layout.bits(FieldTime)
+	settings.TimelineBit = layout.bits(FieldTimeline)
+	settings.SeqBit = layout.bits(FieldSeq)
+	settings.MachineIDBit = 63 - settings.TimeBit - settings.TimelineBit - settings.SeqBit
+	settings.DatacenterBit, settings.DatacenterID = 0, 0
+	settings.Placement, settings.FlagBit = TimelineBelowMachine, false
+	idGen, err := NewGeneratorWithSettings(0, settings)
+	if err != nil {
+		return nil, err
+	}
+	return &LayoutGenerator{layout: layout, idGen: idGen, static: static}, nil
+}
+
+// Generate 生成id
+func (g *LayoutGenerator) Generate() (ID, error) {
+	id, err := g.idGen.generate()
+	if err != nil {
+		return 0, err
+	}
+	return ID(g.relayout(id)), nil
+}
+
+// relayout 将内部生成器的id按Layout重新排列
+func (g *LayoutGenerator) relayout(id int64) int64 {
+	presets := g.idGen.settings.presets
+	shifts := g.layout.shifts
+	timePart := (id & presets.maskTime) >> presets.shiftTimeBit
+	timeline := (id & presets.maskTimeline) >> presets.shiftTimelineBit
+	seq := (id & presets.maskSeq) >> presets.shiftSeq
+	return g.static | timePart<<shifts[FieldTime] | timeline<<shifts[FieldTimeline] | seq<<shifts[FieldSeq]
+}
+
+// Decompose 将id按字段名解析
+func (g *LayoutGenerator) Decompose(id int64) map[string]int64 {
+	return g.layout.Decompose(id)
+}
+
+// Time id的生成时间(精确到时间单位)
+func (g *LayoutGenerator) Time(id int64) time.Time {
+	return g.idGen.timestamp(g.layout.Decompose(id)[FieldTime])
+}
+
+// Layout 生成器的id结构
+func (g *LayoutGenerator) Layout() *Layout {
+	return g.layout
+}
+
+// Generator 内部的IDGenerator，用于设置Hooks、等待策略等；其Generate、Decompose按内部id结构，不应直接使用
+func (g *LayoutGenerator) Generator() *IDGenerator {
+	return g.idGen
+}
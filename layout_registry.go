@@ -0,0 +1,95 @@
+package generator
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrUnknownVersion id的版本号未在LayoutRegistry中注册
+var ErrUnknownVersion = newError(CodeUnknownVersion)
+
+// LayoutRegistry 按id中的版本号选择id结构解析，用于在系统生命周期内迁移id结构
+//   - 各版本的配置使用相同的VersionBit，版本位总是位于最高位，无需知道id结构即可读取版本号
+//   - 新结构使用更大的版本号时，新版本的id总是大于旧版本的id，切换后id仍保持递增
+//   - 可在运行中注册新版本，并发安全
+type LayoutRegistry struct {
+	versionBit  uint64
+	mutex       sync.RWMutex
+	decomposers map[int64]*Decomposer
+}
+
+// NewLayoutRegistry 创建版本位为versionBit(1-16)的注册表
+func NewLayoutRegistry(versionBit uint64) (*LayoutRegistry, error) {
+	if versionBit < 1 || versionBit > 16 {
+		return nil, errorf(CodeRegistryVersionBit)
+	}
+	return &LayoutRegistry{versionBit: versionBit, decomposers: make(map[int64]*Decomposer)}, nil
+}
+
+// Register 注册settings.Version对应的id结构
+//   - settings.VersionBit须与注册表相同；同一版本号只能注册一次，避免已生成的id被按不同结构解析
+func (r *LayoutRegistry) Register(settings Settings) error {
+	if settings.VersionBit != r.versionBit {
+		return errorf(CodeRegistryVersionBitMismatch, r.versionBit, settings.VersionBit)
+	}
+	d, err := NewDecomposer(settings)
+	if err != nil {
+		return err
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if _, exist := r.decomposers[settings.Version]; exist {
+		return errorf(CodeVersionRegistered, settings.Version)
+	}
+	r.decomposers[settings.Version] = d
+	return nil
+}
+
+// Version id的版本号
+func (r *LayoutRegistry) Version(id int64) int64 {
+	return int64(uint64(id) << 1 >> (64 - r.versionBit))
+}
+
+// Decomposer 版本号对应的Decomposer，未注册时返回false
+func (r *LayoutRegistry) Decomposer(version int64) (*Decomposer, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	d, ok := r.decomposers[version]
+	return d, ok
+}
+
+// Decompose 按id的版本号选择id结构，将id解析成time、seq等部分；版本号未注册时返回ErrUnknownVersion
+func (r *LayoutRegistry) Decompose(id int64) (*IDCompose, error) {
+	d, ok := r.Decomposer(r.Version(id))
+	if !ok {
+		return nil, ErrUnknownVersion
+	}
+	return d.Decompose(id), nil
+}
+
+// Time 按id的版本号选择id结构，返回id的生成时间
+func (r *LayoutRegistry) Time(id int64) (time.Time, error) {
+	d, ok := r.Decomposer(r.Version(id))
+	if !ok {
+		return time.Time{}, ErrUnknownVersion
+	}
+	return d.Time(id), nil
+}
+
+// 包级默认注册表，由SetDefaultRegistry设置
+var defaultRegistry atomic.Value //*LayoutRegistry
+
+// SetDefaultRegistry 设置包级默认注册表，供RegistryDecompose使用
+func SetDefaultRegistry(r *LayoutRegistry) {
+	defaultRegistry.Store(r)
+}
+
+// RegistryDecompose 使用包级默认注册表解析id，适用于只需一套注册表、不便传递*LayoutRegistry的应用
+func RegistryDecompose(id int64) (*IDCompose, error) {
+	r, _ := defaultRegistry.Load().(*LayoutRegistry)
+	if r == nil {
+		return nil, errorf(CodeNoDefaultRegistry)
+	}
+	return r.Decompose(id)
+}
@@ -0,0 +1,116 @@
+package generator
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestVersionBits 版本位位于最高位，Decompose返回版本号，新版本的id总是大于旧版本的id
+func TestVersionBits(t *testing.T) {
+	v1 := Settings{VersionBit: 2, Version: 1, TimeBit: 41, MachineIDBit: 8, TimelineBit: 1, SeqBit: 11, Epoch: DefaultEpoch}
+	v2 := Settings{VersionBit: 2, Version: 2, TimeBit: 39, MachineIDBit: 12, TimelineBit: 1, SeqBit: 9, Epoch: DefaultEpoch, TimeUnit: 10 * time.Millisecond}
+	gen1, err := NewGeneratorWithSettings(200, v1)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	gen2, err := NewGeneratorWithSettings(3000, v2)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	id1, _ := gen1.Generate()
+	id2, _ := gen2.Generate()
+
+	c := gen1.Decompose(int64(id1))
+	testCases := []struct {
+		name string
+		got  interface{}
+		want interface{}
+	}{
+		{name: "版本号", got: c.Version, want: int64(1)},
+		{name: "机器ID", got: c.MachineID, want: int64(200)},
+		{name: "版本位", got: int64(id1) >> 61, want: int64(1)},
+		{name: "新版本id更大", got: id2 > id1, want: true},
+		{name: "截止id包含版本", got: gen1.MinIDForTime(time.Now()) >> 61, want: int64(1)},
+	}
+	for _, tc := range testCases {
+		if tc.got != tc.want {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, tc.got, tc.want)
+		}
+	}
+
+	u, err := gen1.ToUUIDv7(int64(id1))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if back, err := gen1.FromUUIDv7(u); err != nil || back != int64(id1) {
+		t.Fatalf("【失败】-%s-got:%v(%v)-want:%v", "UUIDv7还原", back, err, id1)
+	}
+	if _, err := gen1.ToUUIDv7(int64(id2)); err == nil {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "其他版本的id", err, "error")
+	}
+
+	invalid := v1
+	invalid.Version = 4
+	if _, err := NewGeneratorWithSettings(0, invalid); err == nil {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "版本号超出范围", err, "error")
+	}
+}
+
+// TestLayoutRegistry 按版本号选择id结构解析
+func TestLayoutRegistry(t *testing.T) {
+	v0 := Settings{VersionBit: 1, TimeBit: 41, MachineIDBit: 9, TimelineBit: 1, SeqBit: 11, Epoch: DefaultEpoch}
+	v1 := Settings{VersionBit: 1, Version: 1, TimeBit: 32, MachineIDBit: 10, TimelineBit: 1, SeqBit: 19, Epoch: DefaultEpoch, TimeUnit: time.Second}
+	r, err := NewLayoutRegistry(1)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	for _, s := range []Settings{v0, v1} {
+		if err := r.Register(s); err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+	if err := r.Register(v1); err == nil {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "重复注册", err, "error")
+	}
+	if err := r.Register(*DefaultSettings); err == nil {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "版本位不同", err, "error")
+	}
+
+	gen0, _ := NewGeneratorWithSettings(300, v0)
+	gen1, _ := NewGeneratorWithSettings(900, v1)
+	id0, _ := gen0.Generate()
+	id1, _ := gen1.Generate()
+	for _, tc := range []struct {
+		name          string
+		id            int64
+		wantVersion   int64
+		wantMachineID int64
+	}{
+		{name: "版本0", id: int64(id0), wantVersion: 0, wantMachineID: 300},
+		{name: "版本1", id: int64(id1), wantVersion: 1, wantMachineID: 900},
+	} {
+		c, err := r.Decompose(tc.id)
+		if err != nil || c.Version != tc.wantVersion || c.MachineID != tc.wantMachineID {
+			t.Fatalf("【失败】-%s-got:%+v(%v)-want:%v,%v", tc.name, c, err, tc.wantVersion, tc.wantMachineID)
+		}
+		if got, _ := r.Time(tc.id); time.Since(got) > time.Minute {
+			t.Fatalf("【失败】-%s-生成时间-got:%v", tc.name, got)
+		}
+	}
+
+	r2, _ := NewLayoutRegistry(1)
+	r2.Register(v0)
+	if _, err := r2.Decompose(int64(id1)); !errors.Is(err, ErrUnknownVersion) {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "未注册的版本", err, ErrUnknownVersion)
+	}
+
+	if _, err := RegistryDecompose(int64(id0)); err == nil {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "未设置默认注册表", err, "error")
+	}
+	SetDefaultRegistry(r)
+	defer SetDefaultRegistry(nil)
+	if c, err := RegistryDecompose(int64(id1)); err != nil || c.MachineID != 900 {
+		t.Fatalf("【失败】-%s-got:%+v(%v)-want:%v", "默认注册表", c, err, 900)
+	}
+}
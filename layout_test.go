@@ -0,0 +1,88 @@
+package generator
+
+import (
+	"testing"
+	"time"
+)
+
+// TestNewLayout 字段声明的校验
+func TestNewLayout(t *testing.T) {
+	testCases := []struct {
+		name    string
+		fields  []LayoutField
+		wantErr bool
+	}{
+		{name: "完整", fields: []LayoutField{{FieldTime, 41}, {"region", 3}, {"tenant", 4}, {"machine", 6}, {FieldTimeline, 1}, {FieldSeq, 8}}},
+		{name: "无时间线", fields: []LayoutField{{FieldTime, 41}, {"machine", 10}, {FieldSeq, 12}}},
+		{name: "位数之和不为63", fields: []LayoutField{{FieldTime, 41}, {"machine", 10}, {FieldSeq, 10}}, wantErr: true},
+		{name: "缺少时间", fields: []LayoutField{{"machine", 51}, {FieldSeq, 12}}, wantErr: true},
+		{name: "缺少序号", fields: []LayoutField{{FieldTime, 41}, {"machine", 22}}, wantErr: true},
+		{name: "字段重复", fields: []LayoutField{{FieldTime, 41}, {"machine", 5}, {"machine", 5}, {FieldSeq, 12}}, wantErr: true},
+		{name: "位数为0", fields: []LayoutField{{FieldTime, 41}, {"machine", 0}, {"region", 10}, {FieldSeq, 12}}, wantErr: true},
+		{name: "字段名为空", fields: []LayoutField{{FieldTime, 41}, {"", 10}, {FieldSeq, 12}}, wantErr: true},
+	}
+	for _, tc := range testCases {
+		if _, err := NewLayout(tc.fields...); (err != nil) != tc.wantErr {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, err, tc.wantErr)
+		}
+	}
+}
+
+// TestLayoutGenerator 按自定义id结构生成、解析
+func TestLayoutGenerator(t *testing.T) {
+	layout, _ := NewLayout(
+		LayoutField{FieldTime, 41}, LayoutField{"region", 3}, LayoutField{"tenant", 4},
+		LayoutField{"machine", 6}, LayoutField{FieldTimeline, 1}, LayoutField{FieldSeq, 8})
+
+	errCases := []struct {
+		name   string
+		values map[string]int64
+	}{
+		{name: "指定生成器填充的字段", values: map[string]int64{FieldSeq: 1}},
+		{name: "未知的字段", values: map[string]int64{"zone": 1}},
+		{name: "超出字段位数", values: map[string]int64{"region": 8}},
+		{name: "负数", values: map[string]int64{"tenant": -1}},
+	}
+	for _, tc := range errCases {
+		if _, err := NewLayoutGenerator(layout, tc.values, *DefaultSettings); err == nil {
+			t.Fatalf("【失败】-%s-got:%v-want:%s", tc.name, err, "错误")
+		}
+	}
+
+	values := map[string]int64{"region": 5, "tenant": 9, "machine": 33}
+	gen, err := NewLayoutGenerator(layout, values, *DefaultSettings)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	before := time.Now()
+	var last int64
+	for i := 0; i < 1000; i++ {
+		id, err := gen.Generate()
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if int64(id) <= last {
+			t.Fatalf("【失败】-%s-got:%v-want:>%v", "递增", id, last)
+		}
+		last = int64(id)
+	}
+
+	got := gen.Decompose(last)
+	for name, want := range values {
+		if got[name] != want {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", name, got[name], want)
+		}
+	}
+	if at := gen.Time(last); at.Before(before.Truncate(time.Millisecond)) || at.After(time.Now()) {
+		t.Fatalf("【失败】-%s-got:%v-want:>=%v", "时间", at, before)
+	}
+	if got[FieldTime] != last>>22 {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "时间位于最高位", got[FieldTime], last>>22)
+	}
+
+	//Compose与Decompose互逆
+	id, err := layout.Compose(got)
+	if err != nil || id != last {
+		t.Fatalf("【失败】-%s-got:%v,%v-want:%v", "Compose", id, err, last)
+	}
+}
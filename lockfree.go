@@ -0,0 +1,173 @@
+package generator
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// fastValid 快速路径状态字的有效位，0表示快速路径不可用
+const fastValid uint64 = 1 << 63
+
+// genMutex 生成器锁
+//   - 加锁时将快速路径(SetLockFree)的状态合并回生成器并使快速路径失效，持有锁期间只有慢速路径生成id
+//   - 解锁时按当前状态重新发布快速路径
+type genMutex struct {
+	sync.Mutex
+	idGen *IDGenerator
+}
+
+// Lock 加锁
+func (m *genMutex) Lock() {
+	m.Mutex.Lock()
+	if atomic.LoadUint64(&m.idGen.fastState) != 0 {
+		m.idGen.absorbFast()
+	}
+}
+
+// Unlock 解锁
+func (m *genMutex) Unlock() {
+	if atomic.LoadInt32(&m.idGen.lockFree) != 0 {
+		m.idGen.publishFast()
+	}
+	m.Mutex.Unlock()
+}
+
+// fastConfig 快速路径使用的配置，发布后不再修改
+type fastConfig struct {
+	settings     *Settings
+	clock        Clock
+	clockVersion int64
+	prefix       int64
+	machineID    int64
+	shardBase    int64
+	seqLimit     int64 //当前时间单位内允许使用的最大序号
+	advance      bool  //是否可在快速路径中推进到新的时间单位
+}
+
+// SetLockFree 设置是否启用无锁快速路径
+//   - 启用后时间、时间线、序号打包为一个状态字，Generate通过CAS更新状态字生成id，不再获取生成器锁；
+//     时钟回退、序号用完等需要等待或切换时间线的情况转入加锁的慢速路径
+//   - 设置了指标上报、时间部分用尽处理策略、时钟跳变检测、OnTimeNearOverflow回调、时间线状态持久化，或存在待回收的时间线时，每个时间单位的第一个id由慢速路径生成，
+//     快速路径生成的MetricGenerated在下一次进入慢速路径时汇总上报
+//   - 开启序号填充、序号随机化、速率限制或序号空间划分时不使用快速路径；GenerateN、GenerateCtx、子生成器等始终使用慢速路径
+//   - 适用于大量goroutine并发生成的场景，启用前请通过BenchmarkGenParallel*基准测试确认
+func (idGen *IDGenerator) SetLockFree(enabled bool) {
+	idGen.mutex.Lock()
+	defer idGen.mutex.Unlock()
+	var value int32
+	if enabled {
+		value = 1
+	}
+	atomic.StoreInt32(&idGen.lockFree, value)
+}
+
+// generateFast 快速路径生成id，需要转入慢速路径时返回false
+func (idGen *IDGenerator) generateFast() (int64, bool) {
+	state := atomic.LoadUint64(&idGen.fastState)
+	config, _ := idGen.fastConfig.Load().(*fastConfig)
+	if state == 0 || config == nil {
+		return 0, false
+	}
+	settings := config.settings
+	seqBits := settings.SeqBit
+	timelineBits := settings.TimelineBit
+	curTime := int64(state&^fastValid) >> (seqBits + timelineBits)
+	timeline := int64(state>>seqBits) & (1<<timelineBits - 1)
+	seq := int64(state) & (1<<seqBits - 1)
+
+	var nowNano int64
+	if config.clock == nil {
+		nowNano = time.Now().UnixNano()
+	} else {
+		nowNano = config.clock.Now()
+	}
+	now := (nowNano - settings.Epoch) / settings.unit()
+	switch {
+	case now == curTime && seq < config.seqLimit:
+		seq++
+	case now > curTime && config.advance && now <= settings.presets.maxTime:
+		curTime, seq = now, 0
+	default:
+		return 0, false
+	}
+
+	next := fastValid | uint64(curTime)<<(seqBits+timelineBits) | uint64(timeline)<<seqBits | uint64(seq)
+	if !atomic.CompareAndSwapUint64(&idGen.fastState, state, next) {
+		return 0, false
+	}
+	//CAS期间配置已重新发布时无法确定状态对应的配置，放弃该序号
+	if current, _ := idGen.fastConfig.Load().(*fastConfig); current != config {
+		return 0, false
+	}
+	atomic.AddInt64(&idGen.fastGenerated, 1)
+
+	presets := settings.presets
+	return config.prefix |
+		presets.version |
+		(curTime << presets.shiftTimeBit) |
+		presets.datacenter |
+		(config.machineID << presets.shiftMachineIDBit) |
+		(timeline << presets.shiftTimelineBit) |
+		(seq << presets.shiftSeq) |
+		config.shardBase, true
+}
+
+// absorbFast 使快速路径失效，将快速路径生成后的状态合并回生成器，调用方须持有锁
+func (idGen *IDGenerator) absorbFast() {
+	state := atomic.SwapUint64(&idGen.fastState, 0)
+	if state != 0 && state != idGen.fastPublished {
+		seqBits := idGen.settings.SeqBit
+		timelineBits := idGen.settings.TimelineBit
+		curTime := int64(state&^fastValid) >> (seqBits + timelineBits)
+		timeline := int64(state>>seqBits) & (1<<timelineBits - 1)
+		idGen.timelineProgress[timeline] = curTime
+		idGen.curTimeline = timeline
+		idGen.seq = int64(state) & (1<<seqBits - 1)
+		idGen.lastID = idGen.compose(curTime, timeline, idGen.seq)
+		if idGen.lastID > idGen.maxID {
+			idGen.maxID = idGen.lastID
+		}
+	}
+	if generated := atomic.SwapInt64(&idGen.fastGenerated, 0); generated > 0 && idGen.metrics != nil {
+		idGen.metrics.Counter(MetricGenerated, generated)
+	}
+}
+
+// publishFast 按当前状态发布快速路径，不满足条件时保持失效，调用方须持有锁
+func (idGen *IDGenerator) publishFast() {
+	if idGen.fenced != nil || idGen.padding != nil || idGen.seqRandom != nil || idGen.rateLimit != nil || idGen.reservation != nil || idGen.checkBreaker() != nil {
+		return
+	}
+	settings := idGen.settings
+	curTime := idGen.timelineProgress[idGen.curTimeline]
+	if curTime > settings.presets.maxTime {
+		return
+	}
+
+	config := &fastConfig{
+		settings:     settings,
+		clock:        idGen.clock,
+		clockVersion: idGen.clockVersion,
+		prefix:       idGen.prefix,
+		machineID:    idGen.machineID,
+		shardBase:    idGen.shardBase,
+		seqLimit:     idGen.seqLimit(settings.presets.maxSeq),
+		advance: idGen.metrics == nil && idGen.overflow == nil && idGen.burnedCount == 0 &&
+			!idGen.isBeforeEpoch && idGen.pauseDetector.threshold <= 0 && !idGen.nearOverflowPending() && idGen.persist == nil,
+	}
+	if current, _ := idGen.fastConfig.Load().(*fastConfig); current == nil || !current.same(config) {
+		idGen.fastConfig.Store(config)
+	}
+	idGen.fastPublished = fastValid |
+		uint64(curTime)<<(settings.SeqBit+settings.TimelineBit) |
+		uint64(idGen.curTimeline)<<settings.SeqBit |
+		uint64(idGen.seq)
+	atomic.StoreUint64(&idGen.fastState, idGen.fastPublished)
+}
+
+// same 配置是否相同
+func (c *fastConfig) same(other *fastConfig) bool {
+	return c.settings == other.settings && c.clockVersion == other.clockVersion && c.prefix == other.prefix &&
+		c.machineID == other.machineID && c.shardBase == other.shardBase && c.seqLimit == other.seqLimit && c.advance == other.advance
+}
@@ -0,0 +1,125 @@
+package generator
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jayecc/mtl-snowflake/fakeclock"
+)
+
+// TestLockFree 无锁快速路径与慢速路径交替使用时id全局唯一
+func TestLockFree(t *testing.T) {
+	testCases := []struct {
+		name    string
+		metrics bool
+	}{
+		{name: "快速路径推进时间单位"},
+		{name: "设置指标上报", metrics: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			idGen, _ := NewGeneratorWithSettings(1, Settings{TimeBit: 41, MachineIDBit: 10, TimelineBit: 2, SeqBit: 10, Epoch: DefaultEpoch})
+			metrics := countingMetrics{}
+			if tc.metrics {
+				idGen.SetMetrics(metrics)
+			}
+			idGen.SetLockFree(true)
+
+			const goroutines, n = 16, 5000
+			var ids sync.Map
+			var wg sync.WaitGroup
+			for g := 0; g < goroutines; g++ {
+				wg.Add(1)
+				go func(g int) {
+					defer wg.Done()
+					for i := 0; i < n; i++ {
+						var batch []int64
+						switch {
+						case g == 0 && i%100 == 0:
+							batch, _ = idGen.GenerateN(10)
+						case g == 1 && i == n/2:
+							idGen.RotateMachineID(2)
+						default:
+							id, err := idGen.Generate()
+							if err != nil {
+								t.Error(err.Error())
+								return
+							}
+							batch = []int64{int64(id)}
+						}
+						for _, id := range batch {
+							if _, exist := ids.LoadOrStore(id, nil); exist {
+								t.Errorf("出现重复的id:%d", id)
+								return
+							}
+						}
+					}
+				}(g)
+			}
+			wg.Wait()
+
+			var count, max int64
+			ids.Range(func(key, _ interface{}) bool {
+				count++
+				if id := key.(int64); id > max {
+					max = id
+				}
+				return true
+			})
+			if got := idGen.MaxIssuedID(); got != max {
+				t.Fatalf("【失败】-%s-got:%v-want:%v", "已生成的最大id", got, max)
+			}
+			if tc.metrics && metrics[MetricGenerated] != count {
+				t.Fatalf("【失败】-%s-got:%v-want:%v", "生成数量指标", metrics[MetricGenerated], count)
+			}
+		})
+	}
+}
+
+// TestLockFreeBackward 快速路径下时钟回退转入慢速路径切换时间线
+func TestLockFreeBackward(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := fakeclock.New(start)
+	idGen, _ := NewGeneratorWithSettings(1, Settings{TimeBit: 41, MachineIDBit: 17, TimelineBit: 1, SeqBit: 4, Epoch: DefaultEpoch, Clock: clock})
+	idGen.SetLockFree(true)
+
+	seen := make(map[int64]bool)
+	generate := func(n int) {
+		for i := 0; i < n; i++ {
+			id, err := idGen.Generate()
+			if err != nil || seen[int64(id)] {
+				t.Fatalf("【失败】-%s-got:%v-want:%v", "生成不重复", err, nil)
+			}
+			seen[int64(id)] = true
+		}
+	}
+	generate(40) //序号用完时推进假时钟
+	clock.Advance(-10 * time.Millisecond)
+	generate(40)
+	if got := idGen.Decompose(idGen.lastGenerated()).TimeLine; got != 1 {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "切换时间线", got, 1)
+	}
+
+	idGen.SetLockFree(false)
+	generate(10)
+}
+
+// lastGenerated 上一个生成的id
+func (idGen *IDGenerator) lastGenerated() int64 {
+	idGen.mutex.Lock()
+	defer idGen.mutex.Unlock()
+	return idGen.lastID
+}
+
+// BenchmarkGenParallelLockFree 多goroutine并发(无锁快速路径)性能测试
+func BenchmarkGenParallelLockFree(b *testing.B) {
+	idGen, _ := NewGeneratorWithSettings(0, Settings{TimeBit: 41, MachineIDBit: 0, TimelineBit: 1, SeqBit: 21, Epoch: DefaultEpoch})
+	idGen.SetLockFree(true)
+	b.SetParallelism(16)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			idGen.Generate()
+		}
+	})
+}
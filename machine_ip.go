@@ -0,0 +1,44 @@
+package generator
+
+import (
+	"net"
+)
+
+// MachineIDFromPrivateIP 取本机私有IPv4地址(10/8、172.16/12、192.168/16)的低bits位作为机器ID(Sonyflake方式)
+//   - bits应与MachineIDBit一致，介于1-32之间，如MachineIDBit=16时使用IP的低16位
+//   - 须确保集群内各节点IP的低bits位互不相同，例如所有节点位于同一个/16网段内
+//   - 有多个私有地址时使用第一个
+func MachineIDFromPrivateIP(bits uint64) (int64, error) {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return 0, err
+	}
+	return machineIDFromAddrs(addrs, bits)
+}
+
+// machineIDFromAddrs 取第一个私有IPv4地址的低bits位
+func machineIDFromAddrs(addrs []net.Addr, bits uint64) (int64, error) {
+	if bits < 1 || bits > 32 {
+		return 0, errorf(CodeMachineIPBits)
+	}
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok {
+			continue
+		}
+		ip := ipNet.IP.To4()
+		if ip == nil || !isPrivateIPv4(ip) {
+			continue
+		}
+		v := uint32(ip[0])<<24 | uint32(ip[1])<<16 | uint32(ip[2])<<8 | uint32(ip[3])
+		return int64(uint64(v) & (uint64(1)<<bits - 1)), nil
+	}
+	return 0, errorf(CodeNoPrivateIP)
+}
+
+// isPrivateIPv4 是否为私有IPv4地址(RFC 1918)
+func isPrivateIPv4(ip net.IP) bool {
+	return ip[0] == 10 ||
+		ip[0] == 172 && ip[1]&0xf0 == 16 ||
+		ip[0] == 192 && ip[1] == 168
+}
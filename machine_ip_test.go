@@ -0,0 +1,39 @@
+package generator
+
+import (
+	"net"
+	"testing"
+)
+
+// TestMachineIDFromPrivateIP 由私有IP得到机器ID
+func TestMachineIDFromPrivateIP(t *testing.T) {
+	ipNet := func(s string) net.Addr {
+		_, n, _ := net.ParseCIDR(s)
+		ip, _, _ := net.ParseCIDR(s)
+		n.IP = ip
+		return n
+	}
+
+	testCases := []struct {
+		name    string
+		addrs   []net.Addr
+		bits    uint64
+		want    int64
+		wantErr bool
+	}{
+		{name: "低16位", addrs: []net.Addr{ipNet("10.1.2.3/8")}, bits: 16, want: 2<<8 | 3},
+		{name: "低9位", addrs: []net.Addr{ipNet("192.168.1.200/24")}, bits: 9, want: 1<<8 | 200},
+		{name: "跳过回环及公网地址", addrs: []net.Addr{ipNet("127.0.0.1/8"), ipNet("8.8.8.8/24"), ipNet("::1/128"), ipNet("172.20.0.5/16")}, bits: 16, want: 5},
+		{name: "172.32不是私有地址", addrs: []net.Addr{ipNet("172.32.0.5/16")}, bits: 16, wantErr: true},
+		{name: "没有私有地址", addrs: []net.Addr{ipNet("127.0.0.1/8")}, bits: 16, wantErr: true},
+		{name: "位数超出范围", addrs: []net.Addr{ipNet("10.1.2.3/8")}, bits: 33, wantErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := machineIDFromAddrs(tc.addrs, tc.bits)
+			if (err != nil) != tc.wantErr || got != tc.want {
+				t.Fatalf("【失败】-%s-got:%v(%v)-want:%v", tc.name, got, err, tc.want)
+			}
+		})
+	}
+}
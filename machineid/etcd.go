@@ -0,0 +1,246 @@
+package machineid
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	generator "github.com/jayecc/mtl-snowflake"
+)
+
+const defaultEtcdTTL = 10 * time.Second
+
+// ErrLeaseLost 机器ID的etcd租约已丢失
+var ErrLeaseLost = generator.NewError(generator.CodeLeaseLost)
+
+// EtcdClient 分配器依赖的etcd操作，可由clientv3适配
+type EtcdClient interface {
+	// Grant 创建租约(Lease.Grant)，返回租约ID
+	Grant(ttl time.Duration) (leaseID int64, err error)
+	// PutIfAbsent 在租约下创建key，key已存在时不修改，返回是否创建成功
+	//   - 以事务实现：If(CreateRevision(key)==0).Then(Put(key, value, WithLease(leaseID)))
+	PutIfAbsent(key, value string, leaseID int64) (bool, error)
+	// KeepAliveOnce 续约一次(Lease.KeepAliveOnce)，返回续约后的剩余时长，租约已不存在时返回0
+	KeepAliveOnce(leaseID int64) (time.Duration, error)
+	// Revoke 撤销租约(Lease.Revoke)，租约下的key随之删除
+	Revoke(leaseID int64) error
+}
+
+// EtcdConfig etcd分配器配置
+type EtcdConfig struct {
+	Prefix       string                           //key前缀，同一业务的所有实例须相同，机器ID的key为 Prefix/机器ID
+	MaxMachineID int64                            //可分配的最大机器ID(含)
+	TTL          time.Duration                    //租约时长，缺省10s
+	Heartbeat    time.Duration                    //续约间隔，缺省TTL/3
+	Release      time.Duration                    //Close时撤销租约前的等待时长，缺省1s，须不小于一个时间单位
+	Owner        string                           //持有者标识，缺省为 主机名:进程号:随机数
+	OnLost       func(machineID int64, err error) //租约丢失时的回调，在使受保护的生成器失效之后调用
+}
+
+// EtcdAllocator 基于etcd租约的机器ID分配器
+//   - 创建租约后从随机位置开始依次尝试在租约下创建key，第一个成功的机器ID即为分配结果
+//   - 后台每Heartbeat续约一次，续约出错时继续重试；租约已不存在，或下次续约前租约可能过期时视为租约丢失
+//   - 租约丢失时使受保护(Guard)的生成器失效，此后生成返回ErrLeaseLost，确保不会有两个节点以相同的机器ID生成
+type EtcdAllocator struct {
+	client EtcdClient
+	config EtcdConfig
+	random *rand.Rand
+
+	mutex     sync.Mutex
+	machineID int64 //已获得的机器ID，-1表示尚未获得
+	leaseID   int64
+	closed    bool
+	lost      bool
+	guarded   []*generator.IDGenerator
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+var _ Allocator = (*EtcdAllocator)(nil)
+
+// NewEtcdAllocator 创建etcd分配器
+func NewEtcdAllocator(client EtcdClient, config EtcdConfig) (*EtcdAllocator, error) {
+	if client == nil {
+		return nil, generator.NewError(generator.CodeNil, "etcd client")
+	}
+	if config.Prefix == "" {
+		return nil, generator.NewError(generator.CodeEmptyKeyPrefix)
+	}
+	if config.MaxMachineID < 0 {
+		return nil, generator.NewError(generator.CodeNegative, "MaxMachineID")
+	}
+	if config.TTL < 0 || config.Heartbeat < 0 || config.Release < 0 {
+		return nil, generator.NewError(generator.CodeNegative, "TTL, Heartbeat and Release")
+	}
+	if config.TTL == 0 {
+		config.TTL = defaultEtcdTTL
+	}
+	if config.Heartbeat == 0 {
+		config.Heartbeat = config.TTL / 3
+	}
+	if config.Heartbeat >= config.TTL {
+		return nil, generator.NewError(generator.CodeHeartbeatTTL)
+	}
+	if config.Release == 0 {
+		config.Release = defaultRelease
+	}
+	random := rand.New(rand.NewSource(time.Now().UnixNano()))
+	if config.Owner == "" {
+		host, _ := os.Hostname()
+		config.Owner = fmt.Sprintf("%s:%d:%d", host, os.Getpid(), random.Int63())
+	}
+	return &EtcdAllocator{client: client, config: config, random: random, machineID: -1}, nil
+}
+
+// Acquire 在新租约下获得一个空闲的机器ID并开始后台续约
+func (a *EtcdAllocator) Acquire() (int64, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if a.closed {
+		return 0, ErrClosed
+	}
+	if a.lost {
+		return 0, ErrLeaseLost
+	}
+	if a.machineID >= 0 {
+		return a.machineID, nil
+	}
+
+	leaseID, err := a.client.Grant(a.config.TTL)
+	if err != nil {
+		return 0, err
+	}
+	total := a.config.MaxMachineID + 1
+	start := a.random.Int63n(total) //随机起点，减少多个实例同时启动时的冲突
+	for i := int64(0); i < total; i++ {
+		machineID := (start + i) % total
+		ok, err := a.client.PutIfAbsent(a.key(machineID), a.config.Owner, leaseID)
+		if err != nil {
+			a.client.Revoke(leaseID)
+			return 0, err
+		}
+		if ok {
+			a.machineID, a.leaseID = machineID, leaseID
+			a.stop = make(chan struct{})
+			a.done = make(chan struct{})
+			go a.heartbeat(machineID, leaseID, a.stop, a.done)
+			return machineID, nil
+		}
+	}
+	a.client.Revoke(leaseID)
+	return 0, ErrNoFreeMachineID
+}
+
+// Guard 租约丢失或Close时使idGen失效，idGen应使用Acquire获得的机器ID
+//   - 租约已丢失或分配器已关闭时立即使idGen失效
+func (a *EtcdAllocator) Guard(idGen *generator.IDGenerator) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	switch {
+	case a.lost:
+		idGen.Fence(ErrLeaseLost)
+	case a.closed:
+		idGen.Fence(ErrClosed)
+	default:
+		a.guarded = append(a.guarded, idGen)
+	}
+}
+
+// Unguard 取消对idGen的保护，此后租约丢失或Close不再使其失效，用于idGen已切换到其他机器ID时
+func (a *EtcdAllocator) Unguard(idGen *generator.IDGenerator) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	for i, guarded := range a.guarded {
+		if guarded == idGen {
+			a.guarded = append(a.guarded[:i], a.guarded[i+1:]...)
+			return
+		}
+	}
+}
+
+// Close 使受保护的生成器失效，等待Release后撤销租约
+//   - 等待期间机器ID仍被占用，避免新实例在同一时间单位内以相同的机器ID生成
+func (a *EtcdAllocator) Close() error {
+	a.mutex.Lock()
+	if a.closed {
+		a.mutex.Unlock()
+		return nil
+	}
+	a.closed = true
+	a.fenceLocked(ErrClosed)
+	acquired, stop, done := a.machineID >= 0, a.stop, a.done
+	a.mutex.Unlock()
+	if !acquired {
+		return nil
+	}
+
+	close(stop)
+	<-done
+	a.mutex.Lock()
+	lost := a.lost
+	a.mutex.Unlock()
+	if lost {
+		return nil
+	}
+	time.Sleep(a.config.Release)
+	return a.client.Revoke(a.leaseID)
+}
+
+// heartbeat 定期续约，直到停止或租约丢失，租约丢失时在结束后回调OnLost(回调中可调用Close)
+func (a *EtcdAllocator) heartbeat(machineID, leaseID int64, stop <-chan struct{}, done chan<- struct{}) {
+	err := a.keepAlive(leaseID, stop)
+	close(done)
+	if err != nil && a.config.OnLost != nil {
+		a.config.OnLost(machineID, err)
+	}
+}
+
+// keepAlive 定期续约，停止时返回nil，租约丢失时使受保护的生成器失效并返回原因
+func (a *EtcdAllocator) keepAlive(leaseID int64, stop <-chan struct{}) error {
+	ticker := time.NewTicker(a.config.Heartbeat)
+	defer ticker.Stop()
+
+	lastRenew := time.Now()
+	for {
+		select {
+		case <-stop:
+			return nil
+		case now := <-ticker.C:
+			ttl, err := a.client.KeepAliveOnce(leaseID)
+			switch {
+			case err == nil && ttl > 0:
+				lastRenew = now
+				continue
+			case err == nil:
+				err = ErrLeaseLost
+			case now.Add(a.config.Heartbeat).Sub(lastRenew) < a.config.TTL:
+				continue //续约出错，下次续约时租约仍未过期，届时重试
+			}
+
+			a.mutex.Lock()
+			a.lost = true
+			a.fenceLocked(ErrLeaseLost)
+			a.mutex.Unlock()
+			return err
+		}
+	}
+}
+
+// fenceLocked 使受保护的生成器失效，调用方须持有锁
+func (a *EtcdAllocator) fenceLocked(err error) {
+	for _, idGen := range a.guarded {
+		idGen.Fence(err)
+	}
+	a.guarded = nil
+}
+
+// key 机器ID对应的key
+func (a *EtcdAllocator) key(machineID int64) string {
+	return a.config.Prefix + "/" + strconv.FormatInt(machineID, 10)
+}
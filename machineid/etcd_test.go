@@ -0,0 +1,179 @@
+package machineid
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	generator "github.com/jayecc/mtl-snowflake"
+)
+
+// memoryEtcd 内存实现的EtcdClient
+type memoryEtcd struct {
+	mutex     sync.Mutex
+	nextLease int64
+	leases    map[int64]time.Time //租约ID->过期时间
+	keys      map[string]int64    //key->租约ID
+	failRenew bool                //续约返回错误
+}
+
+func newMemoryEtcd() *memoryEtcd {
+	return &memoryEtcd{leases: make(map[int64]time.Time), keys: make(map[string]int64)}
+}
+
+// expireLocked 删除过期的租约及其key
+func (e *memoryEtcd) expireLocked() {
+	now := time.Now()
+	for id, expires := range e.leases {
+		if !now.Before(expires) {
+			e.revokeLocked(id)
+		}
+	}
+}
+
+func (e *memoryEtcd) revokeLocked(leaseID int64) {
+	delete(e.leases, leaseID)
+	for key, id := range e.keys {
+		if id == leaseID {
+			delete(e.keys, key)
+		}
+	}
+}
+
+func (e *memoryEtcd) Grant(ttl time.Duration) (int64, error) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.nextLease++
+	e.leases[e.nextLease] = time.Now().Add(ttl)
+	return e.nextLease, nil
+}
+
+func (e *memoryEtcd) PutIfAbsent(key, value string, leaseID int64) (bool, error) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.expireLocked()
+	if _, ok := e.keys[key]; ok {
+		return false, nil
+	}
+	e.keys[key] = leaseID
+	return true, nil
+}
+
+func (e *memoryEtcd) KeepAliveOnce(leaseID int64) (time.Duration, error) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	if e.failRenew {
+		return 0, errors.New("连接断开")
+	}
+	e.expireLocked()
+	if _, ok := e.leases[leaseID]; !ok {
+		return 0, nil
+	}
+	e.leases[leaseID] = time.Now().Add(60 * time.Millisecond)
+	return 60 * time.Millisecond, nil
+}
+
+func (e *memoryEtcd) Revoke(leaseID int64) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.revokeLocked(leaseID)
+	return nil
+}
+
+// TestEtcdAllocator 分配、续约、关闭
+func TestEtcdAllocator(t *testing.T) {
+	etcd := newMemoryEtcd()
+	config := EtcdConfig{Prefix: "/order/machine", MaxMachineID: 1, TTL: 60 * time.Millisecond, Release: 5 * time.Millisecond}
+	a, _ := NewEtcdAllocator(etcd, config)
+	b, _ := NewEtcdAllocator(etcd, config)
+	c, _ := NewEtcdAllocator(etcd, config)
+
+	idA, errA := a.Acquire()
+	idB, errB := b.Acquire()
+	if errA != nil || errB != nil || idA == idB {
+		t.Fatalf("【失败】-%s-got:%v,%v-want:%v", "分配不同的机器ID", idA, idB, "不同")
+	}
+	if _, err := c.Acquire(); err != ErrNoFreeMachineID {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "机器ID用尽", err, ErrNoFreeMachineID)
+	}
+	etcd.mutex.Lock()
+	leases := len(etcd.leases)
+	etcd.mutex.Unlock()
+	if got := leases; got != 2 {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "分配失败时撤销租约", got, 2)
+	}
+
+	//超过TTL后仍由续约保持
+	idGen, _ := generator.NewGenerator(idA)
+	a.Guard(idGen)
+	time.Sleep(150 * time.Millisecond)
+	if _, err := c.Acquire(); err != ErrNoFreeMachineID {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "续约", err, ErrNoFreeMachineID)
+	}
+	if _, err := idGen.Generate(); err != nil {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "续约期间生成", err, nil)
+	}
+
+	//关闭后生成器失效，机器ID可重新分配
+	if err := a.Close(); err != nil {
+		t.Fatal(err.Error())
+	}
+	if _, err := idGen.Generate(); err != ErrClosed {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "关闭后生成", err, ErrClosed)
+	}
+	if got, err := c.Acquire(); err != nil || got != idA {
+		t.Fatalf("【失败】-%s-got:%v(%v)-want:%v", "关闭后重新分配", got, err, idA)
+	}
+	b.Close()
+	c.Close()
+}
+
+// TestEtcdAllocatorLost 租约丢失时生成器失效
+func TestEtcdAllocatorLost(t *testing.T) {
+	testCases := []struct {
+		name string
+		lose func(e *memoryEtcd, leaseID int64)
+	}{
+		{name: "租约被撤销", lose: func(e *memoryEtcd, leaseID int64) { e.Revoke(leaseID) }},
+		{name: "续约持续失败", lose: func(e *memoryEtcd, leaseID int64) {
+			e.mutex.Lock()
+			e.failRenew = true
+			e.mutex.Unlock()
+		}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			etcd := newMemoryEtcd()
+			lost := make(chan int64, 1)
+			var a *EtcdAllocator
+			a, _ = NewEtcdAllocator(etcd, EtcdConfig{
+				Prefix:    "/order/machine",
+				TTL:       60 * time.Millisecond,
+				Heartbeat: 10 * time.Millisecond,
+				OnLost: func(machineID int64, err error) {
+					a.Close() //回调中关闭不会死锁
+					lost <- machineID
+				},
+			})
+			machineID, _ := a.Acquire()
+			idGen, _ := generator.NewGenerator(machineID)
+			a.Guard(idGen)
+			tc.lose(etcd, a.leaseID)
+
+			select {
+			case <-lost:
+			case <-time.After(time.Second):
+				t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, "未回调", machineID)
+			}
+			if _, err := idGen.Generate(); err != ErrLeaseLost {
+				t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, err, ErrLeaseLost)
+			}
+			later, _ := generator.NewGenerator(machineID)
+			a.Guard(later)
+			if _, err := later.Generate(); err != ErrLeaseLost {
+				t.Fatalf("【失败】-%s-丢失后保护-got:%v-want:%v", tc.name, err, ErrLeaseLost)
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+// Package machineid 自动分配机器ID，免去在弹性伸缩的实例间手工分配
+//   - Allocator 分配器接口，Acquire获得一个空闲的机器ID，Close释放
+//   - RedisAllocator 基于Redis的实现：SET NX PX租用机器ID，后台定期续约
+//   - EtcdAllocator 基于etcd租约的实现：租约丢失时使受保护的生成器失效
+//   - StatefulSetAllocator 由Kubernetes StatefulSet的pod序号得到机器ID，无需外部协调
+//   - Rotate 运行时将生成器切换到新分配的机器ID并释放旧机器ID
+package machineid
+
+import generator "github.com/jayecc/mtl-snowflake"
+
+var (
+	// ErrNoFreeMachineID 所有机器ID均已被占用
+	ErrNoFreeMachineID = generator.NewError(generator.CodeNoFreeMachineID)
+	// ErrClosed 分配器已关闭
+	ErrClosed = generator.NewError(generator.CodeAllocatorClosed)
+)
+
+// Allocator 机器ID分配器
+type Allocator interface {
+	// Acquire 获得一个空闲的机器ID，同一分配器重复调用返回已获得的机器ID
+	Acquire() (int64, error)
+	// Close 释放机器ID，调用前应停止使用该机器ID生成id
+	Close() error
+}
@@ -0,0 +1,40 @@
+package machineid
+
+import (
+	"errors"
+	"testing"
+
+	generator "github.com/jayecc/mtl-snowflake"
+)
+
+// TestErrorMessages 错误信息默认英文，随generator.SetTranslator切换语言，错误码不变
+func TestErrorMessages(t *testing.T) {
+	defer generator.SetTranslator(nil)
+	_, configErr := NewRedisAllocator(nil, RedisConfig{})
+	testCases := []struct {
+		name       string
+		translator generator.Translator
+		err        error
+		want       string
+		wantCode   generator.ErrorCode
+	}{
+		{name: "没有空闲的机器ID", err: ErrNoFreeMachineID, want: "no free machine ID", wantCode: generator.CodeNoFreeMachineID},
+		{name: "分配器已关闭", err: ErrClosed, want: "machine ID allocator is closed", wantCode: generator.CodeAllocatorClosed},
+		{name: "租约丢失", err: ErrLeaseLost, want: "etcd lease of the machine ID is lost", wantCode: generator.CodeLeaseLost},
+		{name: "配置错误", err: configErr, want: "Redis client must not be nil", wantCode: generator.CodeNil},
+		{name: "中文", translator: generator.ChineseMessages, err: ErrLeaseLost, want: "机器ID的etcd租约已丢失", wantCode: generator.CodeLeaseLost},
+		{name: "中文带参数", translator: generator.ChineseMessages, err: generator.NewError(generator.CodeMachineIDTaken, 3), want: "机器ID 3 已被其他实例占用或已过期", wantCode: generator.CodeMachineIDTaken},
+	}
+	for _, tc := range testCases {
+		generator.SetTranslator(tc.translator)
+		if got := tc.err.Error(); got != tc.want {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, got, tc.want)
+		}
+		if code, _ := generator.ErrorCodeOf(tc.err); code != tc.wantCode {
+			t.Fatalf("【失败】-%s-错误码-got:%v-want:%v", tc.name, code, tc.wantCode)
+		}
+	}
+	if !errors.Is(ErrClosed, ErrClosed) || errors.Is(ErrClosed, ErrLeaseLost) {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "errors.Is", errors.Is(ErrClosed, ErrLeaseLost), false)
+	}
+}
@@ -0,0 +1,181 @@
+package machineid
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	generator "github.com/jayecc/mtl-snowflake"
+)
+
+const (
+	defaultTTL     = 30 * time.Second
+	defaultRelease = time.Second
+)
+
+// RenewScript 续约：仅当key的值为持有者时设置过期时间(毫秒)，返回1表示成功
+//   - RedisClient.CompareAndExpire 可通过EVAL执行该脚本实现
+const RenewScript = `if redis.call("GET", KEYS[1]) == ARGV[1] then return redis.call("PEXPIRE", KEYS[1], ARGV[2]) else return 0 end`
+
+// RedisClient 分配器依赖的Redis命令，可由任意Redis客户端适配
+type RedisClient interface {
+	// SetNX SET key value NX PX ttl，返回是否设置成功
+	SetNX(key, value string, ttl time.Duration) (bool, error)
+	// CompareAndExpire key的值为value时将过期时间设置为ttl，返回是否成功(见RenewScript)
+	CompareAndExpire(key, value string, ttl time.Duration) (bool, error)
+}
+
+// RedisConfig Redis分配器配置
+type RedisConfig struct {
+	Prefix       string                           //key前缀，同一业务的所有实例须相同，机器ID的key为 Prefix:机器ID
+	MaxMachineID int64                            //可分配的最大机器ID(含)，通常为2^MachineIDBit-1，预留给应急、迁移的机器ID应排除在外
+	TTL          time.Duration                    //租约时长，缺省30s，实例异常退出后其机器ID在TTL后可被重新分配
+	Heartbeat    time.Duration                    //续约间隔，缺省TTL/3
+	Release      time.Duration                    //释放后机器ID的保留时长，缺省1s，须不小于一个时间单位，避免新实例在同一时间单位内生成相同的id
+	Owner        string                           //持有者标识，缺省为 主机名:进程号:随机数
+	OnLost       func(machineID int64, err error) //租约丢失(续约持续失败即将过期，或已被其他实例占用)时的回调，应立即停止使用该机器ID生成id
+}
+
+// RedisAllocator 基于Redis的机器ID分配器
+//   - 从随机位置开始依次尝试SET NX PX，第一个成功的机器ID即为分配结果
+//   - 后台每Heartbeat续约一次，续约出错时继续重试，下次续约前租约可能过期时视为租约丢失
+type RedisAllocator struct {
+	client RedisClient
+	config RedisConfig
+	random *rand.Rand
+
+	mutex     sync.Mutex
+	machineID int64 //已获得的机器ID，-1表示尚未获得
+	closed    bool
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+var _ Allocator = (*RedisAllocator)(nil)
+
+// NewRedisAllocator 创建Redis分配器
+func NewRedisAllocator(client RedisClient, config RedisConfig) (*RedisAllocator, error) {
+	if client == nil {
+		return nil, generator.NewError(generator.CodeNil, "Redis client")
+	}
+	if config.Prefix == "" {
+		return nil, generator.NewError(generator.CodeEmptyKeyPrefix)
+	}
+	if config.MaxMachineID < 0 {
+		return nil, generator.NewError(generator.CodeNegative, "MaxMachineID")
+	}
+	if config.TTL < 0 || config.Heartbeat < 0 || config.Release < 0 {
+		return nil, generator.NewError(generator.CodeNegative, "TTL, Heartbeat and Release")
+	}
+	if config.TTL == 0 {
+		config.TTL = defaultTTL
+	}
+	if config.Heartbeat == 0 {
+		config.Heartbeat = config.TTL / 3
+	}
+	if config.Heartbeat >= config.TTL {
+		return nil, generator.NewError(generator.CodeHeartbeatTTL)
+	}
+	if config.Release == 0 {
+		config.Release = defaultRelease
+	}
+	random := rand.New(rand.NewSource(time.Now().UnixNano()))
+	if config.Owner == "" {
+		host, _ := os.Hostname()
+		config.Owner = fmt.Sprintf("%s:%d:%d", host, os.Getpid(), random.Int63())
+	}
+	return &RedisAllocator{client: client, config: config, random: random, machineID: -1}, nil
+}
+
+// Acquire 获得一个空闲的机器ID并开始后台续约
+func (a *RedisAllocator) Acquire() (int64, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if a.closed {
+		return 0, ErrClosed
+	}
+	if a.machineID >= 0 {
+		return a.machineID, nil
+	}
+
+	total := a.config.MaxMachineID + 1
+	start := a.random.Int63n(total) //随机起点，减少多个实例同时启动时的冲突
+	for i := int64(0); i < total; i++ {
+		machineID := (start + i) % total
+		ok, err := a.client.SetNX(a.key(machineID), a.config.Owner, a.config.TTL)
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			a.machineID = machineID
+			a.stop = make(chan struct{})
+			a.done = make(chan struct{})
+			go a.heartbeat(machineID, a.stop, a.done)
+			return machineID, nil
+		}
+	}
+	return 0, ErrNoFreeMachineID
+}
+
+// Close 停止续约并释放机器ID
+//   - 机器ID在Release后才可被重新分配，而不是立即删除
+func (a *RedisAllocator) Close() error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if a.closed {
+		return nil
+	}
+	a.closed = true
+	if a.machineID < 0 {
+		return nil
+	}
+	close(a.stop)
+	<-a.done
+	_, err := a.client.CompareAndExpire(a.key(a.machineID), a.config.Owner, a.config.Release)
+	return err
+}
+
+// heartbeat 定期续约，直到停止或租约丢失，租约丢失时在结束后回调OnLost(回调中可调用Close)
+func (a *RedisAllocator) heartbeat(machineID int64, stop <-chan struct{}, done chan<- struct{}) {
+	err := a.keepAlive(machineID, stop)
+	close(done)
+	if err != nil && a.config.OnLost != nil {
+		a.config.OnLost(machineID, err)
+	}
+}
+
+// keepAlive 定期续约，停止时返回nil，租约丢失时返回原因
+func (a *RedisAllocator) keepAlive(machineID int64, stop <-chan struct{}) error {
+	ticker := time.NewTicker(a.config.Heartbeat)
+	defer ticker.Stop()
+
+	lastRenew := time.Now()
+	for {
+		select {
+		case <-stop:
+			return nil
+		case now := <-ticker.C:
+			ok, err := a.client.CompareAndExpire(a.key(machineID), a.config.Owner, a.config.TTL)
+			switch {
+			case err == nil && ok:
+				lastRenew = now
+				continue
+			case err == nil:
+				err = generator.NewError(generator.CodeMachineIDTaken, machineID)
+			case now.Add(a.config.Heartbeat).Sub(lastRenew) < a.config.TTL:
+				continue //续约出错，下次续约时租约仍未过期，届时重试
+			}
+			return err
+		}
+	}
+}
+
+// key 机器ID对应的key
+func (a *RedisAllocator) key(machineID int64) string {
+	return a.config.Prefix + ":" + strconv.FormatInt(machineID, 10)
+}
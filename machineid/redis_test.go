@@ -0,0 +1,161 @@
+package machineid
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryRedis 内存实现的RedisClient
+type memoryRedis struct {
+	mutex sync.Mutex
+	data  map[string]memoryEntry
+}
+
+type memoryEntry struct {
+	value   string
+	expires time.Time
+}
+
+func newMemoryRedis() *memoryRedis {
+	return &memoryRedis{data: make(map[string]memoryEntry)}
+}
+
+func (r *memoryRedis) get(key string) (memoryEntry, bool) {
+	entry, ok := r.data[key]
+	if ok && !time.Now().Before(entry.expires) {
+		delete(r.data, key)
+		return entry, false
+	}
+	return entry, ok
+}
+
+func (r *memoryRedis) SetNX(key, value string, ttl time.Duration) (bool, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if _, ok := r.get(key); ok {
+		return false, nil
+	}
+	r.data[key] = memoryEntry{value: value, expires: time.Now().Add(ttl)}
+	return true, nil
+}
+
+func (r *memoryRedis) CompareAndExpire(key, value string, ttl time.Duration) (bool, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	entry, ok := r.get(key)
+	if !ok || entry.value != value {
+		return false, nil
+	}
+	r.data[key] = memoryEntry{value: value, expires: time.Now().Add(ttl)}
+	return true, nil
+}
+
+func (r *memoryRedis) owner(key string) string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	entry, _ := r.get(key)
+	return entry.value
+}
+
+// TestRedisAllocator 分配、续约、释放
+func TestRedisAllocator(t *testing.T) {
+	redis := newMemoryRedis()
+	config := RedisConfig{Prefix: "order:machine", MaxMachineID: 1, TTL: 60 * time.Millisecond, Release: 20 * time.Millisecond}
+	newAllocator := func(owner string) *RedisAllocator {
+		c := config
+		c.Owner = owner
+		a, err := NewRedisAllocator(redis, c)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		return a
+	}
+
+	a, b, c := newAllocator("a"), newAllocator("b"), newAllocator("c")
+	idA, errA := a.Acquire()
+	idB, errB := b.Acquire()
+	if errA != nil || errB != nil || idA == idB {
+		t.Fatalf("【失败】-%s-got:%v,%v-want:%v", "分配不同的机器ID", idA, idB, "不同")
+	}
+	if again, _ := a.Acquire(); again != idA {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "重复调用", again, idA)
+	}
+	if _, err := c.Acquire(); err != ErrNoFreeMachineID {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "机器ID用尽", err, ErrNoFreeMachineID)
+	}
+
+	//超过TTL后仍由续约保持
+	time.Sleep(150 * time.Millisecond)
+	if got := redis.owner(a.key(idA)); got != "a" {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "续约", got, "a")
+	}
+
+	//释放后保留Release再重新分配
+	if err := a.Close(); err != nil {
+		t.Fatal(err.Error())
+	}
+	if _, err := c.Acquire(); err != ErrNoFreeMachineID {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "释放后保留期内", err, ErrNoFreeMachineID)
+	}
+	time.Sleep(40 * time.Millisecond)
+	if got, err := c.Acquire(); err != nil || got != idA {
+		t.Fatalf("【失败】-%s-got:%v(%v)-want:%v", "保留期后重新分配", got, err, idA)
+	}
+	if _, err := a.Acquire(); err != ErrClosed {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "关闭后申请", err, ErrClosed)
+	}
+	b.Close()
+	c.Close()
+}
+
+// TestRedisAllocatorLost 租约被其他实例占用
+func TestRedisAllocatorLost(t *testing.T) {
+	redis := newMemoryRedis()
+	lost := make(chan int64, 1)
+	a, _ := NewRedisAllocator(redis, RedisConfig{
+		Prefix:    "order:machine",
+		TTL:       60 * time.Millisecond,
+		Heartbeat: 10 * time.Millisecond,
+		OnLost:    func(machineID int64, err error) { lost <- machineID },
+	})
+	defer a.Close()
+	machineID, _ := a.Acquire()
+
+	redis.mutex.Lock()
+	redis.data[a.key(machineID)] = memoryEntry{value: "other", expires: time.Now().Add(time.Minute)}
+	redis.mutex.Unlock()
+
+	select {
+	case got := <-lost:
+		if got != machineID {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", "租约丢失回调", got, machineID)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "租约丢失回调", "未回调", machineID)
+	}
+}
+
+// TestNewRedisAllocator 配置校验
+func TestNewRedisAllocator(t *testing.T) {
+	testCases := []struct {
+		name    string
+		client  RedisClient
+		config  RedisConfig
+		wantErr bool
+	}{
+		{name: "缺省配置", client: newMemoryRedis(), config: RedisConfig{Prefix: "p", MaxMachineID: 511}},
+		{name: "客户端为空", config: RedisConfig{Prefix: "p"}, wantErr: true},
+		{name: "前缀为空", client: newMemoryRedis(), wantErr: true},
+		{name: "续约间隔不小于租约时长", client: newMemoryRedis(), config: RedisConfig{Prefix: "p", TTL: time.Second, Heartbeat: time.Second}, wantErr: true},
+		{name: "最大机器ID为负数", client: newMemoryRedis(), config: RedisConfig{Prefix: "p", MaxMachineID: -1}, wantErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewRedisAllocator(tc.client, tc.config)
+			if got := err != nil; got != tc.wantErr {
+				t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package machineid
+
+import (
+	generator "github.com/jayecc/mtl-snowflake"
+)
+
+// guarder 租约丢失时使生成器失效的分配器，如EtcdAllocator
+type guarder interface {
+	Guard(idGen *generator.IDGenerator)
+	Unguard(idGen *generator.IDGenerator)
+}
+
+// Rotate 将idGen切换到next分配的机器ID并释放current持有的旧机器ID，返回新的机器ID
+//   - 先由next获得新的机器ID，切换失败时关闭next，旧机器ID仍由current持有
+//   - 切换成功后关闭current：旧机器ID在分配器的Release保留期内不会被重新分配，避免其他节点在同一时间单位内以旧机器ID生成
+//   - 分配器支持Guard(如EtcdAllocator)时，idGen改由next保护，关闭current不会使其失效
+func Rotate(idGen *generator.IDGenerator, current, next Allocator) (int64, error) {
+	newID, err := next.Acquire()
+	if err != nil {
+		return 0, err
+	}
+	if _, err := idGen.RotateMachineID(newID); err != nil {
+		next.Close()
+		return 0, err
+	}
+	if g, ok := next.(guarder); ok {
+		g.Guard(idGen)
+	}
+	if g, ok := current.(guarder); ok {
+		g.Unguard(idGen)
+	}
+	return newID, current.Close()
+}
@@ -0,0 +1,78 @@
+package machineid
+
+import (
+	"testing"
+	"time"
+
+	generator "github.com/jayecc/mtl-snowflake"
+)
+
+// TestRotate 切换到新分配的机器ID，旧机器ID释放后在保留期内不被重新分配
+func TestRotate(t *testing.T) {
+	redis := newMemoryRedis()
+	config := RedisConfig{Prefix: "order:machine", MaxMachineID: 1, TTL: time.Second, Release: 50 * time.Millisecond}
+	newAllocator := func(owner string) *RedisAllocator {
+		c := config
+		c.Owner = owner
+		a, err := NewRedisAllocator(redis, c)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		return a
+	}
+
+	current, next, other := newAllocator("current"), newAllocator("next"), newAllocator("other")
+	oldID, _ := current.Acquire()
+	idGen, _ := generator.NewGenerator(oldID)
+	newID, err := Rotate(idGen, current, next)
+	if err != nil || newID == oldID || idGen.GetMachineID() != newID {
+		t.Fatalf("【失败】-%s-got:%v(%v)-want:%v", "切换机器ID", newID, err, 1-oldID)
+	}
+	id, _ := idGen.Generate()
+	if got := idGen.Decompose(int64(id)).MachineID; got != newID {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "切换后生成", got, newID)
+	}
+
+	//旧机器ID已释放，保留期内不能被重新分配
+	if _, err := current.Acquire(); err != ErrClosed {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "旧分配器已关闭", err, ErrClosed)
+	}
+	if _, err := other.Acquire(); err != ErrNoFreeMachineID {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "保留期内", err, ErrNoFreeMachineID)
+	}
+	time.Sleep(80 * time.Millisecond)
+	if got, err := other.Acquire(); err != nil || got != oldID {
+		t.Fatalf("【失败】-%s-got:%v(%v)-want:%v", "保留期后重新分配", got, err, oldID)
+	}
+
+	//没有空闲的机器ID时不切换
+	if _, err := Rotate(idGen, next, newAllocator("full")); err != ErrNoFreeMachineID || idGen.GetMachineID() != newID {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "机器ID用尽", err, ErrNoFreeMachineID)
+	}
+	next.Close()
+	other.Close()
+}
+
+// TestRotateGuard 切换后生成器改由新的etcd分配器保护
+func TestRotateGuard(t *testing.T) {
+	etcd := newMemoryEtcd()
+	config := EtcdConfig{Prefix: "/order/machine", MaxMachineID: 1, TTL: time.Second, Release: 5 * time.Millisecond}
+	current, _ := NewEtcdAllocator(etcd, config)
+	next, _ := NewEtcdAllocator(etcd, config)
+
+	oldID, _ := current.Acquire()
+	idGen, _ := generator.NewGenerator(oldID)
+	current.Guard(idGen)
+	if _, err := Rotate(idGen, current, next); err != nil {
+		t.Fatal(err.Error())
+	}
+	if _, err := idGen.Generate(); err != nil {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "关闭旧分配器后生成", err, nil)
+	}
+	if err := next.Close(); err != nil {
+		t.Fatal(err.Error())
+	}
+	if _, err := idGen.Generate(); err != ErrClosed {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "关闭新分配器后生成", err, ErrClosed)
+	}
+}
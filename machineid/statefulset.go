@@ -0,0 +1,80 @@
+package machineid
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	generator "github.com/jayecc/mtl-snowflake"
+)
+
+// defaultStatefulSetEnv 缺省读取的环境变量，StatefulSet的pod主机名为 <StatefulSet名>-<序号>
+const defaultStatefulSetEnv = "HOSTNAME"
+
+// StatefulSetConfig StatefulSet序号分配器配置
+type StatefulSetConfig struct {
+	EnvVar       string //读取的环境变量，缺省HOSTNAME；值可以是pod名(web-3)或序号(3)，如通过downward API注入的apps.kubernetes.io/pod-index标签
+	Offset       int64  //机器ID=序号+Offset，多个StatefulSet共用机器ID空间时用于错开
+	MachineIDBit uint64 //机器ID位数，机器ID须介于0-2^MachineIDBit-1之间
+}
+
+// StatefulSetAllocator 由Kubernetes StatefulSet的pod序号得到机器ID，无需外部协调
+//   - StatefulSet保证同一时刻每个序号至多一个pod，序号随pod重建保持不变
+//   - 副本数须不超过机器ID的取值范围，扩容超出时Acquire返回错误
+type StatefulSetAllocator struct {
+	config StatefulSetConfig
+}
+
+var _ Allocator = (*StatefulSetAllocator)(nil)
+
+// NewStatefulSetAllocator 创建StatefulSet序号分配器
+func NewStatefulSetAllocator(config StatefulSetConfig) (*StatefulSetAllocator, error) {
+	if config.Offset < 0 {
+		return nil, generator.NewError(generator.CodeNegative, "Offset")
+	}
+	if config.MachineIDBit > 62 {
+		return nil, generator.NewError(generator.CodeMachineIDBitRange, 62)
+	}
+	if config.EnvVar == "" {
+		config.EnvVar = defaultStatefulSetEnv
+	}
+	return &StatefulSetAllocator{config: config}, nil
+}
+
+// Acquire 读取环境变量解析pod序号，返回序号+Offset
+func (a *StatefulSetAllocator) Acquire() (int64, error) {
+	value := os.Getenv(a.config.EnvVar)
+	if value == "" {
+		return 0, generator.NewError(generator.CodeEnvEmpty, a.config.EnvVar)
+	}
+	ordinal, err := parseOrdinal(value)
+	if err != nil {
+		return 0, generator.NewError(generator.CodeEnvValue, a.config.EnvVar, value, err)
+	}
+
+	maxMachineID := int64(1)<<a.config.MachineIDBit - 1
+	if ordinal > maxMachineID-a.config.Offset {
+		return 0, generator.NewError(generator.CodeOrdinalRange, ordinal, a.config.Offset, maxMachineID)
+	}
+	return ordinal + a.config.Offset, nil
+}
+
+// Close 序号随pod存在，无需释放
+func (a *StatefulSetAllocator) Close() error {
+	return nil
+}
+
+// parseOrdinal 从pod名(web-3)或序号(3)中解析序号
+func parseOrdinal(value string) (int64, error) {
+	if i := strings.LastIndexByte(value, '-'); i >= 0 {
+		value = value[i+1:]
+	}
+	if value == "" || value[0] == '+' {
+		return 0, generator.NewError(generator.CodeOrdinalParse)
+	}
+	ordinal, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || ordinal < 0 {
+		return 0, generator.NewError(generator.CodeOrdinalParse)
+	}
+	return ordinal, nil
+}
@@ -0,0 +1,51 @@
+package machineid
+
+import (
+	"os"
+	"testing"
+
+	generator "github.com/jayecc/mtl-snowflake"
+)
+
+// TestStatefulSetAllocator 由pod序号得到机器ID
+func TestStatefulSetAllocator(t *testing.T) {
+	const env = "MTL_SNOWFLAKE_TEST_POD"
+	defer os.Unsetenv(env)
+
+	testCases := []struct {
+		name     string
+		value    string
+		config   StatefulSetConfig
+		want     int64
+		wantCode generator.ErrorCode //错误码，为空表示无错误
+	}{
+		{name: "pod名", value: "order-service-3", config: StatefulSetConfig{MachineIDBit: 9}, want: 3},
+		{name: "pod序号", value: "17", config: StatefulSetConfig{MachineIDBit: 9}, want: 17},
+		{name: "偏移", value: "web-3", config: StatefulSetConfig{MachineIDBit: 9, Offset: 256}, want: 259},
+		{name: "最大机器ID", value: "web-511", config: StatefulSetConfig{MachineIDBit: 9}, want: 511},
+		{name: "超出机器ID范围", value: "web-512", config: StatefulSetConfig{MachineIDBit: 9}, wantCode: generator.CodeOrdinalRange},
+		{name: "偏移后超出范围", value: "web-300", config: StatefulSetConfig{MachineIDBit: 9, Offset: 256}, wantCode: generator.CodeOrdinalRange},
+		{name: "不是StatefulSet pod", value: "web-7d9f8c-x2k4p", config: StatefulSetConfig{MachineIDBit: 9}, wantCode: generator.CodeEnvValue},
+		{name: "以'-'结尾", value: "web-", config: StatefulSetConfig{MachineIDBit: 9}, wantCode: generator.CodeEnvValue},
+		{name: "环境变量为空", value: "", config: StatefulSetConfig{MachineIDBit: 9}, wantCode: generator.CodeEnvEmpty},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			os.Setenv(env, tc.value)
+			tc.config.EnvVar = env
+			a, err := NewStatefulSetAllocator(tc.config)
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+			got, err := a.Acquire()
+			code, _ := generator.ErrorCodeOf(err)
+			if code != tc.wantCode || got != tc.want {
+				t.Fatalf("【失败】-%s-got:%v(%v)-want:%v(%v)", tc.name, got, err, tc.want, tc.wantCode)
+			}
+		})
+	}
+
+	if _, err := NewStatefulSetAllocator(StatefulSetConfig{Offset: -1}); err == nil {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "偏移为负数", err, "error")
+	}
+}
@@ -0,0 +1,41 @@
+package generator
+
+// 生成器上报的指标
+const (
+	MetricGenerated             = "generated"               //counter 生成的id数
+	MetricClockBackward         = "clock_backward"          //counter 时钟回退次数
+	MetricTimelineSwitch        = "timeline_switch"         //counter 时间线切换次数
+	MetricSeqExhausted          = "seq_exhausted"           //counter 序号用完等待次数
+	MetricBurstSpilled          = "burst_spilled"           //counter 每个时间单位的序号预算(SetBurstBudget)用完等待次数
+	MetricRateLimited           = "rate_limited"            //counter 超过生成速率上限(SetRateLimit)被拒绝的次数
+	MetricSeqBorrowed           = "seq_borrowed"            //counter 序号用完时借用下一个时间单位的次数(SetSeqExhaustionPolicy)
+	MetricBackwardAlert         = "clock_backward_alert"    //counter 时钟回退频率告警次数
+	MetricMachineIDRotation     = "machine_id_rotation"     //counter 机器ID切换次数
+	MetricClockJump             = "clock_jump"              //counter 检测到时钟跳变(虚拟机暂停、热迁移等)的次数
+	MetricBeforeEpoch           = "before_epoch"            //counter 时钟早于基准时间导致生成失败的次数
+	MetricSeqSkipped            = "seq_skipped"             //counter 填充跳过的序号数
+	MetricTimelineReclaimed     = "timeline_reclaimed"      //counter 回收的时间线数
+	MetricAnomalyUnknownMachine = "anomaly_unknown_machine" //counter 消费侧检测到未知机器ID的次数
+	MetricAnomalyOldTime        = "anomaly_old_time"        //counter 消费侧检测到机器生成旧时间id的次数
+	MetricAnomalySeqReset       = "anomaly_seq_reset"       //counter 消费侧检测到同一时间单位内序号重复的次数
+	MetricAnomalyFutureTime     = "anomaly_future_time"     //counter 消费侧检测到未来时间id的次数
+	MetricStateSaveError        = "state_save_error"        //counter 后台保存时间线状态失败的次数
+	MetricTimeline              = "timeline"                //gauge   当前时间线
+	MetricTimelineAvailable     = "timeline_available"      //gauge   可用于处理时钟回退的时间线数(不含当前时间线)
+	MetricWaitSeconds           = "wait_seconds"            //histogram 生成时等待的时长(秒)
+)
+
+// Metrics 指标上报接口
+//   - 在生成器锁内调用，实现须快速返回且不能阻塞
+type Metrics interface {
+	Counter(name string, delta int64)
+	Gauge(name string, value float64)
+	Histogram(name string, value float64)
+}
+
+// SetMetrics 设置指标上报，nil表示不上报
+func (idGen *IDGenerator) SetMetrics(metrics Metrics) {
+	idGen.mutex.Lock()
+	defer idGen.mutex.Unlock()
+	idGen.metrics = metrics
+}
@@ -0,0 +1,60 @@
+package metrics
+
+import (
+	"expvar"
+	"sync"
+
+	generator "github.com/jayecc/mtl-snowflake"
+)
+
+// Expvar 通过expvar(/debug/vars)暴露指标
+//   - counter、gauge分别以expvar.Int、expvar.Float发布
+//   - histogram发布为 <name>_count、<name>_sum、<name>_max
+type Expvar struct {
+	vars  *expvar.Map
+	mutex sync.Mutex
+	max   map[string]float64
+}
+
+var _ generator.Metrics = (*Expvar)(nil)
+
+// NewExpvar 以name发布一组指标，name不能与已发布的expvar重名
+func NewExpvar(name string) (*Expvar, error) {
+	if expvar.Get(name) != nil {
+		return nil, generator.NewError(generator.CodeExpvarExists, name)
+	}
+	return &Expvar{vars: expvar.NewMap(name), max: make(map[string]float64)}, nil
+}
+
+// Counter 累加计数
+func (e *Expvar) Counter(name string, delta int64) {
+	e.vars.Add(name, delta)
+}
+
+// Gauge 记录当前值
+func (e *Expvar) Gauge(name string, value float64) {
+	e.floatVar(name).Set(value)
+}
+
+// Histogram 记录样本的数量、总和及最大值
+func (e *Expvar) Histogram(name string, value float64) {
+	e.vars.Add(name+"_count", 1)
+	e.vars.AddFloat(name+"_sum", value)
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	if max, exist := e.max[name]; !exist || value > max {
+		e.max[name] = value
+		e.floatVar(name + "_max").Set(value)
+	}
+}
+
+// floatVar 获取或创建expvar.Float
+func (e *Expvar) floatVar(name string) *expvar.Float {
+	if v, ok := e.vars.Get(name).(*expvar.Float); ok {
+		return v
+	}
+	v := new(expvar.Float)
+	e.vars.Set(name, v)
+	return v
+}
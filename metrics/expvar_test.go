@@ -0,0 +1,44 @@
+package metrics
+
+import (
+	"expvar"
+	"testing"
+
+	generator "github.com/jayecc/mtl-snowflake"
+)
+
+// TestExpvar 通过expvar和Multi上报指标
+func TestExpvar(t *testing.T) {
+	first, err := NewExpvar("idgen_test_first")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	second, _ := NewExpvar("idgen_test_second")
+	if _, err := NewExpvar("idgen_test_first"); err == nil || err.Error() != "expvar idgen_test_first already exists" {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "重复发布", err, "expvar idgen_test_first already exists")
+	}
+
+	idGen, _ := generator.NewGenerator(0)
+	idGen.SetMetrics(Multi(first, second, nil))
+	for i := 0; i < 10; i++ {
+		idGen.Generate()
+	}
+	first.Histogram(generator.MetricWaitSeconds, 0.5)
+	first.Histogram(generator.MetricWaitSeconds, 0.25)
+
+	testCases := []struct {
+		name string
+		key  string
+		want string
+	}{
+		{name: "first计数", key: "idgen_test_first", want: `{"generated": 10, "wait_seconds_count": 2, "wait_seconds_max": 0.5, "wait_seconds_sum": 0.75}`},
+		{name: "second计数", key: "idgen_test_second", want: `{"generated": 10}`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := expvar.Get(tc.key).String(); got != tc.want {
+				t.Fatalf("【失败】-%s-got:%s-want:%s", tc.name, got, tc.want)
+			}
+		})
+	}
+}
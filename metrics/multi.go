@@ -0,0 +1,35 @@
+package metrics
+
+import generator "github.com/jayecc/mtl-snowflake"
+
+// multi 同时上报到多个指标实现
+type multi []generator.Metrics
+
+// Multi 将指标同时上报到多个实现，如同时使用StatsD和expvar
+func Multi(sinks ...generator.Metrics) generator.Metrics {
+	m := make(multi, 0, len(sinks))
+	for _, sink := range sinks {
+		if sink != nil {
+			m = append(m, sink)
+		}
+	}
+	return m
+}
+
+func (m multi) Counter(name string, delta int64) {
+	for _, sink := range m {
+		sink.Counter(name, delta)
+	}
+}
+
+func (m multi) Gauge(name string, value float64) {
+	for _, sink := range m {
+		sink.Gauge(name, value)
+	}
+}
+
+func (m multi) Histogram(name string, value float64) {
+	for _, sink := range m {
+		sink.Histogram(name, value)
+	}
+}
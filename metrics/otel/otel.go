@@ -0,0 +1,140 @@
+// Package otel 将生成器指标上报到OpenTelemetry Meter
+//   - Metrics实现generator.Metrics，通过SetMetrics设置后，生成数量、时钟回退、时间线切换、序号用完等待等counter，
+//     当前时间线等gauge，以及生成时等待时长的histogram均记录为OpenTelemetry指标
+//   - Instrument包装生成器，记录Generate耗时的histogram
+//
+// 独立的go module，避免主模块引入OpenTelemetry依赖
+package otel
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	generator "github.com/jayecc/mtl-snowflake"
+)
+
+// 生成器上报的指标，未列出的指标忽略
+var (
+	counterNames = []string{
+		generator.MetricGenerated,
+		generator.MetricClockBackward,
+		generator.MetricTimelineSwitch,
+		generator.MetricSeqExhausted,
+		generator.MetricBurstSpilled,
+		generator.MetricSeqBorrowed,
+		generator.MetricRateLimited,
+		generator.MetricBackwardAlert,
+		generator.MetricMachineIDRotation,
+		generator.MetricClockJump,
+		generator.MetricBeforeEpoch,
+		generator.MetricSeqSkipped,
+		generator.MetricTimelineReclaimed,
+		generator.MetricAnomalyUnknownMachine,
+		generator.MetricAnomalyOldTime,
+		generator.MetricAnomalySeqReset,
+		generator.MetricAnomalyFutureTime,
+		generator.MetricStateSaveError,
+	}
+	gaugeNames     = []string{generator.MetricTimeline, generator.MetricTimelineAvailable}
+	histogramNames = []string{generator.MetricWaitSeconds}
+)
+
+// Config 指标配置
+type Config struct {
+	Prefix     string               //指标名前缀，缺省为snowflake，如snowflake.generated
+	Attributes []attribute.KeyValue //固定属性，如attribute.String("biz", "order")
+}
+
+// Metrics 上报到OpenTelemetry Meter的生成器指标
+//   - 指标命名为<prefix>.<name>，histogram的单位为秒
+type Metrics struct {
+	counters   map[string]metric.Int64Counter
+	gauges     map[string]metric.Float64Gauge
+	histograms map[string]metric.Float64Histogram
+	latency    metric.Float64Histogram //Generate耗时
+	attributes metric.MeasurementOption
+}
+
+var _ generator.Metrics = (*Metrics)(nil)
+
+// New 在meter上创建指标，meter通常由MeterProvider.Meter得到
+func New(meter metric.Meter, config Config) (*Metrics, error) {
+	if config.Prefix == "" {
+		config.Prefix = "snowflake"
+	}
+
+	m := &Metrics{
+		counters:   make(map[string]metric.Int64Counter, len(counterNames)),
+		gauges:     make(map[string]metric.Float64Gauge, len(gaugeNames)),
+		histograms: make(map[string]metric.Float64Histogram, len(histogramNames)),
+		attributes: metric.WithAttributeSet(attribute.NewSet(config.Attributes...)),
+	}
+	for _, name := range counterNames {
+		counter, err := meter.Int64Counter(config.Prefix+"."+name, metric.WithDescription("id generator counter "+name))
+		if err != nil {
+			return nil, err
+		}
+		m.counters[name] = counter
+	}
+	for _, name := range gaugeNames {
+		gauge, err := meter.Float64Gauge(config.Prefix+"."+name, metric.WithDescription("id generator gauge "+name))
+		if err != nil {
+			return nil, err
+		}
+		m.gauges[name] = gauge
+	}
+	for _, name := range histogramNames {
+		histogram, err := meter.Float64Histogram(config.Prefix+"."+name, metric.WithDescription("id generator histogram "+name), metric.WithUnit("s"))
+		if err != nil {
+			return nil, err
+		}
+		m.histograms[name] = histogram
+	}
+	latency, err := meter.Float64Histogram(config.Prefix+".generate_duration", metric.WithDescription("id generator Generate latency"), metric.WithUnit("s"))
+	if err != nil {
+		return nil, err
+	}
+	m.latency = latency
+	return m, nil
+}
+
+// Counter 累加计数
+func (m *Metrics) Counter(name string, delta int64) {
+	if counter, exist := m.counters[name]; exist && delta > 0 {
+		counter.Add(context.Background(), delta, m.attributes)
+	}
+}
+
+// Gauge 记录当前值
+func (m *Metrics) Gauge(name string, value float64) {
+	if gauge, exist := m.gauges[name]; exist {
+		gauge.Record(context.Background(), value, m.attributes)
+	}
+}
+
+// Histogram 记录样本
+func (m *Metrics) Histogram(name string, value float64) {
+	if histogram, exist := m.histograms[name]; exist {
+		histogram.Record(context.Background(), value, m.attributes)
+	}
+}
+
+// Instrument 包装生成器，记录Generate耗时
+func (m *Metrics) Instrument(gen generator.Generator) generator.Generator {
+	return &instrumented{gen: gen, metrics: m}
+}
+
+type instrumented struct {
+	gen     generator.Generator
+	metrics *Metrics
+}
+
+func (g *instrumented) Generate() (generator.ID, error) {
+	start := time.Now()
+	id, err := g.gen.Generate()
+	g.metrics.latency.Record(context.Background(), time.Since(start).Seconds(), g.metrics.attributes)
+	return id, err
+}
@@ -0,0 +1,72 @@
+package otel
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	generator "github.com/jayecc/mtl-snowflake"
+)
+
+// TestMetrics 生成器上报的指标记录为OpenTelemetry指标
+func TestMetrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	defer provider.Shutdown(context.Background())
+	metrics, err := New(provider.Meter("snowflake"), Config{Attributes: []attribute.KeyValue{attribute.String("biz", "order")}})
+	if err != nil {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "创建", err, nil)
+	}
+
+	idGen, _ := generator.NewGenerator(1)
+	idGen.SetMetrics(metrics)
+	gen := metrics.Instrument(idGen)
+	for i := 0; i < 10; i++ {
+		gen.Generate()
+	}
+	metrics.Counter(generator.MetricClockBackward, 2)
+	metrics.Gauge(generator.MetricTimeline, 1)
+	metrics.Histogram(generator.MetricWaitSeconds, 0.001)
+	metrics.Counter("unknown", 1)
+
+	var data metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &data); err != nil {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "采集", err, nil)
+	}
+	got := make(map[string]float64)
+	for _, scope := range data.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			var attributes attribute.Set
+			switch d := m.Data.(type) {
+			case metricdata.Sum[int64]:
+				got[m.Name], attributes = float64(d.DataPoints[0].Value), d.DataPoints[0].Attributes
+			case metricdata.Gauge[float64]:
+				got[m.Name], attributes = d.DataPoints[0].Value, d.DataPoints[0].Attributes
+			case metricdata.Histogram[float64]:
+				got[m.Name], attributes = float64(d.DataPoints[0].Count), d.DataPoints[0].Attributes
+			}
+			if value, _ := attributes.Value("biz"); value.AsString() != "order" {
+				t.Fatalf("【失败】-%s-got:%v-want:%v", "固定属性", attributes, "biz=order")
+			}
+		}
+	}
+
+	want := map[string]float64{
+		"snowflake.generated":         10,
+		"snowflake.clock_backward":    2,
+		"snowflake.timeline":          1,
+		"snowflake.wait_seconds":      1,
+		"snowflake.generate_duration": 10,
+	}
+	for name, value := range want {
+		if got[name] != value {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", name, got[name], value)
+		}
+	}
+	if _, exist := got["snowflake.unknown"]; exist {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "未知指标", got["snowflake.unknown"], "忽略")
+	}
+}
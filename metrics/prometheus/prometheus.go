@@ -0,0 +1,159 @@
+// Package prometheus 以prometheus.Collector暴露生成器指标
+//   - Collector实现generator.Metrics，通过SetMetrics设置后，生成数量、时钟回退、时间线切换、序号用完等待等counter，
+//     当前时间线等gauge，以及生成时等待时长的histogram均注册为Prometheus指标
+//   - Instrument包装生成器，记录Generate耗时的histogram
+//
+// 独立的go module，避免主模块引入Prometheus依赖
+package prometheus
+
+import (
+	"time"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+
+	generator "github.com/jayecc/mtl-snowflake"
+)
+
+// 生成器上报的指标，未列出的指标忽略
+var (
+	counterNames = []string{
+		generator.MetricGenerated,
+		generator.MetricClockBackward,
+		generator.MetricTimelineSwitch,
+		generator.MetricSeqExhausted,
+		generator.MetricBurstSpilled,
+		generator.MetricSeqBorrowed,
+		generator.MetricRateLimited,
+		generator.MetricBackwardAlert,
+		generator.MetricMachineIDRotation,
+		generator.MetricClockJump,
+		generator.MetricBeforeEpoch,
+		generator.MetricSeqSkipped,
+		generator.MetricTimelineReclaimed,
+		generator.MetricAnomalyUnknownMachine,
+		generator.MetricAnomalyOldTime,
+		generator.MetricAnomalySeqReset,
+		generator.MetricAnomalyFutureTime,
+		generator.MetricStateSaveError,
+	}
+	gaugeNames     = []string{generator.MetricTimeline, generator.MetricTimelineAvailable}
+	histogramNames = []string{generator.MetricWaitSeconds}
+)
+
+// Config 指标配置
+type Config struct {
+	Namespace   string      //指标名前缀，缺省为snowflake，如snowflake_generated_total
+	ConstLabels prom.Labels //固定标签，如{"biz": "order"}
+	Buckets     []float64   //histogram的分桶(秒)，缺省为prometheus.DefBuckets
+}
+
+// Collector 生成器指标的prometheus.Collector
+//   - counter命名为<namespace>_<name>_total，gauge、histogram命名为<namespace>_<name>
+type Collector struct {
+	counters   map[string]prom.Counter
+	gauges     map[string]prom.Gauge
+	histograms map[string]prom.Histogram
+	latency    prom.Histogram //Generate耗时
+}
+
+var (
+	_ generator.Metrics = (*Collector)(nil)
+	_ prom.Collector    = (*Collector)(nil)
+)
+
+// New 创建指标，通过prometheus.MustRegister注册
+func New(config Config) *Collector {
+	if config.Namespace == "" {
+		config.Namespace = "snowflake"
+	}
+	if len(config.Buckets) == 0 {
+		config.Buckets = prom.DefBuckets
+	}
+
+	c := &Collector{
+		counters:   make(map[string]prom.Counter, len(counterNames)),
+		gauges:     make(map[string]prom.Gauge, len(gaugeNames)),
+		histograms: make(map[string]prom.Histogram, len(histogramNames)),
+	}
+	for _, name := range counterNames {
+		c.counters[name] = prom.NewCounter(prom.CounterOpts{
+			Namespace: config.Namespace, Name: name + "_total", Help: "id generator counter " + name, ConstLabels: config.ConstLabels,
+		})
+	}
+	for _, name := range gaugeNames {
+		c.gauges[name] = prom.NewGauge(prom.GaugeOpts{
+			Namespace: config.Namespace, Name: name, Help: "id generator gauge " + name, ConstLabels: config.ConstLabels,
+		})
+	}
+	for _, name := range histogramNames {
+		c.histograms[name] = prom.NewHistogram(prom.HistogramOpts{
+			Namespace: config.Namespace, Name: name, Help: "id generator histogram " + name, ConstLabels: config.ConstLabels, Buckets: config.Buckets,
+		})
+	}
+	c.latency = prom.NewHistogram(prom.HistogramOpts{
+		Namespace: config.Namespace, Name: "generate_duration_seconds", Help: "id generator Generate latency", ConstLabels: config.ConstLabels, Buckets: config.Buckets,
+	})
+	return c
+}
+
+// Counter 累加计数
+func (c *Collector) Counter(name string, delta int64) {
+	if counter, exist := c.counters[name]; exist && delta > 0 {
+		counter.Add(float64(delta))
+	}
+}
+
+// Gauge 记录当前值
+func (c *Collector) Gauge(name string, value float64) {
+	if gauge, exist := c.gauges[name]; exist {
+		gauge.Set(value)
+	}
+}
+
+// Histogram 记录样本
+func (c *Collector) Histogram(name string, value float64) {
+	if histogram, exist := c.histograms[name]; exist {
+		histogram.Observe(value)
+	}
+}
+
+// Describe 实现prometheus.Collector
+func (c *Collector) Describe(ch chan<- *prom.Desc) {
+	c.each(func(collector prom.Collector) { collector.Describe(ch) })
+}
+
+// Collect 实现prometheus.Collector
+func (c *Collector) Collect(ch chan<- prom.Metric) {
+	c.each(func(collector prom.Collector) { collector.Collect(ch) })
+}
+
+// each 按固定顺序遍历各指标
+func (c *Collector) each(f func(collector prom.Collector)) {
+	for _, name := range counterNames {
+		f(c.counters[name])
+	}
+	for _, name := range gaugeNames {
+		f(c.gauges[name])
+	}
+	for _, name := range histogramNames {
+		f(c.histograms[name])
+	}
+	f(c.latency)
+}
+
+// Instrument 包装生成器，记录Generate耗时
+func (c *Collector) Instrument(gen generator.Generator) generator.Generator {
+	return &instrumented{gen: gen, latency: c.latency}
+}
+
+type instrumented struct {
+	gen     generator.Generator
+	latency prom.Histogram
+}
+
+func (g *instrumented) Generate() (generator.ID, error) {
+	start := time.Now()
+	id, err := g.gen.Generate()
+	g.latency.Observe(time.Since(start).Seconds())
+	return id, err
+}
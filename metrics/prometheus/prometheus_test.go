@@ -0,0 +1,66 @@
+package prometheus
+
+import (
+	"testing"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+
+	generator "github.com/jayecc/mtl-snowflake"
+)
+
+// TestCollector 生成器上报的指标注册为Prometheus指标
+func TestCollector(t *testing.T) {
+	collector := New(Config{ConstLabels: prom.Labels{"biz": "order"}})
+	registry := prom.NewPedanticRegistry()
+	if err := registry.Register(collector); err != nil {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "注册", err, nil)
+	}
+
+	idGen, _ := generator.NewGenerator(1)
+	idGen.SetMetrics(collector)
+	gen := collector.Instrument(idGen)
+	for i := 0; i < 10; i++ {
+		gen.Generate()
+	}
+	collector.Counter(generator.MetricClockBackward, 2)
+	collector.Counter(generator.MetricStateSaveError, 1)
+	collector.Gauge(generator.MetricTimeline, 1)
+	collector.Histogram(generator.MetricWaitSeconds, 0.001)
+	collector.Counter("unknown", 1)
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "采集", err, nil)
+	}
+	got := make(map[string]float64)
+	for _, family := range families {
+		metric := family.GetMetric()[0]
+		if label := metric.GetLabel(); len(label) != 1 || label[0].GetValue() != "order" {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", "固定标签", label, "biz=order")
+		}
+		switch {
+		case metric.Counter != nil:
+			got[family.GetName()] = metric.GetCounter().GetValue()
+		case metric.Gauge != nil:
+			got[family.GetName()] = metric.GetGauge().GetValue()
+		case metric.Histogram != nil:
+			got[family.GetName()] = float64(metric.GetHistogram().GetSampleCount())
+		}
+	}
+
+	want := map[string]float64{
+		"snowflake_generated_total":               10,
+		"snowflake_clock_backward_total":          2,
+		"snowflake_timeline":                      1,
+		"snowflake_wait_seconds":                  1,
+		"snowflake_generate_duration_seconds":     10,
+		"snowflake_timeline_switch_total":         0,
+		"snowflake_anomaly_unknown_machine_total": 0,
+		"snowflake_state_save_error_total":        1,
+	}
+	for name, value := range want {
+		if v, exist := got[name]; !exist || v != value {
+			t.Fatalf("【失败】-%s-got:%v(%v)-want:%v", name, v, exist, value)
+		}
+	}
+}
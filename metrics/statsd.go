@@ -0,0 +1,165 @@
+// Package metrics 生成器指标上报(generator.Metrics)的实现
+package metrics
+
+import (
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	generator "github.com/jayecc/mtl-snowflake"
+)
+
+const (
+	statsDMaxPacket    = 1432 //单个UDP包的最大长度，避免分片
+	statsDMaxPending   = 1000 //两次上报之间最多缓存的histogram样本数
+	statsDDefaultFlush = 10 * time.Second
+)
+
+// StatsDConfig StatsD上报配置
+type StatsDConfig struct {
+	Prefix        string        //指标名前缀，如 "order.idgen."
+	Tags          []string      //DogStatsD标签，如 "env:prod"
+	DogStatsD     bool          //使用DogStatsD扩展(标签、|h histogram)
+	FlushInterval time.Duration //上报间隔，缺省10秒
+}
+
+// StatsD StatsD/DogStatsD指标上报
+//   - counter/gauge在本地聚合，按FlushInterval批量上报，生成id的热路径上不产生网络IO
+//   - histogram在标准StatsD中以|ms上报，名称以_seconds结尾的转换为毫秒
+type StatsD struct {
+	conn   net.Conn
+	config StatsDConfig
+	tags   string
+
+	mutex      sync.Mutex
+	counters   map[string]int64
+	gauges     map[string]float64
+	histograms []string
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+var _ generator.Metrics = (*StatsD)(nil)
+
+// NewStatsD 创建StatsD上报，addr如 127.0.0.1:8125
+func NewStatsD(addr string, config StatsDConfig) (*StatsD, error) {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = statsDDefaultFlush
+	}
+
+	s := &StatsD{
+		conn:     conn,
+		config:   config,
+		counters: make(map[string]int64),
+		gauges:   make(map[string]float64),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	if config.DogStatsD && len(config.Tags) > 0 {
+		s.tags = "|#" + strings.Join(config.Tags, ",")
+	}
+	go s.loop()
+	return s, nil
+}
+
+// Counter 累加计数
+func (s *StatsD) Counter(name string, delta int64) {
+	s.mutex.Lock()
+	s.counters[name] += delta
+	s.mutex.Unlock()
+}
+
+// Gauge 记录当前值
+func (s *StatsD) Gauge(name string, value float64) {
+	s.mutex.Lock()
+	s.gauges[name] = value
+	s.mutex.Unlock()
+}
+
+// Histogram 记录样本
+func (s *StatsD) Histogram(name string, value float64) {
+	metricType := "|h"
+	if !s.config.DogStatsD {
+		metricType = "|ms"
+		if strings.HasSuffix(name, "_seconds") {
+			value *= 1000
+		}
+	}
+	line := s.config.Prefix + name + ":" + strconv.FormatFloat(value, 'f', -1, 64) + metricType + s.tags
+
+	s.mutex.Lock()
+	if len(s.histograms) < statsDMaxPending {
+		s.histograms = append(s.histograms, line)
+	}
+	s.mutex.Unlock()
+}
+
+// Flush 立即上报已聚合的指标
+func (s *StatsD) Flush() error {
+	s.mutex.Lock()
+	lines := make([]string, 0, len(s.counters)+len(s.gauges)+len(s.histograms))
+	for name, value := range s.counters {
+		lines = append(lines, s.config.Prefix+name+":"+strconv.FormatInt(value, 10)+"|c"+s.tags)
+	}
+	for name, value := range s.gauges {
+		lines = append(lines, s.config.Prefix+name+":"+strconv.FormatFloat(value, 'f', -1, 64)+"|g"+s.tags)
+	}
+	lines = append(lines, s.histograms...)
+	s.counters = make(map[string]int64)
+	s.histograms = s.histograms[:0]
+	s.mutex.Unlock()
+
+	sort.Strings(lines)
+	packet := make([]byte, 0, statsDMaxPacket)
+	for _, line := range lines {
+		if len(packet) > 0 && len(packet)+1+len(line) > statsDMaxPacket {
+			if _, err := s.conn.Write(packet); err != nil {
+				return err
+			}
+			packet = packet[:0]
+		}
+		if len(packet) > 0 {
+			packet = append(packet, '\n')
+		}
+		packet = append(packet, line...)
+	}
+	if len(packet) > 0 {
+		if _, err := s.conn.Write(packet); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close 上报剩余指标并关闭连接
+func (s *StatsD) Close() error {
+	close(s.stop)
+	<-s.done
+	err := s.Flush()
+	if closeErr := s.conn.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func (s *StatsD) loop() {
+	defer close(s.done)
+	ticker := time.NewTicker(s.config.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.Flush()
+		case <-s.stop:
+			return
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package metrics
+
+import (
+	"net"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	generator "github.com/jayecc/mtl-snowflake"
+)
+
+// TestStatsD 生成器指标上报到StatsD
+func TestStatsD(t *testing.T) {
+	testCases := []struct {
+		name   string
+		config StatsDConfig
+		want   []string
+	}{
+		{
+			name:   "标准StatsD",
+			config: StatsDConfig{Prefix: "idgen."},
+			want:   []string{"idgen.generated:100|c", "idgen.wait_seconds:1.5|ms"},
+		},
+		{
+			name:   "DogStatsD",
+			config: StatsDConfig{Prefix: "idgen.", Tags: []string{"env:test"}, DogStatsD: true},
+			want:   []string{"idgen.generated:100|c|#env:test", "idgen.wait_seconds:0.0015|h|#env:test"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server, err := net.ListenPacket("udp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+			defer server.Close()
+
+			sink, err := NewStatsD(server.LocalAddr().String(), tc.config)
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+			idGen, _ := generator.NewGenerator(0)
+			idGen.SetMetrics(sink)
+			for i := 0; i < 100; i++ {
+				idGen.Generate()
+			}
+			sink.Histogram(generator.MetricWaitSeconds, 0.0015)
+			if err := sink.Close(); err != nil {
+				t.Fatal(err.Error())
+			}
+
+			buf := make([]byte, statsDMaxPacket)
+			server.SetReadDeadline(time.Now().Add(time.Second))
+			n, _, err := server.ReadFrom(buf)
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+			got := strings.Split(string(buf[:n]), "\n")
+			sort.Strings(got)
+			if strings.Join(got, ",") != strings.Join(tc.want, ",") {
+				t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MigrationStrategy 自增主键迁移到id的策略
+type MigrationStrategy int
+
+const (
+	MigrationOffset   MigrationStrategy = iota //偏移：原值整体平移(可能为0)到切换后最小id之下
+	MigrationReencode                          //重编码：将原值按顺序编码为切换时间之前的id
+)
+
+// String 策略名称
+func (s MigrationStrategy) String() string {
+	switch s {
+	case MigrationOffset:
+		return "offset"
+	case MigrationReencode:
+		return "re-encode"
+	}
+	return fmt.Sprintf("MigrationStrategy(%d)", int(s))
+}
+
+// MigrationRequest 迁移参数
+type MigrationRequest struct {
+	Table     string    //表名，可带schema，如 public.orders
+	Column    string    //主键列名
+	MinSerial int64     //已有serial/bigserial最小值
+	MaxSerial int64     //已有serial/bigserial最大值
+	Cutover   time.Time //切换时间，切换后的id均不早于该时间生成
+	Reencode  bool      //使用重编码策略，使原有记录也符合id结构(可被Decompose解析)
+	MachineID int64     //重编码时使用的机器ID，应预留给迁移专用，不参与在线生成
+}
+
+// MigrationPlan 迁移计划
+type MigrationPlan struct {
+	Strategy   MigrationStrategy //迁移策略
+	Offset     int64             //偏移策略：新值=原值+Offset
+	FirstNewID int64             //切换后可能生成的最小id
+	MinID      int64             //迁移后原有记录占用的最小id
+	MaxID      int64             //迁移后原有记录占用的最大id
+	SQL        string            //回填SQL(PostgreSQL)
+
+	req       MigrationRequest
+	settings  Settings
+	startTime int64 //重编码：起始时间(偏移量)
+	perTime   int64 //重编码：每个时间单位可容纳的原值数
+}
+
+// PlanMigration 根据已有serial取值范围和目标配置生成无冲突的迁移计划
+//   - 原值均小于切换后的最小id时，原值保持不变(Offset=0)
+//   - 原值跨度小于切换后的最小id时，整体向下平移
+//   - 指定Reencode时，使用预留的机器ID将原值按顺序编码到切换时间之前的时间单位中
+func PlanMigration(settings Settings, req MigrationRequest) (*MigrationPlan, error) {
+	if err := settings.checkBits(); err != nil {
+		return nil, err
+	}
+	if req.Table == "" || req.Column == "" {
+		return nil, errorf(CodeMigrationTable)
+	}
+	if req.MinSerial < 1 || req.MaxSerial < req.MinSerial {
+		return nil, errorf(CodeMigrationSerialRange)
+	}
+	if req.Cutover.IsZero() {
+		req.Cutover = time.Now()
+	}
+
+	presets := calcPresets(&settings)
+	settings.presets = presets
+	cutoverTime := (req.Cutover.UnixNano() - settings.Epoch) / settings.unit()
+	if cutoverTime <= 0 || cutoverTime > presets.maxTime {
+		return nil, errorf(CodeMigrationCutover)
+	}
+
+	plan := &MigrationPlan{
+		FirstNewID: presets.version | cutoverTime<<presets.shiftTimeBit,
+		req:        req,
+		settings:   settings,
+	}
+
+	span := req.MaxSerial - req.MinSerial
+	switch {
+	case req.Reencode:
+		if req.MachineID < 0 || req.MachineID > presets.maxMachineID {
+			return nil, &MachineIDError{MachineID: req.MachineID, Max: presets.maxMachineID}
+		}
+		plan.Strategy = MigrationReencode
+		plan.perTime = presets.maxSeq + 1
+		plan.startTime = cutoverTime - span/plan.perTime - 1
+		if plan.startTime < 0 {
+			return nil, errorf(CodeMigrationTooMany)
+		}
+	case req.MaxSerial < plan.FirstNewID:
+		plan.Strategy = MigrationOffset
+	case span < plan.FirstNewID-1:
+		plan.Strategy = MigrationOffset
+		plan.Offset = plan.FirstNewID - 1 - req.MaxSerial
+	default:
+		return nil, errorf(CodeMigrationSpan)
+	}
+
+	var err error
+	if plan.MinID, err = plan.Map(req.MinSerial); err != nil {
+		return nil, err
+	}
+	if plan.MaxID, err = plan.Map(req.MaxSerial); err != nil {
+		return nil, err
+	}
+	if err = plan.Verify(); err != nil {
+		return nil, err
+	}
+	plan.SQL = plan.buildSQL()
+	return plan, nil
+}
+
+// Map 计算原值迁移后的id
+func (plan *MigrationPlan) Map(serial int64) (int64, error) {
+	if serial < plan.req.MinSerial || serial > plan.req.MaxSerial {
+		return 0, errorf(CodeMigrationOutOfRange, serial, plan.req.MinSerial, plan.req.MaxSerial)
+	}
+	if plan.Strategy == MigrationOffset {
+		return serial + plan.Offset, nil
+	}
+	presets := plan.settings.presets
+	n := serial - plan.req.MinSerial
+	return presets.version |
+		(plan.startTime+n/plan.perTime)<<presets.shiftTimeBit |
+		presets.datacenter |
+		plan.req.MachineID<<presets.shiftMachineIDBit |
+		(n%plan.perTime)<<presets.shiftSeq, nil
+}
+
+// Verify 校验迁移计划：迁移后的id为正、保持原有顺序，且全部小于切换后可能生成的最小id
+func (plan *MigrationPlan) Verify() error {
+	if plan.MinID < 1 {
+		return errorf(CodeMigrationNegative)
+	}
+	if plan.MaxID-plan.MinID < plan.req.MaxSerial-plan.req.MinSerial {
+		return errorf(CodeMigrationInsufficient)
+	}
+	if plan.MaxID >= plan.FirstNewID {
+		return errorf(CodeMigrationConflict, plan.MaxID, plan.FirstNewID)
+	}
+	return nil
+}
+
+// buildSQL 生成PostgreSQL回填SQL
+func (plan *MigrationPlan) buildSQL() string {
+	table := quoteIdent(plan.req.Table)
+	column := quoteIdent(plan.req.Column)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "-- mtl-snowflake migration plan: %s\n", plan.Strategy)
+	fmt.Fprintf(&b, "-- serial range: [%d, %d] -> id range: [%d, %d]\n", plan.req.MinSerial, plan.req.MaxSerial, plan.MinID, plan.MaxID)
+	fmt.Fprintf(&b, "-- cutover: %s, first generated id >= %d\n", plan.req.Cutover.UTC().Format(time.RFC3339), plan.FirstNewID)
+	b.WriteString("-- 引用该列的外键须声明 ON UPDATE CASCADE 或同步更新\n")
+	b.WriteString("BEGIN;\n")
+	fmt.Fprintf(&b, "ALTER TABLE %s ALTER COLUMN %s DROP DEFAULT;\n", table, column)
+
+	switch {
+	case plan.Strategy == MigrationReencode:
+		presets := plan.settings.presets
+		seq := fmt.Sprintf("((%s - %d) %% %d)", column, plan.req.MinSerial, plan.perTime)
+		if presets.shiftSeq > 0 {
+			seq = fmt.Sprintf("(%s << %d)", seq, presets.shiftSeq)
+		}
+		machine := fmt.Sprintf("(%d::bigint << %d)", plan.req.MachineID, presets.shiftMachineIDBit)
+		if static := presets.version | presets.datacenter; static != 0 {
+			machine = fmt.Sprintf("%d::bigint | %s", static, machine)
+		}
+		fmt.Fprintf(&b, "UPDATE %s SET %s = ((((%s - %d) / %d) + %d) << %d) | %s | %s;\n",
+			table, column,
+			column, plan.req.MinSerial, plan.perTime, plan.startTime, presets.shiftTimeBit,
+			machine, seq)
+	case plan.Offset != 0:
+		fmt.Fprintf(&b, "UPDATE %s SET %s = %s + (%d);\n", table, column, column, plan.Offset)
+	default:
+		b.WriteString("-- 原值均小于切换后生成的id，无需回填\n")
+	}
+
+	fmt.Fprintf(&b, "-- 校验：结果必须为0\n")
+	fmt.Fprintf(&b, "SELECT count(*) FROM %s WHERE %s < %d OR %s > %d;\n", table, column, plan.MinID, column, plan.MaxID)
+	b.WriteString("COMMIT;\n")
+	return b.String()
+}
+
+// quoteIdent 转义PostgreSQL标识符，支持 schema.table 形式
+func quoteIdent(name string) string {
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		parts[i] = `"` + strings.Replace(part, `"`, `""`, -1) + `"`
+	}
+	return strings.Join(parts, ".")
+}
@@ -0,0 +1,63 @@
+package generator
+
+import (
+	"testing"
+	"time"
+)
+
+// TestPlanMigration 自增主键迁移计划
+func TestPlanMigration(t *testing.T) {
+	cutover := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	firstNewID := ((cutover.UnixNano() - DefaultEpoch) / int64(timeUnit)) << 22
+
+	testCases := []struct {
+		name     string
+		req      MigrationRequest
+		want     bool
+		strategy MigrationStrategy
+		offset   int64
+	}{
+		{name: "原值较小无需回填", req: MigrationRequest{Table: "orders", Column: "id", MinSerial: 1, MaxSerial: 1e6, Cutover: cutover}, want: true, strategy: MigrationOffset, offset: 0},
+		{name: "原值较大整体平移", req: MigrationRequest{Table: "orders", Column: "id", MinSerial: firstNewID, MaxSerial: firstNewID + 100, Cutover: cutover}, want: true, strategy: MigrationOffset, offset: -101},
+		{name: "原值跨度过大迁移失败", req: MigrationRequest{Table: "orders", Column: "id", MinSerial: 1, MaxSerial: firstNewID + 1, Cutover: cutover}, want: false},
+		{name: "重编码成功", req: MigrationRequest{Table: "orders", Column: "id", MinSerial: 1, MaxSerial: 1e7, Cutover: cutover, Reencode: true, MachineID: 511}, want: true, strategy: MigrationReencode},
+		{name: "重编码机器ID超限失败", req: MigrationRequest{Table: "orders", Column: "id", MinSerial: 1, MaxSerial: 1e7, Cutover: cutover, Reencode: true, MachineID: 512}, want: false},
+		{name: "serial范围错误", req: MigrationRequest{Table: "orders", Column: "id", MinSerial: 10, MaxSerial: 1, Cutover: cutover}, want: false},
+		{name: "切换时间早于基准时间", req: MigrationRequest{Table: "orders", Column: "id", MinSerial: 1, MaxSerial: 10, Cutover: time.Unix(0, DefaultEpoch).Add(-time.Hour)}, want: false},
+	}
+
+	idGen, _ := NewGenerator(0)
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			plan, err := PlanMigration(*DefaultSettings, tc.req)
+			if got := err == nil; got != tc.want {
+				t.Fatalf("【失败】-%s-got:%v-want:%v-err:%v", tc.name, got, tc.want, err)
+			}
+			if err != nil {
+				return
+			}
+			if plan.Strategy != tc.strategy || (tc.strategy == MigrationOffset && plan.Offset != tc.offset) {
+				t.Fatalf("【失败】-%s-strategy:%s-offset:%d", tc.name, plan.Strategy, plan.Offset)
+			}
+			if plan.FirstNewID != firstNewID || plan.MaxID >= firstNewID {
+				t.Fatalf("【失败】-%s-迁移后的id与新生成的id冲突-maxID:%d-firstNewID:%d", tc.name, plan.MaxID, firstNewID)
+			}
+
+			//顺序保持不变
+			prev := int64(-1)
+			for _, serial := range []int64{tc.req.MinSerial, tc.req.MinSerial + 1, tc.req.MinSerial + 4095, tc.req.MinSerial + 4096, tc.req.MaxSerial} {
+				if serial > tc.req.MaxSerial {
+					continue
+				}
+				id, err := plan.Map(serial)
+				if err != nil || id <= prev {
+					t.Fatalf("【失败】-%s-serial:%d-id:%d-prev:%d-err:%v", tc.name, serial, id, prev, err)
+				}
+				prev = id
+				if tc.strategy == MigrationReencode && idGen.Decompose(id).MachineID != tc.req.MachineID {
+					t.Fatalf("【失败】-%s-重编码后的机器ID错误", tc.name)
+				}
+			}
+		})
+	}
+}
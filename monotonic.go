@@ -0,0 +1,36 @@
+package generator
+
+import (
+	"time"
+)
+
+// ErrNotMonotonic 严格递增模式下时钟回退超过等待上限，无法保证id严格递增
+var ErrNotMonotonic = newError(CodeNotMonotonic)
+
+// SetStrictMonotonic 设置严格递增模式：同一生成器返回的id总是大于上一个id
+//   - 发生时钟回退时只切换到生成的id大于上一个id的时间线；默认结构中时间线位于时间之下，切换后的id总是更小，
+//     因此不会切换时间线，而是等待时钟追回，等待时长超过maxWait时返回ErrNotMonotonic
+//   - 适用于将id作为严格递增游标的消费方；不能与序号空间划分(SetSeqReservation)同时使用
+func (idGen *IDGenerator) SetStrictMonotonic(enable bool, maxWait time.Duration) error {
+	if maxWait < 0 {
+		return errorf(CodeNegative, "maxWait")
+	}
+	idGen.mutex.Lock()
+	defer idGen.mutex.Unlock()
+
+	if enable && idGen.reservation != nil {
+		return errorf(CodeMonotonicReservation)
+	}
+	idGen.strictMonotonic = enable
+	idGen.strictMaxWait = maxWait
+	return nil
+}
+
+// waitClockCatchUp 等待时钟追回到当前时间线进度，返回等待后的时间，调用方须持有锁
+func (idGen *IDGenerator) waitClockCatchUp(progress int64) (int64, error) {
+	wait := time.Duration(idGen.toUnixNano(progress) - idGen.now().UnixNano())
+	if wait > idGen.strictMaxWait {
+		return 0, ErrNotMonotonic
+	}
+	return idGen.waitCatchUp(progress, wait)
+}
@@ -0,0 +1,75 @@
+package generator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jayecc/mtl-snowflake/fakeclock"
+)
+
+// TestStrictMonotonic 严格递增模式
+//   - 通过假时钟模拟时钟回退，等待时钟追回时假时钟直接推进
+func TestStrictMonotonic(t *testing.T) {
+	testCases := []struct {
+		name         string
+		strict       bool
+		maxWait      time.Duration
+		backward     time.Duration
+		placement    Placement
+		wantErr      error
+		wantTimeline int64
+		wantIncrease bool
+	}{
+		{name: "默认模式切换时间线", strict: false, maxWait: 0, backward: 20 * time.Millisecond, wantErr: nil, wantTimeline: 1, wantIncrease: false},
+		{name: "严格递增模式等待时钟追回", strict: true, maxWait: 100 * time.Millisecond, backward: 20 * time.Millisecond, wantErr: nil, wantTimeline: 0, wantIncrease: true},
+		{name: "严格递增模式超过等待上限", strict: true, maxWait: 100 * time.Millisecond, backward: time.Hour, wantErr: ErrNotMonotonic},
+		{name: "时间线位于时间之上时切换时间线", strict: true, maxWait: 0, backward: time.Hour, placement: TimelineAboveTime, wantErr: nil, wantTimeline: 1, wantIncrease: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			settings := *DefaultSettings
+			settings.Placement = tc.placement
+			clock := fakeclock.New(time.Now())
+			settings.Clock = clock
+			idGen, _ := NewGeneratorWithSettings(0, settings)
+			if err := idGen.SetStrictMonotonic(tc.strict, tc.maxWait); err != nil {
+				t.Fatal(err.Error())
+			}
+			last, _ := idGen.Generate()
+			clock.Advance(-tc.backward)
+
+			id, err := idGen.Generate()
+			if err != tc.wantErr {
+				t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, err, tc.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got := idGen.Decompose(int64(id)).TimeLine; got != tc.wantTimeline {
+				t.Fatalf("【失败】-%s-时间线-got:%v-want:%v", tc.name, got, tc.wantTimeline)
+			}
+			if got := id > last; got != tc.wantIncrease {
+				t.Fatalf("【失败】-%s-递增-got:%v-want:%v", tc.name, got, tc.wantIncrease)
+			}
+		})
+	}
+}
+
+// TestStrictMonotonicConflict 严格递增模式与序号空间划分互斥
+func TestStrictMonotonicConflict(t *testing.T) {
+	idGen, _ := NewGenerator(0)
+	idGen.SetSeqReservation(2)
+	if err := idGen.SetStrictMonotonic(true, time.Second); err == nil {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "已划分序号空间", err, "error")
+	}
+
+	idGen, _ = NewGenerator(0)
+	idGen.SetStrictMonotonic(true, time.Second)
+	if err := idGen.SetSeqReservation(2); err == nil {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "已开启严格递增模式", err, "error")
+	}
+	if err := idGen.SetStrictMonotonic(true, -time.Second); err == nil {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "等待上限为负数", err, "error")
+	}
+}
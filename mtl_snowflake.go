@@ -17,7 +17,7 @@
 //  |      0       |------   41bit   ------|--   9bit  --|---  1bit  --|---  12bit  ---|
 //
 //  mtl-snowflake在snowflake id结构中增加时间线部分(推荐1位)，通过设置多条时间线，来解决当发生时钟回退时的id重复问题。
-//    - step1 初始时，算法随机选定一条时间线作为当前时间线，生成id的同时推进当前时间线进度。
+//    - step1 初始时，选定一条时间线作为当前时间线(默认时间线0，可通过RandomStartTimeline随机选定或SetStartTimeline指定)，生成id的同时推进当前时间线进度。
 //    - step2 当发生时钟回退，算法暂停当前时间线进度，选择一条合适的时间线(进度<当前时间)并切换到该时间线，这样算法可以继续生成不重复的ID
 //
 //  mtl-snowflake：
@@ -28,37 +28,105 @@
 package generator
 
 import (
-	"errors"
+	"context"
 	"fmt"
+	"io"
+	"math"
 	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 type IDGenerator struct {
-	mutex            *sync.Mutex //互斥锁，保证线程安全
-	settings         *Settings   //生成器参数
-	timelineProgress []int64     //各时间线进度
-	curTimeline      int64       //当前时间线
-	seq              int64       //当前序号
-	machineID        int64       //节点编号
+	fastState          uint64              //无锁快速路径的状态字(时间|时间线|序号)，0表示快速路径不可用；64位原子操作的字段放在最前以保证对齐
+	fastGenerated      int64               //快速路径生成、尚未上报的id数
+	fastPublished      uint64              //最近一次发布的快速路径状态字
+	fastConfig         atomic.Value        //快速路径使用的配置(*fastConfig)
+	lockFree           int32               //是否启用无锁快速路径
+	mutex              *genMutex           //互斥锁，保证线程安全
+	settings           *Settings           //生成器参数
+	timelineProgress   []int64             //各时间线进度
+	curTimeline        int64               //当前时间线
+	seq                int64               //当前序号
+	machineID          int64               //节点编号
+	metrics            Metrics             //指标上报
+	backwardAlert      *backwardAlert      //时钟回退频率告警
+	prefix             int64               //固定置位的高位(基准时间轮换的选择位)
+	pauseDetector      pauseDetector       //时钟跳变检测
+	padding            *padding            //序号填充
+	seqRandom          *seqRandom          //序号随机化
+	isBeforeEpoch      bool                //时钟是否早于基准时间
+	beforeEpochHandler func(now time.Time) //时钟早于基准时间时的回调
+	overflow           *overflowState      //时间部分用尽处理策略
+	labels             []string            //性能分析标签
+	reservation        *seqReservation     //按调用方划分序号空间
+	coalescing         int32               //是否合并并发请求
+	coalescer          coalescer           //并发请求合并
+	burnedTimelines    []bool              //因时钟回退被切换走、尚未回收的时间线
+	burnedCount        int                 //尚未回收的时间线数量
+	reclaimMargin      int64               //回收时间线的安全余量(时间单位)
+	strictMonotonic    bool                //严格递增模式
+	strictMaxWait      time.Duration       //严格递增模式下等待时钟追回的上限
+	lastID             int64               //上一个生成的id
+	maxID              int64               //已生成的最大id
+	ctx                context.Context     //GenerateCtx的ctx，用于取消生成时的等待
+	clock              Clock               //时间源，nil表示系统时钟
+	clockVersion       int64               //时间源的版本，每次设置时间源时递增
+	timer              Timer               //内部等待的实现，nil表示缺省
+	tracer             Tracer              //链路追踪
+	burstBudget        int64               //每个时间单位内使用的序号数上限，0表示不限制
+	waitPolicy         WaitPolicy          //等待方式
+	maxBackwardWait    time.Duration       //时钟小幅回退时等待时钟追回的上限
+	backwardPolicy     BackwardPolicy      //时钟回退策略，nil表示缺省
+	fenced             error               //生成器失效的原因(机器ID租约丢失等)
+	waitBudget         time.Duration       //GenerateWithin剩余的等待额度
+	shardBase          int64               //作为生成器池的分片时，id中序号高位的分片编号(已移位)
+	shardSeqMax        int64               //作为生成器池的分片时，序号低位允许的最大值，0表示不是分片
+	budgeted           bool                //是否限制等待额度
+	hooks              *Hooks              //事件回调
+	nearOverflowFired  bool                //是否已回调OnTimeNearOverflow
+	backwardCount      int64               //检测到时钟回退的次数
+	switchCount        int64               //切换时间线的次数
+	exhaustedCount     int64               //序号用完等待的次数
+	maxDrift           int64               //序号用完时时间线进度允许超前时钟的时间单位数，0表示等待下一个时间单位
+	borrowedTime       int64               //最近一次借用的时间单位
+	borrowedCount      int64               //序号用完时借用下一个时间单位的次数
+	rateLimit          *rateLimiter        //生成速率限制，nil表示不限制
+	persist            *statePersist       //时间线状态持久化
+	closing            chan struct{}       //Close时关闭，通知后台goroutine退出
+	closed             bool                //是否已关闭
+	closers            []io.Closer         //随生成器关闭的资源，关闭后才移除，Close超时后重试时继续关闭
+	stopped            chan struct{}       //后台goroutine均已退出时关闭，首次Close时创建
+	closeTurn          chan struct{}       //容量为1，同一时间只有一个Close关闭资源
+	stateFlushed       bool                //Close是否已保存最终的时间线状态
+	background         sync.WaitGroup      //Stream等后台goroutine
+	createdAt          int64               //创建时间(时间单位)，GenerateAt只能回填此前的时间
+	backfill           map[int64]int64     //GenerateAt各时间单位已分配的id数
 }
 
 // ID结构
 type IDCompose struct {
-	Time      int64 //时间单位ms
-	MachineID int64 //机器ID
-	TimeLine  int64 //时间线
-	Seq       int64 //序号
+	Version      int64     //id结构的版本号，未设置VersionBit时为0
+	Time         int64     //时间(自基准时间起的时间单位数)
+	DatacenterID int64     //数据中心ID，未设置DatacenterBit时为0
+	MachineID    int64     //机器ID
+	TimeLine     int64     //时间线
+	Seq          int64     //序号
+	Timestamp    time.Time //生成时间(已按基准时间换算，精确到时间单位)
 }
 
 // GetMachineID 节点编号
 func (idGen *IDGenerator) GetMachineID() int64 {
+	idGen.mutex.Lock()
+	defer idGen.mutex.Unlock()
 	return idGen.machineID
 }
 
 // GetSettings 初始化配置
 func (idGen *IDGenerator) GetSettings() Settings {
+	idGen.mutex.Lock()
+	defer idGen.mutex.Unlock()
 	return *idGen.settings
 }
 
@@ -84,115 +152,365 @@ func NewGeneratorWithSettings(machineID int64, settings Settings) (*IDGenerator,
 	settings.presets = calcPresets(&settings)
 
 	idGen := new(IDGenerator)
-	idGen.mutex = new(sync.Mutex)
+	idGen.mutex = &genMutex{idGen: idGen}
+	idGen.closing = make(chan struct{})
+	idGen.closeTurn = make(chan struct{}, 1)
 
 	idGen.settings = &settings
 	idGen.timelineProgress = make([]int64, settings.presets.maxTimeline+1)
+	idGen.burnedTimelines = make([]bool, settings.presets.maxTimeline+1)
 	idGen.curTimeline = 0
 	idGen.seq = 0
 	idGen.machineID = machineID
+	idGen.clock = settings.Clock
+	idGen.timer = settings.Timer
+	idGen.waitPolicy = settings.WaitPolicy
+	idGen.maxBackwardWait = settings.MaxBackwardWait
+	idGen.backwardPolicy = settings.BackwardPolicy
+	idGen.createdAt = idGen.toOffsetTime(idGen.now().UnixNano())
+	if settings.SelfTest {
+		if err := idGen.SelfTest(); err != nil {
+			return nil, err
+		}
+	}
+	if settings.StatePersister != nil {
+		if err := idGen.restoreState(settings.StatePersister, settings.PersistInterval); err != nil {
+			return nil, err
+		}
+	}
 	return idGen, nil
 }
 
 // Generate 生成全局唯一id
-func (idGen *IDGenerator) Generate() (int64, error) {
+func (idGen *IDGenerator) Generate() (ID, error) {
+	id, err := idGen.generate()
+	return ID(id), err
+}
+
+// generate 生成全局唯一id，供返回int64的生成方法复用
+func (idGen *IDGenerator) generate() (int64, error) {
+	if atomic.LoadInt32(&idGen.lockFree) != 0 {
+		if id, ok := idGen.generateFast(); ok {
+			return id, nil
+		}
+	}
+	if atomic.LoadInt32(&idGen.coalescing) != 0 {
+		return idGen.generateCoalesced()
+	}
+
 	idGen.mutex.Lock()
 	defer idGen.mutex.Unlock()
+	return idGen.generateLocked()
+}
+
+// GenerateN 批量生成n个id，只加锁一次
+//   - 同一时间单位内剩余的序号直接顺序分配，不再重复读取时钟；序号用完时按Generate的逻辑等待下一个时间单位
+//   - 开启序号填充、序号随机化或序号空间划分时逐个生成
+func (idGen *IDGenerator) GenerateN(n int) ([]int64, error) {
+	if n < 0 {
+		return nil, errorf(CodeNegative, "n")
+	}
+
+	idGen.mutex.Lock()
+	defer idGen.mutex.Unlock()
+	if err := idGen.checkRate(idGen.now(), n); err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, 0, n)
+	for len(ids) < n {
+		id, err := idGen.generateLocked()
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+		if idGen.padding != nil || idGen.reservation != nil || idGen.seqRandom != nil {
+			continue
+		}
+
+		//顺序分配当前时间单位剩余的序号
+		presets := idGen.settings.presets
+		curTime := idGen.timelineProgress[idGen.curTimeline]
+		if curTime > presets.maxTime {
+			continue
+		}
+		k := int64(n - len(ids))
+		if rest := idGen.seqLimit(presets.maxSeq) - idGen.seq; rest < k {
+			k = rest
+		}
+		if k <= 0 {
+			continue
+		}
+		for i := int64(0); i < k; i++ {
+			idGen.seq++
+			ids = append(ids, idGen.compose(curTime, idGen.curTimeline, idGen.seq))
+		}
+		idGen.lastID = ids[len(ids)-1]
+		if idGen.lastID > idGen.maxID {
+			idGen.maxID = idGen.lastID
+		}
+		idGen.spendRate(k)
+		if idGen.metrics != nil {
+			idGen.metrics.Counter(MetricGenerated, k)
+		}
+	}
+	return ids, nil
+}
+
+// generateLocked 生成id，调用方须持有锁
+func (idGen *IDGenerator) generateLocked() (int64, error) {
+	return idGen.generateCallerLocked(defaultCaller)
+}
+
+// generateCallerLocked 为调用方生成id(未划分序号空间时忽略调用方)，调用方须持有锁
+func (idGen *IDGenerator) generateCallerLocked(caller int) (int64, error) {
+	if idGen.fenced != nil {
+		return 0, idGen.fenced
+	}
+	if err := idGen.checkBreaker(); err != nil {
+		return 0, err
+	}
 
 	settings := idGen.settings
-	curTime := idGen.toOffsetTime(time.Now().UnixNano())
+	now := idGen.now()
+	if err := idGen.checkRate(now, 1); err != nil {
+		return 0, err
+	}
+	curTime := idGen.toOffsetTime(now.UnixNano())
 	progress := idGen.timelineProgress[idGen.curTimeline] //当前时间线进度
+	jumped := idGen.detectClockJump(now)                  //是否检测到时钟跳变(如虚拟机暂停、热迁移)
+	if idGen.burnedCount > 0 {
+		idGen.reclaimTimelines(curTime)
+	}
+	if idGen.borrowing(curTime, progress) {
+		//时钟尚未追上借用的时间单位，继续使用
+		curTime = progress
+	}
 
 	// 处理时钟回退
 	if curTime < progress {
+		if idGen.metrics != nil {
+			idGen.metrics.Counter(MetricClockBackward, 1)
+		}
+		idGen.recordBackward()
+		idGen.backwardCount++
+		idGen.hookClockBackward(time.Duration(progress-curTime) * time.Duration(settings.unit()))
 		if curTime < 0 {
-			return 0, errors.New("时钟回退时间过长，请检查服务器时钟或设置一个更早的基准时间(Epoch)")
+			return 0, idGen.beforeEpoch(now)
 		}
 
-		// 时间小幅回退,等待,直到时间追回
-		if progress-curTime < maxWaitTime {
-			time.Sleep(time.Millisecond * time.Duration(progress-curTime))
-			curTime = idGen.toOffsetTime(time.Now().UnixNano())
-		} else {
-			//查找合适的时间线
+		// 按时钟回退策略等待时钟追回、切换时间线或返回错误；缺省在追回所需时长不超过MaxBackwardWait且未检测到时钟跳变时等待，否则切换时间线
+		wait := time.Duration(idGen.toUnixNano(progress) - now.UnixNano())
+		switch idGen.backwardAction(wait, jumped) {
+		case BackwardWait:
+			var err error
+			if curTime, err = idGen.waitCatchUp(progress, wait); err != nil {
+				return 0, err
+			}
+		case BackwardFail:
+			unit := time.Duration(settings.unit())
+			return 0, &ClockBackwardError{Backward: time.Duration(progress-curTime) * unit, RetryAfter: wait, Err: ErrBackwardRejected}
+		default:
 			timeline, err := idGen.findSuitableTimeLine(curTime)
 			if err != nil {
-				return 0, err
+				//严格递增模式下没有合适的时间线时等待时钟追回
+				if !idGen.strictMonotonic {
+					return 0, err
+				}
+				if curTime, err = idGen.waitClockCatchUp(progress); err != nil {
+					return 0, err
+				}
+				break
 			}
-
-			//切换时间线
-			idGen.timelineProgress[idGen.curTimeline] = curTime
+			//切换时间线，原时间线保留回退前的进度，待时钟追回后回收
+			idGen.traceTimelineSwitch(timeline, time.Duration(progress-curTime)*time.Duration(settings.unit()))
+			idGen.burnTimeline(idGen.curTimeline)
+			idGen.hookTimelineSwitch(idGen.curTimeline, timeline)
+			idGen.switchCount++
 			progress = idGen.timelineProgress[timeline]
 			idGen.curTimeline = timeline
 			idGen.seq = 0
+			if idGen.metrics != nil {
+				idGen.metrics.Counter(MetricTimelineSwitch, 1)
+				idGen.metrics.Gauge(MetricTimeline, float64(timeline))
+			}
 		}
 	}
 
-	if curTime == progress {
-		//如果当前时间单位的序号已用完，等待直到下一个时间单位
-		if idGen.seq = (idGen.seq + 1) & settings.presets.maskSeq; idGen.seq == 0 {
-			time.Sleep(time.Duration(idGen.toUnixNano(curTime+1) - time.Now().UnixNano()))
-			curTime = idGen.toOffsetTime(time.Now().UnixNano())
+	if idGen.reservation != nil {
+		//按调用方划分的序号空间
+		var err error
+		if curTime, err = idGen.reservation.nextSeq(idGen, caller, curTime); err != nil {
+			return 0, err
+		}
+	} else if curTime == progress {
+		//如果当前时间单位的序号已用完，借用或等待直到下一个时间单位；等待被取消时保持序号已用完的状态
+		if idGen.nextSeqExhausted(&idGen.seq, settings.presets.maxSeq) {
+			var err error
+			if curTime, err = idGen.nextTime(curTime); err != nil {
+				idGen.seq = idGen.seqLimit(settings.presets.maxSeq)
+				return 0, err
+			}
 		}
 	} else {
-		idGen.seq = 0
+		idGen.seq = idGen.firstSeq()
+	}
+
+	//跳过填充的序号
+	if idGen.padding != nil {
+		var err error
+		if curTime, err = idGen.skipPadding(curTime); err != nil {
+			return 0, err
+		}
 	}
 
 	//时间线向前推进
 	idGen.timelineProgress[idGen.curTimeline] = curTime
 
 	if curTime > settings.presets.maxTime {
-		return 0, errors.New("当前时间偏移量已超过最大限制，请设置更多的时间位数或设置一个更近的基准时间")
+		return idGen.handleOverflow(now, caller)
+	}
+	idGen.checkOverflowWarning(now)
+	idGen.hookNearOverflow(now)
+	if idGen.persist != nil {
+		if err := idGen.ensurePersisted(curTime); err != nil {
+			return 0, err
+		}
 	}
 
-	id := (curTime << settings.presets.shiftTimeBit) |
-		(idGen.machineID << settings.presets.shiftMachineIDBit) |
-		(idGen.curTimeline << settings.presets.shiftTimelineBit) |
-		(idGen.seq)
+	id := idGen.compose(curTime, idGen.curTimeline, idGen.seq)
+	idGen.lastID = id
+	if id > idGen.maxID {
+		idGen.maxID = id
+	}
+	idGen.isBeforeEpoch = false
+	idGen.spendRate(1)
+	if idGen.metrics != nil {
+		idGen.metrics.Counter(MetricGenerated, 1)
+	}
 	return id, nil
 }
 
+// compose 按id结构组合各部分
+func (idGen *IDGenerator) compose(curTime, timeline, seq int64) int64 {
+	presets := idGen.settings.presets
+	return idGen.prefix |
+		presets.version |
+		(curTime << presets.shiftTimeBit) |
+		presets.datacenter |
+		(idGen.machineID << presets.shiftMachineIDBit) |
+		(timeline << presets.shiftTimelineBit) |
+		(seq << presets.shiftSeq) |
+		idGen.shardBase
+}
+
+// waitNextTime 当前时间单位的序号已用完，等待直到下一个时间单位，返回等待后的时间
+func (idGen *IDGenerator) waitNextTime(curTime int64) (int64, error) {
+	wait := time.Duration(idGen.toUnixNano(curTime+1) - idGen.now().UnixNano())
+	if idGen.metrics != nil {
+		idGen.metrics.Counter(MetricSeqExhausted, 1)
+	}
+	idGen.hookSeqExhausted(wait)
+	idGen.exhaustedCount++
+	span := idGen.traceWait(SpanSeqExhaustedWait, wait)
+	next, err := idGen.waitUntil(curTime + 1)
+	endTrace(span, err)
+	if err != nil {
+		return curTime, err
+	}
+	if idGen.metrics != nil {
+		idGen.metrics.Histogram(MetricWaitSeconds, wait.Seconds())
+	}
+	return next, nil
+}
+
 // findSuitableTimeLine 查找满足当前时间要求的时间线
 func (idGen *IDGenerator) findSuitableTimeLine(curTime int64) (int64, error) {
 	var fastProgress int64 = -1
 	var timeLineFound int64 = -1
 	//找出满足当前时间要求且进度最快的时间线
 	for index, progress := range idGen.timelineProgress {
+		if idGen.burnedCount > 0 && idGen.burnedTimelines[index] {
+			continue
+		}
+		//严格递增模式下只切换到生成的id大于上一个id的时间线
+		if idGen.strictMonotonic && idGen.compose(curTime, int64(index), 0) <= idGen.lastID {
+			continue
+		}
 		if progress < curTime && progress > fastProgress {
 			fastProgress = progress
 			timeLineFound = int64(index)
 		}
 	}
 	if timeLineFound == -1 {
-		return -1, errors.New("时钟回退太频繁，请调整服务器时钟同步策略或增加时间线数量")
+		return -1, idGen.timelinesExhausted(curTime)
 	}
 	return timeLineFound, nil
 }
 
+// timelinesExhausted 没有可切换的时间线，估算恢复所需的时长：时钟追回当前时间线进度，或超过其他可用时间线的进度
+func (idGen *IDGenerator) timelinesExhausted(curTime int64) error {
+	progress := idGen.timelineProgress[idGen.curTimeline]
+	recovery := progress
+	for index, other := range idGen.timelineProgress {
+		if int64(index) == idGen.curTimeline || idGen.burnedTimelines[index] {
+			continue
+		}
+		if other+1 < recovery {
+			recovery = other + 1
+		}
+	}
+	unit := time.Duration(idGen.settings.unit())
+	return &ClockBackwardError{
+		Backward:   time.Duration(progress-curTime) * unit,
+		RetryAfter: time.Duration(recovery-curTime) * unit,
+		Err:        ErrTimelinesExhausted,
+	}
+}
+
 func (idGen *IDGenerator) toOffsetTime(unixNano int64) int64 {
-	return (unixNano - idGen.settings.Epoch) / int64(timeUnit)
+	return (unixNano - idGen.settings.Epoch) / idGen.settings.unit()
 }
 
 func (idGen *IDGenerator) toUnixNano(offset int64) int64 {
-	return idGen.settings.Epoch + offset*int64(timeUnit)
+	return idGen.settings.Epoch + offset*idGen.settings.unit()
 }
 
 // Decompose 将id解析成time、seq等部分
 func (idGen *IDGenerator) Decompose(id int64) *IDCompose {
 	presets := idGen.settings.presets
+	version := (int64(id) & presets.maskVersion) >> presets.shiftVersionBit
 	time := (int64(id) & presets.maskTime) >> presets.shiftTimeBit
+	datacenterID := (int64(id) & presets.maskDatacenter) >> presets.shiftDatacenterBit
 	machineID := (int64(id) & presets.maskMachineID) >> presets.shiftMachineIDBit
 	timeline := (int64(id) & presets.maskTimeline) >> presets.shiftTimelineBit
 	seq := (int64(id) & presets.maskSeq) >> presets.shiftSeq
 	return &IDCompose{
-		Time:      time,
-		MachineID: machineID,
-		TimeLine:  timeline,
-		Seq:       seq,
+		Version:      version,
+		Time:         time,
+		DatacenterID: datacenterID,
+		MachineID:    machineID,
+		TimeLine:     timeline,
+		Seq:          seq,
+		Timestamp:    idGen.timestamp(time),
 	}
 }
 
-// ToReadable 将int64类型的id转换成时间+序号格式，如：2019090419014733273728
+// Time id的生成时间(精确到时间单位)
+func (idGen *IDGenerator) Time(id int64) time.Time {
+	return idGen.Decompose(id).Timestamp
+}
+
+// timestamp 时间部分对应的时间
+func (idGen *IDGenerator) timestamp(offset int64) time.Time {
+	return time.Unix(0, idGen.toUnixNano(offset))
+}
+
+// readableLayout 可读格式中的时间部分(精确到秒)
+const readableLayout = "20060102150405"
+
+// ToReadable 将int64类型的id转换成时间+序号格式，如：2019090419014733273728(毫秒级时间单位)
+//   - 时间按本地时区输出，可通过ParseReadable还原为id
 func (idGen *IDGenerator) ToReadable(id int64) string {
 	presets := idGen.settings.presets
 
@@ -200,11 +518,92 @@ func (idGen *IDGenerator) ToReadable(id int64) string {
 	timePart := (id & presets.maskTime) >> presets.shiftTimeBit
 	genTime := time.Unix(0, idGen.toUnixNano(timePart))
 
+	//剩余部分(去掉时间部分后其余各部分按原顺序拼接)
+	maxInTime := int64(1)<<(63-idGen.settings.TimeBit) - 1
+	inTimesPart := id>>(presets.shiftTimeBit+idGen.settings.TimeBit)<<presets.shiftTimeBit | id&(int64(1)<<presets.shiftTimeBit-1)
+	inTimeDigit := len(strconv.FormatInt(maxInTime, 10)) //十进制位数
+
+	//秒以下部分，按时间单位的精度输出，如毫秒为3位，秒级时间单位不输出
+	subSecond := ""
+	unit := idGen.settings.unit()
+	if perSecond := int64(time.Second) / unit; perSecond > 1 {
+		subSecond = fmt.Sprintf("%0*d", len(strconv.FormatInt(perSecond-1, 10)), int64(genTime.Nanosecond())/unit)
+	}
+
+	format := fmt.Sprintf("%%s%%s%%0.%dd", inTimeDigit)
+	return fmt.Sprintf(format, genTime.Format(readableLayout), subSecond, inTimesPart)
+}
+
+// ParseReadable 解析ToReadable输出的可读格式，返回id
+//   - 时间按本地时区解析，与ToReadable一致；本地时区不存在的时间(夏令时跳变)返回错误
+func (idGen *IDGenerator) ParseReadable(readable string) (int64, error) {
+	settings := idGen.settings
+	presets := settings.presets
+	unit := settings.unit()
+
+	perSecond := int64(time.Second) / unit
+	subDigit := 0
+	if perSecond > 1 {
+		subDigit = len(strconv.FormatInt(perSecond-1, 10))
+	}
+	maxInTime := int64(1)<<(63-settings.TimeBit) - 1
+	inTimeDigit := len(strconv.FormatInt(maxInTime, 10))
+	if len(readable) != len(readableLayout)+subDigit+inTimeDigit {
+		return 0, errorf(CodeReadableLength, len(readableLayout)+subDigit+inTimeDigit)
+	}
+	if !isDigits(readable) {
+		return 0, errorf(CodeReadableDigits)
+	}
+
+	//时间部分
+	t, err := time.ParseInLocation(readableLayout, readable[:len(readableLayout)], time.Local)
+	if err != nil {
+		return 0, err
+	}
+	if t.Format(readableLayout) != readable[:len(readableLayout)] {
+		return 0, errorf(CodeReadableLocalTime)
+	}
+	if sec := t.Unix(); sec <= math.MinInt64/int64(time.Second) || sec >= math.MaxInt64/int64(time.Second) {
+		return 0, errorf(CodeReadableTimeRange)
+	}
+	nanos := t.UnixNano()
+	if subDigit > 0 {
+		sub, _ := strconv.ParseInt(readable[len(readableLayout):len(readableLayout)+subDigit], 10, 64)
+		if sub >= perSecond {
+			return 0, errorf(CodeReadableSubsecond)
+		}
+		nanos += sub * unit
+	}
+	timePart, err := idGen.readableTimePart(nanos)
+	if err != nil {
+		return 0, err
+	}
+
 	//剩余部分
-	maxInTime := (1 << presets.shiftTimeBit) - 1
-	inTimesPart := id & int64(maxInTime)
-	inTimeDigit := len(strconv.Itoa(int(maxInTime))) //十进制位数
+	inTimesPart, err := strconv.ParseInt(readable[len(readableLayout)+subDigit:], 10, 64)
+	if err != nil || inTimesPart > maxInTime {
+		return 0, errorf(CodeReadableRest)
+	}
+	return inTimesPart>>presets.shiftTimeBit<<(presets.shiftTimeBit+settings.TimeBit) |
+		timePart<<presets.shiftTimeBit |
+		inTimesPart&(int64(1)<<presets.shiftTimeBit-1), nil
+}
 
-	format := fmt.Sprintf("%%s%%0.3d%%0.%dd", inTimeDigit)
-	return fmt.Sprintf(format, genTime.Format("20060102150405"), genTime.Nanosecond()/int(timeUnit), inTimesPart)
+// readableTimePart 由可读格式中截断到时间单位的时间(unix nano)计算时间部分
+//   - 基准时间未按时间单位对齐时向上取整
+func (idGen *IDGenerator) readableTimePart(nanos int64) (int64, error) {
+	settings := idGen.settings
+	unit := settings.unit()
+	if nanos <= settings.Epoch-unit {
+		return 0, errorf(CodeReadableBeforeEpoch)
+	}
+	diff := nanos - settings.Epoch
+	timePart := diff / unit
+	if diff > 0 && diff%unit != 0 {
+		timePart++
+	}
+	if timePart > settings.presets.maxTime {
+		return 0, errorf(CodeReadableTimeOverflow)
+	}
+	return timePart, nil
 }
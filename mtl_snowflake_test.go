@@ -8,6 +8,8 @@ import (
 	"sync"
 	"testing"
 	"time"
+
+	"github.com/jayecc/mtl-snowflake/fakeclock"
 )
 
 // TestNewGeneratorWithSettings 创建生成器
@@ -30,6 +32,11 @@ func TestNewGeneratorWithSettings(t *testing.T) {
 		{name: "machineIDBit为0校验成功", args: Args{Settings: Settings{TimeBit: 41, MachineIDBit: 0, TimelineBit: 1, SeqBit: 21, Epoch: DefaultEpoch}, MachineID: 0}, want: true},
 		{name: "machineID超限校验失败", args: Args{Settings: Settings{TimeBit: 41, MachineIDBit: 9, TimelineBit: 1, SeqBit: 12, Epoch: DefaultEpoch}, MachineID: -1}, want: false},
 		{name: "machineID超限校验失败", args: Args{Settings: Settings{TimeBit: 41, MachineIDBit: 9, TimelineBit: 1, SeqBit: 12, Epoch: DefaultEpoch}, MachineID: 1024}, want: false},
+		{name: "秒级配置校验成功", args: Args{Settings: *SecondSettings, MachineID: 1023}, want: true},
+		{name: "微秒单位时间位溢出校验失败", args: Args{Settings: Settings{TimeBit: 41, MachineIDBit: 9, TimelineBit: 1, SeqBit: 12, Epoch: DefaultEpoch, TimeUnit: time.Microsecond}, MachineID: 0}, want: false},
+		{name: "不能整除1秒的时间单位校验失败", args: Args{Settings: Settings{TimeBit: 41, MachineIDBit: 9, TimelineBit: 1, SeqBit: 12, Epoch: DefaultEpoch, TimeUnit: 7 * time.Millisecond}, MachineID: 0}, want: false},
+		{name: "非整微秒的时间单位校验失败", args: Args{Settings: Settings{TimeBit: 41, MachineIDBit: 9, TimelineBit: 1, SeqBit: 12, Epoch: DefaultEpoch, TimeUnit: 1500 * time.Nanosecond}, MachineID: 0}, want: false},
+		{name: "时间范围超出unix nano校验失败", args: Args{Settings: Settings{TimeBit: 52, MachineIDBit: 0, TimelineBit: 1, SeqBit: 10, Epoch: DefaultEpoch, TimeUnit: time.Second}, MachineID: 0}, want: false},
 	}
 
 	for _, tc := range testCases {
@@ -76,7 +83,7 @@ func TestUniqueID(t *testing.T) {
 }
 
 // TestTimeBackward 时钟回退
-// - 通过调整基准时间，模拟时钟回退
+// - 通过假时钟模拟时钟回退，序号用完时假时钟直接推进到下一个时间单位
 func TestTimeBackward(t *testing.T) {
 	testCases := []struct {
 		name      string
@@ -108,11 +115,13 @@ func TestTimeBackward(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
+			clock := fakeclock.New(time.Now())
+			tc.settings.Clock = clock
 			idGen, _ := NewGeneratorWithSettings(0, tc.settings)
 			ids := make(map[int64]interface{})
 
 			// 记录开始时间点
-			startTime := time.Now().UnixNano()
+			startTime := clock.Time()
 			// step 1 先生成一批id
 			err := generator(idGen, ids, 1e7)
 
@@ -122,8 +131,7 @@ func TestTimeBackward(t *testing.T) {
 
 			for backCount := 0; backCount < tc.backCount; backCount++ {
 				// step 2 回退到开始时间点
-				curTime := time.Now().UnixNano()
-				idGen.settings.Epoch += curTime - startTime
+				clock.Set(startTime)
 
 				// step 3 继续生成
 				err := generator(idGen, ids, 1e7)
@@ -161,10 +169,10 @@ func TestDecompose(t *testing.T) {
 	id3, _ := idGen1.Generate()
 
 	testCases := []TestCaseEntity{
-		{name: "验证成功case1", idGen: idGen0, id: id0, want: IDCompose{MachineID: 0, Seq: 0}},
-		{name: "验证成功case2", idGen: idGen0, id: id1, want: IDCompose{MachineID: 0, Seq: 1}},
-		{name: "验证成功case3", idGen: idGen1, id: id2, want: IDCompose{MachineID: 1, Seq: 0}},
-		{name: "验证成功case4", idGen: idGen1, id: id3, want: IDCompose{MachineID: 1, Seq: 1}},
+		{name: "验证成功case1", idGen: idGen0, id: int64(id0), want: IDCompose{MachineID: 0, Seq: 0}},
+		{name: "验证成功case2", idGen: idGen0, id: int64(id1), want: IDCompose{MachineID: 0, Seq: 1}},
+		{name: "验证成功case3", idGen: idGen1, id: int64(id2), want: IDCompose{MachineID: 1, Seq: 0}},
+		{name: "验证成功case4", idGen: idGen1, id: int64(id3), want: IDCompose{MachineID: 1, Seq: 1}},
 	}
 
 	for _, tc := range testCases {
@@ -179,6 +187,199 @@ func TestDecompose(t *testing.T) {
 
 }
 
+// TestGenerateN 批量生成
+func TestGenerateN(t *testing.T) {
+	testCases := []struct {
+		name    string
+		n       int
+		padding float64
+		wantErr bool
+	}{
+		{name: "生成0个", n: 0},
+		{name: "单个时间单位内", n: 500},
+		{name: "跨多个时间单位", n: 10000},
+		{name: "开启序号填充", n: 5000, padding: 0.5},
+		{name: "n为负数", n: -1, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			idGen, _ := NewGenerator(0)
+			metrics := countingMetrics{}
+			idGen.SetMetrics(metrics)
+			if tc.padding > 0 {
+				idGen.SetPadding([]byte("key"), tc.padding)
+			}
+			first, _ := idGen.Generate()
+			last := int64(first)
+
+			ids, err := idGen.GenerateN(tc.n)
+			if got := err != nil; got != tc.wantErr {
+				t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, err, tc.wantErr)
+			}
+			if got := len(ids); got != tc.n && !tc.wantErr {
+				t.Fatalf("【失败】-%s-数量-got:%v-want:%v", tc.name, got, tc.n)
+			}
+			for _, id := range ids {
+				if id <= last {
+					t.Fatalf("【失败】-%s-递增-got:%v-want:>%v", tc.name, id, last)
+				}
+				last = id
+			}
+			if got := metrics[MetricGenerated]; !tc.wantErr && got != int64(tc.n)+1 {
+				t.Fatalf("【失败】-%s-指标-got:%v-want:%v", tc.name, got, tc.n+1)
+			}
+
+			//批量生成后继续逐个生成
+			if id, _ := idGen.Generate(); int64(id) <= last {
+				t.Fatalf("【失败】-%s-后续生成-got:%v-want:>%v", tc.name, id, last)
+			}
+		})
+	}
+}
+
+// TestDecomposePlacement 不同时间线位置的id解构
+func TestDecomposePlacement(t *testing.T) {
+	want := IDCompose{Time: 123456789, MachineID: 300, TimeLine: 1, Seq: 4000, Timestamp: time.Unix(0, DefaultEpoch+123456789*int64(time.Millisecond))}
+	testCases := []struct {
+		name      string
+		placement Placement
+		readable  string //剩余部分
+	}{
+		{name: "时间线位于机器ID之下", placement: TimelineBelowMachine, readable: fmt.Sprintf("%07d", 300<<13|1<<12|4000)},
+		{name: "时间线位于机器ID之上", placement: TimelineAboveMachine, readable: fmt.Sprintf("%07d", 1<<21|300<<12|4000)},
+		{name: "时间线位于时间之上", placement: TimelineAboveTime, readable: fmt.Sprintf("%07d", 1<<21|300<<12|4000)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			settings := *DefaultSettings
+			settings.Placement = tc.placement
+			idGen, err := NewGeneratorWithSettings(want.MachineID, settings)
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+			id := idGen.compose(want.Time, want.TimeLine, want.Seq)
+			if got := *idGen.Decompose(id); got != want {
+				t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, got, want)
+			}
+			if got := idGen.ToReadable(id); got[17:] != tc.readable {
+				t.Fatalf("【失败】-%s-可读格式-got:%v-want:%v", tc.name, got[17:], tc.readable)
+			}
+
+			generated, _ := idGen.Generate()
+			if got := idGen.Decompose(int64(generated)); got.MachineID != want.MachineID || got.TimeLine != 0 || got.Seq != 0 {
+				t.Fatalf("【失败】-%s-生成-got:%v", tc.name, got)
+			}
+		})
+	}
+
+	settings := *DefaultSettings
+	settings.Placement = TimelineAboveTime + 1
+	if _, err := NewGeneratorWithSettings(0, settings); err == nil {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "不支持的时间线位置", err, "error")
+	}
+}
+
+// TestParseReadable 解析可读格式
+func TestParseReadable(t *testing.T) {
+	unaligned := *DefaultSettings
+	unaligned.Epoch += int64(300 * time.Microsecond) //基准时间未按时间单位对齐
+	aboveTime := *DefaultSettings
+	aboveTime.Placement = TimelineAboveTime
+	flagged := Settings{TimeBit: 41, MachineIDBit: 9, TimelineBit: 1, SeqBit: 11, Epoch: DefaultEpoch, FlagBit: true}
+	micro := Settings{TimeBit: 51, MachineIDBit: 5, TimelineBit: 1, SeqBit: 6, Epoch: DefaultEpoch, TimeUnit: time.Microsecond}
+
+	testCases := []struct {
+		name     string
+		settings Settings
+	}{
+		{name: "默认配置", settings: *DefaultSettings},
+		{name: "秒级时间单位", settings: *SecondSettings},
+		{name: "10毫秒时间单位", settings: *CentisecondSettings},
+		{name: "微秒时间单位", settings: micro},
+		{name: "时间线位于时间之上", settings: aboveTime},
+		{name: "基准时间未对齐", settings: unaligned},
+		{name: "标记位", settings: flagged},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			idGen, _ := NewGeneratorWithSettings(3, tc.settings)
+			id := idGen.compose(idGen.toOffsetTime(time.Now().UnixNano()), 1, 5)
+			got, err := idGen.ParseReadable(idGen.ToReadable(id))
+			if err != nil || got != id {
+				t.Fatalf("【失败】-%s-got:%v(%v)-want:%v", tc.name, got, err, id)
+			}
+		})
+	}
+
+	idGen, _ := NewGenerator(0)
+	errCases := []struct {
+		name     string
+		readable string
+	}{
+		{name: "长度错误", readable: "2020"},
+		{name: "包含非数字", readable: "20200101000000000+000001"},
+		{name: "月份超出范围", readable: "20201301000000000000000"},
+		{name: "早于基准时间", readable: "20190101000000000000000"},
+		{name: "剩余部分超出范围", readable: "20200101000000000" + "9999999"},
+	}
+	for _, tc := range errCases {
+		if _, err := idGen.ParseReadable(tc.readable); err == nil {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, err, "error")
+		}
+	}
+}
+
+// TestTime id的生成时间
+func TestTime(t *testing.T) {
+	idGen, _ := NewGenerator(0)
+	before := time.Now().Truncate(time.Millisecond)
+	id, _ := idGen.Generate()
+	after := time.Now()
+
+	if got := idGen.Time(int64(id)); got.Before(before) || got.After(after) {
+		t.Fatalf("【失败】-%s-got:%v-want:[%v,%v]", "生成时间", got, before, after)
+	}
+}
+
+// TestSecondSettings 秒级时间单位
+func TestSecondSettings(t *testing.T) {
+	idGen, err := NewGeneratorWithSettings(1, *SecondSettings)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	before := time.Now().Truncate(time.Second)
+	var last int64
+	for i := 0; i < 1e5; i++ {
+		id, err := idGen.Generate()
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if int64(id) <= last {
+			t.Fatalf("【失败】-%s-got:%v-want:>%v", "递增", id, last)
+		}
+		last = int64(id)
+	}
+	after := time.Now()
+
+	if got := idGen.Time(last); got.Before(before) || got.After(after) {
+		t.Fatalf("【失败】-%s-got:%v-want:[%v,%v]", "生成时间", got, before, after)
+	}
+	if got := idGen.Decompose(last).MachineID; got != 1 {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "机器ID", got, 1)
+	}
+
+	//秒级时间单位不输出毫秒部分：14位时间+10位(2^31-1)剩余部分
+	readable := idGen.ToReadable(last)
+	if want := idGen.Time(last).Format("20060102150405"); len(readable) != 24 || readable[:14] != want {
+		t.Fatalf("【失败】-%s-got:%v-want:%v+10位", "可读格式", readable, want)
+	}
+	if got, want := readable[14:], fmt.Sprintf("%010d", last&(1<<31-1)); got != want {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "可读格式剩余部分", got, want)
+	}
+}
+
 // BenchmarkGenSeqBit12 单节点(12位序列号)性能测试
 func BenchmarkGenSeqBit12(b *testing.B) {
 	idGen, _ := NewGenerator(0)
@@ -187,6 +388,14 @@ func BenchmarkGenSeqBit12(b *testing.B) {
 	}
 }
 
+// BenchmarkGenerateN 批量生成(每批500个)性能测试
+func BenchmarkGenerateN(b *testing.B) {
+	idGen, _ := NewGenerator(0)
+	for i := 0; i < b.N; i += 500 {
+		idGen.GenerateN(500)
+	}
+}
+
 // BenchmarkGenSeqBit14 单节点(14位序列号)性能测试
 func BenchmarkGenSeqBit14(b *testing.B) {
 	idGen, _ := NewGeneratorWithSettings(0, Settings{
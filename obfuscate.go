@@ -0,0 +1,84 @@
+package generator
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"math"
+)
+
+// obfuscateRounds Feistel网络的轮数
+const obfuscateRounds = 8
+
+// Obfuscator 按密钥对id做可逆的混淆，用于对外暴露id
+//   - 以64位Feistel网络为基础，通过循环迭代(cycle walking)限定在63位空间内：非负id混淆后仍为非负数，不同id混淆后不同
+//   - 混淆后的id不再按时间递增，外部无法根据相邻id推算生成速率或先后顺序；持有密钥可通过Deobfuscate还原
+//   - 可并发使用
+type Obfuscator struct {
+	keys [obfuscateRounds]uint64 //各轮的子密钥
+}
+
+// NewObfuscator 创建混淆器，key不能为空，同一系统的混淆与还原须使用相同的key
+func NewObfuscator(key []byte) (*Obfuscator, error) {
+	if len(key) == 0 {
+		return nil, errorf(CodeObfuscateKey)
+	}
+	o := new(Obfuscator)
+	mac := hmac.New(sha256.New, key)
+	for i := range o.keys {
+		mac.Reset()
+		mac.Write([]byte{byte(i)})
+		o.keys[i] = binary.BigEndian.Uint64(mac.Sum(nil))
+	}
+	return o, nil
+}
+
+// Obfuscate 混淆id，符号位保持不变
+func (o *Obfuscator) Obfuscate(id int64) int64 {
+	v := uint64(id & math.MaxInt64)
+	for {
+		v = o.encrypt(v)
+		if v <= math.MaxInt64 {
+			break
+		}
+	}
+	return int64(v) | id&math.MinInt64
+}
+
+// Deobfuscate 还原Obfuscate混淆的id
+func (o *Obfuscator) Deobfuscate(id int64) int64 {
+	v := uint64(id & math.MaxInt64)
+	for {
+		v = o.decrypt(v)
+		if v <= math.MaxInt64 {
+			break
+		}
+	}
+	return int64(v) | id&math.MinInt64
+}
+
+// encrypt 64位Feistel网络
+func (o *Obfuscator) encrypt(v uint64) uint64 {
+	l, r := uint32(v>>32), uint32(v)
+	for _, k := range o.keys {
+		l, r = r, l^feistelRound(r, k)
+	}
+	return uint64(l)<<32 | uint64(r)
+}
+
+// decrypt encrypt的逆运算
+func (o *Obfuscator) decrypt(v uint64) uint64 {
+	l, r := uint32(v>>32), uint32(v)
+	for i := len(o.keys) - 1; i >= 0; i-- {
+		l, r = r^feistelRound(l, o.keys[i]), l
+	}
+	return uint64(l)<<32 | uint64(r)
+}
+
+// feistelRound 轮函数(splitmix64的混合步骤)
+func feistelRound(r uint32, k uint64) uint32 {
+	z := uint64(r) ^ k
+	z = (z ^ z>>30) * 0xBF58476D1CE4E5B9
+	z = (z ^ z>>27) * 0x94D049BB133111EB
+	return uint32((z ^ z>>31) >> 32)
+}
@@ -0,0 +1,53 @@
+package generator
+
+import (
+	"math"
+	"testing"
+)
+
+// TestObfuscator 混淆可还原，且打乱相邻id的顺序
+func TestObfuscator(t *testing.T) {
+	o, err := NewObfuscator([]byte("secret"))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	idGen, _ := NewGenerator(1)
+	ids, _ := idGen.GenerateN(1e4)
+
+	seen := make(map[int64]bool, len(ids))
+	var ascending int
+	for i, id := range ids {
+		obf := o.Obfuscate(id)
+		if obf < 0 {
+			t.Fatalf("【失败】-非负id混淆后为负数-%d-%d", id, obf)
+		}
+		if seen[obf] {
+			t.Fatalf("【失败】-混淆后重复-%d", obf)
+		}
+		seen[obf] = true
+		if got := o.Deobfuscate(obf); got != id {
+			t.Fatalf("【失败】-还原-got:%d-want:%d", got, id)
+		}
+		if i > 0 && obf > o.Obfuscate(ids[i-1]) {
+			ascending++
+		}
+	}
+	if ascending < len(ids)/3 || ascending > len(ids)*2/3 {
+		t.Fatalf("【失败】-混淆后的顺序仍与原id相关-递增次数:%d", ascending)
+	}
+
+	for _, id := range []int64{0, 1, math.MaxInt64, -1, math.MinInt64} {
+		obf := o.Obfuscate(id)
+		if (obf < 0) != (id < 0) || o.Deobfuscate(obf) != id {
+			t.Fatalf("【失败】-边界值-%d-%d", id, obf)
+		}
+	}
+
+	other, _ := NewObfuscator([]byte("other"))
+	if other.Obfuscate(ids[0]) == o.Obfuscate(ids[0]) {
+		t.Fatalf("【失败】-不同密钥的混淆结果相同")
+	}
+	if _, err := NewObfuscator(nil); err == nil {
+		t.Fatalf("【失败】-空密钥应创建失败")
+	}
+}
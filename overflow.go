@@ -0,0 +1,150 @@
+package generator
+
+import (
+	"time"
+)
+
+// ErrTimeOverflow 当前时间偏移量超过时间位数所能表示的范围
+var ErrTimeOverflow = newError(CodeTimeOverflow)
+
+// OverflowAction 时间部分用尽时的处理方式
+type OverflowAction int
+
+const (
+	OverflowError       OverflowAction = iota //返回ErrTimeOverflow(默认)
+	OverflowSwitchEpoch                       //切换到基准时间轮换(EpochRotation)的新基准时间继续生成
+	OverflowCallback                          //调用OnOverflow，由回调返回id
+)
+
+// OverflowPolicy 时间部分用尽时的处理策略
+type OverflowPolicy struct {
+	Action     OverflowAction
+	Rotation   *EpochRotation                     //OverflowSwitchEpoch：生成器须使用Rotation的旧配置
+	OnOverflow func(now time.Time) (int64, error) //OverflowCallback：在生成器锁内调用，不能再调用当前生成器
+	WarnBefore time.Duration                      //剩余可用时长小于该值时调用OnWarning，0表示不预警
+	OnWarning  func(remaining time.Duration)      //预警回调，只调用一次，在独立goroutine中执行
+}
+
+// overflowState 时间部分用尽处理状态
+type overflowState struct {
+	policy  OverflowPolicy
+	warned  bool     //是否已预警
+	presets *presets //OverflowSwitchEpoch设置前的presets，替换策略或恢复默认时还原
+}
+
+// SetOverflowPolicy 设置时间部分用尽时的处理策略，nil表示恢复默认(返回错误)
+//   - OverflowSwitchEpoch：生成器的时间部分只使用低TimeBit-1位，用尽后切换到Rotation的新基准时间并置位选择位，
+//     切换后的id总是大于切换前的id；切换后应使用Rotation.Decompose/Time解析id
+func (idGen *IDGenerator) SetOverflowPolicy(policy *OverflowPolicy) error {
+	idGen.mutex.Lock()
+	defer idGen.mutex.Unlock()
+
+	if policy != nil {
+		if err := idGen.checkOverflowPolicy(policy); err != nil {
+			return err
+		}
+	}
+	if state := idGen.overflow; state != nil && state.presets != nil {
+		idGen.settings.presets = state.presets
+	}
+	if policy == nil {
+		idGen.overflow = nil
+		return nil
+	}
+	state := &overflowState{policy: *policy}
+	if policy.Action == OverflowSwitchEpoch {
+		state.presets = idGen.settings.presets
+		idGen.settings.presets = policy.Rotation.oldPresets
+	}
+	idGen.overflow = state
+	return nil
+}
+
+// checkOverflowPolicy 校验处理策略，不修改生成器状态，调用方须持有锁
+func (idGen *IDGenerator) checkOverflowPolicy(policy *OverflowPolicy) error {
+	switch policy.Action {
+	case OverflowError:
+	case OverflowSwitchEpoch:
+		r := policy.Rotation
+		if r == nil {
+			return errorf(CodeOverflowRotation)
+		}
+		if !sameLayout(*idGen.settings, r.Old) || idGen.prefix != 0 {
+			return errorf(CodeOverflowOldSettings)
+		}
+		for _, progress := range idGen.timelineProgress {
+			if progress > r.oldPresets.maxTime {
+				return errorf(CodeOverflowTooLate)
+			}
+		}
+	case OverflowCallback:
+		if policy.OnOverflow == nil {
+			return errorf(CodeOverflowCallback)
+		}
+	default:
+		return errorf(CodeUnknownOverflowAction)
+	}
+	if policy.WarnBefore < 0 {
+		return errorf(CodeNegative, "WarnBefore")
+	}
+	return nil
+}
+
+// ExhaustionTime 时间部分用尽的时间，此后生成返回ErrTimeOverflow或按SetOverflowPolicy设置的策略处理
+//   - 设置OverflowSwitchEpoch时为切换到新基准时间的时间，切换后为新基准时间下的用尽时间
+//   - 剩余时长不足时的预警见Hooks.OnTimeNearOverflow、OverflowPolicy.OnWarning
+func (idGen *IDGenerator) ExhaustionTime() time.Time {
+	idGen.mutex.Lock()
+	defer idGen.mutex.Unlock()
+	return time.Unix(0, idGen.exhaustionNano())
+}
+
+// exhaustionNano 时间部分用尽的时间(unix nano)，调用方须持有锁
+func (idGen *IDGenerator) exhaustionNano() int64 {
+	return idGen.toUnixNano(idGen.settings.presets.maxTime + 1)
+}
+
+// checkOverflowWarning 剩余可用时长不足时预警，调用方须持有锁
+func (idGen *IDGenerator) checkOverflowWarning(now time.Time) {
+	state := idGen.overflow
+	if state == nil || state.warned || state.policy.WarnBefore <= 0 || state.policy.OnWarning == nil {
+		return
+	}
+	remaining := time.Duration(idGen.exhaustionNano() - now.UnixNano())
+	if remaining < state.policy.WarnBefore {
+		state.warned = true
+		go state.policy.OnWarning(remaining)
+	}
+}
+
+// handleOverflow 时间部分用尽，按策略处理，调用方须持有锁
+func (idGen *IDGenerator) handleOverflow(now time.Time, caller int) (int64, error) {
+	state := idGen.overflow
+	if state == nil {
+		return 0, ErrTimeOverflow
+	}
+	switch state.policy.Action {
+	case OverflowSwitchEpoch:
+		r := state.policy.Rotation
+		settings := r.New
+		settings.presets = r.newPresets
+		idGen.settings = &settings
+		idGen.prefix = r.selector
+		for i := range idGen.timelineProgress {
+			idGen.timelineProgress[i] = 0
+			idGen.burnedTimelines[i] = false
+		}
+		idGen.burnedCount = 0
+		idGen.seq = 0
+		idGen.pauseDetector.last = time.Time{}
+		idGen.nearOverflowFired = false
+		idGen.overflow = &overflowState{policy: OverflowPolicy{
+			WarnBefore: state.policy.WarnBefore,
+			OnWarning:  state.policy.OnWarning,
+		}}
+		return idGen.generateCallerLocked(caller)
+	case OverflowCallback:
+		return state.policy.OnOverflow(now)
+	}
+	return 0, ErrTimeOverflow
+}
@@ -0,0 +1,168 @@
+package generator
+
+import (
+	"testing"
+	"time"
+)
+
+// TestOverflowPolicy 时间部分用尽处理策略
+func TestOverflowPolicy(t *testing.T) {
+	const timeBit = 30
+	now := time.Now()
+	half := time.Duration(1<<(timeBit-1)) * time.Millisecond //轮换后旧基准时间可用时长
+	oldSettings := Settings{TimeBit: timeBit, MachineIDBit: 9, TimelineBit: 1, SeqBit: 23, Epoch: now.Add(-half + 10*time.Minute).UnixNano()}
+	newSettings := oldSettings
+	newSettings.Epoch = now.Add(-time.Minute).UnixNano()
+	rotation, err := NewEpochRotation(oldSettings, newSettings, now, time.Minute)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	testCases := []struct {
+		name    string
+		policy  *OverflowPolicy
+		wantErr error
+		check   func(id int64) bool
+	}{
+		{name: "默认返回错误", policy: nil, wantErr: ErrTimeOverflow},
+		{
+			name:   "切换到新基准时间",
+			policy: &OverflowPolicy{Action: OverflowSwitchEpoch, Rotation: rotation},
+			check: func(id int64) bool {
+				return rotation.IsNewEpoch(id) && rotation.Time(id).Sub(time.Now()) < time.Second
+			},
+		},
+		{
+			name: "回调",
+			policy: &OverflowPolicy{Action: OverflowCallback, OnOverflow: func(now time.Time) (int64, error) {
+				return 42, nil
+			}},
+			check: func(id int64) bool { return id == 42 },
+		},
+	}
+
+	for _, tc := range testCases {
+		idGen, err := rotation.OldGenerator(1)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		warnings := make(chan time.Duration, 1)
+		if tc.policy != nil {
+			tc.policy.WarnBefore = time.Hour
+			tc.policy.OnWarning = func(remaining time.Duration) { warnings <- remaining }
+			if err := idGen.SetOverflowPolicy(tc.policy); err != nil {
+				t.Fatal(err.Error())
+			}
+		}
+
+		before, err := idGen.Generate()
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if tc.policy != nil {
+			select {
+			case remaining := <-warnings:
+				if remaining > 10*time.Minute {
+					t.Fatalf("【失败】-%s-预警-got:%v-want:<=%v", tc.name, remaining, 10*time.Minute)
+				}
+			case <-time.After(time.Second):
+				t.Fatalf("【失败】-%s-预警-got:%v-want:%v", tc.name, "未预警", "预警")
+			}
+		}
+
+		idGen.settings.Epoch -= int64(20 * time.Minute) //模拟时间流逝，时间部分用尽
+		id, err := idGen.Generate()
+		if err != tc.wantErr {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, err, tc.wantErr)
+		}
+		if tc.check != nil && (!tc.check(int64(id)) || (tc.policy.Action == OverflowSwitchEpoch && id <= before)) {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, id, "符合策略的id")
+		}
+	}
+
+	//生成器与Rotation配置不一致
+	idGen, _ := NewGenerator(1)
+	if err := idGen.SetOverflowPolicy(&OverflowPolicy{Action: OverflowSwitchEpoch, Rotation: rotation}); err == nil {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "配置不一致", err, "error")
+	}
+}
+
+// TestExhaustionTime 时间部分用尽的时间
+func TestExhaustionTime(t *testing.T) {
+	now := time.Now()
+	const timeBit = 30
+	oldSettings := Settings{TimeBit: timeBit, MachineIDBit: 9, TimelineBit: 1, SeqBit: 23, Epoch: now.Add(-time.Hour).UnixNano()}
+	newSettings := oldSettings
+	newSettings.Epoch = now.Add(-time.Minute).UnixNano()
+	rotation, err := NewEpochRotation(oldSettings, newSettings, now, time.Minute)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	testCases := []struct {
+		name     string
+		settings Settings
+		policy   *OverflowPolicy
+		want     time.Time
+	}{
+		{name: "默认配置", settings: *DefaultSettings, want: DefaultSettings.Capacity().Until},
+		{name: "秒级配置", settings: *SecondSettings, want: SecondSettings.Capacity().Until},
+		{
+			name:     "基准时间轮换",
+			settings: oldSettings,
+			policy:   &OverflowPolicy{Action: OverflowSwitchEpoch, Rotation: rotation},
+			want:     time.Unix(0, oldSettings.Epoch).Add(time.Duration(1<<(timeBit-1)) * time.Millisecond),
+		},
+	}
+	for _, tc := range testCases {
+		idGen, err := NewGeneratorWithSettings(0, tc.settings)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if err := idGen.SetOverflowPolicy(tc.policy); err != nil {
+			t.Fatal(err.Error())
+		}
+		if got := idGen.ExhaustionTime(); !got.Equal(tc.want) {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, got, tc.want)
+		}
+	}
+}
+
+// TestOverflowPolicyRestore 策略设置失败时不改变时间范围，替换策略或恢复默认时还原时间范围
+func TestOverflowPolicyRestore(t *testing.T) {
+	now := time.Now()
+	oldSettings := Settings{TimeBit: 30, MachineIDBit: 9, TimelineBit: 1, SeqBit: 23, Epoch: now.Add(-time.Hour).UnixNano()}
+	newSettings := oldSettings
+	newSettings.Epoch = now.Add(-time.Minute).UnixNano()
+	rotation, err := NewEpochRotation(oldSettings, newSettings, now, time.Minute)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	idGen, _ := NewGeneratorWithSettings(0, oldSettings)
+	full := idGen.ExhaustionTime()
+	half := time.Unix(0, oldSettings.Epoch).Add(time.Duration(1<<29) * time.Millisecond)
+
+	testCases := []struct {
+		name    string
+		policy  *OverflowPolicy
+		wantErr bool
+		want    time.Time
+	}{
+		{name: "预警时长为负数", policy: &OverflowPolicy{Action: OverflowSwitchEpoch, Rotation: rotation, WarnBefore: -1}, wantErr: true, want: full},
+		{name: "切换到新基准时间", policy: &OverflowPolicy{Action: OverflowSwitchEpoch, Rotation: rotation}, want: half},
+		{name: "重复设置", policy: &OverflowPolicy{Action: OverflowSwitchEpoch, Rotation: rotation}, want: half},
+		{name: "设置失败时保持原策略", policy: &OverflowPolicy{Action: OverflowCallback}, wantErr: true, want: half},
+		{name: "替换为其他策略", policy: &OverflowPolicy{Action: OverflowError}, want: full},
+		{name: "再次切换", policy: &OverflowPolicy{Action: OverflowSwitchEpoch, Rotation: rotation}, want: half},
+		{name: "恢复默认", policy: nil, want: full},
+	}
+	for _, tc := range testCases {
+		err := idGen.SetOverflowPolicy(tc.policy)
+		if (err != nil) != tc.wantErr {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, err, tc.wantErr)
+		}
+		if got := idGen.ExhaustionTime(); !got.Equal(tc.want) {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package generator
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"hash"
+	"math"
+)
+
+const maxPaddingRate = 0.9
+
+// padding 序号填充：按密钥伪随机地跳过部分序号
+type padding struct {
+	rate      float64
+	threshold uint64 //PRF输出小于该值的序号被跳过
+	mac       hash.Hash
+	in        [32]byte
+	sum       []byte
+}
+
+// SetPadding 设置序号填充，rate为0表示关闭
+//   - 生成时按rate的比例伪随机地跳过部分序号，外部无法根据相邻公开id的序号差推算出准确的订单量
+//   - 跳过哪些序号由key和(机器ID,时间线,时间单位,序号)决定，持有key可通过PaddingIndex还原准确的发放数
+//   - 每个时间单位的有效容量降为(1-rate)，可通过EffectiveSeqCapacity查看；rate不能超过0.9
+func (idGen *IDGenerator) SetPadding(key []byte, rate float64) error {
+	if rate < 0 || rate > maxPaddingRate || math.IsNaN(rate) {
+		return errorf(CodePaddingRatio)
+	}
+	if rate > 0 && len(key) == 0 {
+		return errorf(CodePaddingKey)
+	}
+
+	idGen.mutex.Lock()
+	defer idGen.mutex.Unlock()
+	if rate > 0 && idGen.seqRandom != nil {
+		return errorf(CodePaddingRandom)
+	}
+	if rate > 0 && idGen.reservation != nil {
+		return errorf(CodePaddingReservation)
+	}
+	if rate > 0 && idGen.shardSeqMax > 0 {
+		return errPoolShard
+	}
+	if rate == 0 {
+		idGen.padding = nil
+		return nil
+	}
+	idGen.padding = &padding{
+		rate:      rate,
+		threshold: uint64(rate * math.Exp2(64)),
+		mac:       hmac.New(sha256.New, key),
+	}
+	return nil
+}
+
+// skip 序号是否被跳过
+func (p *padding) skip(machineID, timeline, time, seq int64) bool {
+	binary.BigEndian.PutUint64(p.in[0:], uint64(machineID))
+	binary.BigEndian.PutUint64(p.in[8:], uint64(timeline))
+	binary.BigEndian.PutUint64(p.in[16:], uint64(time))
+	binary.BigEndian.PutUint64(p.in[24:], uint64(seq))
+	p.mac.Reset()
+	p.mac.Write(p.in[:])
+	p.sum = p.mac.Sum(p.sum[:0])
+	return binary.BigEndian.Uint64(p.sum) < p.threshold
+}
+
+// skipPadding 跳过当前序号起被填充的序号，返回跳过后的时间(序号用完时等待下一个时间单位)，调用方须持有锁
+func (idGen *IDGenerator) skipPadding(curTime int64) (int64, error) {
+	var skipped int64
+	defer func() {
+		if skipped > 0 && idGen.metrics != nil {
+			idGen.metrics.Counter(MetricSeqSkipped, skipped)
+		}
+	}()
+	for idGen.padding.skip(idGen.machineID, idGen.curTimeline, curTime, idGen.seq) {
+		skipped++
+		if idGen.nextSeqExhausted(&idGen.seq, idGen.settings.presets.maxSeq) {
+			var err error
+			if curTime, err = idGen.nextTime(curTime); err != nil {
+				idGen.seq = idGen.seqLimit(idGen.settings.presets.maxSeq)
+				return 0, err
+			}
+		}
+	}
+	return curTime, nil
+}
+
+// PaddingIndex id在其时间单位内的实际发放序号(不计被跳过的序号)，未开启填充时等于序号
+//   - 时间单位内最大id的PaddingIndex+1即该时间单位的准确发放数
+func (idGen *IDGenerator) PaddingIndex(id int64) int64 {
+	compose := idGen.Decompose(id)
+
+	idGen.mutex.Lock()
+	defer idGen.mutex.Unlock()
+	if idGen.padding == nil {
+		return compose.Seq
+	}
+	index := compose.Seq
+	for seq := int64(0); seq < compose.Seq; seq++ {
+		if idGen.padding.skip(compose.MachineID, compose.TimeLine, compose.Time, seq) {
+			index--
+		}
+	}
+	return index
+}
+
+// EffectiveSeqCapacity 每个时间单位预期可生成的id数
+func (idGen *IDGenerator) EffectiveSeqCapacity() float64 {
+	idGen.mutex.Lock()
+	defer idGen.mutex.Unlock()
+	capacity := float64(idGen.settings.presets.maxSeq + 1)
+	if idGen.padding != nil {
+		capacity *= 1 - idGen.padding.rate
+	}
+	if r := idGen.seqRandom; r != nil {
+		//起始序号平均为maxOffset/2，序号增量平均为(1+maxStep)/2
+		capacity = (capacity - float64(r.maxOffset)/2) / (float64(1+r.maxStep) / 2)
+	}
+	return capacity
+}
@@ -0,0 +1,64 @@
+package generator
+
+import (
+	"math"
+	"testing"
+)
+
+// TestPadding 序号填充
+func TestPadding(t *testing.T) {
+	idGen, _ := NewGenerator(5)
+	metrics := countingMetrics{}
+	idGen.SetMetrics(metrics)
+	if err := idGen.SetPadding([]byte("secret"), 0.3); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	const n = 20000
+	counts := make(map[int64]int64) //时间单位 -> 发放数
+	last := make(map[int64]int64)   //时间单位 -> 最大id
+	var prev ID
+	for i := 0; i < n; i++ {
+		id, err := idGen.Generate()
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if id <= prev {
+			t.Fatalf("【失败】-%s-got:%v-want:>%v", "趋势递增", id, prev)
+		}
+		prev = id
+		unit := idGen.Decompose(int64(id)).Time
+		counts[unit]++
+		last[unit] = int64(id)
+	}
+
+	skipRate := float64(metrics[MetricSeqSkipped]) / float64(metrics[MetricSeqSkipped]+n)
+	if math.Abs(skipRate-0.3) > 0.02 {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "跳过比例", skipRate, 0.3)
+	}
+	for unit, id := range last {
+		if got := idGen.PaddingIndex(id) + 1; got != counts[unit] {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", "还原发放数", got, counts[unit])
+		}
+	}
+	if got := idGen.EffectiveSeqCapacity(); math.Abs(got-4096*0.7) > 1e-6 {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "有效容量", got, 4096*0.7)
+	}
+
+	testCases := []struct {
+		name    string
+		key     []byte
+		rate    float64
+		wantErr bool
+	}{
+		{name: "关闭填充", key: nil, rate: 0, wantErr: false},
+		{name: "比例过大", key: []byte("secret"), rate: 0.95, wantErr: true},
+		{name: "比例为负", key: []byte("secret"), rate: -0.1, wantErr: true},
+		{name: "密钥为空", key: nil, rate: 0.1, wantErr: true},
+	}
+	for _, tc := range testCases {
+		if err := idGen.SetPadding(tc.key, tc.rate); (err != nil) != tc.wantErr {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, err, tc.wantErr)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package generator
+
+import (
+	"time"
+)
+
+// PartitionKey 按id中的时间计算分区号：unix零点起第几个granularity，如按天分区时为unix天数
+//   - 与基准时间无关，不同配置的业务按同一粒度得到的分区号一致
+//   - granularity不大于0时按生成器的时间单位分区
+func (idGen *IDGenerator) PartitionKey(id ID, granularity time.Duration) int64 {
+	presets := idGen.settings.presets
+	timePart := (int64(id) & presets.maskTime) >> presets.shiftTimeBit
+	return floorDiv(idGen.toUnixNano(timePart), idGen.granularity(granularity))
+}
+
+// PartitionBounds 分区对应的id范围[from, to)，可直接用于声明范围分区，如：
+//
+//	CREATE TABLE orders_19723 PARTITION OF orders FOR VALUES FROM (from) TO (to);
+//
+// granularity不大于0时按生成器的时间单位分区，与PartitionKey一致
+func (idGen *IDGenerator) PartitionBounds(key int64, granularity time.Duration) (from, to int64, err error) {
+	if !idGen.settings.timeOrdered() {
+		return 0, 0, errNotTimeOrdered
+	}
+	presets := idGen.settings.presets
+
+	// 分区起始时间之后(含)的第一个时间单位
+	bound := func(key int64) int64 {
+		offset := key*idGen.granularity(granularity) - idGen.settings.Epoch
+		timePart := -floorDiv(-offset, idGen.settings.unit())
+		if timePart < 0 {
+			timePart = 0
+		}
+		if timePart > presets.maxTime {
+			return presets.version | presets.maxTime<<presets.shiftTimeBit | (1<<presets.shiftTimeBit - 1)
+		}
+		return presets.version | timePart<<presets.shiftTimeBit
+	}
+	return bound(key), bound(key + 1), nil
+}
+
+// granularity 分区粒度(ns)，不大于0时为时间单位
+func (idGen *IDGenerator) granularity(granularity time.Duration) int64 {
+	if granularity <= 0 {
+		return idGen.settings.unit()
+	}
+	return int64(granularity)
+}
+
+// floorDiv 向下取整的除法
+func floorDiv(a, b int64) int64 {
+	q := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		q--
+	}
+	return q
+}
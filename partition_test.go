@@ -0,0 +1,56 @@
+package generator
+
+import (
+	"testing"
+	"time"
+)
+
+// TestPartitionKey 分区号及分区id范围
+func TestPartitionKey(t *testing.T) {
+	idGen, _ := NewGenerator(0)
+	day := 24 * time.Hour
+
+	id, _ := idGen.Generate()
+	key := idGen.PartitionKey(id, day)
+	if want := time.Now().Unix() / 86400; key != want && key != want-1 {
+		t.Fatalf("【失败】-分区号-got:%d-want:%d", key, want)
+	}
+
+	from, to, err := idGen.PartitionBounds(key, day)
+	if err != nil || id.Int64() < from || id.Int64() >= to {
+		t.Fatalf("【失败】-id不在分区范围内-id:%d-from:%d-to:%d-err:%v", id, from, to, err)
+	}
+
+	testCases := []struct {
+		name        string
+		granularity time.Duration
+	}{
+		{name: "按天分区", granularity: day},
+		{name: "按小时分区", granularity: time.Hour},
+		{name: "非时间单位整数倍的粒度", granularity: 1500 * time.Microsecond},
+		{name: "粒度为0时按时间单位分区", granularity: 0},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			key := idGen.PartitionKey(id, tc.granularity)
+			from, to, _ := idGen.PartitionBounds(key, tc.granularity)
+			// 分区边界两侧的id分属相邻分区
+			if k := idGen.PartitionKey(ID(from), tc.granularity); k != key {
+				t.Fatalf("【失败】-%s-分区起始id的分区号-got:%d-want:%d", tc.name, k, key)
+			}
+			if k := idGen.PartitionKey(ID(from-1), tc.granularity); k >= key {
+				t.Fatalf("【失败】-%s-前一分区的最大id-got:%d-want:<%d", tc.name, k, key)
+			}
+			if k := idGen.PartitionKey(ID(to), tc.granularity); k <= key {
+				t.Fatalf("【失败】-%s-后一分区的最小id-got:%d-want:>%d", tc.name, k, key)
+			}
+			if k := idGen.PartitionKey(ID(to-1), tc.granularity); k != key {
+				t.Fatalf("【失败】-%s-分区最大id的分区号-got:%d-want:%d", tc.name, k, key)
+			}
+		})
+	}
+
+	if got, want := idGen.PartitionKey(id, 0), idGen.Decompose(id.Int64()).Time+DefaultEpoch/int64(time.Millisecond); got != want {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "粒度为0", got, want)
+	}
+}
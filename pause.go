@@ -0,0 +1,45 @@
+package generator
+
+import "time"
+
+// pauseDetector 时钟跳变检测
+//   - 虚拟机暂停、热迁移或系统休眠期间单调时钟与墙上时钟的走时会出现偏差，恢复后墙上时钟可能被NTP大幅校正
+//   - 通过比较相邻两次生成之间墙上时钟与单调时钟的走时差异来发现此类事件
+type pauseDetector struct {
+	threshold time.Duration //偏差阈值，0表示不检测
+	last      time.Time     //上一次生成的时间(含单调时钟读数)
+	lastWall  int64         //上一次生成时墙上时钟相对基准时间的偏移(ns)
+}
+
+// SetPauseDetection 设置时钟跳变检测阈值，0表示关闭
+//   - 相邻两次生成之间墙上时钟与单调时钟的走时相差超过threshold时，视为发生了虚拟机暂停、热迁移等事件
+//   - 检测到跳变后，若墙上时钟落后于当前时间线进度，立即切换时间线而不是等待时钟追回
+func (idGen *IDGenerator) SetPauseDetection(threshold time.Duration) {
+	idGen.mutex.Lock()
+	defer idGen.mutex.Unlock()
+	idGen.pauseDetector = pauseDetector{threshold: threshold}
+}
+
+// detectClockJump 检测自上一次生成以来是否发生时钟跳变
+func (idGen *IDGenerator) detectClockJump(now time.Time) bool {
+	detector := &idGen.pauseDetector
+	if detector.threshold <= 0 {
+		return false
+	}
+	last, lastWall := detector.last, detector.lastWall
+	wallNow := now.UnixNano() - idGen.settings.Epoch
+	detector.last, detector.lastWall = now, wallNow
+	if last.IsZero() {
+		return false
+	}
+
+	monotonic := now.Sub(last)                //单调时钟走时
+	wall := time.Duration(wallNow - lastWall) //墙上时钟走时
+	if drift := wall - monotonic; drift > detector.threshold || drift < -detector.threshold {
+		if idGen.metrics != nil {
+			idGen.metrics.Counter(MetricClockJump, 1)
+		}
+		return true
+	}
+	return false
+}
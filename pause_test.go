@@ -0,0 +1,49 @@
+package generator
+
+import (
+	"testing"
+	"time"
+)
+
+// countingMetrics 记录counter的指标实现
+type countingMetrics map[string]int64
+
+func (m countingMetrics) Counter(name string, delta int64)     { m[name] += delta }
+func (m countingMetrics) Gauge(name string, value float64)     {}
+func (m countingMetrics) Histogram(name string, value float64) {}
+
+// TestPauseDetection 时钟跳变检测
+func TestPauseDetection(t *testing.T) {
+	testCases := []struct {
+		name      string
+		threshold time.Duration
+		jump      time.Duration //墙上时钟跳变(通过调整基准时间模拟)
+		want      int64         //检测到的跳变次数
+	}{
+		{name: "未开启检测", threshold: 0, jump: -50 * time.Millisecond, want: 0},
+		{name: "墙上时钟回退", threshold: 10 * time.Millisecond, jump: -50 * time.Millisecond, want: 1},
+		{name: "墙上时钟前跳", threshold: 10 * time.Millisecond, jump: 50 * time.Millisecond, want: 1},
+		{name: "偏差小于阈值", threshold: 100 * time.Millisecond, jump: -50 * time.Millisecond, want: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			idGen, _ := NewGenerator(0)
+			metrics := countingMetrics{}
+			idGen.SetMetrics(metrics)
+			idGen.SetPauseDetection(tc.threshold)
+
+			idGen.Generate()
+			idGen.settings.Epoch -= int64(tc.jump)
+			if _, err := idGen.Generate(); err != nil {
+				t.Fatal(err.Error())
+			}
+			if got := metrics[MetricClockJump]; got != tc.want {
+				t.Fatalf("【失败】-%s-got:%d-want:%d", tc.name, got, tc.want)
+			}
+			if tc.jump < 0 && metrics[MetricTimelineSwitch] != 1 {
+				t.Fatalf("【失败】-%s-墙上时钟落后于时间线进度时应切换时间线", tc.name)
+			}
+		})
+	}
+}
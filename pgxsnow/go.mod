@@ -0,0 +1,10 @@
+module github.com/jayecc/mtl-snowflake/pgxsnow
+
+go 1.25.0
+
+require (
+	github.com/jackc/pgx/v5 v5.11.0
+	github.com/jayecc/mtl-snowflake v0.0.0
+)
+
+replace github.com/jayecc/mtl-snowflake => ../
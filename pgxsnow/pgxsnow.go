@@ -0,0 +1,139 @@
+// Package pgxsnow generator.ID的pgx v5编解码
+//   - BIGINT(int8)列按整数读写，TEXT、VARCHAR列按十进制字符串读写，不经过反射或database/sql的Valuer/Scanner
+//   - COPY(pgx.CopyFrom)按列类型编码，[]any中可直接使用generator.ID
+//   - 参数类型未知时(如QueryExecModeSimpleProtocol)generator.ID按int8编码
+//
+// 如：
+//
+//	config.AfterConnect = func(ctx context.Context, conn *pgx.Conn) error {
+//		pgxsnow.Register(conn.TypeMap())
+//		return nil
+//	}
+package pgxsnow
+
+import (
+	"github.com/jackc/pgx/v5/pgtype"
+
+	generator "github.com/jayecc/mtl-snowflake"
+)
+
+// Register 在m中注册generator.ID的编解码：替换int8、text、varchar的Codec，其他类型的值仍由pgx原有的Codec处理
+func Register(m *pgtype.Map) {
+	m.RegisterType(&pgtype.Type{Name: "int8", OID: pgtype.Int8OID, Codec: Int8Codec{}})
+	m.RegisterType(&pgtype.Type{Name: "text", OID: pgtype.TextOID, Codec: TextCodec{}})
+	m.RegisterType(&pgtype.Type{Name: "varchar", OID: pgtype.VarcharOID, Codec: TextCodec{}})
+	m.RegisterDefaultPgType(generator.ID(0), "int8")
+}
+
+// errNull NULL不能读取到generator.ID，可为NULL的列使用*generator.ID
+var errNull = generator.NewError(generator.CodeNullID)
+
+// Int8Codec 支持generator.ID的int8编解码
+type Int8Codec struct {
+	pgtype.Int8Codec
+}
+
+// PlanEncode generator.ID按int64编码
+func (c Int8Codec) PlanEncode(m *pgtype.Map, oid uint32, format int16, value any) pgtype.EncodePlan {
+	if _, ok := value.(generator.ID); ok {
+		if plan := c.Int8Codec.PlanEncode(m, oid, format, int64(0)); plan != nil {
+			return encodeInt64Plan{next: plan}
+		}
+		return nil
+	}
+	return c.Int8Codec.PlanEncode(m, oid, format, value)
+}
+
+// PlanScan 读取为int64后转换为generator.ID
+func (c Int8Codec) PlanScan(m *pgtype.Map, oid uint32, format int16, target any) pgtype.ScanPlan {
+	if _, ok := target.(*generator.ID); ok {
+		if plan := c.Int8Codec.PlanScan(m, oid, format, new(int64)); plan != nil {
+			return scanInt64Plan{next: plan}
+		}
+		return nil
+	}
+	return c.Int8Codec.PlanScan(m, oid, format, target)
+}
+
+// TextCodec 支持generator.ID的text、varchar编解码，id按十进制字符串存储
+type TextCodec struct {
+	pgtype.TextCodec
+}
+
+// PlanEncode generator.ID按十进制字符串编码
+func (c TextCodec) PlanEncode(m *pgtype.Map, oid uint32, format int16, value any) pgtype.EncodePlan {
+	if _, ok := value.(generator.ID); ok {
+		if plan := c.TextCodec.PlanEncode(m, oid, format, ""); plan != nil {
+			return encodeStringPlan{next: plan}
+		}
+		return nil
+	}
+	return c.TextCodec.PlanEncode(m, oid, format, value)
+}
+
+// PlanScan 读取为字符串后按十进制解析
+func (c TextCodec) PlanScan(m *pgtype.Map, oid uint32, format int16, target any) pgtype.ScanPlan {
+	if _, ok := target.(*generator.ID); ok {
+		if plan := c.TextCodec.PlanScan(m, oid, format, new(string)); plan != nil {
+			return scanStringPlan{next: plan}
+		}
+		return nil
+	}
+	return c.TextCodec.PlanScan(m, oid, format, target)
+}
+
+// encodeInt64Plan 将generator.ID转换为int64后编码
+type encodeInt64Plan struct {
+	next pgtype.EncodePlan
+}
+
+func (plan encodeInt64Plan) Encode(value any, buf []byte) ([]byte, error) {
+	return plan.next.Encode(int64(value.(generator.ID)), buf)
+}
+
+// scanInt64Plan 读取为int64后转换为generator.ID
+type scanInt64Plan struct {
+	next pgtype.ScanPlan
+}
+
+func (plan scanInt64Plan) Scan(src []byte, target any) error {
+	if src == nil {
+		return errNull
+	}
+	var v int64
+	if err := plan.next.Scan(src, &v); err != nil {
+		return err
+	}
+	*target.(*generator.ID) = generator.ID(v)
+	return nil
+}
+
+// encodeStringPlan 将generator.ID转换为十进制字符串后编码
+type encodeStringPlan struct {
+	next pgtype.EncodePlan
+}
+
+func (plan encodeStringPlan) Encode(value any, buf []byte) ([]byte, error) {
+	return plan.next.Encode(value.(generator.ID).String(), buf)
+}
+
+// scanStringPlan 读取为字符串后按十进制解析为generator.ID
+type scanStringPlan struct {
+	next pgtype.ScanPlan
+}
+
+func (plan scanStringPlan) Scan(src []byte, target any) error {
+	if src == nil {
+		return errNull
+	}
+	var s string
+	if err := plan.next.Scan(src, &s); err != nil {
+		return err
+	}
+	id, err := generator.ParseID(s)
+	if err != nil {
+		return err
+	}
+	*target.(*generator.ID) = id
+	return nil
+}
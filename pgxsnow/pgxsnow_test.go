@@ -0,0 +1,73 @@
+package pgxsnow
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	generator "github.com/jayecc/mtl-snowflake"
+)
+
+// TestRegister 注册后generator.ID按int8、text读写，与int64、string的编码一致
+func TestRegister(t *testing.T) {
+	m := pgtype.NewMap()
+	Register(m)
+	id := generator.ID(1234567890123456789)
+
+	testCases := []struct {
+		name   string
+		oid    uint32
+		format int16
+		same   any //与generator.ID编码结果相同的值
+	}{
+		{name: "int8二进制", oid: pgtype.Int8OID, format: pgtype.BinaryFormatCode, same: int64(id)},
+		{name: "int8文本", oid: pgtype.Int8OID, format: pgtype.TextFormatCode, same: int64(id)},
+		{name: "text二进制", oid: pgtype.TextOID, format: pgtype.BinaryFormatCode, same: id.String()},
+		{name: "text文本", oid: pgtype.TextOID, format: pgtype.TextFormatCode, same: id.String()},
+		{name: "varchar", oid: pgtype.VarcharOID, format: pgtype.TextFormatCode, same: id.String()},
+	}
+	for _, tc := range testCases {
+		got, err := m.Encode(tc.oid, tc.format, id, nil)
+		if err != nil {
+			t.Fatalf("【失败】-%s-编码-got:%v-want:%v", tc.name, err, nil)
+		}
+		want, _ := m.Encode(tc.oid, tc.format, tc.same, nil)
+		if !bytes.Equal(got, want) {
+			t.Fatalf("【失败】-%s-编码-got:%v-want:%v", tc.name, got, want)
+		}
+
+		var scanned generator.ID
+		if err := m.Scan(tc.oid, tc.format, got, &scanned); err != nil || scanned != id {
+			t.Fatalf("【失败】-%s-读取-got:%v(%v)-want:%v", tc.name, scanned, err, id)
+		}
+		var nullable *generator.ID
+		if err := m.Scan(tc.oid, tc.format, got, &nullable); err != nil || nullable == nil || *nullable != id {
+			t.Fatalf("【失败】-%s-读取指针-got:%v(%v)-want:%v", tc.name, nullable, err, id)
+		}
+		if err := m.Scan(tc.oid, tc.format, nil, &nullable); err != nil || nullable != nil {
+			t.Fatalf("【失败】-%s-读取NULL到指针-got:%v(%v)-want:%v", tc.name, nullable, err, nil)
+		}
+		if err := m.Scan(tc.oid, tc.format, nil, &scanned); err == nil {
+			t.Fatalf("【失败】-%s-读取NULL-got:%v-want:%v", tc.name, err, "error")
+		}
+	}
+
+	//text列中不是id的值
+	var scanned generator.ID
+	if err := m.Scan(pgtype.TextOID, pgtype.TextFormatCode, []byte("abc"), &scanned); err == nil {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "非法字符串", err, "error")
+	}
+
+	//参数类型未知时按int8编码
+	if dt, ok := m.TypeForValue(id); !ok || dt.OID != pgtype.Int8OID {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "缺省类型", dt, "int8")
+	}
+
+	//其他类型不受影响
+	var n int64
+	buf, _ := m.Encode(pgtype.Int8OID, pgtype.BinaryFormatCode, int64(42), nil)
+	if err := m.Scan(pgtype.Int8OID, pgtype.BinaryFormatCode, buf, &n); err != nil || n != 42 {
+		t.Fatalf("【失败】-%s-got:%v(%v)-want:%v", "int64", n, err, 42)
+	}
+}
@@ -0,0 +1,93 @@
+package generator
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// errPoolShard 生成器池的分片不支持该操作
+var errPoolShard = newError(CodePoolShard)
+
+// GeneratorPool 分片生成器池：序号的高位为分片编号，各分片独立加锁，并发的Generate分散到不同分片，不会在同一把锁上排队
+//   - 各分片使用相同的机器ID，序号空间互不重叠，生成的id全局唯一；按原配置解析时序号包含分片编号
+//   - 每个分片在一个时间单位内最多生成2^(SeqBit-分片位数)个id，池整体的容量与单个生成器相同
+//   - 同一时间单位内不同分片的id不按生成顺序排列，只满足趋势递增；各分片独立处理时钟回退
+type GeneratorPool struct {
+	shards []*IDGenerator
+	cache  sync.Pool //按P缓存分片，同一P上的调用倾向于使用同一分片
+	next   uint32    //缓存为空时轮询选择分片
+}
+
+// NewGeneratorPool 创建分片生成器池，shards<=0时按runtime.NumCPU()
+//   - 分片数向上取整为2的幂，且须小于2^SeqBit(至少保留1位序号)；按CPU数确定时超出的部分截断
+func NewGeneratorPool(machineID int64, settings Settings, shards int) (*GeneratorPool, error) {
+	if settings.StatePersister != nil {
+		return nil, errorf(CodePoolPersister)
+	}
+	maxShardBits := uint64(0)
+	if settings.SeqBit > 0 {
+		maxShardBits = settings.SeqBit - 1
+	}
+	if shards <= 0 {
+		shards = runtime.NumCPU()
+		if shards > 1<<maxShardBits {
+			shards = 1 << maxShardBits
+		}
+	}
+	var shardBits uint64
+	for 1<<shardBits < shards {
+		shardBits++
+	}
+	if shardBits > maxShardBits {
+		return nil, errorf(CodePoolShards, 1<<maxShardBits)
+	}
+
+	pool := &GeneratorPool{shards: make([]*IDGenerator, 1<<shardBits)}
+	for i := range pool.shards {
+		idGen, err := NewGeneratorWithSettings(machineID, settings)
+		if err != nil {
+			return nil, err
+		}
+		seqBits := idGen.settings.SeqBit - shardBits
+		idGen.shardBase = int64(i) << (idGen.settings.presets.shiftSeq + seqBits)
+		idGen.shardSeqMax = int64(1)<<seqBits - 1
+		pool.shards[i] = idGen
+	}
+	return pool, nil
+}
+
+// Generate 生成全局唯一id
+func (pool *GeneratorPool) Generate() (ID, error) {
+	idGen, _ := pool.cache.Get().(*IDGenerator)
+	if idGen == nil {
+		idGen = pool.shards[int(atomic.AddUint32(&pool.next, 1))%len(pool.shards)]
+	}
+	id, err := idGen.Generate()
+	pool.cache.Put(idGen)
+	return id, err
+}
+
+// Shards 分片数
+func (pool *GeneratorPool) Shards() int {
+	return len(pool.shards)
+}
+
+// Decompose 将id解析成time、seq等部分，序号包含分片编号
+func (pool *GeneratorPool) Decompose(id int64) *IDCompose {
+	return pool.shards[0].Decompose(id)
+}
+
+// ForEach 对每个分片调用f，用于统一设置指标上报、时间源、等待方式、无锁快速路径等
+//   - 分片上开启序号填充、序号空间划分或切换机器ID会返回错误
+func (pool *GeneratorPool) ForEach(f func(shard *IDGenerator) error) error {
+	if f == nil {
+		return errorf(CodeNil, "f")
+	}
+	for _, idGen := range pool.shards {
+		if err := f(idGen); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package generator
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+)
+
+// TestGeneratorPool 分片生成器池并发生成id全局唯一，分片编号位于序号高位
+func TestGeneratorPool(t *testing.T) {
+	settings := Settings{TimeBit: 41, MachineIDBit: 10, TimelineBit: 1, SeqBit: 11, Epoch: DefaultEpoch}
+	pool, err := NewGeneratorPool(3, settings, 3)
+	if err != nil {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "创建生成器池", err, nil)
+	}
+	if pool.Shards() != 4 {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "分片数取整为2的幂", pool.Shards(), 4)
+	}
+	pool.ForEach(func(shard *IDGenerator) error {
+		shard.SetLockFree(true)
+		return nil
+	})
+
+	var ids sync.Map
+	var wg sync.WaitGroup
+	for g := 0; g < 16; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 5000; i++ {
+				id, err := pool.Generate()
+				if err != nil {
+					t.Error(err.Error())
+					return
+				}
+				if _, exist := ids.LoadOrStore(id, nil); exist {
+					t.Errorf("出现重复的id:%d", id)
+					return
+				}
+				if compose := pool.Decompose(int64(id)); compose.MachineID != 3 {
+					t.Errorf("【失败】-%s-got:%v-want:%v", "机器ID", compose.MachineID, 3)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	//各分片的序号高位为分片编号
+	for i, shard := range pool.shards {
+		id, _ := shard.Generate()
+		if got := pool.Decompose(int64(id)).Seq >> 9; got != int64(i) {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", "分片编号", got, i)
+		}
+		if _, err := shard.RotateMachineID(4); err == nil {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", "分片切换机器ID", err, "error")
+		}
+	}
+
+	testCases := []struct {
+		name   string
+		shards int
+		want   int
+		err    bool
+	}{
+		{name: "按CPU数", shards: 0, want: nextPowerOfTwo(runtime.NumCPU())},
+		{name: "1个分片", shards: 1, want: 1},
+		{name: "超出序号位数", shards: 2048, err: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			pool, err := NewGeneratorPool(1, settings, tc.shards)
+			if tc.err {
+				if err == nil {
+					t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, err, "error")
+				}
+				return
+			}
+			if err != nil || pool.Shards() != tc.want {
+				t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, err, tc.want)
+			}
+		})
+	}
+}
+
+func nextPowerOfTwo(n int) int {
+	p := 1
+	for p < n {
+		p <<= 1
+	}
+	return p
+}
+
+// BenchmarkGenParallelPool 多goroutine并发(分片生成器池)性能测试
+func BenchmarkGenParallelPool(b *testing.B) {
+	pool, _ := NewGeneratorPool(0, Settings{TimeBit: 41, MachineIDBit: 0, TimelineBit: 1, SeqBit: 21, Epoch: DefaultEpoch}, 0)
+	b.SetParallelism(16)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			pool.Generate()
+		}
+	})
+}
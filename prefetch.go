@@ -0,0 +1,196 @@
+package generator
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	defaultPrefetchMinSize = 64
+	defaultPrefetchMaxSize = 1 << 16
+	defaultPrefetchCover   = 10 * time.Millisecond
+	prefetchAdjustInterval = 100 * time.Millisecond
+)
+
+// PrefetchConfig 预取缓冲配置
+type PrefetchConfig struct {
+	MinSize int           //缓冲最小容量，缺省64
+	MaxSize int           //缓冲最大容量，缺省65536
+	Cover   time.Duration //缓冲应能覆盖的消费时长(不含补充耗时)，缺省10ms
+}
+
+// Prefetcher 预取缓冲：后台预先生成id，Next直接从缓冲中取出
+//   - 缓冲容量根据观测到的消费速率和补充耗时自动调整：容量≈消费速率×(补充耗时+Cover)，并限制在[MinSize, MaxSize]之间
+//   - 预取的id时间早于实际取出的时间，不适合对id时间精度有要求的场景
+type Prefetcher struct {
+	gen    Generator
+	config PrefetchConfig
+
+	mutex    sync.Mutex
+	notEmpty *sync.Cond
+	needFill *sync.Cond
+	buf      []int64 //环形缓冲
+	head     int
+	count    int
+	size     int //当前目标容量
+	err      error
+	closed   bool
+
+	consumed   int64         //本统计周期内取出的数量
+	starved    bool          //本统计周期内是否出现缓冲为空的等待
+	fillCost   time.Duration //最近一次补充的耗时
+	lastAdjust time.Time
+}
+
+// NewPrefetcher 创建预取缓冲并启动后台补充
+func NewPrefetcher(gen Generator, config PrefetchConfig) (*Prefetcher, error) {
+	if gen == nil {
+		return nil, errorf(CodeNil, "idGen")
+	}
+	if config.MinSize <= 0 {
+		config.MinSize = defaultPrefetchMinSize
+	}
+	if config.MaxSize <= 0 {
+		config.MaxSize = defaultPrefetchMaxSize
+	}
+	if config.Cover <= 0 {
+		config.Cover = defaultPrefetchCover
+	}
+	if config.MinSize > config.MaxSize {
+		return nil, errorf(CodePrefetchSize)
+	}
+
+	p := &Prefetcher{
+		gen:        gen,
+		config:     config,
+		buf:        make([]int64, config.MaxSize),
+		size:       config.MinSize,
+		lastAdjust: time.Now(),
+	}
+	p.notEmpty = sync.NewCond(&p.mutex)
+	p.needFill = sync.NewCond(&p.mutex)
+	goLabeled(gen, p.fill)
+	return p, nil
+}
+
+// Next 从缓冲中取出一个id，缓冲为空时等待补充
+func (p *Prefetcher) Next() (int64, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	for p.count == 0 {
+		if p.closed {
+			return 0, errorf(CodePrefetchClosed)
+		}
+		if p.err != nil {
+			err := p.err
+			p.err = nil
+			p.needFill.Signal()
+			return 0, err
+		}
+		p.starved = true
+		p.needFill.Signal()
+		p.notEmpty.Wait()
+	}
+
+	id := p.buf[p.head]
+	p.head = (p.head + 1) % len(p.buf)
+	p.count--
+	p.consumed++
+	p.adjust()
+	if p.count <= p.size/2 {
+		p.needFill.Signal()
+	}
+	return id, nil
+}
+
+// Generate 同Next，使Prefetcher可作为Generator使用
+func (p *Prefetcher) Generate() (ID, error) {
+	id, err := p.Next()
+	return ID(id), err
+}
+
+// Size 当前缓冲目标容量
+func (p *Prefetcher) Size() int {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.size
+}
+
+// Close 停止后台补充，缓冲中剩余的id仍可取出
+func (p *Prefetcher) Close() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.closed = true
+	p.needFill.Broadcast()
+	p.notEmpty.Broadcast()
+}
+
+// fill 后台补充缓冲
+func (p *Prefetcher) fill() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	for {
+		for !p.closed && (p.count > p.size/2 || p.err != nil) {
+			p.needFill.Wait()
+		}
+		if p.closed {
+			return
+		}
+		p.adjust()
+
+		want := p.size - p.count
+		if want <= 0 {
+			continue
+		}
+		p.mutex.Unlock()
+		start := time.Now()
+		ids := make([]int64, 0, want)
+		var err error
+		for i := 0; i < want; i++ {
+			var id ID
+			if id, err = p.gen.Generate(); err != nil {
+				break
+			}
+			ids = append(ids, int64(id))
+		}
+		cost := time.Since(start)
+		p.mutex.Lock()
+
+		if len(ids) > 0 {
+			p.fillCost = cost * time.Duration(want) / time.Duration(len(ids))
+		}
+		for _, id := range ids {
+			p.buf[(p.head+p.count)%len(p.buf)] = id
+			p.count++
+		}
+		p.err = err
+		p.notEmpty.Broadcast()
+	}
+}
+
+// adjust 根据消费速率和补充耗时调整目标容量
+func (p *Prefetcher) adjust() {
+	elapsed := time.Since(p.lastAdjust)
+	if elapsed < prefetchAdjustInterval && !p.starved {
+		return
+	}
+
+	rate := float64(p.consumed) / elapsed.Seconds()
+	size := int(rate * (p.fillCost + p.config.Cover).Seconds())
+	if p.starved && size <= p.size {
+		size = p.size * 2
+	}
+	if size < p.config.MinSize {
+		size = p.config.MinSize
+	}
+	if size > p.config.MaxSize {
+		size = p.config.MaxSize
+	}
+
+	p.size = size
+	p.consumed = 0
+	p.starved = false
+	p.lastAdjust = time.Now()
+}
@@ -0,0 +1,63 @@
+package generator
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestPrefetcher 预取缓冲
+func TestPrefetcher(t *testing.T) {
+	idGen, _ := NewGenerator(0)
+	prefetcher, err := NewPrefetcher(idGen, PrefetchConfig{MinSize: 16, MaxSize: 4096})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	// 并发取出，id不重复
+	var ids sync.Map
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 2e4; i++ {
+				id, err := prefetcher.Next()
+				if err != nil {
+					t.Error(err.Error())
+					return
+				}
+				if _, exist := ids.LoadOrStore(id, nil); exist {
+					t.Errorf("出现重复的id:%d", id)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	// 高速消费后缓冲扩容
+	if size := prefetcher.Size(); size <= 16 {
+		t.Fatalf("【失败】-高速消费后缓冲应扩容-size:%d", size)
+	}
+
+	// 低速消费后缓冲缩容
+	for i := 0; i < 3; i++ {
+		time.Sleep(2 * prefetchAdjustInterval)
+		prefetcher.Next()
+	}
+	if size := prefetcher.Size(); size != 16 {
+		t.Fatalf("【失败】-低速消费后缓冲应缩容到MinSize-size:%d", size)
+	}
+
+	prefetcher.Close()
+	for {
+		if _, err := prefetcher.Next(); err != nil {
+			break
+		}
+	}
+
+	if _, err := NewPrefetcher(idGen, PrefetchConfig{MinSize: 10, MaxSize: 5}); err == nil {
+		t.Fatalf("【失败】-MinSize大于MaxSize应返回错误")
+	}
+}
@@ -0,0 +1,71 @@
+package generator
+
+import (
+	"math"
+	"time"
+)
+
+// rateLimiter 令牌桶
+type rateLimiter struct {
+	rate   float64 //每秒补充的令牌数
+	burst  float64 //令牌桶容量
+	tokens float64 //当前令牌数
+	last   int64   //上次补充令牌的时间(unix nano)
+}
+
+// SetRateLimit 限制生成速率，perSecond为0表示不限制
+//   - 按令牌桶限流：每秒补充perSecond个令牌，最多积累burst个，每生成一个id消耗一个令牌；
+//     令牌不足时返回*RateLimitError(匹配ErrRateLimited)，不等待，可通过RetryAfter读取建议的重试间隔
+//   - 避免异常的调用方耗尽序号空间或挤占共享同一生成器的其他使用方；GenerateN须一次取得n个令牌，n不能超过burst
+//   - 开启后不使用无锁快速路径
+func (idGen *IDGenerator) SetRateLimit(perSecond float64, burst int) error {
+	if perSecond < 0 || math.IsNaN(perSecond) || math.IsInf(perSecond, 0) {
+		return errorf(CodeNegative, "perSecond")
+	}
+	if perSecond > 0 && burst < 1 {
+		return errorf(CodeNotPositive, "burst")
+	}
+
+	idGen.mutex.Lock()
+	defer idGen.mutex.Unlock()
+	if perSecond == 0 {
+		idGen.rateLimit = nil
+		return nil
+	}
+	idGen.rateLimit = &rateLimiter{
+		rate:   perSecond,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   idGen.now().UnixNano(),
+	}
+	return nil
+}
+
+// checkRate 补充令牌，不足n个时返回*RateLimitError，调用方须持有锁
+func (idGen *IDGenerator) checkRate(now time.Time, n int) error {
+	l := idGen.rateLimit
+	if l == nil {
+		return nil
+	}
+	if float64(n) > l.burst {
+		return errorf(CodeRateLimitBurst, int64(l.burst))
+	}
+	if nanos := now.UnixNano(); nanos > l.last {
+		l.tokens = math.Min(l.burst, l.tokens+float64(nanos-l.last)/float64(time.Second)*l.rate)
+		l.last = nanos
+	}
+	if lack := float64(n) - l.tokens; lack > 0 {
+		if idGen.metrics != nil {
+			idGen.metrics.Counter(MetricRateLimited, 1)
+		}
+		return &RateLimitError{RetryAfter: time.Duration(math.Ceil(lack / l.rate * float64(time.Second)))}
+	}
+	return nil
+}
+
+// spendRate 消耗n个令牌，调用方须持有锁并已通过checkRate确认令牌充足
+func (idGen *IDGenerator) spendRate(n int64) {
+	if idGen.rateLimit != nil {
+		idGen.rateLimit.tokens -= float64(n)
+	}
+}
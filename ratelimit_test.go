@@ -0,0 +1,70 @@
+package generator
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jayecc/mtl-snowflake/fakeclock"
+)
+
+// TestRateLimit 限制生成速率
+func TestRateLimit(t *testing.T) {
+	clock := fakeclock.New(time.Now())
+	settings := *DefaultSettings
+	settings.Clock = clock
+	idGen, _ := NewGeneratorWithSettings(0, settings)
+	metrics := countingMetrics{}
+	idGen.SetMetrics(metrics)
+	if err := idGen.SetRateLimit(1000, 5); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	//突发容量用完后返回*RateLimitError
+	for i := 0; i < 5; i++ {
+		if _, err := idGen.Generate(); err != nil {
+			t.Fatalf("【失败】-第%d个-%v", i, err)
+		}
+	}
+	_, err := idGen.Generate()
+	var limited *RateLimitError
+	if !errors.As(err, &limited) || !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "超过速率", err, ErrRateLimited)
+	}
+	if retry, ok := RetryAfter(err); !ok || retry != time.Millisecond {
+		t.Fatalf("【失败】-%s-got:%v,%v-want:%v", "重试间隔", retry, ok, time.Millisecond)
+	}
+	if metrics[MetricRateLimited] != 1 {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "限流次数", metrics[MetricRateLimited], 1)
+	}
+
+	//按速率补充令牌
+	clock.Advance(3 * time.Millisecond)
+	if _, err := idGen.GenerateN(4); err == nil {
+		t.Fatalf("【失败】-%s-got:%v-want:%s", "GenerateN令牌不足", err, "错误")
+	}
+	ids, err := idGen.GenerateN(3)
+	if err != nil || len(ids) != 3 {
+		t.Fatalf("【失败】-%s-got:%v-err:%v", "GenerateN", len(ids), err)
+	}
+	if _, err := idGen.Generate(); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "GenerateN消耗令牌", err, ErrRateLimited)
+	}
+	if _, err := idGen.GenerateN(6); err == nil || errors.Is(err, ErrRateLimited) {
+		t.Fatalf("【失败】-%s-got:%v", "n超过burst", err)
+	}
+
+	//关闭后不再限制
+	if err := idGen.SetRateLimit(0, 0); err != nil {
+		t.Fatal(err.Error())
+	}
+	if _, err := idGen.GenerateN(100); err != nil {
+		t.Fatalf("【失败】-%s-%v", "关闭限流", err)
+	}
+	if err := idGen.SetRateLimit(-1, 1); err == nil {
+		t.Fatalf("【失败】-%s-got:%v-want:%s", "速率为负", err, "错误")
+	}
+	if err := idGen.SetRateLimit(10, 0); err == nil {
+		t.Fatalf("【失败】-%s-got:%v-want:%s", "burst为0", err, "错误")
+	}
+}
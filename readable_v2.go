@@ -0,0 +1,191 @@
+package generator
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// readableV2Layout v2可读格式中的时间部分(精确到秒)
+const readableV2Layout = "20060102T150405"
+
+// readableV2Widths v2可读格式秒以下部分及机器ID、时间线、序号的宽度
+func (idGen *IDGenerator) readableV2Widths() (sub, machine, timeline, seq int) {
+	presets := idGen.settings.presets
+	if perSecond := int64(time.Second) / idGen.settings.unit(); perSecond > 1 {
+		sub = len(strconv.FormatInt(perSecond-1, 10))
+	}
+	machine = len(strconv.FormatInt(presets.maxMachineID, 10))
+	timeline = len(strconv.FormatInt(presets.maxTimeline, 10))
+	seq = len(strconv.FormatInt(presets.maxSeq, 10))
+	return
+}
+
+// readableV2NodeWidths v2可读格式中版本号、数据中心ID的宽度，未设置VersionBit、DatacenterBit时为0(不输出该部分)
+func (idGen *IDGenerator) readableV2NodeWidths() (version, datacenter int) {
+	presets := idGen.settings.presets
+	if idGen.settings.VersionBit > 0 {
+		version = len(strconv.FormatInt(int64(1)<<idGen.settings.VersionBit-1, 10))
+	}
+	if idGen.settings.DatacenterBit > 0 {
+		datacenter = len(strconv.FormatInt(presets.maxDatacenter, 10))
+	}
+	return
+}
+
+// ToReadableV2 v2可读格式：各部分以'-'分隔并带前缀字母，如 20240904T190147.123-m012-t1-s0042
+//   - 时间按UTC输出，精确到时间单位，秒级时间单位不输出秒以下部分
+//   - 机器ID、时间线、序号按位数固定宽度(不足补0)，同一配置下长度固定
+//   - 设置VersionBit时以版本号开头，如 v1-20240904T190147.123-m012-t1-s0042
+//   - 设置DatacenterBit时在机器ID之前输出数据中心ID，如 20240904T190147.123-d2-m012-t1-s0042
+//   - 预留标记位时追加 -f0 或 -f1
+func (idGen *IDGenerator) ToReadableV2(id int64) string {
+	c := idGen.Decompose(id)
+	subWidth, machineWidth, timelineWidth, seqWidth := idGen.readableV2Widths()
+	versionWidth, datacenterWidth := idGen.readableV2NodeWidths()
+	unit := idGen.settings.unit()
+	genTime := time.Unix(0, idGen.toUnixNano(c.Time)).UTC()
+
+	var b strings.Builder
+	if versionWidth > 0 {
+		fmt.Fprintf(&b, "v%0*d-", versionWidth, c.Version)
+	}
+	b.WriteString(genTime.Format(readableV2Layout))
+	if subWidth > 0 {
+		fmt.Fprintf(&b, ".%0*d", subWidth, int64(genTime.Nanosecond())/unit)
+	}
+	if datacenterWidth > 0 {
+		fmt.Fprintf(&b, "-d%0*d", datacenterWidth, c.DatacenterID)
+	}
+	fmt.Fprintf(&b, "-m%0*d-t%0*d-s%0*d", machineWidth, c.MachineID, timelineWidth, c.TimeLine, seqWidth, c.Seq)
+	if idGen.settings.FlagBit {
+		fmt.Fprintf(&b, "-f%d", id&1)
+	}
+	return b.String()
+}
+
+// ParseReadableV2 解析v2可读格式，返回id
+//   - 严格校验：分隔符、前缀字母、各部分宽度须与ToReadableV2输出一致，且不超过对应位数的最大值
+//   - 数据中心ID取自字符串，可解析其他数据中心的id；版本号须与当前配置的Version相同，其他版本的id结构不同，无法按当前配置解析
+func (idGen *IDGenerator) ParseReadableV2(readable string) (int64, error) {
+	settings := idGen.settings
+	presets := settings.presets
+	subWidth, machineWidth, timelineWidth, seqWidth := idGen.readableV2Widths()
+	versionWidth, datacenterWidth := idGen.readableV2NodeWidths()
+
+	parts := strings.Split(readable, "-")
+	want := 4
+	if settings.FlagBit {
+		want++
+	}
+	if versionWidth > 0 {
+		want++
+	}
+	if datacenterWidth > 0 {
+		want++
+	}
+	if len(parts) != want {
+		return 0, errorf(CodeReadableV2Parts, want)
+	}
+
+	//版本号
+	if versionWidth > 0 {
+		version, err := parseReadableV2Field(parts[0], 'v', versionWidth, int64(1)<<settings.VersionBit-1)
+		if err != nil {
+			return 0, err
+		}
+		if version != settings.Version {
+			return 0, errorf(CodeReadableV2Version, version, settings.Version)
+		}
+		parts = parts[1:]
+	}
+
+	//时间部分
+	timeText, subText := parts[0], ""
+	if subWidth > 0 {
+		if len(timeText) != len(readableV2Layout)+1+subWidth || timeText[len(readableV2Layout)] != '.' {
+			return 0, errorf(CodeReadableV2Time, readableV2Layout+"."+strings.Repeat("0", subWidth))
+		}
+		timeText, subText = timeText[:len(readableV2Layout)], timeText[len(readableV2Layout)+1:]
+	}
+	if len(timeText) != len(readableV2Layout) || !isDigits(timeText[:8]) || timeText[8] != 'T' || !isDigits(timeText[9:]) || !isDigits(subText) {
+		return 0, errorf(CodeReadableV2Time, readableV2Layout)
+	}
+	t, err := time.Parse(readableV2Layout, timeText)
+	if err != nil {
+		return 0, err
+	}
+	if sec := t.Unix(); sec <= math.MinInt64/int64(time.Second) || sec >= math.MaxInt64/int64(time.Second) {
+		return 0, errorf(CodeReadableV2TimeRange)
+	}
+	nanos := t.UnixNano()
+	if subWidth > 0 {
+		sub, _ := strconv.ParseInt(subText, 10, 64)
+		nanos += sub * settings.unit()
+	}
+	timePart, err := idGen.readableTimePart(nanos)
+	if err != nil {
+		return 0, err
+	}
+
+	//其余部分
+	parts = parts[1:]
+	var datacenterID int64
+	if datacenterWidth > 0 {
+		if datacenterID, err = parseReadableV2Field(parts[0], 'd', datacenterWidth, presets.maxDatacenter); err != nil {
+			return 0, err
+		}
+		parts = parts[1:]
+	}
+	machineID, err := parseReadableV2Field(parts[0], 'm', machineWidth, presets.maxMachineID)
+	if err != nil {
+		return 0, err
+	}
+	timeline, err := parseReadableV2Field(parts[1], 't', timelineWidth, presets.maxTimeline)
+	if err != nil {
+		return 0, err
+	}
+	seq, err := parseReadableV2Field(parts[2], 's', seqWidth, presets.maxSeq)
+	if err != nil {
+		return 0, err
+	}
+	var flag int64
+	if settings.FlagBit {
+		if flag, err = parseReadableV2Field(parts[3], 'f', 1, 1); err != nil {
+			return 0, err
+		}
+	}
+
+	return idGen.prefix |
+		presets.version |
+		timePart<<presets.shiftTimeBit |
+		datacenterID<<presets.shiftDatacenterBit |
+		machineID<<presets.shiftMachineIDBit |
+		timeline<<presets.shiftTimelineBit |
+		seq<<presets.shiftSeq |
+		flag, nil
+}
+
+// parseReadableV2Field 解析v2可读格式中带前缀字母、固定宽度的部分
+func parseReadableV2Field(part string, prefix byte, width int, max int64) (int64, error) {
+	if len(part) != 1+width || part[0] != prefix || !isDigits(part[1:]) {
+		return 0, errorf(CodeReadableV2Field, prefix, prefix, width)
+	}
+	v, err := strconv.ParseInt(part[1:], 10, 64)
+	if err != nil || v > max {
+		return 0, errorf(CodeReadableV2FieldRange, prefix, max)
+	}
+	return v, nil
+}
+
+// isDigits 是否全部为数字
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,86 @@
+package generator
+
+import (
+	"testing"
+	"time"
+)
+
+// TestReadableV2 v2可读格式
+func TestReadableV2(t *testing.T) {
+	genTime := time.Date(2024, 9, 4, 19, 1, 47, 123e6, time.UTC)
+	flagged := Settings{TimeBit: 41, MachineIDBit: 9, TimelineBit: 1, SeqBit: 11, Epoch: DefaultEpoch, FlagBit: true}
+	aboveTime := *DefaultSettings
+	aboveTime.Placement = TimelineAboveTime
+	node := Settings{VersionBit: 2, Version: 1, TimeBit: 41, DatacenterBit: 3, DatacenterID: 2, MachineIDBit: 6, TimelineBit: 1, SeqBit: 10, Epoch: DefaultEpoch}
+
+	testCases := []struct {
+		name     string
+		settings Settings
+		flag     bool
+		want     string
+	}{
+		{name: "默认配置", settings: *DefaultSettings, want: "20240904T190147.123-m012-t1-s0042"},
+		{name: "秒级时间单位", settings: *SecondSettings, want: "20240904T190147-m0012-t1-s0000042"},
+		{name: "时间线位于时间之上", settings: aboveTime, want: "20240904T190147.123-m012-t1-s0042"},
+		{name: "标记位", settings: flagged, flag: true, want: "20240904T190147.123-m012-t1-s0042-f1"},
+		{name: "版本号及数据中心", settings: node, want: "v1-20240904T190147.123-d2-m12-t1-s0042"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			idGen, _ := NewGeneratorWithSettings(12, tc.settings)
+			id := idGen.compose(idGen.toOffsetTime(genTime.UnixNano()), 1, 42)
+			if tc.flag {
+				id = idGen.WithFlag(id)
+			}
+			if got := idGen.ToReadableV2(id); got != tc.want {
+				t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, got, tc.want)
+			}
+			if got, err := idGen.ParseReadableV2(tc.want); err != nil || got != int64(id) {
+				t.Fatalf("【失败】-%s-解析-got:%v(%v)-want:%v", tc.name, got, err, id)
+			}
+		})
+	}
+
+	//按其他数据中心的生成器输出、解析，得到原id
+	dc2, _ := NewGeneratorWithSettings(12, node)
+	node.DatacenterID = 1
+	dc1, _ := NewGeneratorWithSettings(12, node)
+	id, _ := dc2.Generate()
+	if got, err := dc1.ParseReadableV2(dc1.ToReadableV2(int64(id))); err != nil || got != int64(id) {
+		t.Fatalf("【失败】-%s-got:%v(%v)-want:%v", "其他数据中心", got, err, id)
+	}
+	node.Version = 2
+	v2, _ := NewGeneratorWithSettings(12, node)
+	id, _ = v2.Generate()
+	if _, err := dc1.ParseReadableV2(v2.ToReadableV2(int64(id))); err == nil {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "版本号不同", err, "error")
+	}
+	for _, readable := range []string{"20240904T190147.123-d2-m12-t1-s0042", "v1-20240904T190147.123-d8-m12-t1-s0042", "v1-20240904T190147.123-m12-t1-s0042"} {
+		if _, err := dc1.ParseReadableV2(readable); err == nil {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", readable, err, "error")
+		}
+	}
+
+	idGen, _ := NewGenerator(0)
+	errCases := []struct {
+		name     string
+		readable string
+	}{
+		{name: "部分数量错误", readable: "20240904T190147.123-m012-t1"},
+		{name: "缺少毫秒", readable: "20240904T190147-m012-t1-s0042"},
+		{name: "时间分隔符错误", readable: "20240904 190147.123-m012-t1-s0042"},
+		{name: "日期不存在", readable: "20240231T190147.123-m012-t1-s0042"},
+		{name: "前缀字母错误", readable: "20240904T190147.123-x012-t1-s0042"},
+		{name: "宽度不足", readable: "20240904T190147.123-m12-t1-s0042"},
+		{name: "符号", readable: "20240904T190147.123-m+12-t1-s0042"},
+		{name: "机器ID超出范围", readable: "20240904T190147.123-m512-t1-s0042"},
+		{name: "时间线超出范围", readable: "20240904T190147.123-m012-t2-s0042"},
+		{name: "早于基准时间", readable: "20190904T190147.123-m012-t1-s0042"},
+		{name: "未预留标记位", readable: "20240904T190147.123-m012-t1-s0042-f1"},
+	}
+	for _, tc := range errCases {
+		if _, err := idGen.ParseReadableV2(tc.readable); err == nil {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, err, "error")
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package generator
+
+import (
+	"time"
+)
+
+// SetTimelineReclaimMargin 设置回收时间线的安全余量
+//   - 发生时钟回退切换时间线后，原时间线保留回退前的进度，暂不可用
+//   - 当前时间超过其进度margin后回收该时间线，长期运行的进程可重新获得处理时钟回退的能力
+//   - margin为0表示时钟追回后立即回收；适当的余量可避免时钟在回退点附近反复抖动时刚回收又被消耗
+func (idGen *IDGenerator) SetTimelineReclaimMargin(margin time.Duration) error {
+	if margin < 0 {
+		return errorf(CodeNegative, "margin")
+	}
+	idGen.mutex.Lock()
+	defer idGen.mutex.Unlock()
+
+	unit := idGen.settings.unit()
+	idGen.reclaimMargin = (int64(margin) + unit - 1) / unit
+	return nil
+}
+
+// AvailableTimelines 可用于处理时钟回退的时间线数(不含当前时间线)
+func (idGen *IDGenerator) AvailableTimelines() int {
+	idGen.mutex.Lock()
+	defer idGen.mutex.Unlock()
+	return idGen.availableTimelines()
+}
+
+// availableTimelines 可用时间线数，调用方须持有锁
+func (idGen *IDGenerator) availableTimelines() int {
+	return len(idGen.timelineProgress) - 1 - idGen.burnedCount
+}
+
+// burnTimeline 标记因时钟回退被切换走的时间线，调用方须持有锁
+func (idGen *IDGenerator) burnTimeline(timeline int64) {
+	if !idGen.burnedTimelines[timeline] {
+		idGen.burnedTimelines[timeline] = true
+		idGen.burnedCount++
+	}
+	if idGen.metrics != nil {
+		idGen.metrics.Gauge(MetricTimelineAvailable, float64(idGen.availableTimelines()))
+	}
+}
+
+// reclaimTimelines 回收当前时间已超过其进度(含安全余量)的时间线，调用方须持有锁
+func (idGen *IDGenerator) reclaimTimelines(curTime int64) {
+	var reclaimed int
+	for timeline, burned := range idGen.burnedTimelines {
+		if burned && idGen.timelineProgress[timeline]+idGen.reclaimMargin < curTime {
+			idGen.burnedTimelines[timeline] = false
+			idGen.burnedCount--
+			reclaimed++
+		}
+	}
+	if reclaimed > 0 && idGen.metrics != nil {
+		idGen.metrics.Counter(MetricTimelineReclaimed, int64(reclaimed))
+		idGen.metrics.Gauge(MetricTimelineAvailable, float64(idGen.availableTimelines()))
+	}
+}
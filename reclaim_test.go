@@ -0,0 +1,82 @@
+package generator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jayecc/mtl-snowflake/fakeclock"
+)
+
+// TestTimelineReclaim 时钟追回后回收时间线
+//   - 通过假时钟模拟时钟回退及追回
+func TestTimelineReclaim(t *testing.T) {
+	testCases := []struct {
+		name          string
+		margin        time.Duration
+		wantAvailable int   //时钟追回后可用的时间线数
+		wantReclaimed int64 //回收的时间线数
+		wantErr       bool  //再次回退是否失败
+	}{
+		{name: "追回后立即回收", margin: 0, wantAvailable: 1, wantReclaimed: 1, wantErr: false},
+		{name: "未超过安全余量不回收", margin: time.Hour, wantAvailable: 0, wantReclaimed: 0, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			clock := fakeclock.New(time.Now())
+			settings := *DefaultSettings
+			settings.Clock = clock
+			idGen, _ := NewGeneratorWithSettings(0, settings)
+			metrics := countingMetrics{}
+			idGen.SetMetrics(metrics)
+			idGen.SetTimelineReclaimMargin(tc.margin)
+			start := clock.Time()
+			ids := make(map[int64]bool)
+			generate := func() error {
+				id, err := idGen.Generate()
+				if err != nil {
+					return err
+				}
+				if ids[int64(id)] {
+					t.Fatalf("【失败】-%s-出现重复的id:%d", tc.name, id)
+				}
+				ids[int64(id)] = true
+				return nil
+			}
+
+			//时钟回退100ms，切换到时间线1
+			generate()
+			clock.Set(start.Add(-100 * time.Millisecond))
+			if err := generate(); err != nil {
+				t.Fatal(err.Error())
+			}
+			if got := idGen.AvailableTimelines(); got != 0 {
+				t.Fatalf("【失败】-%s-回退后可用时间线-got:%v-want:%v", tc.name, got, 0)
+			}
+
+			//时钟追回
+			clock.Set(start.Add(2 * time.Millisecond))
+			generate()
+			if got := idGen.AvailableTimelines(); got != tc.wantAvailable {
+				t.Fatalf("【失败】-%s-追回后可用时间线-got:%v-want:%v", tc.name, got, tc.wantAvailable)
+			}
+			if got := metrics[MetricTimelineReclaimed]; got != tc.wantReclaimed {
+				t.Fatalf("【失败】-%s-回收次数-got:%v-want:%v", tc.name, got, tc.wantReclaimed)
+			}
+
+			//再次回退到时间线0的进度与时间线1的进度之间
+			clock.Set(start.Add(52 * time.Millisecond))
+			generate()
+			clock.Set(start.Add(22 * time.Millisecond))
+			err := generate()
+			if got := err != nil; got != tc.wantErr {
+				t.Fatalf("【失败】-%s-再次回退-got:%v-want:%v", tc.name, err, tc.wantErr)
+			}
+		})
+	}
+
+	idGen, _ := NewGenerator(0)
+	if err := idGen.SetTimelineReclaimMargin(-time.Second); err == nil {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "安全余量为负数", err, "error")
+	}
+}
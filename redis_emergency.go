@@ -0,0 +1,93 @@
+package generator
+
+import (
+	"strconv"
+	"time"
+)
+
+// redisEmergencyKeyTTL 计数key的过期时间，只需覆盖单个时间单位
+const redisEmergencyKeyTTL = 10 * time.Second
+
+// RedisClient 应急生成器依赖的Redis命令，可由任意Redis客户端适配
+type RedisClient interface {
+	// Time Redis服务器时间(TIME)
+	Time() (time.Time, error)
+	// Incr 对key执行INCR，返回自增后的值
+	Incr(key string) (int64, error)
+	// Expire 设置key的过期时间
+	Expire(key string, ttl time.Duration) error
+}
+
+// RedisEmergencyGenerator 基于Redis INCR的应急生成器，用于本地时钟不可信时降级
+//   - 时间部分取自Redis服务器时间，序号由INCR分配，机器ID使用预留给应急生成的机器ID
+//   - Redis主从切换或数据丢失时可能生成重复id，仅应作为FallbackChain中的后备
+//   - 不支持时间线，TimelineBit与SeqBit合并作为序号：低SeqBit位写入序号位，超出的部分写入时间线位，与Placement无关
+type RedisEmergencyGenerator struct {
+	client    RedisClient
+	prefix    string
+	settings  *Settings
+	machineID int64
+	maxSeq    int64 //每个时间单位可分配的最大序号(时间线位+序号位)
+}
+
+var _ EmergencyGenerator = (*RedisEmergencyGenerator)(nil)
+
+// NewRedisEmergencyGenerator 创建应急生成器
+//   - prefix 计数key前缀，同一业务的所有节点须相同
+//   - machineID 预留给应急生成的机器ID，不能与在线节点的机器ID相同
+func NewRedisEmergencyGenerator(client RedisClient, prefix string, machineID int64, settings Settings) (*RedisEmergencyGenerator, error) {
+	if client == nil {
+		return nil, errorf(CodeNil, "client")
+	}
+	if err := checkSettings(&settings, machineID); err != nil {
+		return nil, err
+	}
+	settings.presets = calcPresets(&settings)
+	return &RedisEmergencyGenerator{
+		client:    client,
+		prefix:    prefix,
+		settings:  &settings,
+		machineID: machineID,
+		maxSeq:    (1 << (settings.TimelineBit + settings.SeqBit)) - 1,
+	}, nil
+}
+
+// Emergency 标记为应急生成器
+func (gen *RedisEmergencyGenerator) Emergency() {}
+
+// Generate 生成id
+func (gen *RedisEmergencyGenerator) Generate() (ID, error) {
+	now, err := gen.client.Time()
+	if err != nil {
+		return 0, err
+	}
+	presets := gen.settings.presets
+	curTime := (now.UnixNano() - gen.settings.Epoch) / gen.settings.unit()
+	if curTime < 0 || curTime > presets.maxTime {
+		return 0, errorf(CodeRedisTimeRange)
+	}
+
+	key := gen.prefix + ":" + strconv.FormatInt(curTime, 10)
+	n, err := gen.client.Incr(key)
+	if err != nil {
+		return 0, err
+	}
+	if n == 1 {
+		if err := gen.client.Expire(key, redisEmergencyKeyTTL); err != nil {
+			return 0, err
+		}
+	}
+	if n < 1 || n-1 > gen.maxSeq {
+		return 0, errorf(CodeEmergencyExhausted, n)
+	}
+
+	//时间线位不一定紧邻序号位(TimelineAboveMachine、TimelineAboveTime)，须分别写入，否则溢出到机器ID或时间位
+	seq := (n - 1) & presets.maxSeq
+	timeline := (n - 1) >> gen.settings.SeqBit
+	return ID(presets.version |
+		(curTime << presets.shiftTimeBit) |
+		presets.datacenter |
+		(gen.machineID << presets.shiftMachineIDBit) |
+		(timeline << presets.shiftTimelineBit) |
+		(seq << presets.shiftSeq)), nil
+}
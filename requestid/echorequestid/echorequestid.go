@@ -0,0 +1,30 @@
+// Package echorequestid requestid中间件的echo适配
+//   - 请求ID写入响应头、c.Request()的context(requestid.FromContext)及c.Get(ContextKey)
+//
+// 如：
+//
+//	e.Use(echorequestid.New(requestid.New(idGen)))
+package echorequestid
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"github.com/jayecc/mtl-snowflake/requestid"
+)
+
+// ContextKey 请求ID在echo.Context中的key
+const ContextKey = "requestid"
+
+// New 以m的设置创建echo中间件
+func New(m *requestid.RequestID) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			r := m.Apply(c.Response(), c.Request())
+			c.SetRequest(r)
+			if id, ok := requestid.FromContext(r.Context()); ok {
+				c.Set(ContextKey, id)
+			}
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package echorequestid
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+
+	generator "github.com/jayecc/mtl-snowflake"
+	"github.com/jayecc/mtl-snowflake/requestid"
+)
+
+// TestNew 请求ID写入响应头、context及echo.Context
+func TestNew(t *testing.T) {
+	idGen, _ := generator.NewGenerator(1)
+	var fromContext, fromKeys string
+	e := echo.New()
+	e.Use(New(requestid.New(idGen)))
+	e.GET("/", func(c echo.Context) error {
+		fromContext, _ = requestid.FromContext(c.Request().Context())
+		fromKeys, _ = c.Get(ContextKey).(string)
+		return c.NoContent(http.StatusOK)
+	})
+	w := httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	header := w.Header().Get(requestid.DefaultHeader)
+	if header == "" || header != fromContext || header != fromKeys {
+		t.Fatalf("【失败】-%s-got:%v,%v,%v", "请求ID", header, fromContext, fromKeys)
+	}
+}
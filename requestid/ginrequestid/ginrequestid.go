@@ -0,0 +1,27 @@
+// Package ginrequestid requestid中间件的gin适配
+//   - 请求ID写入响应头、c.Request的context(requestid.FromContext)及c.Keys(c.GetString(ContextKey))
+//
+// 如：
+//
+//	r.Use(ginrequestid.New(requestid.New(idGen)))
+package ginrequestid
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/jayecc/mtl-snowflake/requestid"
+)
+
+// ContextKey 请求ID在gin.Context中的key
+const ContextKey = "requestid"
+
+// New 以m的设置创建gin中间件
+func New(m *requestid.RequestID) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request = m.Apply(c.Writer, c.Request)
+		if id, ok := requestid.FromContext(c.Request.Context()); ok {
+			c.Set(ContextKey, id)
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,31 @@
+package ginrequestid
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	generator "github.com/jayecc/mtl-snowflake"
+	"github.com/jayecc/mtl-snowflake/requestid"
+)
+
+// TestNew 请求ID写入响应头、context及gin.Context
+func TestNew(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	idGen, _ := generator.NewGenerator(1)
+	var fromContext, fromKeys string
+	r := gin.New()
+	r.Use(New(requestid.New(idGen)))
+	r.GET("/", func(c *gin.Context) {
+		fromContext, _ = requestid.FromContext(c.Request.Context())
+		fromKeys = c.GetString(ContextKey)
+	})
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	header := w.Header().Get(requestid.DefaultHeader)
+	if header == "" || header != fromContext || header != fromKeys {
+		t.Fatalf("【失败】-%s-got:%v,%v,%v", "请求ID", header, fromContext, fromKeys)
+	}
+}
@@ -0,0 +1,107 @@
+// Package requestid 为每个HTTP请求生成id作为请求ID：写入context及X-Request-ID响应头
+//   - 请求ID即snowflake id，按生成时间排序，日志按请求ID排序即按请求到达的先后排序，并可通过Decompose得到处理请求的节点与时间
+//   - Handler符合func(http.Handler) http.Handler，可直接用于net/http及chi(r.Use(m.Handler))；gin、echo的适配见ginrequestid、echorequestid
+//   - 生成失败时不设置请求ID，请求照常处理，请求ID只用于诊断，不应影响业务
+package requestid
+
+import (
+	"context"
+	"net/http"
+
+	generator "github.com/jayecc/mtl-snowflake"
+)
+
+// DefaultHeader 缺省的请求头及响应头名称
+const DefaultHeader = "X-Request-ID"
+
+// maxIncomingLength 沿用的请求ID的最大长度
+const maxIncomingLength = 128
+
+// RequestID 请求ID中间件
+type RequestID struct {
+	Header string             //请求头及响应头名称，为空时使用DefaultHeader
+	Trust  bool               //沿用请求头中已有的请求ID(如网关或上游服务生成的)，不合法时仍生成新的
+	Encode func(int64) string //请求ID的字符串形式，nil表示十进制，也可使用generator.ID(id).Base62()等
+
+	idGen generator.Generator
+}
+
+// New 创建使用idGen生成请求ID的中间件，idGen可以是*generator.IDGenerator、降级链等任意Generator
+func New(idGen generator.Generator) *RequestID {
+	return &RequestID{idGen: idGen}
+}
+
+// header 请求头及响应头名称
+func (m *RequestID) header() string {
+	if m.Header == "" {
+		return DefaultHeader
+	}
+	return m.Header
+}
+
+// Next 请求r的请求ID：Trust时沿用请求头中合法的请求ID，否则生成新的；生成失败时返回空字符串
+//   - 供其他框架的适配器使用，net/http直接使用Handler
+func (m *RequestID) Next(r *http.Request) string {
+	if m.Trust {
+		if id := r.Header.Get(m.header()); validIncoming(id) {
+			return id
+		}
+	}
+	id, err := m.idGen.Generate()
+	if err != nil {
+		return ""
+	}
+	if m.Encode != nil {
+		return m.Encode(int64(id))
+	}
+	return id.String()
+}
+
+// Apply 为请求设置请求ID：写入响应头，返回带有请求ID的请求；生成失败时原样返回r
+func (m *RequestID) Apply(w http.ResponseWriter, r *http.Request) *http.Request {
+	id := m.Next(r)
+	if id == "" {
+		return r
+	}
+	w.Header().Set(m.header(), id)
+	return r.WithContext(NewContext(r.Context(), id))
+}
+
+// Handler 中间件，可直接用于chi：r.Use(m.Handler)
+func (m *RequestID) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, m.Apply(w, r))
+	})
+}
+
+// Middleware 以默认设置创建中间件，同New(idGen).Handler
+func Middleware(idGen generator.Generator) func(http.Handler) http.Handler {
+	return New(idGen).Handler
+}
+
+// validIncoming 沿用的请求ID只允许可打印的ASCII字符且长度有限，避免日志注入
+func validIncoming(id string) bool {
+	if id == "" || len(id) > maxIncomingLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7E {
+			return false
+		}
+	}
+	return true
+}
+
+// contextKey context中请求ID的key
+type contextKey struct{}
+
+// NewContext 返回带有请求ID的context
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext context中的请求ID，未设置时返回false
+func FromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(contextKey{}).(string)
+	return id, ok
+}
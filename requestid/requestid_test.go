@@ -0,0 +1,83 @@
+package requestid
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	generator "github.com/jayecc/mtl-snowflake"
+)
+
+type failingGenerator struct{}
+
+func (failingGenerator) Generate() (generator.ID, error) { return 0, errors.New("生成失败") }
+
+// serve 经过中间件处理请求，返回响应头中的请求ID及handler从context读取的请求ID
+func serve(m *RequestID, incoming string) (header, fromContext string) {
+	handler := m.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fromContext, _ = FromContext(r.Context())
+	}))
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	if incoming != "" {
+		r.Header.Set(m.header(), incoming)
+	}
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	return w.Header().Get(m.header()), fromContext
+}
+
+// TestRequestID 生成请求ID并写入响应头及context
+func TestRequestID(t *testing.T) {
+	idGen, _ := generator.NewGenerator(3)
+
+	header, fromContext := serve(New(idGen), "upstream-1")
+	id, err := strconv.ParseInt(header, 10, 64)
+	if err != nil || header != fromContext || idGen.Decompose(id).MachineID != 3 {
+		t.Fatalf("【失败】-%s-got:%v,%v", "生成请求ID", header, fromContext)
+	}
+
+	trusted := New(idGen)
+	trusted.Trust = true
+	trusted.Header = "X-Trace-ID"
+	testCases := []struct {
+		name     string
+		incoming string
+		keep     bool
+	}{
+		{name: "沿用上游请求ID", incoming: "upstream-1", keep: true},
+		{name: "包含控制字符", incoming: "a\nb", keep: false},
+		{name: "过长", incoming: string(make([]byte, 129)), keep: false},
+	}
+	for _, tc := range testCases {
+		header, fromContext := serve(trusted, tc.incoming)
+		if (header == tc.incoming) != tc.keep || header == "" || header != fromContext {
+			t.Fatalf("【失败】-%s-got:%q,%q", tc.name, header, fromContext)
+		}
+	}
+
+	encoded := New(idGen)
+	encoded.Encode = func(id int64) string { return generator.ID(id).Base62() }
+	if header, _ := serve(encoded, ""); header == "" || len(header) >= len(strconv.FormatInt(id, 10)) {
+		t.Fatalf("【失败】-%s-got:%v", "自定义编码", header)
+	}
+
+	called := false
+	handler := Middleware(failingGenerator{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		if _, ok := FromContext(r.Context()); ok {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", "生成失败时不设置请求ID", ok, false)
+		}
+	}))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if !called || w.Header().Get(DefaultHeader) != "" {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "生成失败时照常处理", called, true)
+	}
+
+	if _, ok := FromContext(context.Background()); ok {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "未设置请求ID", ok, false)
+	}
+}
@@ -0,0 +1,132 @@
+package generator
+
+// defaultCaller 默认调用方，Generate使用
+const defaultCaller = 0
+
+// seqReservation 按调用方划分序号空间：序号高位为调用方编号，低位为调用方在当前时间单位内的序号
+type seqReservation struct {
+	shift   uint64 //调用方编号左移位数
+	mask    int64  //调用方序号掩码
+	callers []*reservedCaller
+	names   map[string]int
+}
+
+// reservedCaller 调用方的序号状态
+type reservedCaller struct {
+	name      string
+	timeline  int64 //最近一次生成使用的时间线
+	time      int64 //最近一次生成使用的时间单位，-1表示未生成过
+	seq       int64
+	exhausted int64 //序号用完等待的次数
+}
+
+// SetSeqReservation 按调用方划分序号空间，callerBits为0表示关闭
+//   - 序号的高callerBits位为调用方编号，每个调用方在一个时间单位内最多生成2^(SeqBit-callerBits)个id，
+//     某个调用方突发大量请求时只会等待自身的序号空间，不会挤占其他调用方
+//   - 编号0为默认调用方，Generate使用；其他调用方通过RegisterCaller登记后使用GenerateFor生成
+//   - 切换前等待当前时间单位结束；不能与序号填充(SetPadding)、序号随机化(SetSeqRandomization)、严格递增模式(SetStrictMonotonic)同时使用
+func (idGen *IDGenerator) SetSeqReservation(callerBits uint64) error {
+	idGen.mutex.Lock()
+	defer idGen.mutex.Unlock()
+
+	if callerBits >= idGen.settings.SeqBit {
+		return errorf(CodeCallerBits, idGen.settings.SeqBit)
+	}
+	if callerBits > 0 && idGen.padding != nil {
+		return errorf(CodePaddingReservation)
+	}
+	if callerBits > 0 && idGen.seqRandom != nil {
+		return errorf(CodeRandomReservation)
+	}
+	if callerBits > 0 && idGen.strictMonotonic {
+		return errorf(CodeMonotonicReservation)
+	}
+	if callerBits > 0 && idGen.shardSeqMax > 0 {
+		return errPoolShard
+	}
+
+	if err := idGen.drainLatestTime(); err != nil {
+		return err
+	}
+	idGen.seq = 0
+	if callerBits == 0 {
+		idGen.reservation = nil
+		return nil
+	}
+	shift := idGen.settings.SeqBit - callerBits
+	idGen.reservation = &seqReservation{
+		shift:   shift,
+		mask:    int64(1)<<shift - 1,
+		callers: []*reservedCaller{{name: "", time: -1}},
+		names:   make(map[string]int),
+	}
+	return nil
+}
+
+// RegisterCaller 登记调用方，返回调用方编号，重复登记返回相同编号
+func (idGen *IDGenerator) RegisterCaller(name string) (int, error) {
+	idGen.mutex.Lock()
+	defer idGen.mutex.Unlock()
+
+	r := idGen.reservation
+	if r == nil {
+		return 0, errorf(CodeReservationDisabled)
+	}
+	if name == "" {
+		return 0, errorf(CodeCallerNameEmpty)
+	}
+	if caller, exist := r.names[name]; exist {
+		return caller, nil
+	}
+	if int64(len(r.callers)) > idGen.settings.presets.maxSeq>>r.shift {
+		return 0, errorf(CodeCallersFull)
+	}
+	r.names[name] = len(r.callers)
+	r.callers = append(r.callers, &reservedCaller{name: name, time: -1})
+	return len(r.callers) - 1, nil
+}
+
+// GenerateFor 使用调用方的序号空间生成id
+func (idGen *IDGenerator) GenerateFor(caller int) (int64, error) {
+	idGen.mutex.Lock()
+	defer idGen.mutex.Unlock()
+
+	if r := idGen.reservation; r == nil || caller < 0 || caller >= len(r.callers) {
+		return 0, errorf(CodeCallerUnknown, caller)
+	}
+	return idGen.generateCallerLocked(caller)
+}
+
+// CallerExhaustions 各调用方序号用完等待的次数，默认调用方的名称为空字符串
+func (idGen *IDGenerator) CallerExhaustions() map[string]int64 {
+	idGen.mutex.Lock()
+	defer idGen.mutex.Unlock()
+
+	result := make(map[string]int64)
+	if idGen.reservation != nil {
+		for _, c := range idGen.reservation.callers {
+			result[c.name] = c.exhausted
+		}
+	}
+	return result
+}
+
+// nextSeq 推进调用方的序号，返回推进后的时间(序号用完时等待下一个时间单位)，调用方须持有锁
+func (r *seqReservation) nextSeq(idGen *IDGenerator, caller int, curTime int64) (int64, error) {
+	c := r.callers[caller]
+	if c.timeline == idGen.curTimeline && c.time == curTime {
+		if idGen.nextSeqExhausted(&c.seq, r.mask) {
+			c.exhausted++
+			var err error
+			if curTime, err = idGen.waitNextTime(curTime); err != nil {
+				c.seq = idGen.seqLimit(r.mask)
+				return 0, err
+			}
+		}
+	} else {
+		c.seq = 0
+	}
+	c.timeline, c.time = idGen.curTimeline, curTime
+	idGen.seq = int64(caller)<<r.shift | c.seq
+	return curTime, nil
+}
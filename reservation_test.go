@@ -0,0 +1,79 @@
+package generator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jayecc/mtl-snowflake/fakeclock"
+)
+
+// TestSeqReservation 按调用方划分序号空间
+func TestSeqReservation(t *testing.T) {
+	//时钟不前进，调用方a每个时间单位的256个序号必然用完，不依赖生成速度
+	settings := *DefaultSettings
+	settings.Clock = fakeclock.New(time.Now())
+	idGen, _ := NewGeneratorWithSettings(1, settings)
+	if err := idGen.SetSeqReservation(4); err != nil {
+		t.Fatal(err.Error())
+	}
+	a, _ := idGen.RegisterCaller("a")
+	b, _ := idGen.RegisterCaller("b")
+	if again, _ := idGen.RegisterCaller("a"); again != a {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "重复登记", again, a)
+	}
+
+	seen := make(map[int64]bool)
+	check := func(name string, caller int, id int64, err error) {
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if seen[id] {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", name, id, "不重复的id")
+		}
+		seen[id] = true
+		if got := idGen.Decompose(id).Seq >> 8; got != int64(caller) {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", name, got, caller)
+		}
+	}
+	for i := 0; i < 256*3; i++ {
+		id, err := idGen.GenerateFor(a)
+		check("调用方a", a, id, err)
+		if i%8 == 0 {
+			id, err = idGen.GenerateFor(b)
+			check("调用方b", b, id, err)
+			generated, err := idGen.Generate()
+			check("默认调用方", defaultCaller, int64(generated), err)
+		}
+	}
+
+	exhaustions := idGen.CallerExhaustions()
+	if exhaustions["a"] < 2 || exhaustions["b"] != 0 || exhaustions[""] != 0 {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "序号用完统计", exhaustions, "仅调用方a用完")
+	}
+
+	testCases := []struct {
+		name string
+		err  error
+	}{
+		{name: "调用方未登记", err: func() error { _, err := idGen.GenerateFor(99); return err }()},
+		{name: "不能同时开启填充", err: idGen.SetPadding([]byte("k"), 0.1)},
+		{name: "callerBits过大", err: idGen.SetSeqReservation(12)},
+	}
+	for _, tc := range testCases {
+		if tc.err == nil {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, tc.err, "error")
+		}
+	}
+
+	//关闭后恢复整个序号空间
+	if err := idGen.SetSeqReservation(0); err != nil {
+		t.Fatal(err.Error())
+	}
+	for i := 0; i < 1000; i++ {
+		id, _ := idGen.Generate()
+		if seen[int64(id)] {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", "关闭后", id, "不重复的id")
+		}
+		seen[int64(id)] = true
+	}
+}
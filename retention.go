@@ -0,0 +1,55 @@
+package generator
+
+import "time"
+
+// CutoffID 保留期截止id：小于该值的id均生成于olderThan之前，用于按保留期删除数据，如：
+//
+//	DELETE FROM orders WHERE id < cutoff
+//
+// 当前时间取自生成器的时间源(Settings.Clock、SetClock)，与生成的id使用同一时钟
+func (idGen *IDGenerator) CutoffID(olderThan time.Duration) int64 {
+	idGen.mutex.Lock()
+	now := idGen.now()
+	idGen.mutex.Unlock()
+	return idGen.CutoffIDAt(now.Add(-olderThan))
+}
+
+// CutoffIDAt 小于该值的id均生成于t之前
+//   - 时间线位于时间之上(TimelineAboveTime)时，小于该值的id只包含时间线0的id，其他时间线的id须按时间线分别处理
+func (idGen *IDGenerator) CutoffIDAt(t time.Time) int64 {
+	presets := idGen.settings.presets
+	timePart := idGen.toOffsetTime(t.UnixNano())
+	if t.UnixNano() < idGen.settings.Epoch {
+		return 0
+	}
+	if timePart > presets.maxTime {
+		return presets.version | presets.maxTime<<presets.shiftTimeBit | (1<<presets.shiftTimeBit - 1)
+	}
+	return presets.version | timePart<<presets.shiftTimeBit
+}
+
+// MinIDForTime t所在时间单位可能生成的最小id
+//   - t早于基准时间时返回0，超出时间位数所能表示的范围时返回最大的id
+//   - 时间线位于时间之上(TimelineAboveTime)时只包含时间线0的id，其他时间线的id须按时间线分别处理
+//
+// 与MaxIDForTime配合将时间范围转换为id范围，如：
+//
+//	SELECT * FROM orders WHERE id BETWEEN MinIDForTime(from) AND MaxIDForTime(to)
+func (idGen *IDGenerator) MinIDForTime(t time.Time) int64 {
+	return idGen.CutoffIDAt(t)
+}
+
+// MaxIDForTime t所在时间单位可能生成的最大id
+//   - t早于基准时间时返回-1(id范围为空)，超出时间位数所能表示的范围时返回最大的id
+//   - 时间线位于时间之上(TimelineAboveTime)时只包含时间线0的id
+func (idGen *IDGenerator) MaxIDForTime(t time.Time) int64 {
+	presets := idGen.settings.presets
+	if t.UnixNano() < idGen.settings.Epoch {
+		return -1
+	}
+	timePart := idGen.toOffsetTime(t.UnixNano())
+	if timePart > presets.maxTime {
+		timePart = presets.maxTime
+	}
+	return presets.version | timePart<<presets.shiftTimeBit | (1<<presets.shiftTimeBit - 1)
+}
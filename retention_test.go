@@ -0,0 +1,80 @@
+package generator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jayecc/mtl-snowflake/fakeclock"
+)
+
+// TestCutoffID 保留期截止id
+func TestCutoffID(t *testing.T) {
+	idGen, _ := NewGenerator(511)
+
+	old, _ := idGen.Generate()
+	time.Sleep(5 * time.Millisecond)
+	cutoff := idGen.CutoffID(2 * time.Millisecond)
+	fresh, _ := idGen.Generate()
+
+	if int64(old) >= cutoff {
+		t.Fatalf("【失败】-过期id应小于截止id-id:%d-cutoff:%d", old, cutoff)
+	}
+	if int64(fresh) < cutoff {
+		t.Fatalf("【失败】-未过期id不应小于截止id-id:%d-cutoff:%d", fresh, cutoff)
+	}
+
+	if got := idGen.CutoffIDAt(time.Unix(0, DefaultEpoch).Add(-time.Hour)); got != 0 {
+		t.Fatalf("【失败】-早于基准时间的截止id应为0-got:%d", got)
+	}
+	if got := idGen.CutoffIDAt(time.Unix(0, DefaultEpoch).AddDate(100, 0, 0)); got <= int64(fresh) {
+		t.Fatalf("【失败】-超出时间范围的截止id应为最大id-got:%d", got)
+	}
+
+	//使用生成器的时间源而不是系统时钟
+	clock := fakeclock.New(time.Now().Add(-time.Hour))
+	settings := *DefaultSettings
+	settings.Clock = clock
+	idGen, _ = NewGeneratorWithSettings(1, settings)
+	old, _ = idGen.Generate()
+	clock.Advance(time.Minute)
+	fresh, _ = idGen.Generate()
+	if cutoff := idGen.CutoffID(30 * time.Second); int64(old) >= cutoff || int64(fresh) < cutoff {
+		t.Fatalf("【失败】-%s-got:%v-want:(%v,%v]", "假时钟", cutoff, old, fresh)
+	}
+}
+
+// TestIDForTime 时间范围转换为id范围
+func TestIDForTime(t *testing.T) {
+	idGen, _ := NewGenerator(511)
+	id, _ := idGen.Generate()
+	at := idGen.Time(int64(id))
+	epoch := time.Unix(0, DefaultEpoch)
+	maxID := idGen.MaxIDForTime(epoch.AddDate(100, 0, 0))
+
+	testCases := []struct {
+		name    string
+		from    time.Time
+		to      time.Time
+		wantIn  bool
+		wantMin int64
+		wantMax int64
+	}{
+		{name: "id所在时间单位", from: at, to: at.Add(999 * time.Microsecond), wantIn: true},
+		{name: "包含id的范围", from: at.Add(-time.Hour), to: at.Add(time.Hour), wantIn: true},
+		{name: "之前的范围", from: at.Add(-time.Hour), to: at.Add(-time.Millisecond), wantIn: false},
+		{name: "之后的范围", from: at.Add(time.Millisecond), to: at.Add(time.Hour), wantIn: false},
+		{name: "早于基准时间", from: epoch.Add(-time.Hour), to: epoch.Add(-time.Second), wantIn: false, wantMin: 0, wantMax: -1},
+		{name: "超出时间范围", from: epoch.AddDate(100, 0, 0), to: epoch.AddDate(200, 0, 0), wantIn: false, wantMin: maxID, wantMax: maxID},
+	}
+	for _, tc := range testCases {
+		min, max := idGen.MinIDForTime(tc.from), idGen.MaxIDForTime(tc.to)
+		if in := int64(id) >= min && int64(id) <= max; in != tc.wantIn {
+			t.Fatalf("【失败】-%s-got:[%d,%d]-id:%d", tc.name, min, max, id)
+		}
+		if tc.wantMin != 0 || tc.wantMax != 0 {
+			if min != tc.wantMin || max != tc.wantMax {
+				t.Fatalf("【失败】-%s-got:[%d,%d]-want:[%d,%d]", tc.name, min, max, tc.wantMin, tc.wantMax)
+			}
+		}
+	}
+}
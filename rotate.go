@@ -0,0 +1,53 @@
+package generator
+
+// RotateMachineID 运行时切换机器ID，返回切换前的机器ID
+//   - 切换前等待当前时间单位结束，确保旧机器ID释放后被其他节点重新分配时不会生成相同的id
+//   - 切换期间(最多一个时间单位)Generate会被阻塞
+//   - 返回后调用方应将旧机器ID归还给分配方，使用machineid包的分配器时可通过machineid.Rotate完成申请、切换、释放
+//   - 切换成功后回调Hooks.OnMachineIDRotate
+//   - 生成器因机器ID租约丢失失效(Fence)时，切换到新的机器ID后恢复
+//   - 等待当前时间单位结束失败(如Timer返回错误)时不切换，返回该错误
+func (idGen *IDGenerator) RotateMachineID(newID int64) (int64, error) {
+	idGen.mutex.Lock()
+	defer idGen.mutex.Unlock()
+
+	//时间部分用尽切换基准时间时会替换presets，须在锁内读取
+	if maxMachineID := idGen.settings.presets.maxMachineID; newID < 0 || newID > maxMachineID {
+		return 0, &MachineIDError{MachineID: newID, Max: maxMachineID}
+	}
+
+	oldID := idGen.machineID
+	if idGen.closed {
+		return oldID, ErrGeneratorClosed
+	}
+	if newID == oldID {
+		return oldID, nil
+	}
+	if idGen.shardSeqMax > 0 {
+		return oldID, errPoolShard
+	}
+
+	if err := idGen.drainLatestTime(); err != nil {
+		return oldID, err
+	}
+	idGen.machineID = newID
+	idGen.seq = 0
+	idGen.fenced = nil
+	if idGen.metrics != nil {
+		idGen.metrics.Counter(MetricMachineIDRotation, 1)
+	}
+	idGen.hookMachineIDRotate(oldID, newID)
+	return oldID, nil
+}
+
+// drainLatestTime 等待已使用的最新时间单位结束，调用方须持有锁
+func (idGen *IDGenerator) drainLatestTime() error {
+	var latest int64
+	for _, progress := range idGen.timelineProgress {
+		if progress > latest {
+			latest = progress
+		}
+	}
+	_, err := idGen.waitUntil(latest + 1)
+	return err
+}
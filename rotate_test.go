@@ -0,0 +1,77 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jayecc/mtl-snowflake/fakeclock"
+)
+
+// TestRotateMachineID 运行时切换机器ID
+func TestRotateMachineID(t *testing.T) {
+	idGen, _ := NewGenerator(1)
+	before, _ := idGen.Generate()
+
+	oldID, err := idGen.RotateMachineID(2)
+	if err != nil || oldID != 1 {
+		t.Fatalf("【失败】-切换机器ID-old:%d-err:%v", oldID, err)
+	}
+	after, _ := idGen.Generate()
+
+	if idGen.GetMachineID() != 2 || idGen.Decompose(int64(after)).MachineID != 2 {
+		t.Fatalf("【失败】-切换后应使用新机器ID")
+	}
+	if idGen.Decompose(int64(after)).Time <= idGen.Decompose(int64(before)).Time {
+		t.Fatalf("【失败】-切换前应等待当前时间单位结束")
+	}
+
+	if _, err := idGen.RotateMachineID(512); err == nil {
+		t.Fatalf("【失败】-机器ID超限应返回错误")
+	}
+}
+
+// TestRotateMachineIDHook 切换机器ID回调
+func TestRotateMachineIDHook(t *testing.T) {
+	idGen, _ := NewGenerator(1)
+	rotated := make(chan [2]int64, 1)
+	idGen.SetHooks(&Hooks{OnMachineIDRotate: func(from, to int64) { rotated <- [2]int64{from, to} }})
+	idGen.RotateMachineID(3)
+
+	select {
+	case got := <-rotated:
+		if got != [2]int64{1, 3} {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", "切换机器ID回调", got, [2]int64{1, 3})
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "切换机器ID回调", "未回调", [2]int64{1, 3})
+	}
+}
+
+// failingTimer 等待总是失败的Timer
+type failingTimer struct{ err error }
+
+func (t failingTimer) Sleep(ctx context.Context, d time.Duration) error { return t.err }
+
+func (failingTimer) Yield() {}
+
+// TestRotateMachineIDWaitError 等待当前时间单位结束失败时不切换机器ID
+func TestRotateMachineIDWaitError(t *testing.T) {
+	errSleep := errors.New("sleep failed")
+	settings := *DefaultSettings
+	settings.Clock = fakeclock.New(time.Now()) //时钟不走动，切换须等待
+	settings.Timer = failingTimer{err: errSleep}
+	settings.WaitPolicy = WaitSleep
+	idGen, _ := NewGeneratorWithSettings(1, settings)
+	if _, err := idGen.Generate(); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if oldID, err := idGen.RotateMachineID(2); err != errSleep || oldID != 1 {
+		t.Fatalf("【失败】-%s-got:%v(%v)-want:%v", "等待失败", oldID, err, errSleep)
+	}
+	if got := idGen.GetMachineID(); got != 1 {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "机器ID不变", got, 1)
+	}
+}
@@ -0,0 +1,74 @@
+package generator
+
+import (
+	"time"
+)
+
+const (
+	selfTestSamples     = 3                    //自检生成的id数
+	selfTestInterval    = time.Millisecond     //相邻两次生成的间隔
+	selfTestMinLifetime = 365 * 24 * time.Hour //时间部分剩余可用时长的下限
+)
+
+// SelfTest 自检：生成几个id并逐一校验，发现配置错误时返回说明原因的错误
+//   - Epoch距1970年不足一年(通常是误用了秒或毫秒)，或时间部分剩余可用时长不足一年
+//   - 相邻两次生成之间时钟未走动
+//   - id解析出的机器ID、时间与当前配置及时钟不符，或按配置重新组合后与id不一致
+//   - v2可读格式无法还原id，时间有序的结构下id未递增
+//
+// 设置Settings.SelfTest时在创建生成器时执行；也可在运行中调用，生成的id直接丢弃
+func (idGen *IDGenerator) SelfTest() error {
+	idGen.mutex.Lock()
+	defer idGen.mutex.Unlock()
+
+	settings := idGen.settings
+	if settings.Epoch > 0 && settings.Epoch < int64(365*24*time.Hour) {
+		return errorf(CodeSelfTestEpoch, settings.Epoch)
+	}
+	end := time.Unix(0, idGen.exhaustionNano())
+	if remaining := end.Sub(idGen.now()); remaining < selfTestMinLifetime {
+		return errorf(CodeSelfTestTimeRange, end.UTC().Format(time.RFC3339), remaining)
+	}
+
+	var lastID, lastNow int64
+	for i := 0; i < selfTestSamples; i++ {
+		if i > 0 {
+			if err := idGen.sleep(selfTestInterval); err != nil {
+				return err
+			}
+		}
+		before := idGen.now().UnixNano()
+		if i > 0 && before <= lastNow {
+			return errorf(CodeSelfTestClockStuck, selfTestInterval, lastNow, before)
+		}
+		id, err := idGen.generateLocked()
+		if err != nil {
+			return errorf(CodeSelfTestGenerate, err)
+		}
+		after := idGen.now().UnixNano()
+
+		c := idGen.Decompose(id)
+		if c.MachineID != idGen.machineID {
+			return errorf(CodeSelfTestMachineID, id, c.MachineID, idGen.machineID)
+		}
+		if c.DatacenterID != settings.DatacenterID {
+			return errorf(CodeSelfTestDatacenter, id, c.DatacenterID, settings.DatacenterID)
+		}
+		if got := idGen.compose(c.Time, c.TimeLine, c.Seq); got != id {
+			return errorf(CodeSelfTestRecompose, id, got)
+		}
+		if c.Time < idGen.toOffsetTime(before) || c.Time > idGen.toOffsetTime(after) {
+			return errorf(CodeSelfTestTime,
+				id, time.Unix(0, idGen.toUnixNano(c.Time)).UTC().Format(time.RFC3339Nano), time.Unix(0, before).UTC().Format(time.RFC3339Nano))
+		}
+		readable := idGen.ToReadableV2(id)
+		if got, err := idGen.ParseReadableV2(readable); err != nil || got != id {
+			return errorf(CodeSelfTestReadable, id, readable, got, err)
+		}
+		if i > 0 && settings.timeOrdered() && id <= lastID {
+			return errorf(CodeSelfTestNotIncreasing, lastID, id)
+		}
+		lastID, lastNow = id, before
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jayecc/mtl-snowflake/fakeclock"
+)
+
+// frozenClock 不走动的时钟
+type frozenClock int64
+
+func (c frozenClock) Now() int64 { return int64(c) }
+
+// TestSelfTest 创建时自检
+func TestSelfTest(t *testing.T) {
+	now := time.Now()
+	testCases := []struct {
+		name     string
+		settings Settings
+		wantErr  string //错误信息包含的内容，空表示自检通过
+	}{
+		{name: "默认配置", settings: *DefaultSettings},
+		{name: "秒级时间单位", settings: *SecondSettings},
+		{name: "假时钟", settings: Settings{TimeBit: 41, MachineIDBit: 9, TimelineBit: 1, SeqBit: 12, Epoch: DefaultEpoch, Clock: fakeclock.New(now)}},
+		{name: "Epoch误用秒", settings: Settings{TimeBit: 41, MachineIDBit: 9, TimelineBit: 1, SeqBit: 12, Epoch: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC).Unix()}, wantErr: "Epoch"},
+		{name: "剩余可用时长不足", settings: Settings{TimeBit: 30, MachineIDBit: 10, TimelineBit: 1, SeqBit: 22, Epoch: now.Add(-24 * time.Hour).UnixNano()}, wantErr: "runs out"},
+		{name: "时钟未走动", settings: Settings{TimeBit: 41, MachineIDBit: 9, TimelineBit: 1, SeqBit: 12, Epoch: DefaultEpoch, Clock: frozenClock(now.UnixNano())}, wantErr: "clock is not moving"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.settings.SelfTest = true
+			_, err := NewGeneratorWithSettings(1, tc.settings)
+			if tc.wantErr == "" && err != nil || tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)) {
+				t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package generator
+
+import (
+	cryptorand "crypto/rand"
+	"encoding/binary"
+	"math/rand"
+)
+
+// seqRandom 序号随机化：每个时间单位的起始序号及序号增量随机
+type seqRandom struct {
+	maxOffset int64 //起始序号的最大值
+	maxStep   int64 //序号增量的最大值
+	rnd       *rand.Rand
+}
+
+// SetSeqRandomization 设置序号随机化，maxOffset、maxStep均为0表示关闭
+//   - 每个时间单位的起始序号随机取0-maxOffset，此后每次生成序号随机增加1-maxStep(maxStep为0或1时增加1)，
+//     外部无法根据相邻公开id的序号推算出每个时间单位准确的发放数
+//   - 同一时间单位内序号仍严格递增，不会重复；代价是每个时间单位的有效容量降低，可通过EffectiveSeqCapacity查看
+//   - 不能与序号填充(SetPadding)、序号空间划分(SetSeqReservation)同时使用；开启后不使用无锁快速路径
+func (idGen *IDGenerator) SetSeqRandomization(maxOffset, maxStep int) error {
+	maxSeq := idGen.settings.presets.maxSeq
+	if maxOffset < 0 || int64(maxOffset) > maxSeq {
+		return errorf(CodeMaxOffsetRange, maxSeq)
+	}
+	if maxStep < 0 || int64(maxStep) > maxSeq+1 {
+		return errorf(CodeMaxStepRange, maxSeq+1)
+	}
+
+	idGen.mutex.Lock()
+	defer idGen.mutex.Unlock()
+	if maxOffset == 0 && maxStep <= 1 {
+		idGen.seqRandom = nil
+		return nil
+	}
+	if idGen.padding != nil {
+		return errorf(CodePaddingRandom)
+	}
+	if idGen.reservation != nil {
+		return errorf(CodeRandomReservation)
+	}
+	//使用crypto/rand作为种子，同时启动的实例不会产生相同的随机序列
+	var seed [8]byte
+	if _, err := cryptorand.Read(seed[:]); err != nil {
+		return err
+	}
+	if maxStep == 0 {
+		maxStep = 1
+	}
+	idGen.seqRandom = &seqRandom{
+		maxOffset: int64(maxOffset),
+		maxStep:   int64(maxStep),
+		rnd:       rand.New(rand.NewSource(int64(binary.BigEndian.Uint64(seed[:])))),
+	}
+	return nil
+}
+
+// firstSeq 时间单位的起始序号，不超过允许使用的最大序号，调用方须持有锁
+func (idGen *IDGenerator) firstSeq() int64 {
+	r := idGen.seqRandom
+	if r == nil {
+		return 0
+	}
+	maxOffset := r.maxOffset
+	if limit := idGen.seqLimit(idGen.settings.presets.maxSeq); limit < maxOffset {
+		maxOffset = limit
+	}
+	return r.rnd.Int63n(maxOffset + 1)
+}
+
+// seqStep 序号增量，调用方须持有锁
+func (idGen *IDGenerator) seqStep() int64 {
+	r := idGen.seqRandom
+	if r == nil || r.maxStep == 1 {
+		return 1
+	}
+	return 1 + r.rnd.Int63n(r.maxStep)
+}
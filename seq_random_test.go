@@ -0,0 +1,77 @@
+package generator
+
+import (
+	"math"
+	"testing"
+)
+
+// TestSeqRandomization 序号随机化
+func TestSeqRandomization(t *testing.T) {
+	idGen, _ := NewGenerator(5)
+	if err := idGen.SetSeqRandomization(100, 8); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	const n = 20000
+	prevSeq := make(map[int64]int64) //时间单位 -> 上一个序号
+	var prev ID
+	var randomStart int
+	for i := 0; i < n; i++ {
+		id, err := idGen.Generate()
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if id <= prev {
+			t.Fatalf("【失败】-%s-got:%v-want:>%v", "趋势递增", id, prev)
+		}
+		prev = id
+		compose := idGen.Decompose(int64(id))
+		last, exist := prevSeq[compose.Time]
+		switch {
+		case !exist && compose.Seq > 100:
+			t.Fatalf("【失败】-%s-got:%v-want:<=%v", "起始序号", compose.Seq, 100)
+		case !exist && compose.Seq > 0:
+			randomStart++
+		case exist && (compose.Seq-last < 1 || compose.Seq-last > 8):
+			t.Fatalf("【失败】-%s-got:%v-want:1-8", "序号增量", compose.Seq-last)
+		}
+		prevSeq[compose.Time] = compose.Seq
+	}
+	if randomStart < len(prevSeq)/2 {
+		t.Fatalf("【失败】-%s-got:%v-want:>=%v", "随机起始序号", randomStart, len(prevSeq)/2)
+	}
+	if got, want := idGen.EffectiveSeqCapacity(), (4096-50)/4.5; math.Abs(got-want) > 1e-6 {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "有效容量", got, want)
+	}
+	if _, err := idGen.GenerateN(100); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if err := idGen.SetPadding([]byte("secret"), 0.1); err == nil {
+		t.Fatalf("【失败】-%s-got:%v-want:%s", "与序号填充同时使用", err, "错误")
+	}
+	if err := idGen.SetSeqReservation(2); err == nil {
+		t.Fatalf("【失败】-%s-got:%v-want:%s", "与序号空间划分同时使用", err, "错误")
+	}
+
+	testCases := []struct {
+		name      string
+		maxOffset int
+		maxStep   int
+		wantErr   bool
+	}{
+		{name: "起始序号过大", maxOffset: 4096, maxStep: 0, wantErr: true},
+		{name: "增量过大", maxOffset: 0, maxStep: 4097, wantErr: true},
+		{name: "起始序号为负", maxOffset: -1, maxStep: 0, wantErr: true},
+		{name: "只随机增量", maxOffset: 0, maxStep: 4, wantErr: false},
+		{name: "关闭", maxOffset: 0, maxStep: 0, wantErr: false},
+	}
+	for _, tc := range testCases {
+		if err := idGen.SetSeqRandomization(tc.maxOffset, tc.maxStep); (err != nil) != tc.wantErr {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, err, tc.wantErr)
+		}
+	}
+	if err := idGen.SetPadding([]byte("secret"), 0.1); err != nil {
+		t.Fatalf("【失败】-%s-%v", "关闭后开启序号填充", err)
+	}
+}
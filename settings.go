@@ -1,8 +1,8 @@
 package generator
 
 import (
-	"errors"
 	"fmt"
+	"math"
 	"time"
 )
 
@@ -11,8 +11,7 @@ const (
 	defaultMachineIDBit uint64 = 9                                                              //实例ID位数(512实例)
 	defaultTimelineBit  uint64 = 1                                                              //时间线位数,处理时钟回退
 	defaultSeqBit       uint64 = 63 - defaultTimeBit - defaultMachineIDBit - defaultTimelineBit //序号位数
-	timeUnit            uint64 = 1e6                                                            //时间单位(1e6相当于ms)
-	maxWaitTime         int64  = 1                                                              //当时间出现小幅回退时(这里设置为1时间单位)，等待时间递进到回退前时间再继续
+	timeUnit            uint64 = 1e6                                                            //默认时间单位(1e6相当于ms)
 )
 
 var (
@@ -20,12 +19,113 @@ var (
 )
 
 type Settings struct {
-	TimeBit      uint64   //时间位长度
-	MachineIDBit uint64   //实例ID位长度
-	TimelineBit  uint64   //时间线位长度
-	SeqBit       uint64   //序号位长度
-	Epoch        int64    //时间位的基准时间(unix nano)
-	presets      *presets //预先计算的参数
+	TimeBit         uint64         //时间位长度
+	MachineIDBit    uint64         //实例ID位长度
+	DatacenterBit   uint64         //数据中心位长度，0表示不划分数据中心；数据中心位紧邻机器ID之上，各数据中心独立分配机器ID
+	DatacenterID    int64          //数据中心ID，须介于0-2^DatacenterBit-1之间
+	TimelineBit     uint64         //时间线位长度
+	SeqBit          uint64         //序号位长度
+	Epoch           int64          //时间位的基准时间(unix nano)
+	TimeUnit        time.Duration  //时间单位，0表示毫秒，须为1微秒的整数倍且能整除1秒(如1µs、100µs、1ms、10ms、1s)
+	Placement       Placement      //时间线位置，默认位于机器ID之下
+	FlagBit         bool           //是否在最低位预留1位标记位，供应用通过WithFlag/HasFlag标记派生的key
+	Clock           Clock          //时间源，nil表示系统时钟，也可通过SetClock设置
+	Timer           Timer          //内部等待的实现，nil表示缺省，也可通过SetTimer设置
+	SelfTest        bool           //创建生成器时自检(SelfTest)，配置错误时创建失败
+	WaitPolicy      WaitPolicy     //序号用完、时钟回退时的等待方式，也可通过SetWaitPolicy设置
+	MaxBackwardWait time.Duration  //时钟小幅回退时等待时钟追回的上限，0表示直接切换时间线，也可通过SetMaxBackwardWait设置
+	BackwardPolicy  BackwardPolicy //时钟回退策略，nil表示缺省(小幅回退等待，否则切换时间线)，也可通过SetBackwardPolicy设置
+	StatePersister  StatePersister //时间线状态的存储，创建生成器时恢复、运行时定期保存，nil表示不持久化
+	PersistInterval time.Duration  //时间线状态的保存间隔，0表示1秒
+	VersionBit      uint64         //版本位长度，0表示不使用；版本位位于最高位(符号位之下)，用于在系统生命周期内迁移id结构，见LayoutRegistry
+	Version         int64          //id结构的版本号，须介于0-2^VersionBit-1之间；版本号更大的id总是大于旧版本的id
+	presets         *presets       //预先计算的参数
+}
+
+// Placement 时间线在id结构中的位置，影响时间线切换前后id的排序
+type Placement int
+
+const (
+	TimelineBelowMachine Placement = iota //时间|机器ID|时间线|序号(默认)：同一时间单位内同一机器的id连续
+	TimelineAboveMachine                  //时间|时间线|机器ID|序号：同一时间单位内先按时间线再按机器ID排序
+	TimelineAboveTime                     //时间线|时间|机器ID|序号：切换到更大的时间线后id继续递增，但id不再按时间排序
+)
+
+// String 时间线位置名称
+func (p Placement) String() string {
+	switch p {
+	case TimelineBelowMachine:
+		return "below-machine"
+	case TimelineAboveMachine:
+		return "above-machine"
+	case TimelineAboveTime:
+		return "above-time"
+	}
+	return fmt.Sprintf("Placement(%d)", int(p))
+}
+
+// ParsePlacement 解析时间线位置名称：below-machine、above-machine、above-time
+func ParsePlacement(name string) (Placement, error) {
+	for p := TimelineBelowMachine; p <= TimelineAboveTime; p++ {
+		if p.String() == name {
+			return p, nil
+		}
+	}
+	return 0, errorf(CodeUnknownPlacement, name)
+}
+
+// Bits 各部分位数之和(含版本位、数据中心位、标记位)，合法的id结构为63
+func (settings *Settings) Bits() uint64 {
+	bits := settings.VersionBit + settings.TimeBit + settings.DatacenterBit + settings.MachineIDBit + settings.TimelineBit + settings.SeqBit
+	if settings.FlagBit {
+		bits++
+	}
+	return bits
+}
+
+// checkBits 各部分位数之和(含版本位、数据中心位、标记位)须为63
+func (settings *Settings) checkBits() error {
+	if settings.Bits() == 63 {
+		return nil
+	}
+	if settings.FlagBit {
+		return errorf(CodeBitsSumFlag)
+	}
+	return errorf(CodeBitsSum)
+}
+
+// checkLayout 检查id结构(各部分位数、时间单位、时间线位置)，不检查基准时间与当前时间
+func (settings *Settings) checkLayout() error {
+	if err := settings.checkBits(); err != nil {
+		return err
+	}
+	if err := checkTimeUnit(settings.TimeUnit); err != nil {
+		return err
+	}
+	if settings.Placement < TimelineBelowMachine || settings.Placement > TimelineAboveTime {
+		return errorf(CodePlacementUnsupported)
+	}
+	return settings.checkVersion()
+}
+
+// checkVersion 版本号须介于0-2^VersionBit-1之间
+func (settings *Settings) checkVersion() error {
+	maxVersion := int64(1)<<settings.VersionBit - 1
+	if settings.Version < 0 || settings.Version > maxVersion {
+		return errorf(CodeVersionRange, maxVersion, settings.Version)
+	}
+	return nil
+}
+
+// errNotTimeOrdered 依赖id按时间排序的功能不支持时间线位于时间之上的结构
+var errNotTimeOrdered = newError(CodeNotTimeOrdered)
+
+// errVersionMismatch id的版本号与当前配置不同，须使用对应版本的配置(见LayoutRegistry)
+var errVersionMismatch = newError(CodeVersionMismatch)
+
+// timeOrdered id是否按时间排序(时间位于时间线之上)
+func (settings *Settings) timeOrdered() bool {
+	return settings.Placement != TimelineAboveTime
 }
 
 // presets 预先计算的参数
@@ -33,6 +133,12 @@ type presets struct {
 	shiftTimeBit, shiftMachineIDBit, shiftTimelineBit, shiftSeq uint64
 	maskTime, maskMachineID, maskTimeline, maskSeq              int64
 	maxTime, maxMachineID, maxTimeline, maxSeq                  int64
+	shiftDatacenterBit                                          uint64
+	maskDatacenter, maxDatacenter                               int64
+	datacenter                                                  int64 //数据中心ID移位后的值，组合id时置位
+	shiftVersionBit                                             uint64
+	maskVersion                                                 int64
+	version                                                     int64 //版本号移位后的值，组合id时置位
 }
 
 var DefaultSettings = &Settings{
@@ -43,50 +149,161 @@ var DefaultSettings = &Settings{
 	Epoch:        DefaultEpoch,
 }
 
+// SecondSettings 秒级精度的长周期配置，适用于更看重使用年限而非毫秒级有序的场景(如物联网平台)
+//   - TimeBit=32 可使用136年
+//   - MachineIDBit=10 最多1024个节点
+//   - TimelineBit=1  两条时间线
+//   - SeqBit=20 1秒内最多生成1048576个序号
+//   - TimeUnit=1s
+var SecondSettings = &Settings{
+	TimeBit:      32,
+	MachineIDBit: 10,
+	TimelineBit:  1,
+	SeqBit:       20,
+	Epoch:        DefaultEpoch,
+	TimeUnit:     time.Second,
+}
+
+// CentisecondSettings 10毫秒时间单位的长周期配置(与Sonyflake相同的时间精度)，以毫秒级有序换取更长的使用年限
+//   - TimeBit=39 可使用174年
+//   - MachineIDBit=15 最多32768个节点
+//   - TimelineBit=1  两条时间线
+//   - SeqBit=8 10毫秒内最多生成256个序号(每秒25600个)
+//   - TimeUnit=10ms
+var CentisecondSettings = &Settings{
+	TimeBit:      39,
+	MachineIDBit: 15,
+	TimelineBit:  1,
+	SeqBit:       8,
+	Epoch:        DefaultEpoch,
+	TimeUnit:     10 * time.Millisecond,
+}
+
+// checkTimeUnit 检查时间单位：须为1微秒的整数倍且能整除1秒，可读格式按时间单位输出秒以下部分
+func checkTimeUnit(unit time.Duration) error {
+	if unit == 0 {
+		return nil
+	}
+	if unit < time.Microsecond || unit > time.Second || unit%time.Microsecond != 0 || time.Second%unit != 0 {
+		return errorf(CodeTimeUnit, unit)
+	}
+	return nil
+}
+
+// unit 时间单位(ns)
+func (settings *Settings) unit() int64 {
+	if settings.TimeUnit == 0 {
+		return int64(timeUnit)
+	}
+	return int64(settings.TimeUnit)
+}
+
 // calcPresets 计算预置参数
 func calcPresets(settings *Settings) *presets {
 	curPresets := new(presets)
 
 	//移位位数
 	curPresets.shiftSeq = 0
-	curPresets.shiftTimelineBit = curPresets.shiftSeq + settings.SeqBit
-	curPresets.shiftMachineIDBit = curPresets.shiftTimelineBit + settings.TimelineBit
-	curPresets.shiftTimeBit = curPresets.shiftMachineIDBit + settings.MachineIDBit
+	if settings.FlagBit {
+		curPresets.shiftSeq = 1
+	}
+	//数据中心位紧邻机器ID之上，与机器ID作为一个整体排列
+	nodeBit := settings.DatacenterBit + settings.MachineIDBit
+	switch settings.Placement {
+	case TimelineAboveMachine:
+		curPresets.shiftMachineIDBit = curPresets.shiftSeq + settings.SeqBit
+		curPresets.shiftTimelineBit = curPresets.shiftMachineIDBit + nodeBit
+		curPresets.shiftTimeBit = curPresets.shiftTimelineBit + settings.TimelineBit
+	case TimelineAboveTime:
+		curPresets.shiftMachineIDBit = curPresets.shiftSeq + settings.SeqBit
+		curPresets.shiftTimeBit = curPresets.shiftMachineIDBit + nodeBit
+		curPresets.shiftTimelineBit = curPresets.shiftTimeBit + settings.TimeBit
+	default:
+		curPresets.shiftTimelineBit = curPresets.shiftSeq + settings.SeqBit
+		curPresets.shiftMachineIDBit = curPresets.shiftTimelineBit + settings.TimelineBit
+		curPresets.shiftTimeBit = curPresets.shiftMachineIDBit + nodeBit
+	}
+	curPresets.shiftDatacenterBit = curPresets.shiftMachineIDBit + settings.MachineIDBit
 
 	//最大值
 	curPresets.maxSeq = (1 << settings.SeqBit) - 1
 	curPresets.maxTimeline = (1 << settings.TimelineBit) - 1
 	curPresets.maxMachineID = (1 << settings.MachineIDBit) - 1
+	curPresets.maxDatacenter = (1 << settings.DatacenterBit) - 1
 	curPresets.maxTime = (1 << settings.TimeBit) - 1
 
 	//掩码
 	curPresets.maskSeq = ((1 << settings.SeqBit) - 1) << curPresets.shiftSeq
 	curPresets.maskTimeline = ((1 << settings.TimelineBit) - 1) << curPresets.shiftTimelineBit
 	curPresets.maskMachineID = ((1 << settings.MachineIDBit) - 1) << curPresets.shiftMachineIDBit
+	curPresets.maskDatacenter = ((1 << settings.DatacenterBit) - 1) << curPresets.shiftDatacenterBit
 	curPresets.maskTime = ((1 << settings.TimeBit) - 1) << curPresets.shiftTimeBit
+
+	curPresets.datacenter = settings.DatacenterID << curPresets.shiftDatacenterBit
+
+	//版本位总是位于最高位
+	curPresets.shiftVersionBit = 63 - settings.VersionBit
+	curPresets.maskVersion = ((1 << settings.VersionBit) - 1) << curPresets.shiftVersionBit
+	curPresets.version = settings.Version << curPresets.shiftVersionBit
 	return curPresets
 }
 
 // checkSettings 参数校验
 func checkSettings(settings *Settings, machineID int64) error {
-	if 63 != settings.TimeBit+settings.MachineIDBit+settings.TimelineBit+settings.SeqBit {
-		return errors.New("TimeBit+MachineIDBit+TimelineBit+SeqBit !=63")
+	if err := settings.checkBits(); err != nil {
+		return err
+	}
+
+	if err := checkTimeUnit(settings.TimeUnit); err != nil {
+		return err
+	}
+
+	if settings.WaitPolicy < WaitSleep || settings.WaitPolicy > WaitHybrid {
+		return errorf(CodeUnknownWaitPolicy)
+	}
+
+	if settings.MaxBackwardWait < 0 {
+		return errorf(CodeNegative, "MaxBackwardWait")
+	}
+
+	if settings.PersistInterval < 0 {
+		return errorf(CodeNegative, "PersistInterval")
+	}
+
+	if settings.Placement < TimelineBelowMachine || settings.Placement > TimelineAboveTime {
+		return errorf(CodePlacementUnsupported)
 	}
 
 	maxTime := int64((1 << settings.TimeBit) - 1)
-	curTime := (time.Now().UnixNano() - settings.Epoch) / int64(timeUnit)
+	if maxTime > (math.MaxInt64-settings.Epoch)/settings.unit() {
+		return errorf(CodeTimeBitsTooMany)
+	}
+	now := time.Now().UnixNano()
+	if settings.Clock != nil {
+		now = settings.Clock.Now()
+	}
+	curTime := (now - settings.Epoch) / settings.unit()
 
 	if curTime < 0 {
-		return errors.New("基准时间epoch须不晚于当前时间")
+		return errorf(CodeEpochInFuture)
 	}
 
 	if curTime > maxTime {
-		return errors.New("当前时间偏移量已超过最大限制，请设置更多的时间位数或设置一个更近的基准时间")
+		return ErrTimeOverflow
+	}
+
+	maxDatacenter := int64(1)<<settings.DatacenterBit - 1
+	if settings.DatacenterID < 0 || settings.DatacenterID > maxDatacenter {
+		return errorf(CodeDatacenterRange, maxDatacenter, settings.DatacenterID)
+	}
+
+	if err := settings.checkVersion(); err != nil {
+		return err
 	}
 
 	maxMachineID := (1 << settings.MachineIDBit) - 1
 	if machineID < 0 || machineID > int64(maxMachineID) {
-		return errors.New(fmt.Sprintf("machineID 必须介于0-%d(2^MachineIDBit-1)之间", maxMachineID))
+		return &MachineIDError{MachineID: machineID, Max: int64(maxMachineID)}
 	}
 	return nil
 }
@@ -0,0 +1,318 @@
+package generator
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// settingsKeys 配置文件及环境变量支持的配置项，环境变量名为前缀+配置项的大写形式(如SNOWFLAKE_TIME_BIT)
+var settingsKeys = []string{
+	"time_bit",
+	"datacenter_bit",
+	"datacenter_id",
+	"machine_id_bit",
+	"timeline_bit",
+	"seq_bit",
+	"epoch",              //RFC3339，如2020-01-01T00:00:00Z
+	"time_unit",          //time.ParseDuration格式，如1ms、10ms、1s
+	"timeline_placement", //below-machine、above-machine、above-time
+	"flag_bit",           //true/false
+	"version_bit",        //版本位长度，0表示不使用
+	"version",            //id结构的版本号，见LayoutRegistry
+	"machine_id",         //机器ID来源，见MachineIDSource
+	"fingerprint",        //期望的id结构指纹，配置时校验与Settings.Fingerprint一致
+}
+
+// MachineIDSource 配置中的机器ID来源
+//   - 数字：直接使用该机器ID，如"42"
+//   - "private-ip"：取本机私有IPv4地址的低MachineIDBit位(MachineIDFromPrivateIP)
+//   - "env:NAME"：读取环境变量NAME的值作为机器ID，如由部署系统注入的"env:NODE_ID"
+//   - 空：未配置，由调用方自行分配(如machineid包的分配器)
+type MachineIDSource string
+
+// MachineIDPrivateIP 取本机私有IPv4地址作为机器ID
+const MachineIDPrivateIP MachineIDSource = "private-ip"
+
+// check 检查来源格式，不读取环境变量和网卡
+func (source MachineIDSource) check() error {
+	s := string(source)
+	switch {
+	case source == "" || source == MachineIDPrivateIP:
+		return nil
+	case strings.HasPrefix(s, "env:"):
+		if strings.TrimPrefix(s, "env:") == "" {
+			return errorf(CodeMachineIDEnvName, s)
+		}
+		return nil
+	}
+	if _, err := strconv.ParseInt(s, 10, 64); err != nil {
+		return errorf(CodeMachineIDSource, s)
+	}
+	return nil
+}
+
+// Resolve 按来源得到机器ID并校验范围，bits为MachineIDBit
+func (source MachineIDSource) Resolve(bits uint64) (int64, error) {
+	if err := source.check(); err != nil {
+		return 0, err
+	}
+	s := string(source)
+	var (
+		machineID int64
+		err       error
+	)
+	switch {
+	case source == "":
+		return 0, errorf(CodeMachineIDSourceMissing)
+	case source == MachineIDPrivateIP:
+		if machineID, err = MachineIDFromPrivateIP(bits); err != nil {
+			return 0, err
+		}
+	case strings.HasPrefix(s, "env:"):
+		name := strings.TrimPrefix(s, "env:")
+		value := os.Getenv(name)
+		if value == "" {
+			return 0, errorf(CodeEnvEmpty, name)
+		}
+		if machineID, err = strconv.ParseInt(strings.TrimSpace(value), 10, 64); err != nil {
+			return 0, errorf(CodeEnvMachineID, name, value)
+		}
+	default:
+		machineID, _ = strconv.ParseInt(s, 10, 64)
+	}
+	maxMachineID := int64(1)<<bits - 1
+	if machineID < 0 || machineID > maxMachineID {
+		return 0, &MachineIDError{MachineID: machineID, Max: maxMachineID}
+	}
+	return machineID, nil
+}
+
+// SettingsConfig 由配置文件或环境变量加载的配置
+type SettingsConfig struct {
+	Settings  Settings        //id结构，未配置的项取DefaultSettings的值
+	MachineID MachineIDSource //机器ID来源
+}
+
+// NewGenerator 按机器ID来源得到机器ID并创建生成器
+func (config *SettingsConfig) NewGenerator() (*IDGenerator, error) {
+	machineID, err := config.MachineID.Resolve(config.Settings.MachineIDBit)
+	if err != nil {
+		return nil, err
+	}
+	return NewGeneratorWithSettings(machineID, config.Settings)
+}
+
+// LoadSettings 从配置文件加载id结构及机器ID来源，按扩展名识别格式：.json或.yaml/.yml
+//   - 配置项见settingsKeys，如time_bit、seq_bit、epoch(RFC3339)、time_unit、machine_id；未配置的项取DefaultSettings的值
+//   - YAML仅支持单层的"key: value"，不支持嵌套、列表等结构
+//   - 未知的配置项、格式错误及不合法的id结构均返回错误，避免配置拼写错误时静默使用默认值
+func LoadSettings(path string) (*SettingsConfig, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var values map[string]string
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		values, err = parseJSONSettings(data)
+	case ".yaml", ".yml":
+		values, err = parseYAMLSettings(data)
+	default:
+		return nil, errorf(CodeSettingsFormat, path)
+	}
+	if err != nil {
+		return nil, errorf(CodeSettingsFile, path, err)
+	}
+	config, err := settingsFromValues(values)
+	if err != nil {
+		return nil, errorf(CodeSettingsFile, path, err)
+	}
+	return config, nil
+}
+
+// SettingsFromEnv 从环境变量加载id结构及机器ID来源，变量名为prefix加配置项的大写形式
+//   - 如prefix为"SNOWFLAKE"时读取SNOWFLAKE_TIME_BIT、SNOWFLAKE_EPOCH、SNOWFLAKE_MACHINE_ID等，未设置的项取DefaultSettings的值
+func SettingsFromEnv(prefix string) (*SettingsConfig, error) {
+	if prefix != "" && !strings.HasSuffix(prefix, "_") {
+		prefix += "_"
+	}
+	values := make(map[string]string)
+	for _, key := range settingsKeys {
+		if value, ok := os.LookupEnv(prefix + strings.ToUpper(key)); ok {
+			values[key] = value
+		}
+	}
+	return settingsFromValues(values)
+}
+
+// parseJSONSettings 解析单层JSON对象，值可以是数字、字符串或布尔值
+func parseJSONSettings(data []byte) (map[string]string, error) {
+	var raw map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, err
+	}
+	values := make(map[string]string, len(raw))
+	for key, value := range raw {
+		switch v := value.(type) {
+		case string:
+			values[key] = v
+		case json.Number:
+			values[key] = v.String()
+		case bool:
+			values[key] = strconv.FormatBool(v)
+		default:
+			return nil, errorf(CodeSettingValueType, key)
+		}
+	}
+	return values, nil
+}
+
+// parseYAMLSettings 解析单层的"key: value"，支持#注释及单双引号
+func parseYAMLSettings(data []byte) (map[string]string, error) {
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || trimmed[0] == '#' || trimmed == "---" {
+			continue
+		}
+		if line[0] == ' ' || line[0] == '\t' || trimmed[0] == '-' {
+			return nil, errorf(CodeYAMLNested, lineNo)
+		}
+		i := strings.Index(trimmed, ":")
+		if i <= 0 {
+			return nil, errorf(CodeYAMLColon, lineNo)
+		}
+		key := strings.TrimSpace(trimmed[:i])
+		value := strings.TrimSpace(trimmed[i+1:])
+		if strings.HasPrefix(value, "#") {
+			value = ""
+		}
+		if n := len(value); n >= 2 && (value[0] == '"' && value[n-1] == '"' || value[0] == '\'' && value[n-1] == '\'') {
+			value = value[1 : n-1]
+		} else if j := strings.Index(value, " #"); j >= 0 {
+			value = strings.TrimSpace(value[:j])
+		} else if value == "" {
+			return nil, errorf(CodeYAMLValue, lineNo, key)
+		}
+		if _, ok := values[key]; ok {
+			return nil, errorf(CodeYAMLDuplicate, lineNo, key)
+		}
+		values[key] = value
+	}
+	return values, scanner.Err()
+}
+
+// settingsFromValues 在DefaultSettings的基础上应用配置项并校验
+func settingsFromValues(values map[string]string) (*SettingsConfig, error) {
+	known := make(map[string]bool, len(settingsKeys))
+	for _, key := range settingsKeys {
+		known[key] = true
+	}
+	for key := range values {
+		if !known[key] {
+			return nil, errorf(CodeSettingUnknown, key)
+		}
+	}
+
+	config := &SettingsConfig{Settings: *DefaultSettings}
+	settings := &config.Settings
+	bits := []struct {
+		key   string
+		value *uint64
+	}{
+		{"time_bit", &settings.TimeBit},
+		{"datacenter_bit", &settings.DatacenterBit},
+		{"machine_id_bit", &settings.MachineIDBit},
+		{"timeline_bit", &settings.TimelineBit},
+		{"seq_bit", &settings.SeqBit},
+		{"version_bit", &settings.VersionBit},
+	}
+	for _, bit := range bits {
+		value, ok := values[bit.key]
+		if !ok {
+			continue
+		}
+		n, err := strconv.ParseUint(value, 10, 64)
+		if err != nil || n > 63 {
+			return nil, errorf(CodeSettingBits, bit.key, value)
+		}
+		*bit.value = n
+	}
+	ids := []struct {
+		key   string
+		value *int64
+	}{
+		{"datacenter_id", &settings.DatacenterID},
+		{"version", &settings.Version},
+	}
+	for _, id := range ids {
+		value, ok := values[id.key]
+		if !ok {
+			continue
+		}
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, errorf(CodeSettingInteger, id.key, value)
+		}
+		*id.value = n
+	}
+	if value, ok := values["epoch"]; ok {
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, errorf(CodeSettingEpoch, value)
+		}
+		settings.Epoch = t.UnixNano()
+	}
+	if value, ok := values["time_unit"]; ok {
+		unit, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, errorf(CodeSettingTimeUnit, value)
+		}
+		settings.TimeUnit = unit
+	}
+	if value, ok := values["timeline_placement"]; ok {
+		placement, err := ParsePlacement(value)
+		if err != nil {
+			return nil, err
+		}
+		settings.Placement = placement
+	}
+	if value, ok := values["flag_bit"]; ok {
+		flag, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, errorf(CodeSettingFlagBit, value)
+		}
+		settings.FlagBit = flag
+	}
+	config.MachineID = MachineIDSource(values["machine_id"])
+	if err := config.MachineID.check(); err != nil {
+		return nil, err
+	}
+
+	if err := checkSettings(settings, 0); err != nil {
+		return nil, err
+	}
+	if fingerprint, ok := values["fingerprint"]; ok {
+		if err := settings.VerifyFingerprint(fingerprint); err != nil {
+			return nil, err
+		}
+	}
+	//机器ID固定时提前校验范围，环境变量、网卡在创建生成器时才读取
+	if _, err := strconv.ParseInt(string(config.MachineID), 10, 64); err == nil {
+		if _, err := config.MachineID.Resolve(settings.MachineIDBit); err != nil {
+			return nil, err
+		}
+	}
+	return config, nil
+}
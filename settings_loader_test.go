@@ -0,0 +1,113 @@
+package generator
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestLoadSettings 从JSON、YAML配置文件加载
+func TestLoadSettings(t *testing.T) {
+	dir, err := ioutil.TempDir("", "settings")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer os.RemoveAll(dir)
+
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC).UnixNano()
+	testCases := []struct {
+		name      string
+		file      string
+		content   string
+		wantErr   bool
+		wantSeq   uint64
+		wantUnit  time.Duration
+		wantEpoch int64
+		wantID    MachineIDSource
+	}{
+		{name: "JSON", file: "a.json", content: `{"time_bit": 39, "machine_id_bit": 15, "timeline_bit": 1, "seq_bit": 8, "time_unit": "10ms", "epoch": "2020-01-01T00:00:00Z", "machine_id": 7}`,
+			wantSeq: 8, wantUnit: 10 * time.Millisecond, wantEpoch: epoch, wantID: "7"},
+		{name: "YAML", file: "a.yaml", content: "# id结构\n---\ntime_bit: 41\nmachine_id_bit: 9\ntimeline_bit: 1\nseq_bit: 12 # 每毫秒4096个\nepoch: \"2020-01-01T00:00:00Z\"\nmachine_id: env:NODE_ID\n",
+			wantSeq: 12, wantEpoch: epoch, wantID: "env:NODE_ID"},
+		{name: "未配置的项取默认值", file: "b.yml", content: "machine_id: private-ip\n",
+			wantSeq: DefaultSettings.SeqBit, wantEpoch: DefaultSettings.Epoch, wantID: MachineIDPrivateIP},
+		{name: "未知的配置项", file: "c.json", content: `{"seq_bits": 12}`, wantErr: true},
+		{name: "位数之和不为63", file: "d.yaml", content: "seq_bit: 13\n", wantErr: true},
+		{name: "epoch格式错误", file: "e.yaml", content: "epoch: 1577836800000\n", wantErr: true},
+		{name: "时间单位不合法", file: "f.json", content: `{"time_unit": "3ms"}`, wantErr: true},
+		{name: "机器ID超出范围", file: "g.json", content: `{"machine_id": 1024}`, wantErr: true},
+		{name: "机器ID来源不合法", file: "h.json", content: `{"machine_id": "hostname"}`, wantErr: true},
+		{name: "嵌套结构", file: "i.yaml", content: "settings:\n  seq_bit: 12\n", wantErr: true},
+		{name: "JSON嵌套结构", file: "j.json", content: `{"seq_bit": [12]}`, wantErr: true},
+		{name: "指纹一致", file: "l.json", content: `{"fingerprint": "` + DefaultSettings.Fingerprint() + `"}`,
+			wantSeq: DefaultSettings.SeqBit, wantEpoch: DefaultSettings.Epoch},
+		{name: "指纹不一致", file: "m.json", content: `{"seq_bit": 11, "machine_id_bit": 11, "fingerprint": "` + DefaultSettings.Fingerprint() + `"}`, wantErr: true},
+		{name: "不支持的格式", file: "k.toml", content: "seq_bit = 12\n", wantErr: true},
+	}
+	for _, tc := range testCases {
+		path := filepath.Join(dir, tc.file)
+		if err := ioutil.WriteFile(path, []byte(tc.content), 0644); err != nil {
+			t.Fatal(err.Error())
+		}
+		config, err := LoadSettings(path)
+		if (err != nil) != tc.wantErr {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, err, tc.wantErr)
+		}
+		if err != nil {
+			continue
+		}
+		if config.Settings.SeqBit != tc.wantSeq || config.Settings.TimeUnit != tc.wantUnit ||
+			config.Settings.Epoch != tc.wantEpoch || config.MachineID != tc.wantID {
+			t.Fatalf("【失败】-%s-got:%+v,%v", tc.name, config.Settings, config.MachineID)
+		}
+	}
+	if _, err := LoadSettings(filepath.Join(dir, "missing.json")); err == nil {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "文件不存在", err, "error")
+	}
+}
+
+// TestSettingsFromEnv 从环境变量加载并创建生成器
+func TestSettingsFromEnv(t *testing.T) {
+	env := map[string]string{
+		"SNOWFLAKE_TIME_BIT":           "41",
+		"SNOWFLAKE_MACHINE_ID_BIT":     "9",
+		"SNOWFLAKE_SEQ_BIT":            "12",
+		"SNOWFLAKE_TIMELINE_PLACEMENT": "above-machine",
+		"SNOWFLAKE_MACHINE_ID":         "env:SNOWFLAKE_TEST_NODE",
+		"SNOWFLAKE_TEST_NODE":          "300",
+	}
+	for k, v := range env {
+		os.Setenv(k, v)
+		defer os.Unsetenv(k)
+	}
+
+	config, err := SettingsFromEnv("SNOWFLAKE")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if config.Settings.MachineIDBit != 9 || config.Settings.Placement != TimelineAboveMachine {
+		t.Fatalf("【失败】-%s-got:%+v", "配置", config.Settings)
+	}
+	idGen, err := config.NewGenerator()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	id, _ := idGen.Generate()
+	if got := idGen.Decompose(int64(id)).MachineID; got != 300 {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "机器ID", got, 300)
+	}
+
+	os.Setenv("SNOWFLAKE_TEST_NODE", "512")
+	if _, err := config.NewGenerator(); err == nil {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "环境变量中的机器ID超出范围", err, "error")
+	}
+	os.Setenv("SNOWFLAKE_SEQ_BIT", "x")
+	if _, err := SettingsFromEnv("SNOWFLAKE_"); err == nil {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "位数不是整数", err, "error")
+	}
+	if _, err := (&SettingsConfig{Settings: *DefaultSettings}).NewGenerator(); err == nil {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "未配置机器ID来源", err, "error")
+	}
+}
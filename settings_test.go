@@ -0,0 +1,137 @@
+package generator
+
+import (
+	"testing"
+	"time"
+)
+
+// TestTimeUnit 可配置的时间单位
+func TestTimeUnit(t *testing.T) {
+	testCases := []struct {
+		name    string
+		unit    time.Duration
+		timeBit uint64
+		wantErr bool
+	}{
+		{name: "缺省毫秒", unit: 0, timeBit: 41},
+		{name: "微秒", unit: time.Microsecond, timeBit: 51},
+		{name: "100微秒", unit: 100 * time.Microsecond, timeBit: 45},
+		{name: "毫秒", unit: time.Millisecond, timeBit: 41},
+		{name: "10毫秒", unit: 10 * time.Millisecond, timeBit: 39},
+		{name: "秒", unit: time.Second, timeBit: 32},
+		{name: "小于微秒", unit: 500 * time.Nanosecond, timeBit: 41, wantErr: true},
+		{name: "非微秒整数倍", unit: 2500 * time.Nanosecond, timeBit: 41, wantErr: true},
+		{name: "不能整除1秒", unit: 3 * time.Millisecond, timeBit: 41, wantErr: true},
+		{name: "大于1秒", unit: 2 * time.Second, timeBit: 41, wantErr: true},
+		{name: "负数", unit: -time.Millisecond, timeBit: 41, wantErr: true},
+	}
+	for _, tc := range testCases {
+		settings := Settings{TimeBit: tc.timeBit, MachineIDBit: 56 - tc.timeBit, TimelineBit: 1, SeqBit: 6, Epoch: DefaultEpoch, TimeUnit: tc.unit}
+		idGen, err := NewGeneratorWithSettings(3, settings)
+		if (err != nil) != tc.wantErr {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, err, tc.wantErr)
+		}
+		if err != nil {
+			continue
+		}
+
+		//id的时间截断到时间单位
+		unit := time.Duration(settings.unit())
+		before := time.Now()
+		id, err := idGen.Generate()
+		after := time.Now()
+		if err != nil {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, err, nil)
+		}
+		got := idGen.Time(int64(id))
+		if got.Before(before.Add(-unit)) || got.After(after) {
+			t.Fatalf("【失败】-%s-got:%v-want:[%v,%v]", tc.name, got, before.Add(-unit), after)
+		}
+		if err := idGen.SelfTest(); err != nil {
+			t.Fatalf("【失败】-%s-自检-got:%v-want:%v", tc.name, err, nil)
+		}
+	}
+}
+
+// TestCentisecondSettings 10毫秒时间单位的配置
+func TestCentisecondSettings(t *testing.T) {
+	c := CentisecondSettings.Capacity()
+	if c.Years < 174 || c.SeqPerUnit != 256 || c.IDsPerSecond != 25600 {
+		t.Fatalf("【失败】-%s-got:%+v-want:%v", "容量", c, "174年、每秒25600个")
+	}
+}
+
+// TestDatacenter 数据中心位紧邻机器ID之上
+func TestDatacenter(t *testing.T) {
+	testCases := []struct {
+		name      string
+		settings  Settings
+		machineID int64
+		wantErr   bool
+	}{
+		{name: "时间线位于机器ID之下", settings: Settings{TimeBit: 41, DatacenterBit: 5, MachineIDBit: 5, TimelineBit: 1, SeqBit: 11, DatacenterID: 17}, machineID: 9},
+		{name: "时间线位于机器ID之上", settings: Settings{TimeBit: 41, DatacenterBit: 5, MachineIDBit: 5, TimelineBit: 1, SeqBit: 11, DatacenterID: 31, Placement: TimelineAboveMachine}, machineID: 31},
+		{name: "时间线位于时间之上", settings: Settings{TimeBit: 41, DatacenterBit: 5, MachineIDBit: 5, TimelineBit: 1, SeqBit: 11, DatacenterID: 1, Placement: TimelineAboveTime}, machineID: 0},
+		{name: "标记位", settings: Settings{TimeBit: 41, DatacenterBit: 5, MachineIDBit: 5, TimelineBit: 1, SeqBit: 10, DatacenterID: 3, FlagBit: true}, machineID: 4},
+		{name: "位数之和不为63", settings: Settings{TimeBit: 41, DatacenterBit: 5, MachineIDBit: 9, TimelineBit: 1, SeqBit: 12}, wantErr: true},
+		{name: "数据中心ID超出范围", settings: Settings{TimeBit: 41, DatacenterBit: 5, MachineIDBit: 5, TimelineBit: 1, SeqBit: 11, DatacenterID: 32}, wantErr: true},
+		{name: "未设置数据中心位", settings: Settings{TimeBit: 41, MachineIDBit: 9, TimelineBit: 1, SeqBit: 12, DatacenterID: 1}, wantErr: true},
+	}
+	for _, tc := range testCases {
+		tc.settings.Epoch = DefaultEpoch
+		tc.settings.SelfTest = true
+		idGen, err := NewGeneratorWithSettings(tc.machineID, tc.settings)
+		if (err != nil) != tc.wantErr {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, err, tc.wantErr)
+		}
+		if err != nil {
+			continue
+		}
+
+		id, _ := idGen.Generate()
+		c := idGen.Decompose(int64(id))
+		if c.DatacenterID != tc.settings.DatacenterID || c.MachineID != tc.machineID {
+			t.Fatalf("【失败】-%s-got:%v,%v-want:%v,%v", tc.name, c.DatacenterID, c.MachineID, tc.settings.DatacenterID, tc.machineID)
+		}
+		presets := idGen.settings.presets
+		if got := int64(id) >> (presets.shiftMachineIDBit + tc.settings.MachineIDBit) & 31; got != tc.settings.DatacenterID {
+			t.Fatalf("【失败】-%s-数据中心位置-got:%v-want:%v", tc.name, got, tc.settings.DatacenterID)
+		}
+		if got, _ := DecomposeWith(tc.settings, int64(id)); got.DatacenterID != tc.settings.DatacenterID {
+			t.Fatalf("【失败】-%s-DecomposeWith-got:%v-want:%v", tc.name, got.DatacenterID, tc.settings.DatacenterID)
+		}
+	}
+
+	//不同数据中心相同机器ID的id空间不重叠
+	a := Settings{TimeBit: 41, DatacenterBit: 5, MachineIDBit: 5, TimelineBit: 1, SeqBit: 11, Epoch: DefaultEpoch, DatacenterID: 1}
+	b := a
+	b.DatacenterID = 2
+	if err := DisjointIDSpaces(a, b, []int64{7}, []int64{7}); err != nil {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "不同数据中心", err, nil)
+	}
+	if err := DisjointIDSpaces(a, a, []int64{7}, []int64{7}); err == nil {
+		t.Fatalf("【失败】-%s-got:%v-want:%s", "相同数据中心", err, "重叠")
+	}
+}
+
+// TestSettingsBits 各部分位数之和计入版本位、数据中心位及标记位
+func TestSettingsBits(t *testing.T) {
+	testCases := []struct {
+		name     string
+		settings Settings
+		want     uint64
+	}{
+		{name: "默认配置", settings: *DefaultSettings, want: 63},
+		{name: "版本位", settings: Settings{VersionBit: 2, TimeBit: 39, MachineIDBit: 9, TimelineBit: 1, SeqBit: 12}, want: 63},
+		{name: "数据中心位及标记位", settings: Settings{TimeBit: 41, DatacenterBit: 2, MachineIDBit: 7, TimelineBit: 1, SeqBit: 11, FlagBit: true}, want: 63},
+		{name: "版本位超出", settings: Settings{VersionBit: 1, TimeBit: 41, MachineIDBit: 9, TimelineBit: 1, SeqBit: 12}, want: 64},
+	}
+	for _, tc := range testCases {
+		if got := tc.settings.Bits(); got != tc.want {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, got, tc.want)
+		}
+		if err := tc.settings.checkBits(); (err == nil) != (tc.want == 63) {
+			t.Fatalf("【失败】-%s-校验-got:%v-want:%v", tc.name, err, tc.want == 63)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package generator
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/bits"
+)
+
+// ShardKey 分片键：id中的机器ID，同一节点生成的id落在同一分片
+func (idGen *IDGenerator) ShardKey(id int64) int64 {
+	presets := idGen.settings.presets
+	return (id & presets.maskMachineID) >> presets.shiftMachineIDBit
+}
+
+// VitessKeyspaceID 将id转换为Vitess keyspace id(8字节)
+//   - 机器ID位于最高位，其余部分按id结构中的顺序依次排列(默认为时间、时间线、序号)
+//   - 按keyrange拆分分片时，同一机器的id总是落在同一分片；分片数不超过机器数且为2的幂时，每个分片对应一段连续的机器ID
+func (idGen *IDGenerator) VitessKeyspaceID(id int64) []byte {
+	presets := idGen.settings.presets
+	machineIDBit := idGen.settings.MachineIDBit
+
+	machineID := uint64(idGen.ShardKey(id))
+	high := uint64(id) >> (presets.shiftMachineIDBit + machineIDBit) << presets.shiftMachineIDBit
+	low := uint64(id) & (uint64(1)<<presets.shiftMachineIDBit - 1)
+	rest := (high | low) << 1 //去掉机器ID后剩余的63-MachineIDBit位，左移补齐64位
+
+	ksid := make([]byte, 8)
+	binary.BigEndian.PutUint64(ksid, machineID<<(64-machineIDBit)|rest)
+	return ksid
+}
+
+// ShardIndex 按Vitess keyrange均分规则计算id所在分片(0 ~ shards-1)，与VitessKeyRanges一致
+func (idGen *IDGenerator) ShardIndex(id int64, shards int) int {
+	if shards <= 1 {
+		return 0
+	}
+	hi, _ := bits.Mul64(binary.BigEndian.Uint64(idGen.VitessKeyspaceID(id)), uint64(shards))
+	return int(hi)
+}
+
+// VitessKeyRanges 将keyspace均分为shards个分片，返回Vitess分片名，如 -80、80-
+func VitessKeyRanges(shards int) ([]string, error) {
+	if shards < 1 {
+		return nil, errorf(CodeNotPositive, "shards")
+	}
+	if shards == 1 {
+		return []string{"-"}, nil
+	}
+
+	boundary := func(i int) string {
+		if i == 0 || i == shards {
+			return ""
+		}
+		// ceil(i*2^64/shards)
+		quo, rem := bits.Div64(uint64(i), 0, uint64(shards))
+		if rem != 0 {
+			quo++
+		}
+		buf := make([]byte, 8)
+		binary.BigEndian.PutUint64(buf, quo)
+		for len(buf) > 1 && buf[len(buf)-1] == 0 {
+			buf = buf[:len(buf)-1]
+		}
+		return hex.EncodeToString(buf)
+	}
+
+	ranges := make([]string, shards)
+	for i := range ranges {
+		ranges[i] = boundary(i) + "-" + boundary(i+1)
+	}
+	return ranges, nil
+}
+
+// ShardKeySQL 生成从id列中提取分片键(机器ID)的SQL表达式，适用于MySQL(Vitess)和PostgreSQL(Citus)
+//   - 可用于生成列、分区表达式或按节点路由的查询条件
+func (idGen *IDGenerator) ShardKeySQL(column string) string {
+	presets := idGen.settings.presets
+	return fmt.Sprintf("((%s >> %d) & %d)", column, presets.shiftMachineIDBit, presets.maxMachineID)
+}
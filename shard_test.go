@@ -0,0 +1,70 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/hex"
+	"reflect"
+	"testing"
+)
+
+// TestVitessKeyRanges 分片名
+func TestVitessKeyRanges(t *testing.T) {
+	testCases := []struct {
+		name   string
+		shards int
+		want   []string
+	}{
+		{name: "单分片", shards: 1, want: []string{"-"}},
+		{name: "2分片", shards: 2, want: []string{"-80", "80-"}},
+		{name: "4分片", shards: 4, want: []string{"-40", "40-80", "80-c0", "c0-"}},
+		{name: "3分片", shards: 3, want: []string{"-5555555555555556", "5555555555555556-aaaaaaaaaaaaaaab", "aaaaaaaaaaaaaaab-"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := VitessKeyRanges(tc.shards)
+			if err != nil || !reflect.DeepEqual(got, tc.want) {
+				t.Fatalf("【失败】-%s-got:%v-want:%v-err:%v", tc.name, got, tc.want, err)
+			}
+		})
+	}
+}
+
+// TestShardIndex 同一机器的id落在同一分片，且与keyrange一致
+func TestShardIndex(t *testing.T) {
+	const shards = 4
+	ranges, _ := VitessKeyRanges(shards)
+
+	for _, machineID := range []int64{0, 1, 127, 128, 300, 511} {
+		idGen, _ := NewGenerator(machineID)
+		want := int(machineID * shards / 512)
+		for i := 0; i < 100; i++ {
+			id, _ := idGen.Generate()
+			if got := idGen.ShardKey(int64(id)); got != machineID {
+				t.Fatalf("【失败】-分片键-got:%d-want:%d", got, machineID)
+			}
+			if got := idGen.ShardIndex(int64(id), shards); got != want {
+				t.Fatalf("【失败】-分片下标-machineID:%d-got:%d-want:%d", machineID, got, want)
+			}
+			if !inKeyRange(idGen.VitessKeyspaceID(int64(id)), ranges[want]) {
+				t.Fatalf("【失败】-keyspace id不在分片%s内", ranges[want])
+			}
+		}
+	}
+
+	idGen, _ := NewGenerator(0)
+	if got := idGen.ShardKeySQL("id"); got != "((id >> 13) & 511)" {
+		t.Fatalf("【失败】-分片键SQL-got:%s", got)
+	}
+}
+
+// inKeyRange keyspace id是否在Vitess分片范围内
+func inKeyRange(ksid []byte, keyRange string) bool {
+	var start, end []byte
+	for i, c := range keyRange {
+		if c == '-' {
+			start, _ = hex.DecodeString(keyRange[:i])
+			end, _ = hex.DecodeString(keyRange[i+1:])
+		}
+	}
+	return bytes.Compare(ksid, start) >= 0 && (len(end) == 0 || bytes.Compare(ksid, end) < 0)
+}
@@ -0,0 +1,46 @@
+package generator
+
+import (
+	"crypto/rand"
+	"math/big"
+)
+
+// SetStartTimeline 指定初始时间线，须在生成id前设置
+//   - 默认从时间线0开始；大批实例在时钟事故后同时重启时，可为各实例指定不同的初始时间线，避免按相同顺序消耗时间线
+func (idGen *IDGenerator) SetStartTimeline(timeline int64) error {
+	idGen.mutex.Lock()
+	defer idGen.mutex.Unlock()
+
+	if maxTimeline := idGen.settings.presets.maxTimeline; timeline < 0 || timeline > maxTimeline {
+		return errorf(CodeTimelineRange, maxTimeline)
+	}
+	return idGen.setStartTimeline(timeline)
+}
+
+// RandomStartTimeline 随机选定初始时间线，须在生成id前设置，返回选定的时间线
+//   - 使用crypto/rand，同时启动的实例不会因随机种子相同而选中相同的时间线
+func (idGen *IDGenerator) RandomStartTimeline() (int64, error) {
+	idGen.mutex.Lock()
+	defer idGen.mutex.Unlock()
+
+	n, err := rand.Int(rand.Reader, big.NewInt(idGen.settings.presets.maxTimeline+1))
+	if err != nil {
+		return 0, err
+	}
+	timeline := n.Int64()
+	return timeline, idGen.setStartTimeline(timeline)
+}
+
+// setStartTimeline 设置初始时间线，调用方须持有锁
+func (idGen *IDGenerator) setStartTimeline(timeline int64) error {
+	for _, progress := range idGen.timelineProgress {
+		if progress != 0 {
+			return errorf(CodeStartTimelineLate)
+		}
+	}
+	idGen.curTimeline = timeline
+	if idGen.metrics != nil {
+		idGen.metrics.Gauge(MetricTimeline, float64(timeline))
+	}
+	return nil
+}
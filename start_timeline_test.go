@@ -0,0 +1,70 @@
+package generator
+
+import (
+	"testing"
+)
+
+// TestSetStartTimeline 指定初始时间线
+func TestSetStartTimeline(t *testing.T) {
+	settings := Settings{TimeBit: 41, MachineIDBit: 8, TimelineBit: 2, SeqBit: 12, Epoch: DefaultEpoch}
+	testCases := []struct {
+		name     string
+		timeline int64
+		generate bool //设置前是否已生成过id
+		wantErr  bool
+	}{
+		{name: "指定时间线", timeline: 2, wantErr: false},
+		{name: "最大时间线", timeline: 3, wantErr: false},
+		{name: "时间线超限", timeline: 4, wantErr: true},
+		{name: "时间线为负数", timeline: -1, wantErr: true},
+		{name: "已生成过id", timeline: 1, generate: true, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			idGen, _ := NewGeneratorWithSettings(0, settings)
+			if tc.generate {
+				idGen.Generate()
+			}
+			err := idGen.SetStartTimeline(tc.timeline)
+			if got := err != nil; got != tc.wantErr {
+				t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, err, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+			id, _ := idGen.Generate()
+			if got := idGen.Decompose(int64(id)).TimeLine; got != tc.timeline {
+				t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, got, tc.timeline)
+			}
+		})
+	}
+}
+
+// TestRandomStartTimeline 随机选定初始时间线
+func TestRandomStartTimeline(t *testing.T) {
+	settings := Settings{TimeBit: 41, MachineIDBit: 7, TimelineBit: 3, SeqBit: 12, Epoch: DefaultEpoch}
+	seen := make(map[int64]bool)
+	for i := 0; i < 200; i++ {
+		idGen, _ := NewGeneratorWithSettings(0, settings)
+		timeline, err := idGen.RandomStartTimeline()
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		id, _ := idGen.Generate()
+		if got := idGen.Decompose(int64(id)).TimeLine; got != timeline {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", "使用选定的时间线", got, timeline)
+		}
+		seen[timeline] = true
+	}
+	//200次随机选择应覆盖全部8条时间线
+	if len(seen) != 8 {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "随机分布", len(seen), 8)
+	}
+
+	idGen, _ := NewGenerator(0)
+	idGen.Generate()
+	if _, err := idGen.RandomStartTimeline(); err == nil {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "已生成过id", err, "error")
+	}
+}
@@ -0,0 +1,299 @@
+package generator
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// defaultPersistInterval 缺省的时间线状态保存间隔
+const defaultPersistInterval = time.Second
+
+// TimelineState 持久化的时间线状态
+type TimelineState struct {
+	MachineID int64   `json:"machine_id"` //机器ID
+	Timeline  int64   `json:"timeline"`   //当前时间线
+	Progress  []int64 `json:"progress"`   //各时间线进度(unix nano)，当前时间线包含提前量
+}
+
+// StatePersister 时间线状态的存储(本地文件、Redis等)
+//   - 同一机器ID的生成器须使用同一存储，不同机器ID可以共用(按机器ID区分)，也可以各自独立
+type StatePersister interface {
+	// Load 读取保存的状态，尚未保存过时返回nil, nil
+	Load() (*TimelineState, error)
+	// Save 保存状态，返回后状态须已持久化
+	Save(state *TimelineState) error
+}
+
+// statePersist 时间线状态持久化
+//   - 保存的当前时间线进度比实际进度提前lookahead个时间单位，ceiling为已保存的各时间线进度，
+//     生成的id超出ceiling前在生成器锁内同步保存，保证重启后恢复的进度不小于已生成的任何id
+//   - 后台每interval保存一次，正常情况下ceiling始终领先于实际进度，生成时不需要同步保存
+type statePersist struct {
+	persister StatePersister
+	interval  time.Duration
+	lookahead int64   //提前量(时间单位)
+	ceiling   []int64 //已保存的各时间线进度(时间单位)，持有生成器锁访问
+	version   int64   //最新快照的版本，持有生成器锁访问
+	applied   int64   //ceiling对应的快照版本，持有生成器锁访问
+
+	mutex sync.Mutex //保证保存顺序
+	saved int64      //已保存的快照版本，持有mutex访问
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// restoreState 从存储中恢复时间线状态并启动后台保存，创建生成器时调用
+//   - 恢复的进度通常晚于当前时间，重启后第一次生成按时钟回退处理(等待或切换时间线)
+//   - 保存的机器ID与当前机器ID不同时忽略保存的状态
+func (idGen *IDGenerator) restoreState(persister StatePersister, interval time.Duration) error {
+	if interval == 0 {
+		interval = defaultPersistInterval
+	}
+	state, err := persister.Load()
+	if err != nil {
+		return err
+	}
+
+	unit := idGen.settings.unit()
+	p := &statePersist{
+		persister: persister,
+		interval:  interval,
+		lookahead: (2*int64(interval) + unit - 1) / unit,
+		ceiling:   make([]int64, len(idGen.timelineProgress)),
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	if state != nil && state.MachineID == idGen.machineID {
+		if len(state.Progress) != len(idGen.timelineProgress) || state.Timeline < 0 || state.Timeline >= int64(len(state.Progress)) {
+			return errorf(CodeStateTimelines)
+		}
+		for i, nanos := range state.Progress {
+			offset := int64(0)
+			if diff := nanos - idGen.settings.Epoch; diff > 0 {
+				offset = (diff + unit - 1) / unit
+			}
+			p.ceiling[i] = offset
+			if offset > idGen.timelineProgress[i] {
+				idGen.timelineProgress[i] = offset
+			}
+		}
+		idGen.curTimeline = state.Timeline
+		idGen.seq = idGen.settings.presets.maxSeq
+	}
+	idGen.persist = p
+	goLabeled(idGen, func() { idGen.runPersist(p) })
+	return nil
+}
+
+// FlushState 立即保存时间线状态，未设置StatePersister时返回nil
+func (idGen *IDGenerator) FlushState() error {
+	idGen.mutex.Lock()
+	p := idGen.persist
+	if p == nil {
+		idGen.mutex.Unlock()
+		return nil
+	}
+	state, ceiling, version := idGen.snapshotState(-1)
+	idGen.mutex.Unlock()
+
+	saved, err := p.save(state, version)
+	if err != nil || !saved {
+		return err
+	}
+	idGen.mutex.Lock()
+	defer idGen.mutex.Unlock()
+	p.apply(ceiling, version)
+	return nil
+}
+
+// ensurePersisted 当前时间线的进度超出已保存的进度时同步保存，调用方须持有锁
+func (idGen *IDGenerator) ensurePersisted(curTime int64) error {
+	p := idGen.persist
+	if curTime <= p.ceiling[idGen.curTimeline] {
+		return nil
+	}
+	state, ceiling, version := idGen.snapshotState(curTime)
+	if _, err := p.save(state, version); err != nil {
+		return err
+	}
+	p.apply(ceiling, version)
+	return nil
+}
+
+// snapshotState 生成要保存的状态，curTime>=0时作为当前时间线的进度，调用方须持有锁
+func (idGen *IDGenerator) snapshotState(curTime int64) (*TimelineState, []int64, int64) {
+	p := idGen.persist
+	p.version++
+	state := &TimelineState{
+		MachineID: idGen.machineID,
+		Timeline:  idGen.curTimeline,
+		Progress:  make([]int64, len(idGen.timelineProgress)),
+	}
+	ceiling := make([]int64, len(idGen.timelineProgress))
+	for i, progress := range idGen.timelineProgress {
+		if int64(i) == idGen.curTimeline {
+			if curTime > progress {
+				progress = curTime
+			}
+			progress += p.lookahead
+		}
+		ceiling[i] = progress
+		state.Progress[i] = idGen.toUnixNano(progress)
+	}
+	return state, ceiling, p.version
+}
+
+// save 保存状态，已保存更新的快照时跳过并返回false
+func (p *statePersist) save(state *TimelineState, version int64) (bool, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if version <= p.saved {
+		return false, nil
+	}
+	if err := p.persister.Save(state); err != nil {
+		return false, err
+	}
+	p.saved = version
+	return true, nil
+}
+
+// apply 更新已保存的进度，调用方须持有生成器锁
+func (p *statePersist) apply(ceiling []int64, version int64) {
+	if version > p.applied {
+		p.ceiling = ceiling
+		p.applied = version
+	}
+}
+
+// runPersist 后台定期保存，保存失败时下个周期重试(生成时超出已保存的进度会同步保存)
+func (idGen *IDGenerator) runPersist(p *statePersist) {
+	defer close(p.done)
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			if err := idGen.FlushState(); err != nil {
+				idGen.mutex.Lock()
+				if idGen.metrics != nil {
+					idGen.metrics.Counter(MetricStateSaveError, 1)
+				}
+				idGen.mutex.Unlock()
+			}
+		}
+	}
+}
+
+// FileStatePersister 将时间线状态以JSON保存到本地文件，先写临时文件再重命名，不会读到不完整的内容
+type FileStatePersister struct {
+	path string
+}
+
+var _ StatePersister = (*FileStatePersister)(nil)
+
+// NewFileStatePersister 创建保存到path的时间线状态存储
+func NewFileStatePersister(path string) (*FileStatePersister, error) {
+	if path == "" {
+		return nil, errorf(CodeEmptyPath)
+	}
+	return &FileStatePersister{path: path}, nil
+}
+
+// Load 读取保存的状态，文件不存在时返回nil, nil
+func (f *FileStatePersister) Load() (*TimelineState, error) {
+	data, err := ioutil.ReadFile(f.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	state := new(TimelineState)
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// Save 保存状态
+func (f *FileStatePersister) Save(state *TimelineState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(f.path), filepath.Base(f.path)+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err = tmp.Write(data); err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), f.path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+	return err
+}
+
+// RedisStateClient 时间线状态存储依赖的Redis命令，可由任意Redis客户端适配
+type RedisStateClient interface {
+	// Get GET key，key不存在时返回空字符串
+	Get(key string) (string, error)
+	// Set SET key value(不过期)
+	Set(key, value string) error
+}
+
+// RedisStatePersister 将时间线状态以JSON保存到Redis，key为 prefix:机器ID
+//   - 须开启持久化(AOF)，否则Redis重启后状态丢失
+type RedisStatePersister struct {
+	client RedisStateClient
+	key    string
+}
+
+var _ StatePersister = (*RedisStatePersister)(nil)
+
+// NewRedisStatePersister 创建保存到Redis的时间线状态存储
+func NewRedisStatePersister(client RedisStateClient, prefix string, machineID int64) (*RedisStatePersister, error) {
+	if client == nil {
+		return nil, errorf(CodeNil, "client")
+	}
+	if prefix == "" {
+		return nil, errorf(CodeEmptyKeyPrefix)
+	}
+	return &RedisStatePersister{client: client, key: prefix + ":" + strconv.FormatInt(machineID, 10)}, nil
+}
+
+// Load 读取保存的状态，key不存在时返回nil, nil
+func (r *RedisStatePersister) Load() (*TimelineState, error) {
+	value, err := r.client.Get(r.key)
+	if err != nil || value == "" {
+		return nil, err
+	}
+	state := new(TimelineState)
+	if err := json.Unmarshal([]byte(value), state); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// Save 保存状态
+func (r *RedisStatePersister) Save(state *TimelineState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return r.client.Set(r.key, string(data))
+}
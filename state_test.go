@@ -0,0 +1,161 @@
+package generator
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jayecc/mtl-snowflake/fakeclock"
+)
+
+// memoryPersister 内存中的时间线状态存储
+type memoryPersister struct {
+	mutex sync.Mutex
+	state *TimelineState
+	saves int
+	err   error
+}
+
+func (m *memoryPersister) Load() (*TimelineState, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.state, nil
+}
+
+func (m *memoryPersister) Save(state *TimelineState) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.err != nil {
+		return m.err
+	}
+	m.state = state
+	m.saves++
+	return nil
+}
+
+// TestStatePersister 重启后恢复时间线状态
+func TestStatePersister(t *testing.T) {
+	start := time.Now().Truncate(time.Millisecond)
+	testCases := []struct {
+		name    string
+		restart time.Duration //重启时的时钟相对停止时的偏移
+	}{
+		{name: "同一毫秒内重启", restart: 0},
+		{name: "停机期间时钟回退", restart: -time.Second},
+		{name: "停机期间时钟大幅回退", restart: -time.Hour},
+	}
+
+	for _, tc := range testCases {
+		clock := fakeclock.New(start)
+		persister := &memoryPersister{}
+		settings := *DefaultSettings
+		settings.Clock = clock
+		settings.StatePersister = persister
+		settings.PersistInterval = time.Hour
+
+		ids := map[int64]bool{}
+		generate := func(idGen *IDGenerator, n int) {
+			for i := 0; i < n; i++ {
+				id, err := idGen.Generate()
+				if err != nil {
+					t.Fatalf("【失败】-%s-%v", tc.name, err)
+				}
+				if ids[int64(id)] {
+					t.Fatalf("【失败】-%s-重复id:%d", tc.name, id)
+				}
+				ids[int64(id)] = true
+			}
+		}
+
+		first, err := NewGeneratorWithSettings(1, settings)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		generate(first, 10)
+		clock.Advance(time.Millisecond)
+		generate(first, 10)
+
+		clock.Advance(tc.restart)
+		second, err := NewGeneratorWithSettings(1, settings)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		generate(second, 10)
+		clock.Advance(time.Millisecond)
+		generate(second, 10)
+	}
+}
+
+// TestStatePersisterSync 超出已保存的进度时同步保存，保存失败时返回错误
+func TestStatePersisterSync(t *testing.T) {
+	clock := fakeclock.New(time.Now())
+	persister := &memoryPersister{}
+	settings := *DefaultSettings
+	settings.Clock = clock
+	settings.StatePersister = persister
+	settings.PersistInterval = time.Hour
+	idGen, err := NewGeneratorWithSettings(1, settings)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	testCases := []struct {
+		name      string
+		advance   time.Duration
+		err       error
+		wantSaves int
+		wantErr   bool
+	}{
+		{name: "首次生成", wantSaves: 1},
+		{name: "提前量之内", advance: time.Hour, wantSaves: 1},
+		{name: "超出提前量", advance: 2 * time.Hour, wantSaves: 2},
+		{name: "保存失败", advance: 3 * time.Hour, err: errors.New("save failed"), wantSaves: 2, wantErr: true},
+		{name: "恢复", wantSaves: 3},
+	}
+	for _, tc := range testCases {
+		clock.Advance(tc.advance)
+		persister.mutex.Lock()
+		persister.err = tc.err
+		persister.mutex.Unlock()
+
+		_, err := idGen.Generate()
+		if (err != nil) != tc.wantErr {
+			t.Fatalf("【失败】-%s-err:%v", tc.name, err)
+		}
+		persister.mutex.Lock()
+		saves := persister.saves
+		persister.mutex.Unlock()
+		if saves != tc.wantSaves {
+			t.Fatalf("【失败】-%s-保存次数-got:%v-want:%v", tc.name, saves, tc.wantSaves)
+		}
+	}
+}
+
+// TestFileStatePersister 本地文件存储
+func TestFileStatePersister(t *testing.T) {
+	dir, err := ioutil.TempDir("", "state")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer os.RemoveAll(dir)
+
+	persister, err := NewFileStatePersister(filepath.Join(dir, "timeline.json"))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if state, err := persister.Load(); state != nil || err != nil {
+		t.Fatalf("【失败】-%s-got:%v,%v", "文件不存在", state, err)
+	}
+	want := &TimelineState{MachineID: 3, Timeline: 1, Progress: []int64{100, 200}}
+	if err := persister.Save(want); err != nil {
+		t.Fatal(err.Error())
+	}
+	got, err := persister.Load()
+	if err != nil || got.MachineID != 3 || got.Timeline != 1 || len(got.Progress) != 2 || got.Progress[1] != 200 {
+		t.Fatalf("【失败】-%s-got:%+v-want:%+v", "读取", got, want)
+	}
+}
@@ -0,0 +1,50 @@
+package generator
+
+import (
+	"time"
+)
+
+// Stats 生成器运行状态快照
+type Stats struct {
+	MachineID          int64         //机器ID
+	CurrentTimeline    int64         //当前时间线
+	TimelineProgress   []time.Time   //各时间线进度(已生成的最新id的时间)，未使用的时间线为基准时间
+	AvailableTimelines int           //可用于处理时钟回退的时间线数(不含当前时间线)
+	Remaining          time.Duration //时间部分用尽前剩余的可用时长
+	SeqUsed            int64         //当前时间线最新时间单位内已使用的序号数
+	SeqCapacity        int64         //每个时间单位允许使用的序号数
+	ClockBackwards     int64         //检测到时钟回退的次数
+	TimelineSwitches   int64         //因时钟回退切换时间线的次数
+	SeqExhausted       int64         //序号用完等待下一个时间单位的次数
+	SeqBorrowed        int64         //序号用完时借用下一个时间单位的次数
+}
+
+// Stats 返回生成器当前的运行状态，供管理接口查看生成器健康状况
+//   - 各次数自生成器创建起累计；无锁快速路径(SetLockFree)生成的id在下一次加锁时合并到快照中
+func (idGen *IDGenerator) Stats() Stats {
+	idGen.mutex.Lock()
+	defer idGen.mutex.Unlock()
+
+	presets := idGen.settings.presets
+	progress := make([]time.Time, len(idGen.timelineProgress))
+	for i, offset := range idGen.timelineProgress {
+		progress[i] = time.Unix(0, idGen.toUnixNano(offset))
+	}
+	var seqUsed int64
+	if idGen.lastID != 0 {
+		seqUsed = idGen.seq + 1
+	}
+	return Stats{
+		MachineID:          idGen.machineID,
+		CurrentTimeline:    idGen.curTimeline,
+		TimelineProgress:   progress,
+		AvailableTimelines: idGen.availableTimelines(),
+		Remaining:          time.Duration(idGen.exhaustionNano() - idGen.now().UnixNano()),
+		SeqUsed:            seqUsed,
+		SeqCapacity:        idGen.seqLimit(presets.maxSeq) + 1,
+		ClockBackwards:     idGen.backwardCount,
+		TimelineSwitches:   idGen.switchCount,
+		SeqExhausted:       idGen.exhaustedCount,
+		SeqBorrowed:        idGen.borrowedCount,
+	}
+}
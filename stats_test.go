@@ -0,0 +1,57 @@
+package generator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jayecc/mtl-snowflake/fakeclock"
+)
+
+// TestStats 运行状态快照
+func TestStats(t *testing.T) {
+	start := time.Now().Truncate(time.Millisecond)
+	clock := fakeclock.New(start)
+	settings := Settings{TimeBit: 41, MachineIDBit: 19, TimelineBit: 1, SeqBit: 2, Epoch: DefaultSettings.Epoch, Clock: clock}
+	idGen, err := NewGeneratorWithSettings(3, settings)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	epoch := time.Unix(0, settings.Epoch)
+	testCases := []struct {
+		name   string
+		action func()
+		check  func(stats Stats) bool
+	}{
+		{name: "未生成", action: func() {}, check: func(stats Stats) bool {
+			return stats.MachineID == 3 && stats.SeqUsed == 0 && stats.SeqCapacity == 4 &&
+				stats.TimelineProgress[0].Equal(epoch) && stats.AvailableTimelines == 1
+		}},
+		{name: "生成", action: func() { idGen.Generate(); idGen.Generate() }, check: func(stats Stats) bool {
+			return stats.SeqUsed == 2 && stats.TimelineProgress[0].Equal(start) && stats.CurrentTimeline == 0
+		}},
+		{name: "序号用完", action: func() {
+			for i := 0; i < 3; i++ {
+				idGen.Generate()
+			}
+		}, check: func(stats Stats) bool {
+			return stats.SeqExhausted == 1 && stats.SeqUsed == 1 && stats.TimelineProgress[0].Equal(start.Add(time.Millisecond))
+		}},
+		{name: "时钟回退", action: func() { clock.Set(start.Add(-time.Second)); idGen.Generate() }, check: func(stats Stats) bool {
+			return stats.ClockBackwards == 1 && stats.TimelineSwitches == 1 && stats.CurrentTimeline == 1 &&
+				stats.AvailableTimelines == 0 && stats.TimelineProgress[1].Equal(start.Add(-time.Second))
+		}},
+	}
+
+	for _, tc := range testCases {
+		tc.action()
+		stats := idGen.Stats()
+		if !tc.check(stats) {
+			t.Fatalf("【失败】-%s-got:%+v", tc.name, stats)
+		}
+		remaining := stats.Remaining - idGen.settings.Capacity().Until.Sub(clock.Time())
+		if remaining < -time.Millisecond || remaining > time.Millisecond {
+			t.Fatalf("【失败】-%s-剩余可用时长-got:%v", tc.name, stats.Remaining)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package generator
+
+import "context"
+
+// Stream 后台goroutine持续生成id，填充容量为buffer的channel，消费方的热路径不再承担生成时的等待
+//   - ctx取消后停止生成并关闭channel，channel中剩余的id仍可读取
+//   - 时钟回退等带有重试间隔(RetryAfter)的错误等待后重试；生成器失效、时间部分用尽等无法恢复的错误时关闭channel，可调用Generate获取原因
+//   - 预先生成的id时间早于实际读取的时间，不适合对id时间精度有要求的场景
+//   - 生成器关闭(Close)时同样停止生成并关闭channel
+func (idGen *IDGenerator) Stream(ctx context.Context, buffer int) <-chan int64 {
+	if buffer < 0 {
+		buffer = 0
+	}
+	ch := make(chan int64, buffer)
+	idGen.mutex.Lock()
+	if idGen.closed {
+		idGen.mutex.Unlock()
+		close(ch)
+		return ch
+	}
+	idGen.background.Add(1)
+	idGen.mutex.Unlock()
+
+	//生成器关闭时取消ctx，中断生成及重试时的等待
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		select {
+		case <-idGen.closing:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	goLabeled(idGen, func() {
+		defer idGen.background.Done()
+		defer cancel()
+		defer close(ch)
+		for {
+			id, err := idGen.GenerateCtx(ctx)
+			if err != nil {
+				retry, ok := RetryAfter(err)
+				if !ok || ctx.Err() != nil {
+					return
+				}
+				idGen.mutex.Lock()
+				timer := idGen.currentTimer()
+				idGen.mutex.Unlock()
+				if timer.Sleep(ctx, retry) != nil {
+					return
+				}
+				continue
+			}
+			select {
+			case ch <- id:
+			case <-ctx.Done():
+				return
+			}
+		}
+	})
+	return ch
+}
@@ -0,0 +1,31 @@
+package generator
+
+import (
+	"context"
+	"testing"
+)
+
+// TestStream 后台生成的id递增不重复，ctx取消或生成器失效后关闭channel
+func TestStream(t *testing.T) {
+	idGen, _ := NewGenerator(1)
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := idGen.Stream(ctx, 64)
+
+	var last int64
+	for i := 0; i < 10000; i++ {
+		id, ok := <-stream
+		if !ok || id <= last {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", "递增不重复", id, last)
+		}
+		last = id
+	}
+	cancel()
+	for range stream {
+	}
+
+	fenced, _ := NewGenerator(2)
+	fenced.Fence(nil)
+	if _, ok := <-fenced.Stream(context.Background(), 0); ok {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "生成器失效", ok, false)
+	}
+}
@@ -0,0 +1,29 @@
+package generator
+
+import (
+	"fmt"
+	"time"
+)
+
+// String 生成器概要：id结构、机器ID、基准时间及时间线状态，便于日志输出
+//   - 如：IDGenerator{layout:41/9/1/12 machineID:1 epoch:2020-01-01T00:00:00Z timeline:0 progress:[1234 0]}
+func (idGen *IDGenerator) String() string {
+	idGen.mutex.Lock()
+	defer idGen.mutex.Unlock()
+
+	s := idGen.settings
+	return fmt.Sprintf("IDGenerator{layout:%d/%d/%d/%d machineID:%d epoch:%s timeline:%d progress:%v}",
+		s.TimeBit, s.MachineIDBit, s.TimelineBit, s.SeqBit, idGen.machineID,
+		time.Unix(0, s.Epoch).UTC().Format(time.RFC3339Nano), idGen.curTimeline, idGen.timelineProgress)
+}
+
+// GoString %#v格式输出，只包含配置与时间线状态，不输出锁、指标上报等内部字段
+func (idGen *IDGenerator) GoString() string {
+	idGen.mutex.Lock()
+	defer idGen.mutex.Unlock()
+
+	s := idGen.settings
+	return fmt.Sprintf("&generator.IDGenerator{settings:generator.Settings{TimeBit:%d, MachineIDBit:%d, TimelineBit:%d, SeqBit:%d, Epoch:%d}, machineID:%d, curTimeline:%d, seq:%d, timelineProgress:%#v}",
+		s.TimeBit, s.MachineIDBit, s.TimelineBit, s.SeqBit, s.Epoch,
+		idGen.machineID, idGen.curTimeline, idGen.seq, idGen.timelineProgress)
+}
@@ -0,0 +1,34 @@
+package generator
+
+import (
+	"fmt"
+	"testing"
+)
+
+// TestString 生成器格式化输出
+func TestString(t *testing.T) {
+	idGen, _ := NewGenerator(3)
+
+	testCases := []struct {
+		name   string
+		format string
+		want   string
+	}{
+		{
+			name:   "String",
+			format: "%v",
+			want:   "IDGenerator{layout:41/9/1/12 machineID:3 epoch:2020-01-01T00:00:00Z timeline:0 progress:[0 0]}",
+		},
+		{
+			name:   "GoString",
+			format: "%#v",
+			want:   fmt.Sprintf("&generator.IDGenerator{settings:generator.Settings{TimeBit:41, MachineIDBit:9, TimelineBit:1, SeqBit:12, Epoch:%d}, machineID:3, curTimeline:0, seq:0, timelineProgress:[]int64{0, 0}}", DefaultEpoch),
+		},
+	}
+
+	for _, tc := range testCases {
+		if got := fmt.Sprintf(tc.format, idGen); got != tc.want {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, got, tc.want)
+		}
+	}
+}
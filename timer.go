@@ -0,0 +1,86 @@
+package generator
+
+import (
+	"context"
+	"runtime"
+	"time"
+)
+
+// Timer 生成器内部等待(序号用完、时钟回退、自检等)的实现
+//   - 可替换为高精度定时器、仿真中的虚拟时间或自定义运行时中的协作式让出
+//   - Sleep等待d，ctx取消时提前返回ctx.Err()；d<=0时立即返回ctx.Err()
+//   - Yield在自旋等待(WaitSpin、WaitHybrid)的每次循环中调用
+type Timer interface {
+	Sleep(ctx context.Context, d time.Duration) error
+	Yield()
+}
+
+// SystemTimer 基于time.Timer与runtime.Gosched的实现，生成器缺省使用
+var SystemTimer Timer = systemTimer{}
+
+type systemTimer struct{}
+
+func (systemTimer) Sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	if ctx.Done() == nil {
+		time.Sleep(d)
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (systemTimer) Yield() { runtime.Gosched() }
+
+// sleeperTimer 将实现了Sleeper的时间源(如假时钟)适配为Timer
+type sleeperTimer struct {
+	sleeper Sleeper
+}
+
+func (t sleeperTimer) Sleep(ctx context.Context, d time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if d > 0 {
+		t.sleeper.Sleep(d)
+	}
+	return nil
+}
+
+func (t sleeperTimer) Yield() {}
+
+// SetTimer 设置内部等待的实现，nil表示缺省：时间源实现了Sleeper时由时间源等待，否则使用SystemTimer
+//   - 也可通过Settings.Timer在创建时指定
+func (idGen *IDGenerator) SetTimer(timer Timer) {
+	idGen.mutex.Lock()
+	defer idGen.mutex.Unlock()
+	idGen.timer = timer
+}
+
+// currentTimer 当前使用的等待实现，调用方须持有锁
+func (idGen *IDGenerator) currentTimer() Timer {
+	if idGen.timer != nil {
+		return idGen.timer
+	}
+	if sleeper, ok := idGen.clock.(Sleeper); ok {
+		return sleeperTimer{sleeper: sleeper}
+	}
+	return SystemTimer
+}
+
+// sleep 等待d，GenerateCtx的ctx取消时提前返回ctx.Err()，调用方须持有锁
+func (idGen *IDGenerator) sleep(d time.Duration) error {
+	ctx := idGen.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return idGen.currentTimer().Sleep(ctx, d)
+}
@@ -0,0 +1,101 @@
+package generator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jayecc/mtl-snowflake/fakeclock"
+)
+
+// virtualTimer 虚拟时间的等待实现：Sleep推进时钟，Yield推进一微秒
+type virtualTimer struct {
+	clock  *fakeclock.Clock
+	sleeps []time.Duration
+	yields int
+}
+
+func (t *virtualTimer) Sleep(ctx context.Context, d time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	t.sleeps = append(t.sleeps, d)
+	t.clock.Advance(d)
+	return nil
+}
+
+func (t *virtualTimer) Yield() {
+	t.yields++
+	t.clock.Advance(time.Microsecond)
+}
+
+// TestTimer 替换内部等待的实现
+func TestTimer(t *testing.T) {
+	testCases := []struct {
+		name       string
+		policy     WaitPolicy
+		wantSleeps bool
+		wantYields bool
+	}{
+		{name: "休眠", policy: WaitSleep, wantSleeps: true, wantYields: false},
+		{name: "自旋", policy: WaitSpin, wantSleeps: false, wantYields: true},
+		{name: "混合", policy: WaitHybrid, wantSleeps: true, wantYields: true},
+	}
+	for _, tc := range testCases {
+		//时钟位于时间单位起点，每个时间单位4个序号
+		clock := fakeclock.New(time.Now().Truncate(time.Millisecond))
+		timer := &virtualTimer{clock: clock}
+		settings := Settings{TimeBit: 41, MachineIDBit: 19, TimelineBit: 1, SeqBit: 2, Epoch: DefaultEpoch, Clock: clock, Timer: timer}
+		idGen, _ := NewGeneratorWithSettings(0, settings)
+		idGen.SetWaitPolicy(tc.policy)
+
+		start := clock.Now()
+		for i := 0; i < 5; i++ {
+			if _, err := idGen.Generate(); err != nil {
+				t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, err, nil)
+			}
+		}
+		if (len(timer.sleeps) > 0) != tc.wantSleeps || (timer.yields > 0) != tc.wantYields {
+			t.Fatalf("【失败】-%s-got:%v,%v-want:%v,%v", tc.name, timer.sleeps, timer.yields, tc.wantSleeps, tc.wantYields)
+		}
+		if elapsed := time.Duration(clock.Now() - start); elapsed < time.Millisecond {
+			t.Fatalf("【失败】-%s-虚拟时间-got:%v-want:>=%v", tc.name, elapsed, time.Millisecond)
+		}
+	}
+
+	//SetTimer(nil)恢复缺省：由假时钟等待
+	clock := fakeclock.New(time.Now())
+	settings := *DefaultSettings
+	settings.Clock = clock
+	settings.Timer = &virtualTimer{clock: clock}
+	idGen, _ := NewGeneratorWithSettings(0, settings)
+	idGen.SetTimer(nil)
+	if _, ok := idGen.currentTimer().(sleeperTimer); !ok {
+		t.Fatalf("【失败】-%s-got:%T-want:%v", "缺省", idGen.currentTimer(), "sleeperTimer")
+	}
+}
+
+// TestSystemTimer 缺省等待实现
+func TestSystemTimer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	testCases := []struct {
+		name    string
+		ctx     context.Context
+		d       time.Duration
+		wantErr error
+	}{
+		{name: "等待", ctx: context.Background(), d: time.Millisecond, wantErr: nil},
+		{name: "无需等待", ctx: context.Background(), d: 0, wantErr: nil},
+		{name: "已取消", ctx: ctx, d: time.Hour, wantErr: context.Canceled},
+	}
+	for _, tc := range testCases {
+		start := time.Now()
+		if err := SystemTimer.Sleep(tc.ctx, tc.d); err != tc.wantErr {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, err, tc.wantErr)
+		}
+		if elapsed := time.Since(start); elapsed < tc.d && tc.wantErr == nil || elapsed > time.Second {
+			t.Fatalf("【失败】-%s-等待时长-got:%v", tc.name, elapsed)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package generator
+
+import (
+	"encoding/hex"
+	"strings"
+)
+
+// uuidEpochOffset 1582-10-15 00:00:00 UTC 与 unix 零点之间相差的100ns间隔数
+const uuidEpochOffset int64 = 0x01B21DD213814000
+
+// maxTimeUUIDLowBit TimeUUID节点字段中可用于存放id低位(机器ID+时间线+序号)的位数
+const maxTimeUUIDLowBit uint64 = 40
+
+// TimeUUID Cassandra timeuuid(RFC 4122 version 1)
+type TimeUUID [16]byte
+
+// String 标准格式：xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx
+func (u TimeUUID) String() string {
+	return formatUUID(u)
+}
+
+// ParseTimeUUID 解析标准格式的timeuuid字符串
+func ParseTimeUUID(s string) (TimeUUID, error) {
+	u, err := parseUUID("timeuuid", s)
+	return TimeUUID(u), err
+}
+
+// formatUUID 按标准格式输出uuid
+func formatUUID(u [16]byte) string {
+	buf := make([]byte, 36)
+	hex.Encode(buf[0:8], u[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], u[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], u[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], u[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], u[10:])
+	return string(buf)
+}
+
+// parseUUID 解析标准格式的uuid字符串，name用于错误信息
+func parseUUID(name, s string) ([16]byte, error) {
+	var u [16]byte
+	if len(s) != 36 || s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+		return u, errorf(CodeUUIDFormat, name, s)
+	}
+	raw, err := hex.DecodeString(strings.Replace(s, "-", "", -1))
+	if err != nil {
+		return u, errorf(CodeUUIDFormat, name, s)
+	}
+	copy(u[:], raw)
+	return u, nil
+}
+
+// ToTimeUUID 将id转换成Cassandra timeuuid
+//   - 时间戳字段：id中的时间部分(精确到时间单位)
+//   - clock-seq字段：序号的低14位，使同一时间单位内的timeuuid仍按序号排序
+//   - node字段：id的低位(机器ID+时间线+序号)，并置多播位以区别于基于网卡地址生成的timeuuid
+func (idGen *IDGenerator) ToTimeUUID(id int64) (TimeUUID, error) {
+	var u TimeUUID
+	presets := idGen.settings.presets
+	if !idGen.settings.timeOrdered() {
+		return u, errNotTimeOrdered
+	}
+	if presets.shiftTimeBit > maxTimeUUIDLowBit {
+		return u, errorf(CodeTimeUUIDBits, maxTimeUUIDLowBit)
+	}
+	if id < 0 {
+		return u, errorf(CodeNegativeID)
+	}
+	if id&presets.maskVersion != presets.version {
+		return u, errVersionMismatch
+	}
+
+	timePart := (id & presets.maskTime) >> presets.shiftTimeBit
+	ts := idGen.toUnixNano(timePart)/100 + uuidEpochOffset
+	low := id & (1<<presets.shiftTimeBit - 1)
+	clockSeq := (id & presets.maskSeq) >> presets.shiftSeq & 0x3FFF
+
+	u[0] = byte(ts >> 24)
+	u[1] = byte(ts >> 16)
+	u[2] = byte(ts >> 8)
+	u[3] = byte(ts)
+	u[4] = byte(ts >> 40)
+	u[5] = byte(ts >> 32)
+	u[6] = byte(ts>>56)&0x0F | 0x10 //version 1
+	u[7] = byte(ts >> 48)
+	u[8] = byte(clockSeq>>8)&0x3F | 0x80 //variant RFC 4122
+	u[9] = byte(clockSeq)
+	u[10] = byte(low>>40) | 0x01 //多播位
+	u[11] = byte(low >> 32)
+	u[12] = byte(low >> 24)
+	u[13] = byte(low >> 16)
+	u[14] = byte(low >> 8)
+	u[15] = byte(low)
+	return u, nil
+}
+
+// FromTimeUUID 将由ToTimeUUID生成的timeuuid还原成id
+func (idGen *IDGenerator) FromTimeUUID(u TimeUUID) (int64, error) {
+	presets := idGen.settings.presets
+	if !idGen.settings.timeOrdered() {
+		return 0, errNotTimeOrdered
+	}
+	if u[6]>>4 != 1 || u[8]&0xC0 != 0x80 {
+		return 0, errorf(CodeTimeUUIDVersion)
+	}
+	if u[10]&0x01 == 0 {
+		return 0, errorf(CodeTimeUUIDMulticast)
+	}
+
+	ts := int64(u[6]&0x0F)<<56 | int64(u[7])<<48 | int64(u[4])<<40 | int64(u[5])<<32 |
+		int64(u[0])<<24 | int64(u[1])<<16 | int64(u[2])<<8 | int64(u[3])
+	unixNano := (ts - uuidEpochOffset) * 100
+	timePart := idGen.toOffsetTime(unixNano)
+	if timePart < 0 || timePart > presets.maxTime || idGen.toUnixNano(timePart) != unixNano {
+		return 0, errorf(CodeTimeUUIDTime)
+	}
+
+	low := int64(u[10]&0xFE)<<40 | int64(u[11])<<32 | int64(u[12])<<24 |
+		int64(u[13])<<16 | int64(u[14])<<8 | int64(u[15])
+	if low>>presets.shiftTimeBit != 0 {
+		return 0, errorf(CodeTimeUUIDNode)
+	}
+	return presets.version | timePart<<presets.shiftTimeBit | low, nil
+}
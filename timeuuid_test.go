@@ -0,0 +1,62 @@
+package generator
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+// TestTimeUUID id与timeuuid互转
+func TestTimeUUID(t *testing.T) {
+	idGen, _ := NewGenerator(3)
+
+	var prev TimeUUID
+	for i := 0; i < 1e4; i++ {
+		id, err := idGen.Generate()
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		u, err := idGen.ToTimeUUID(int64(id))
+		if err != nil {
+			t.Fatalf("【失败】-转换timeuuid-%v", err)
+		}
+		parsed, err := ParseTimeUUID(u.String())
+		if err != nil || parsed != u {
+			t.Fatalf("【失败】-解析timeuuid-got:%v-want:%v-err:%v", parsed, u, err)
+		}
+		got, err := idGen.FromTimeUUID(parsed)
+		if err != nil || got != int64(id) {
+			t.Fatalf("【失败】-还原id-got:%d-want:%d-err:%v", got, id, err)
+		}
+		if i > 0 && timeUUIDLess(u, prev) {
+			t.Fatalf("【失败】-timeuuid未保持时间顺序-%s-%s", prev, u)
+		}
+		prev = u
+	}
+
+	//位数过多无法转换
+	wide, err := NewGeneratorWithSettings(0, Settings{TimeBit: 21, MachineIDBit: 20, TimelineBit: 1, SeqBit: 21, Epoch: time.Now().Add(-time.Minute).UnixNano()})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if _, err := wide.ToTimeUUID(1); err == nil {
+		t.Fatalf("【失败】-低位超过40位应转换失败")
+	}
+
+	//非id转换而来的timeuuid
+	foreign, _ := ParseTimeUUID("5c2a6f30-0d5c-11ee-8000-0242ac120002")
+	if _, err := idGen.FromTimeUUID(foreign); err == nil {
+		t.Fatalf("【失败】-未置多播位的timeuuid应还原失败")
+	}
+}
+
+// timeUUIDLess 按Cassandra TimeUUIDType的规则比较：先比较时间戳，再比较剩余字节
+func timeUUIDLess(a, b TimeUUID) bool {
+	ts := func(u TimeUUID) []byte {
+		return []byte{u[6] & 0x0F, u[7], u[4], u[5], u[0], u[1], u[2], u[3]}
+	}
+	if c := bytes.Compare(ts(a), ts(b)); c != 0 {
+		return c < 0
+	}
+	return bytes.Compare(a[8:], b[8:]) < 0
+}
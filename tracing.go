@@ -0,0 +1,81 @@
+package generator
+
+import (
+	"context"
+	"time"
+)
+
+// 链路追踪的span及事件名称
+const (
+	SpanSeqExhaustedWait  = "snowflake.wait.seq_exhausted"  //span  序号用完等待下一个时间单位
+	SpanClockBackwardWait = "snowflake.wait.clock_backward" //span  时钟回退等待时钟追回
+	EventTimelineSwitch   = "snowflake.timeline_switch"     //event 时钟回退切换时间线
+)
+
+// 链路追踪的属性
+const (
+	AttrMachineID    = "snowflake.machine_id"       //机器ID
+	AttrTimeline     = "snowflake.timeline"         //时间线(切换时为切换后的时间线)
+	AttrExpectedWait = "snowflake.expected_wait_ns" //预计等待时长(ns)
+	AttrBackward     = "snowflake.backward_ns"      //时钟回退的时长(ns)
+)
+
+// Tracer 链路追踪接口，可适配OpenTelemetry等实现
+//   - 只在少见的慢路径(序号用完等待、时钟回退等待、切换时间线)调用，解释偶发的毫秒级生成延迟
+//   - ctx为GenerateCtx的ctx(Generate时为context.Background())，实现应将span关联到ctx中调用方的活动span
+//   - 在生成器锁内调用，实现须快速返回且不能阻塞
+type Tracer interface {
+	StartSpan(ctx context.Context, name string, attrs map[string]int64) TraceSpan
+	Event(ctx context.Context, name string, attrs map[string]int64)
+}
+
+// TraceSpan 慢路径的span，等待结束时调用End，err为等待失败的原因(如ctx取消)
+type TraceSpan interface {
+	End(err error)
+}
+
+// SetTracer 设置链路追踪，nil表示不追踪
+func (idGen *IDGenerator) SetTracer(tracer Tracer) {
+	idGen.mutex.Lock()
+	defer idGen.mutex.Unlock()
+	idGen.tracer = tracer
+}
+
+// traceContext 链路追踪使用的ctx，调用方须持有锁
+func (idGen *IDGenerator) traceContext() context.Context {
+	if idGen.ctx != nil {
+		return idGen.ctx
+	}
+	return context.Background()
+}
+
+// traceWait 开始等待的span，未设置链路追踪时返回nil，调用方须持有锁
+func (idGen *IDGenerator) traceWait(name string, wait time.Duration) TraceSpan {
+	if idGen.tracer == nil {
+		return nil
+	}
+	return idGen.tracer.StartSpan(idGen.traceContext(), name, map[string]int64{
+		AttrMachineID:    idGen.machineID,
+		AttrTimeline:     idGen.curTimeline,
+		AttrExpectedWait: int64(wait),
+	})
+}
+
+// endTrace 结束span
+func endTrace(span TraceSpan, err error) {
+	if span != nil {
+		span.End(err)
+	}
+}
+
+// traceTimelineSwitch 记录切换时间线事件，调用方须持有锁
+func (idGen *IDGenerator) traceTimelineSwitch(timeline int64, backward time.Duration) {
+	if idGen.tracer == nil {
+		return
+	}
+	idGen.tracer.Event(idGen.traceContext(), EventTimelineSwitch, map[string]int64{
+		AttrMachineID: idGen.machineID,
+		AttrTimeline:  timeline,
+		AttrBackward:  int64(backward),
+	})
+}
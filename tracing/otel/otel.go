@@ -0,0 +1,74 @@
+// Package otel 将生成器慢路径的链路追踪记录为OpenTelemetry span
+//   - Tracer实现generator.Tracer，通过SetTracer设置后，序号用完等待、时钟回退等待记录为ctx中活动span的子span，
+//     切换时间线记录为活动span的事件，ctx中没有活动span时记录为单独的span
+//   - 等待失败(如ctx取消)时span记录错误并将状态设置为Error
+//
+// 独立的go module，避免主模块引入OpenTelemetry依赖
+package otel
+
+import (
+	"context"
+	"sort"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	generator "github.com/jayecc/mtl-snowflake"
+)
+
+// Tracer 基于OpenTelemetry trace.Tracer的链路追踪
+type Tracer struct {
+	tracer trace.Tracer
+}
+
+var _ generator.Tracer = (*Tracer)(nil)
+
+// New 创建链路追踪，tracer通常由TracerProvider.Tracer得到
+func New(tracer trace.Tracer) *Tracer {
+	return &Tracer{tracer: tracer}
+}
+
+// StartSpan 开始ctx中活动span的子span
+func (t *Tracer) StartSpan(ctx context.Context, name string, attrs map[string]int64) generator.TraceSpan {
+	_, span := t.tracer.Start(ctx, name, trace.WithAttributes(attributes(attrs)...))
+	return traceSpan{span: span}
+}
+
+// Event 在ctx中活动的span上记录事件，没有活动的span时记录为单独的span
+func (t *Tracer) Event(ctx context.Context, name string, attrs map[string]int64) {
+	if span := trace.SpanFromContext(ctx); span.IsRecording() {
+		span.AddEvent(name, trace.WithAttributes(attributes(attrs)...))
+		return
+	}
+	_, span := t.tracer.Start(ctx, name, trace.WithAttributes(attributes(attrs)...))
+	span.End()
+}
+
+// traceSpan 慢路径的span
+type traceSpan struct {
+	span trace.Span
+}
+
+// End 结束span，err不为nil时记录错误
+func (s traceSpan) End(err error) {
+	if err != nil {
+		s.span.RecordError(err)
+		s.span.SetStatus(codes.Error, err.Error())
+	}
+	s.span.End()
+}
+
+// attributes 按属性名排序转换为OpenTelemetry属性
+func attributes(attrs map[string]int64) []attribute.KeyValue {
+	keys := make([]string, 0, len(attrs))
+	for key := range attrs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	result := make([]attribute.KeyValue, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, attribute.Int64(key, attrs[key]))
+	}
+	return result
+}
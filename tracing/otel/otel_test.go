@@ -0,0 +1,88 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	generator "github.com/jayecc/mtl-snowflake"
+	"github.com/jayecc/mtl-snowflake/fakeclock"
+)
+
+// TestTracer 慢路径记录为OpenTelemetry span及事件
+func TestTracer(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	defer provider.Shutdown(context.Background())
+	tracer := provider.Tracer("snowflake")
+
+	//时钟位于时间单位起点，每个时间单位4个序号
+	clock := fakeclock.New(time.Now().Truncate(time.Millisecond))
+	settings := generator.Settings{TimeBit: 41, MachineIDBit: 19, TimelineBit: 1, SeqBit: 2, Epoch: generator.DefaultEpoch, Clock: clock}
+	idGen, _ := generator.NewGeneratorWithSettings(7, settings)
+	idGen.SetTracer(New(tracer))
+
+	ctx, request := tracer.Start(context.Background(), "request")
+	for i := 0; i < 5; i++ {
+		idGen.GenerateCtx(ctx)
+	}
+	clock.Advance(-5 * time.Millisecond)
+	idGen.GenerateCtx(ctx)
+	request.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "span数", len(spans), 2)
+	}
+	wait, parent := spans[0], spans[1]
+	if wait.Name() != generator.SpanSeqExhaustedWait || wait.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "序号用完等待", wait.Name(), generator.SpanSeqExhaustedWait)
+	}
+	if got := attrMap(wait.Attributes()); got[generator.AttrMachineID] != 7 || got[generator.AttrExpectedWait] != int64(time.Millisecond) {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "序号用完等待属性", got, "machine_id=7")
+	}
+	events := parent.Events()
+	if len(events) != 1 || events[0].Name != generator.EventTimelineSwitch {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "切换时间线事件", events, generator.EventTimelineSwitch)
+	}
+	if got := attrMap(events[0].Attributes); got[generator.AttrTimeline] != 1 || got[generator.AttrBackward] != int64(5*time.Millisecond) {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "切换时间线属性", got, "timeline=1")
+	}
+}
+
+// TestTracerStandalone 没有活动span时事件记录为单独的span，等待失败时记录错误
+func TestTracerStandalone(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	defer provider.Shutdown(context.Background())
+	tracer := New(provider.Tracer("snowflake"))
+
+	tracer.Event(context.Background(), generator.EventTimelineSwitch, map[string]int64{generator.AttrTimeline: 1})
+	tracer.StartSpan(context.Background(), generator.SpanClockBackwardWait, nil).End(errors.New("context canceled"))
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "span数", len(spans), 2)
+	}
+	if spans[0].Name() != generator.EventTimelineSwitch || attrMap(spans[0].Attributes())[generator.AttrTimeline] != 1 {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "单独的事件span", spans[0].Name(), generator.EventTimelineSwitch)
+	}
+	if status := spans[1].Status(); status.Code != codes.Error || len(spans[1].Events()) != 1 {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "等待失败", status, codes.Error)
+	}
+}
+
+// attrMap 属性转换为map
+func attrMap(attrs []attribute.KeyValue) map[string]int64 {
+	result := make(map[string]int64, len(attrs))
+	for _, kv := range attrs {
+		result[string(kv.Key)] = kv.Value.AsInt64()
+	}
+	return result
+}
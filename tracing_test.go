@@ -0,0 +1,111 @@
+package generator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jayecc/mtl-snowflake/fakeclock"
+)
+
+// traceRecord 记录的span或事件
+type traceRecord struct {
+	name   string
+	parent interface{} //ctx中调用方的span
+	attrs  map[string]int64
+	ended  bool
+}
+
+// recordingTracer 记录span及事件的链路追踪实现
+type recordingTracer struct {
+	records []*traceRecord
+}
+
+type parentSpanKey struct{}
+
+func (t *recordingTracer) StartSpan(ctx context.Context, name string, attrs map[string]int64) TraceSpan {
+	r := &traceRecord{name: name, parent: ctx.Value(parentSpanKey{}), attrs: attrs}
+	t.records = append(t.records, r)
+	return r
+}
+
+func (t *recordingTracer) Event(ctx context.Context, name string, attrs map[string]int64) {
+	t.records = append(t.records, &traceRecord{name: name, parent: ctx.Value(parentSpanKey{}), attrs: attrs, ended: true})
+}
+
+func (r *traceRecord) End(err error) { r.ended = true }
+
+// TestTracer 慢路径的链路追踪
+func TestTracer(t *testing.T) {
+	//时钟位于时间单位起点，每个时间单位4个序号
+	clock := fakeclock.New(time.Now().Truncate(time.Millisecond))
+	settings := Settings{TimeBit: 41, MachineIDBit: 19, TimelineBit: 1, SeqBit: 2, Epoch: DefaultEpoch, Clock: clock}
+	idGen, _ := NewGeneratorWithSettings(7, settings)
+	tracer := &recordingTracer{}
+	idGen.SetTracer(tracer)
+	ctx := context.WithValue(context.Background(), parentSpanKey{}, "request")
+
+	testCases := []struct {
+		name      string
+		prepare   func()
+		wantName  string
+		wantAttrs map[string]int64
+	}{
+		{name: "快路径不追踪", prepare: func() {}, wantName: ""},
+		{name: "序号用完等待", prepare: func() {
+			for i := 0; i < 3; i++ {
+				idGen.Generate()
+			}
+		}, wantName: SpanSeqExhaustedWait, wantAttrs: map[string]int64{AttrMachineID: 7, AttrTimeline: 0, AttrExpectedWait: int64(time.Millisecond)}},
+		{name: "切换时间线", prepare: func() { clock.Advance(-5 * time.Millisecond) },
+			wantName: EventTimelineSwitch, wantAttrs: map[string]int64{AttrMachineID: 7, AttrTimeline: 1, AttrBackward: int64(5 * time.Millisecond)}},
+	}
+	for _, tc := range testCases {
+		tc.prepare()
+		tracer.records = nil
+		if _, err := idGen.GenerateCtx(ctx); err != nil {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, err, nil)
+		}
+		if tc.wantName == "" {
+			if len(tracer.records) != 0 {
+				t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, len(tracer.records), 0)
+			}
+			continue
+		}
+		if len(tracer.records) != 1 {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, len(tracer.records), 1)
+		}
+		r := tracer.records[0]
+		if r.name != tc.wantName || r.parent != "request" || !r.ended {
+			t.Fatalf("【失败】-%s-got:%+v-want:%v", tc.name, r, tc.wantName)
+		}
+		for key, want := range tc.wantAttrs {
+			if got := r.attrs[key]; got != want {
+				t.Fatalf("【失败】-%s-%s-got:%v-want:%v", tc.name, key, got, want)
+			}
+		}
+	}
+}
+
+// TestTracerClockCatchUp 严格递增模式下等待时钟追回
+func TestTracerClockCatchUp(t *testing.T) {
+	clock := fakeclock.New(time.Now().Truncate(time.Millisecond))
+	settings := *DefaultSettings
+	settings.Clock = clock
+	idGen, _ := NewGeneratorWithSettings(0, settings)
+	idGen.SetStrictMonotonic(true, time.Second)
+	tracer := &recordingTracer{}
+	idGen.SetTracer(tracer)
+
+	idGen.Generate()
+	clock.Advance(-3 * time.Millisecond)
+	if _, err := idGen.Generate(); err != nil {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "生成", err, nil)
+	}
+	if len(tracer.records) != 1 || tracer.records[0].name != SpanClockBackwardWait || !tracer.records[0].ended {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "时钟回退等待", tracer.records, SpanClockBackwardWait)
+	}
+	if got := tracer.records[0].attrs[AttrExpectedWait]; got != int64(3*time.Millisecond) {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "预计等待", got, 3*time.Millisecond)
+	}
+}
@@ -0,0 +1,176 @@
+package generator
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// SQLDialect SQL方言
+type SQLDialect int
+
+const (
+	DialectMySQL      SQLDialect = iota //MySQL 8.0+
+	DialectPostgres                     //PostgreSQL 10+
+	DialectClickHouse                   //ClickHouse 21.10+
+)
+
+// String 方言名称
+func (d SQLDialect) String() string {
+	switch d {
+	case DialectMySQL:
+		return "mysql"
+	case DialectPostgres:
+		return "postgres"
+	case DialectClickHouse:
+		return "clickhouse"
+	}
+	return fmt.Sprintf("SQLDialect(%d)", int(d))
+}
+
+// ParseSQLDialect 解析方言名称：mysql、postgres、clickhouse
+func ParseSQLDialect(name string) (SQLDialect, error) {
+	switch strings.ToLower(name) {
+	case "mysql":
+		return DialectMySQL, nil
+	case "postgres", "postgresql", "pg":
+		return DialectPostgres, nil
+	case "clickhouse", "ch":
+		return DialectClickHouse, nil
+	}
+	return 0, errorf(CodeUnsupportedDialect, name)
+}
+
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// udfParams 生成SQL函数使用的常量
+type udfParams struct {
+	prefix                                             string
+	shiftTime, shiftMachineID, shiftTimeline, shiftSeq uint64
+	maxMachineID, maxTimeline, maxSeq                  int64
+	epochMicros, unitMicros                            int64
+}
+
+// seqExpr 序号表达式，预留标记位时使用shifted(移位位数, 掩码)，否则使用plain(掩码)
+func (p udfParams) seqExpr(shifted, plain string) string {
+	if p.shiftSeq > 0 {
+		return fmt.Sprintf(shifted, p.shiftSeq, p.maxSeq)
+	}
+	return fmt.Sprintf(plain, p.maxSeq)
+}
+
+// GenerateSQLFunctions 根据配置生成解析id的SQL函数，函数名均以prefix开头：
+//   - prefix_machine_id(id)、prefix_timeline(id)、prefix_seq(id) 解析id各部分
+//   - prefix_decompose(id) 一次解析全部部分(MySQL返回JSON，PostgreSQL返回记录，ClickHouse返回tuple)
+//   - prefix_to_timestamp(id) id的生成时间
+//   - prefix_min_id_for_time(ts) 不早于ts生成的最小id，与CutoffIDAt一致
+//
+// 时间精度为微秒，时区由数据库会话决定
+func GenerateSQLFunctions(settings Settings, dialect SQLDialect, prefix string) (string, error) {
+	if err := settings.checkBits(); err != nil {
+		return "", err
+	}
+	if !identifierPattern.MatchString(prefix) {
+		return "", errorf(CodeFunctionPrefix)
+	}
+	if settings.Epoch%1000 != 0 {
+		return "", errorf(CodeEpochMicrosecond)
+	}
+	if !settings.timeOrdered() {
+		return "", errNotTimeOrdered
+	}
+	if settings.VersionBit != 0 {
+		return "", errorf(CodeUDFVersion)
+	}
+
+	presets := calcPresets(&settings)
+	p := udfParams{
+		prefix:         prefix,
+		shiftTime:      presets.shiftTimeBit,
+		shiftMachineID: presets.shiftMachineIDBit,
+		shiftTimeline:  presets.shiftTimelineBit,
+		shiftSeq:       presets.shiftSeq,
+		maxMachineID:   presets.maxMachineID,
+		maxTimeline:    presets.maxTimeline,
+		maxSeq:         presets.maxSeq,
+		epochMicros:    settings.Epoch / 1000,
+		unitMicros:     settings.unit() / 1000,
+	}
+
+	switch dialect {
+	case DialectMySQL:
+		return mysqlFunctions(p), nil
+	case DialectPostgres:
+		return postgresFunctions(p), nil
+	case DialectClickHouse:
+		return clickhouseFunctions(p), nil
+	}
+	return "", errorf(CodeUnsupportedDialect, dialect)
+}
+
+// mysqlFunctions MySQL函数，需要 log_bin_trust_function_creators 或 SUPER 权限
+func mysqlFunctions(p udfParams) string {
+	var b strings.Builder
+	part := func(name, expr string) {
+		fmt.Fprintf(&b, "DROP FUNCTION IF EXISTS %s_%s;\n", p.prefix, name)
+		fmt.Fprintf(&b, "CREATE FUNCTION %s_%s(id BIGINT) RETURNS BIGINT DETERMINISTIC\nRETURN %s;\n\n", p.prefix, name, expr)
+	}
+	part("machine_id", fmt.Sprintf("(id >> %d) & %d", p.shiftMachineID, p.maxMachineID))
+	part("timeline", fmt.Sprintf("(id >> %d) & %d", p.shiftTimeline, p.maxTimeline))
+	part("seq", p.seqExpr("(id >> %d) & %d", "id & %d"))
+
+	fmt.Fprintf(&b, "DROP FUNCTION IF EXISTS %s_to_timestamp;\n", p.prefix)
+	fmt.Fprintf(&b, "CREATE FUNCTION %s_to_timestamp(id BIGINT) RETURNS DATETIME(6) DETERMINISTIC\n", p.prefix)
+	micros := fmt.Sprintf("((id >> %d) * %d + %d)", p.shiftTime, p.unitMicros, p.epochMicros)
+	fmt.Fprintf(&b, "RETURN FROM_UNIXTIME(%[1]s DIV 1000000) + INTERVAL (%[1]s MOD 1000000) MICROSECOND;\n\n", micros)
+
+	fmt.Fprintf(&b, "DROP FUNCTION IF EXISTS %s_decompose;\n", p.prefix)
+	fmt.Fprintf(&b, "CREATE FUNCTION %s_decompose(id BIGINT) RETURNS JSON DETERMINISTIC\n", p.prefix)
+	fmt.Fprintf(&b, "RETURN JSON_OBJECT('generated_at', %[1]s_to_timestamp(id), 'machine_id', %[1]s_machine_id(id), 'timeline', %[1]s_timeline(id), 'seq', %[1]s_seq(id));\n\n", p.prefix)
+
+	fmt.Fprintf(&b, "DROP FUNCTION IF EXISTS %s_min_id_for_time;\n", p.prefix)
+	fmt.Fprintf(&b, "CREATE FUNCTION %s_min_id_for_time(ts DATETIME(6)) RETURNS BIGINT DETERMINISTIC\n", p.prefix)
+	fmt.Fprintf(&b, "RETURN (GREATEST(FLOOR(UNIX_TIMESTAMP(ts) * 1000000) - %d, 0) DIV %d) << %d;\n", p.epochMicros, p.unitMicros, p.shiftTime)
+	return b.String()
+}
+
+// postgresFunctions PostgreSQL函数
+func postgresFunctions(p udfParams) string {
+	var b strings.Builder
+	part := func(name, expr string) {
+		fmt.Fprintf(&b, "CREATE OR REPLACE FUNCTION %s_%s(id bigint) RETURNS bigint\n", p.prefix, name)
+		fmt.Fprintf(&b, "LANGUAGE sql IMMUTABLE STRICT AS $$ SELECT %s $$;\n\n", expr)
+	}
+	part("machine_id", fmt.Sprintf("(id >> %d) & %d", p.shiftMachineID, p.maxMachineID))
+	part("timeline", fmt.Sprintf("(id >> %d) & %d", p.shiftTimeline, p.maxTimeline))
+	part("seq", p.seqExpr("(id >> %d) & %d", "id & %d"))
+
+	fmt.Fprintf(&b, "CREATE OR REPLACE FUNCTION %s_to_timestamp(id bigint) RETURNS timestamptz\n", p.prefix)
+	fmt.Fprintf(&b, "LANGUAGE sql IMMUTABLE STRICT AS $$ SELECT timestamptz 'epoch' + ((id >> %d) * %d + %d) * interval '1 microsecond' $$;\n\n",
+		p.shiftTime, p.unitMicros, p.epochMicros)
+
+	fmt.Fprintf(&b, "CREATE OR REPLACE FUNCTION %s_decompose(id bigint, OUT generated_at timestamptz, OUT machine_id bigint, OUT timeline bigint, OUT seq bigint)\n", p.prefix)
+	fmt.Fprintf(&b, "LANGUAGE sql IMMUTABLE STRICT AS $$ SELECT %[1]s_to_timestamp(id), %[1]s_machine_id(id), %[1]s_timeline(id), %[1]s_seq(id) $$;\n\n", p.prefix)
+
+	fmt.Fprintf(&b, "CREATE OR REPLACE FUNCTION %s_min_id_for_time(ts timestamptz) RETURNS bigint\n", p.prefix)
+	fmt.Fprintf(&b, "LANGUAGE sql IMMUTABLE STRICT AS $$ SELECT (GREATEST(floor(extract(epoch FROM ts) * 1000000)::bigint - %d, 0) / %d) << %d $$;\n",
+		p.epochMicros, p.unitMicros, p.shiftTime)
+	return b.String()
+}
+
+// clickhouseFunctions ClickHouse函数
+func clickhouseFunctions(p udfParams) string {
+	var b strings.Builder
+	part := func(name, expr string) {
+		fmt.Fprintf(&b, "CREATE OR REPLACE FUNCTION %s_%s AS (id) -> %s;\n", p.prefix, name, expr)
+	}
+	part("machine_id", fmt.Sprintf("bitAnd(bitShiftRight(toInt64(id), %d), %d)", p.shiftMachineID, p.maxMachineID))
+	part("timeline", fmt.Sprintf("bitAnd(bitShiftRight(toInt64(id), %d), %d)", p.shiftTimeline, p.maxTimeline))
+	part("seq", p.seqExpr("bitAnd(bitShiftRight(toInt64(id), %d), %d)", "bitAnd(toInt64(id), %d)"))
+	part("to_timestamp", fmt.Sprintf("fromUnixTimestamp64Micro(toInt64(bitShiftRight(toInt64(id), %d) * %d + %d))",
+		p.shiftTime, p.unitMicros, p.epochMicros))
+	part("decompose", fmt.Sprintf("tuple(%[1]s_to_timestamp(id), %[1]s_machine_id(id), %[1]s_timeline(id), %[1]s_seq(id))", p.prefix))
+	fmt.Fprintf(&b, "CREATE OR REPLACE FUNCTION %s_min_id_for_time AS (ts) -> bitShiftLeft(intDiv(greatest(toUnixTimestamp64Micro(toDateTime64(ts, 6)) - %d, 0), %d), %d);\n",
+		p.prefix, p.epochMicros, p.unitMicros, p.shiftTime)
+	return b.String()
+}
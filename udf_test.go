@@ -0,0 +1,86 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestGenerateSQLFunctions 生成SQL函数
+func TestGenerateSQLFunctions(t *testing.T) {
+	custom := Settings{TimeBit: 39, MachineIDBit: 8, TimelineBit: 2, SeqBit: 14, Epoch: DefaultEpoch}
+
+	testCases := []struct {
+		name     string
+		settings Settings
+		dialect  SQLDialect
+		prefix   string
+		contains []string
+		wantErr  bool
+	}{
+		{
+			name:     "MySQL默认配置",
+			settings: *DefaultSettings,
+			dialect:  DialectMySQL,
+			prefix:   "sf",
+			contains: []string{
+				"CREATE FUNCTION sf_machine_id(id BIGINT) RETURNS BIGINT DETERMINISTIC\nRETURN (id >> 13) & 511;",
+				"RETURN (id >> 12) & 1;",
+				"RETURN id & 4095;",
+				"((id >> 22) * 1000 + 1577836800000000)",
+				"DIV 1000) << 22;",
+			},
+		},
+		{
+			name:     "PostgreSQL自定义配置",
+			settings: custom,
+			dialect:  DialectPostgres,
+			prefix:   "sf",
+			contains: []string{
+				"CREATE OR REPLACE FUNCTION sf_machine_id(id bigint) RETURNS bigint\nLANGUAGE sql IMMUTABLE STRICT AS $$ SELECT (id >> 16) & 255 $$;",
+				"SELECT (id >> 14) & 3 $$;",
+				"SELECT id & 16383 $$;",
+				"((id >> 24) * 1000 + 1577836800000000) * interval '1 microsecond'",
+				"SELECT sf_to_timestamp(id), sf_machine_id(id), sf_timeline(id), sf_seq(id) $$;",
+			},
+		},
+		{
+			name:     "ClickHouse默认配置",
+			settings: *DefaultSettings,
+			dialect:  DialectClickHouse,
+			prefix:   "sf",
+			contains: []string{
+				"CREATE OR REPLACE FUNCTION sf_machine_id AS (id) -> bitAnd(bitShiftRight(toInt64(id), 13), 511);",
+				"fromUnixTimestamp64Micro(toInt64(bitShiftRight(toInt64(id), 22) * 1000 + 1577836800000000))",
+				"- 1577836800000000, 0), 1000), 22);",
+			},
+		},
+		{name: "前缀不合法", settings: *DefaultSettings, dialect: DialectMySQL, prefix: "sf; DROP TABLE t", wantErr: true},
+		{name: "配置位数错误", settings: Settings{TimeBit: 41, MachineIDBit: 9, TimelineBit: 1, SeqBit: 10}, dialect: DialectMySQL, prefix: "sf", wantErr: true},
+		{name: "不支持的方言", settings: *DefaultSettings, dialect: SQLDialect(9), prefix: "sf", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		sql, err := GenerateSQLFunctions(tc.settings, tc.dialect, tc.prefix)
+		if (err != nil) != tc.wantErr {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, err, tc.wantErr)
+		}
+		for _, want := range tc.contains {
+			if !strings.Contains(sql, want) {
+				t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, sql, want)
+			}
+		}
+	}
+}
+
+// TestParseSQLDialect 解析方言名称
+func TestParseSQLDialect(t *testing.T) {
+	for _, d := range []SQLDialect{DialectMySQL, DialectPostgres, DialectClickHouse} {
+		got, err := ParseSQLDialect(d.String())
+		if err != nil || got != d {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", d, got, d)
+		}
+	}
+	if _, err := ParseSQLDialect("oracle"); err == nil {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "不支持的方言", err, "error")
+	}
+}
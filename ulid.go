@@ -0,0 +1,120 @@
+package generator
+
+// crockfordAlphabet Crockford Base32字母表，去掉了易混淆的I、L、O、U
+const crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// crockfordIndex 字符在字母表中的位置(不区分大小写)，-1表示非法字符
+var crockfordIndex = func() (index [256]int8) {
+	for i := range index {
+		index[i] = -1
+	}
+	for i := 0; i < len(crockfordAlphabet); i++ {
+		c := crockfordAlphabet[i]
+		index[c] = int8(i)
+		if c >= 'A' && c <= 'Z' {
+			index[c+'a'-'A'] = int8(i)
+		}
+	}
+	return
+}()
+
+// ULID 与ULID规范兼容的128位id：48位unix毫秒时间戳+80位
+type ULID [16]byte
+
+// String 26位Crockford Base32字符串，字典序与时间顺序一致
+func (u ULID) String() string {
+	hi := uint64(u[0])<<56 | uint64(u[1])<<48 | uint64(u[2])<<40 | uint64(u[3])<<32 |
+		uint64(u[4])<<24 | uint64(u[5])<<16 | uint64(u[6])<<8 | uint64(u[7])
+	lo := uint64(u[8])<<56 | uint64(u[9])<<48 | uint64(u[10])<<40 | uint64(u[11])<<32 |
+		uint64(u[12])<<24 | uint64(u[13])<<16 | uint64(u[14])<<8 | uint64(u[15])
+	buf := make([]byte, 26)
+	for i := len(buf) - 1; i >= 0; i-- {
+		buf[i] = crockfordAlphabet[lo&0x1F]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(buf)
+}
+
+// ParseULID 解析26位Crockford Base32字符串，不区分大小写
+func ParseULID(s string) (ULID, error) {
+	var u ULID
+	if len(s) != 26 {
+		return u, errorf(CodeULIDLength, s)
+	}
+	var hi, lo uint64
+	for i := 0; i < len(s); i++ {
+		digit := crockfordIndex[s[i]]
+		if digit < 0 {
+			return u, errorf(CodeULIDChar, s, s[i])
+		}
+		if i == 0 && digit > 7 {
+			return u, errorf(CodeULIDOverflow, s)
+		}
+		hi = hi<<5 | lo>>59
+		lo = lo<<5 | uint64(digit)
+	}
+	for i := 0; i < 8; i++ {
+		u[i] = byte(hi >> (56 - 8*uint(i)))
+		u[8+i] = byte(lo >> (56 - 8*uint(i)))
+	}
+	return u, nil
+}
+
+// GenerateULID 生成ULID，时钟回退处理、等待策略等与Generate相同
+//   - 同一生成器生成的ULID按生成顺序递增(时钟回退切换时间线时除外，与id相同)，不依赖随机数保证唯一
+func (idGen *IDGenerator) GenerateULID() (ULID, error) {
+	id, err := idGen.generate()
+	if err != nil {
+		return ULID{}, err
+	}
+	return idGen.ToULID(id)
+}
+
+// ToULID 将id转换成ULID
+//   - 时间戳字段：id中的时间部分(unix毫秒)
+//   - 其后12位：毫秒以下部分按1/4096毫秒计，时间单位小于1毫秒时仍按时间排序
+//   - 最低62位：id的低位(机器ID+时间线+序号)，可通过FromULID还原
+func (idGen *IDGenerator) ToULID(id int64) (ULID, error) {
+	var u ULID
+	ms, subMs, low, err := idGen.splitTime(id)
+	if err != nil {
+		return u, err
+	}
+	u[0] = byte(ms >> 40)
+	u[1] = byte(ms >> 32)
+	u[2] = byte(ms >> 24)
+	u[3] = byte(ms >> 16)
+	u[4] = byte(ms >> 8)
+	u[5] = byte(ms)
+	u[6] = byte(subMs >> 4)
+	u[7] = byte(subMs << 4)
+	u[8] = byte(low >> 56)
+	u[9] = byte(low >> 48)
+	u[10] = byte(low >> 40)
+	u[11] = byte(low >> 32)
+	u[12] = byte(low >> 24)
+	u[13] = byte(low >> 16)
+	u[14] = byte(low >> 8)
+	u[15] = byte(low)
+	return u, nil
+}
+
+// FromULID 将由ToULID生成的ULID还原成id
+func (idGen *IDGenerator) FromULID(u ULID) (int64, error) {
+	ms := int64(u[0])<<40 | int64(u[1])<<32 | int64(u[2])<<24 | int64(u[3])<<16 | int64(u[4])<<8 | int64(u[5])
+	subMs := int64(u[6])<<4 | int64(u[7])>>4
+	low := int64(u[8])<<56 | int64(u[9])<<48 | int64(u[10])<<40 | int64(u[11])<<32 |
+		int64(u[12])<<24 | int64(u[13])<<16 | int64(u[14])<<8 | int64(u[15])
+	if u[7]&0x0F != 0 || low < 0 {
+		return 0, errorf(CodeULIDNotID)
+	}
+	id, err := idGen.joinTime(ms, subMs, low)
+	if err != nil {
+		return 0, err
+	}
+	if back, _ := idGen.ToULID(id); back != u {
+		return 0, errorf(CodeULIDLayout)
+	}
+	return id, nil
+}
@@ -0,0 +1,68 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestULID 生成ULID，与id互转
+func TestULID(t *testing.T) {
+	testCases := []struct {
+		name     string
+		settings Settings
+	}{
+		{name: "默认配置", settings: *DefaultSettings},
+		{name: "微秒时间单位", settings: Settings{TimeBit: 51, MachineIDBit: 2, TimelineBit: 1, SeqBit: 9, Epoch: DefaultEpoch, TimeUnit: time.Microsecond}},
+	}
+	for _, tc := range testCases {
+		idGen, err := NewGeneratorWithSettings(1, tc.settings)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		var prev string
+		for i := 0; i < 1e4; i++ {
+			u, err := idGen.GenerateULID()
+			if err != nil {
+				t.Fatalf("【失败】-%s-生成-%v", tc.name, err)
+			}
+			s := u.String()
+			if len(s) != 26 {
+				t.Fatalf("【失败】-%s-长度-got:%s", tc.name, s)
+			}
+			parsed, err := ParseULID(strings.ToLower(s))
+			if err != nil || parsed != u {
+				t.Fatalf("【失败】-%s-解析-got:%v-want:%v-err:%v", tc.name, parsed, u, err)
+			}
+			id, err := idGen.FromULID(parsed)
+			if err != nil {
+				t.Fatalf("【失败】-%s-还原id-%v", tc.name, err)
+			}
+			if ms := idGen.Time(id).UnixNano() / 1e6; int64(u[0])<<40|int64(u[1])<<32|int64(u[2])<<24|int64(u[3])<<16|int64(u[4])<<8|int64(u[5]) != ms {
+				t.Fatalf("【失败】-%s-时间戳-%s-want:%d", tc.name, s, ms)
+			}
+			if s <= prev {
+				t.Fatalf("【失败】-%s-未保持顺序-%s-%s", tc.name, prev, s)
+			}
+			prev = s
+		}
+	}
+
+	//ULID规范示例
+	spec, err := ParseULID("01ARZ3NDEKTSV4RRFFQ69G5FAV")
+	if err != nil || spec.String() != "01ARZ3NDEKTSV4RRFFQ69G5FAV" {
+		t.Fatalf("【失败】-规范示例-got:%s-err:%v", spec, err)
+	}
+	if ms := int64(spec[0])<<40 | int64(spec[1])<<32 | int64(spec[2])<<24 | int64(spec[3])<<16 | int64(spec[4])<<8 | int64(spec[5]); ms != 1469922850259 {
+		t.Fatalf("【失败】-规范示例时间戳-got:%d-want:%d", ms, 1469922850259)
+	}
+	idGen, _ := NewGenerator(1)
+	if _, err := idGen.FromULID(spec); err == nil {
+		t.Fatalf("【失败】-非id转换而来的ULID应还原失败")
+	}
+	for _, s := range []string{"01ARZ3NDEKTSV4RRFFQ69G5FA", "01ARZ3NDEKTSV4RRFFQ69G5FAU", "81ARZ3NDEKTSV4RRFFQ69G5FAV"} {
+		if _, err := ParseULID(s); err == nil {
+			t.Fatalf("【失败】-%s-应解析失败", s)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package generator
+
+import (
+	"math"
+	"time"
+)
+
+// UUIDv7 RFC 9562 version 7 UUID
+type UUIDv7 [16]byte
+
+// String 标准格式：xxxxxxxx-xxxx-7xxx-xxxx-xxxxxxxxxxxx
+func (u UUIDv7) String() string {
+	return formatUUID(u)
+}
+
+// ParseUUIDv7 解析标准格式的UUIDv7字符串
+func ParseUUIDv7(s string) (UUIDv7, error) {
+	u, err := parseUUID("UUIDv7", s)
+	if err != nil {
+		return UUIDv7{}, err
+	}
+	if u[6]>>4 != 7 || u[8]&0xC0 != 0x80 {
+		return UUIDv7{}, errorf(CodeUUIDFormat, "UUIDv7", s)
+	}
+	return UUIDv7(u), nil
+}
+
+// GenerateUUIDv7 生成UUIDv7，时钟回退处理、等待策略等与Generate相同
+//   - 适用于要求UUID类型主键、又希望按时间排序且在时钟回退时不重复的场景
+func (idGen *IDGenerator) GenerateUUIDv7() (UUIDv7, error) {
+	id, err := idGen.generate()
+	if err != nil {
+		return UUIDv7{}, err
+	}
+	return idGen.ToUUIDv7(id)
+}
+
+// ToUUIDv7 将id转换成UUIDv7
+//   - unix_ts_ms字段：id中的时间部分(unix毫秒)
+//   - rand_a字段：毫秒以下部分按1/4096毫秒计(RFC 9562 方法3)，时间单位小于1毫秒时仍按时间排序
+//   - rand_b字段：id的低位(机器ID+时间线+序号)，使同一时间单位内的UUID按id排序，并可通过FromUUIDv7还原
+func (idGen *IDGenerator) ToUUIDv7(id int64) (UUIDv7, error) {
+	var u UUIDv7
+	ms, subMs, low, err := idGen.splitTime(id)
+	if err != nil {
+		return u, err
+	}
+	u[0] = byte(ms >> 40)
+	u[1] = byte(ms >> 32)
+	u[2] = byte(ms >> 24)
+	u[3] = byte(ms >> 16)
+	u[4] = byte(ms >> 8)
+	u[5] = byte(ms)
+	u[6] = byte(subMs>>8)&0x0F | 0x70 //version 7
+	u[7] = byte(subMs)
+	u[8] = byte(low>>56)&0x3F | 0x80 //variant RFC 9562
+	u[9] = byte(low >> 48)
+	u[10] = byte(low >> 40)
+	u[11] = byte(low >> 32)
+	u[12] = byte(low >> 24)
+	u[13] = byte(low >> 16)
+	u[14] = byte(low >> 8)
+	u[15] = byte(low)
+	return u, nil
+}
+
+// FromUUIDv7 将由ToUUIDv7生成的UUIDv7还原成id
+func (idGen *IDGenerator) FromUUIDv7(u UUIDv7) (int64, error) {
+	if u[6]>>4 != 7 || u[8]&0xC0 != 0x80 {
+		return 0, errorf(CodeUUIDv7Invalid)
+	}
+	ms := int64(u[0])<<40 | int64(u[1])<<32 | int64(u[2])<<24 | int64(u[3])<<16 | int64(u[4])<<8 | int64(u[5])
+	subMs := int64(u[6]&0x0F)<<8 | int64(u[7])
+	low := int64(u[8]&0x3F)<<56 | int64(u[9])<<48 | int64(u[10])<<40 | int64(u[11])<<32 |
+		int64(u[12])<<24 | int64(u[13])<<16 | int64(u[14])<<8 | int64(u[15])
+	id, err := idGen.joinTime(ms, subMs, low)
+	if err != nil {
+		return 0, err
+	}
+	if back, _ := idGen.ToUUIDv7(id); back != u {
+		return 0, errorf(CodeUUIDv7Layout)
+	}
+	return id, nil
+}
+
+// splitTime 将id拆分为unix毫秒、毫秒以下部分(1/4096毫秒)及时间以下的低位，供UUIDv7、ULID转换
+func (idGen *IDGenerator) splitTime(id int64) (ms, subMs, low int64, err error) {
+	presets := idGen.settings.presets
+	if !idGen.settings.timeOrdered() {
+		return 0, 0, 0, errNotTimeOrdered
+	}
+	if id < 0 {
+		return 0, 0, 0, errorf(CodeNegativeID)
+	}
+	if id&presets.maskVersion != presets.version {
+		return 0, 0, 0, errVersionMismatch
+	}
+	timePart := (id & presets.maskTime) >> presets.shiftTimeBit
+	unixNano := idGen.toUnixNano(timePart)
+	if unixNano < 0 {
+		return 0, 0, 0, errorf(CodeBeforeUnixEpoch)
+	}
+	ms = unixNano / int64(time.Millisecond)
+	subMs = unixNano % int64(time.Millisecond) * 4096 / int64(time.Millisecond)
+	return ms, subMs, id & (1<<presets.shiftTimeBit - 1), nil
+}
+
+// joinTime splitTime的逆过程，由unix毫秒、毫秒以下部分及低位还原id
+func (idGen *IDGenerator) joinTime(ms, subMs, low int64) (int64, error) {
+	presets := idGen.settings.presets
+	if !idGen.settings.timeOrdered() {
+		return 0, errNotTimeOrdered
+	}
+	if ms >= math.MaxInt64/int64(time.Millisecond) {
+		return 0, errorf(CodeTimeRange)
+	}
+	//毫秒以下部分对应的最大纳秒数，时间单位不小于1微秒，区间内至多有一个时间单位的起点
+	unixNano := ms*int64(time.Millisecond) + ((subMs+1)*int64(time.Millisecond)-1)/4096
+	if unixNano < idGen.settings.Epoch {
+		return 0, errorf(CodeTimeBeforeEpoch)
+	}
+	timePart := idGen.toOffsetTime(unixNano)
+	if timePart > presets.maxTime {
+		return 0, errorf(CodeTimeBitsOverflow)
+	}
+	if low>>presets.shiftTimeBit != 0 {
+		return 0, errorf(CodeLowBitsOverflow)
+	}
+	return presets.version | timePart<<presets.shiftTimeBit | low, nil
+}
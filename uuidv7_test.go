@@ -0,0 +1,66 @@
+package generator
+
+import (
+	"testing"
+	"time"
+)
+
+// TestUUIDv7 生成UUIDv7，与id互转
+func TestUUIDv7(t *testing.T) {
+	testCases := []struct {
+		name     string
+		settings Settings
+	}{
+		{name: "默认配置", settings: *DefaultSettings},
+		{name: "微秒时间单位", settings: Settings{TimeBit: 51, MachineIDBit: 2, TimelineBit: 1, SeqBit: 9, Epoch: DefaultEpoch, TimeUnit: time.Microsecond}},
+		{name: "秒级时间单位", settings: *SecondSettings},
+	}
+	for _, tc := range testCases {
+		idGen, err := NewGeneratorWithSettings(1, tc.settings)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		var prev string
+		for i := 0; i < 1e4; i++ {
+			u, err := idGen.GenerateUUIDv7()
+			if err != nil {
+				t.Fatalf("【失败】-%s-生成-%v", tc.name, err)
+			}
+			s := u.String()
+			if s[14] != '7' {
+				t.Fatalf("【失败】-%s-版本-got:%s", tc.name, s)
+			}
+			parsed, err := ParseUUIDv7(s)
+			if err != nil || parsed != u {
+				t.Fatalf("【失败】-%s-解析-got:%v-want:%v-err:%v", tc.name, parsed, u, err)
+			}
+			id, err := idGen.FromUUIDv7(parsed)
+			if err != nil {
+				t.Fatalf("【失败】-%s-还原id-%v", tc.name, err)
+			}
+			if back, _ := idGen.ToUUIDv7(id); back != u {
+				t.Fatalf("【失败】-%s-往返-got:%s-want:%s", tc.name, back, u)
+			}
+			if ms := idGen.Time(id).UnixNano() / 1e6; int64(u[0])<<40|int64(u[1])<<32|int64(u[2])<<24|int64(u[3])<<16|int64(u[4])<<8|int64(u[5]) != ms {
+				t.Fatalf("【失败】-%s-unix_ts_ms-%s-want:%d", tc.name, s, ms)
+			}
+			if s <= prev {
+				t.Fatalf("【失败】-%s-未保持顺序-%s-%s", tc.name, prev, s)
+			}
+			prev = s
+		}
+	}
+
+	idGen, _ := NewGenerator(1)
+	if _, err := ParseUUIDv7("5c2a6f30-0d5c-11ee-8000-0242ac120002"); err == nil {
+		t.Fatalf("【失败】-version 1的uuid应解析失败")
+	}
+	foreign, _ := ParseUUIDv7("01890a5d-ac96-774b-bcce-b302099a8057")
+	if _, err := idGen.FromUUIDv7(foreign); err == nil {
+		t.Fatalf("【失败】-非id转换而来的UUIDv7应还原失败")
+	}
+	above, _ := NewGeneratorWithSettings(1, Settings{TimeBit: 41, MachineIDBit: 9, TimelineBit: 1, SeqBit: 12, Epoch: DefaultEpoch, Placement: TimelineAboveTime})
+	if _, err := above.GenerateUUIDv7(); err != errNotTimeOrdered {
+		t.Fatalf("【失败】-时间线位于时间之上-got:%v-want:%v", err, errNotTimeOrdered)
+	}
+}
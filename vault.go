@@ -0,0 +1,274 @@
+package generator
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"sync"
+	"time"
+)
+
+// IDBlock 离线id块：预留机器ID(及时间线)在一段时间单位内的全部或部分id
+//   - 供离线或间歇联网的系统事后使用，预留的机器ID不能分配给在线节点
+//   - 块内容经HMAC-SHA256签名，使用前须校验
+type IDBlock struct {
+	Settings  Settings //id结构
+	MachineID int64    //预留的机器ID
+	Timeline  int64    //预留的时间线
+	StartTime int64    //起始时间单位(偏移量，含)
+	EndTime   int64    //结束时间单位(偏移量，不含)
+	Count     int64    //块内id数量
+	IssuedAt  int64    //签发时间(unix nano)
+	Signature string   //签名
+}
+
+// idBlockFile id块的文件格式
+type idBlockFile struct {
+	TimeBit      uint64 `json:"time_bit"`
+	MachineIDBit uint64 `json:"machine_id_bit"`
+	TimelineBit  uint64 `json:"timeline_bit"`
+	SeqBit       uint64 `json:"seq_bit"`
+	Epoch        int64  `json:"epoch"`
+	TimeUnit     int64  `json:"time_unit,omitempty"`
+	Placement    int    `json:"timeline_placement,omitempty"`
+	FlagBit      bool   `json:"flag_bit,omitempty"`
+	MachineID    int64  `json:"machine_id"`
+	Timeline     int64  `json:"timeline"`
+	StartTime    int64  `json:"start_time"`
+	EndTime      int64  `json:"end_time"`
+	Count        int64  `json:"count"`
+	IssuedAt     int64  `json:"issued_at"`
+	Signature    string `json:"signature"`
+}
+
+// payload 签名内容
+func (block *IDBlock) payload() []byte {
+	s := block.Settings
+	payload := fmt.Sprintf("mtl-snowflake-block/v1|%d|%d|%d|%d|%d|%d|%d|%d|%d|%d|%d",
+		s.TimeBit, s.MachineIDBit, s.TimelineBit, s.SeqBit, s.Epoch,
+		block.MachineID, block.Timeline, block.StartTime, block.EndTime, block.Count, block.IssuedAt)
+	switch {
+	case s.FlagBit:
+		payload += fmt.Sprintf("|%d|%d|flag", s.TimeUnit, s.Placement)
+	case s.Placement != TimelineBelowMachine:
+		payload += fmt.Sprintf("|%d|%d", s.TimeUnit, s.Placement)
+	case s.TimeUnit != 0:
+		payload += fmt.Sprintf("|%d", s.TimeUnit)
+	}
+	return []byte(payload)
+}
+
+// sign 计算签名
+func (block *IDBlock) sign(key []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(block.payload())
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify 校验签名及块内容是否符合id结构
+func (block *IDBlock) Verify(key []byte) error {
+	if !hmac.Equal([]byte(block.sign(key)), []byte(block.Signature)) {
+		return errorf(CodeVaultSignature)
+	}
+	s := block.Settings
+	if err := s.checkBits(); err != nil {
+		return err
+	}
+	maxTime := int64(1)<<s.TimeBit - 1
+	if block.MachineID < 0 || block.MachineID > int64(1)<<s.MachineIDBit-1 ||
+		block.Timeline < 0 || block.Timeline > int64(1)<<s.TimelineBit-1 ||
+		block.StartTime < 0 || block.EndTime > maxTime+1 || block.StartTime >= block.EndTime ||
+		block.Count < 1 || block.Count > (block.EndTime-block.StartTime)<<s.SeqBit {
+		return errorf(CodeVaultLayout)
+	}
+	return nil
+}
+
+// ID 块内第index个id
+func (block *IDBlock) ID(index int64) (int64, error) {
+	if index < 0 || index >= block.Count {
+		return 0, errorf(CodeVaultIndex, index, block.Count)
+	}
+	s := block.Settings
+	presets := calcPresets(&s)
+	return (block.StartTime+index>>s.SeqBit)<<presets.shiftTimeBit |
+		block.MachineID<<presets.shiftMachineIDBit |
+		block.Timeline<<presets.shiftTimelineBit |
+		(index&presets.maxSeq)<<presets.shiftSeq, nil
+}
+
+// VerifyDisjoint 校验块与在线节点不会生成相同的id：块使用的机器ID不能分配给任何在线节点
+func (block *IDBlock) VerifyDisjoint(liveMachineIDs []int64) error {
+	for _, machineID := range liveMachineIDs {
+		if machineID == block.MachineID {
+			return errorf(CodeVaultMachineID, machineID)
+		}
+	}
+	return nil
+}
+
+// VerifyBlocks 校验一组块两两之间没有重叠
+func VerifyBlocks(blocks []*IDBlock) error {
+	sorted := append([]*IDBlock(nil), blocks...)
+	sort.Slice(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.MachineID != b.MachineID {
+			return a.MachineID < b.MachineID
+		}
+		if a.Timeline != b.Timeline {
+			return a.Timeline < b.Timeline
+		}
+		return a.StartTime < b.StartTime
+	})
+	for i := 1; i < len(sorted); i++ {
+		prev, cur := sorted[i-1], sorted[i]
+		if !sameLayout(prev.Settings, cur.Settings) {
+			return errorf(CodeVaultSettings)
+		}
+		if prev.MachineID == cur.MachineID && prev.Timeline == cur.Timeline && cur.StartTime < prev.EndTime {
+			return errorf(CodeVaultOverlap,
+				cur.MachineID, cur.Timeline, prev.StartTime, prev.EndTime, cur.StartTime, cur.EndTime)
+		}
+	}
+	return nil
+}
+
+// sameLayout 两个配置的id结构是否相同
+func sameLayout(a, b Settings) bool {
+	return a.TimeBit == b.TimeBit && a.DatacenterBit == b.DatacenterBit && a.MachineIDBit == b.MachineIDBit &&
+		a.TimelineBit == b.TimelineBit && a.SeqBit == b.SeqBit && a.Epoch == b.Epoch && a.unit() == b.unit() &&
+		a.Placement == b.Placement && a.FlagBit == b.FlagBit && a.VersionBit == b.VersionBit && a.Version == b.Version
+}
+
+// SaveBlock 将块保存为JSON文件
+func SaveBlock(path string, block *IDBlock) error {
+	data, err := json.MarshalIndent(idBlockFile{
+		TimeBit:      block.Settings.TimeBit,
+		MachineIDBit: block.Settings.MachineIDBit,
+		TimelineBit:  block.Settings.TimelineBit,
+		SeqBit:       block.Settings.SeqBit,
+		Epoch:        block.Settings.Epoch,
+		TimeUnit:     int64(block.Settings.TimeUnit),
+		Placement:    int(block.Settings.Placement),
+		FlagBit:      block.Settings.FlagBit,
+		MachineID:    block.MachineID,
+		Timeline:     block.Timeline,
+		StartTime:    block.StartTime,
+		EndTime:      block.EndTime,
+		Count:        block.Count,
+		IssuedAt:     block.IssuedAt,
+		Signature:    block.Signature,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0644)
+}
+
+// LoadBlock 读取块并校验签名
+func LoadBlock(path string, key []byte) (*IDBlock, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file idBlockFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+	block := &IDBlock{
+		Settings: Settings{
+			TimeBit:      file.TimeBit,
+			MachineIDBit: file.MachineIDBit,
+			TimelineBit:  file.TimelineBit,
+			SeqBit:       file.SeqBit,
+			Epoch:        file.Epoch,
+			TimeUnit:     time.Duration(file.TimeUnit),
+			Placement:    Placement(file.Placement),
+			FlagBit:      file.FlagBit,
+		},
+		MachineID: file.MachineID,
+		Timeline:  file.Timeline,
+		StartTime: file.StartTime,
+		EndTime:   file.EndTime,
+		Count:     file.Count,
+		IssuedAt:  file.IssuedAt,
+		Signature: file.Signature,
+	}
+	if err := block.Verify(key); err != nil {
+		return nil, err
+	}
+	return block, nil
+}
+
+// VaultIssuer 离线id块签发者，使用预留的机器ID和时间线
+type VaultIssuer struct {
+	mutex     sync.Mutex
+	settings  Settings
+	machineID int64
+	timeline  int64
+	key       []byte
+	nextTime  int64 //下一个可用的时间单位
+}
+
+// NewVaultIssuer 创建签发者
+//   - issued 为此前已签发的块，新签发的块不会与其重叠
+func NewVaultIssuer(settings Settings, machineID, timeline int64, key []byte, issued []*IDBlock) (*VaultIssuer, error) {
+	if err := checkSettings(&settings, machineID); err != nil {
+		return nil, err
+	}
+	if settings.DatacenterBit != 0 || settings.VersionBit != 0 {
+		return nil, errorf(CodeVaultUnsupported)
+	}
+	if timeline < 0 || timeline > int64(1)<<settings.TimelineBit-1 {
+		return nil, errorf(CodeVaultTimeline)
+	}
+	if len(key) == 0 {
+		return nil, errorf(CodeVaultKey)
+	}
+	settings.presets = nil
+	issuer := &VaultIssuer{settings: settings, machineID: machineID, timeline: timeline, key: key}
+	for _, block := range issued {
+		if block.MachineID == machineID && block.Timeline == timeline && block.EndTime > issuer.nextTime {
+			issuer.nextTime = block.EndTime
+		}
+	}
+	return issuer, nil
+}
+
+// Issue 签发包含n个id的块，从当前时间单位(或上一个块结束处)开始
+func (issuer *VaultIssuer) Issue(n int64) (*IDBlock, error) {
+	if n < 1 {
+		return nil, errorf(CodeNotPositive, "n")
+	}
+	issuer.mutex.Lock()
+	defer issuer.mutex.Unlock()
+
+	s := issuer.settings
+	now := time.Now().UnixNano()
+	start := (now - s.Epoch) / s.unit()
+	if start < issuer.nextTime {
+		start = issuer.nextTime
+	}
+	perTime := int64(1) << s.SeqBit
+	end := start + (n+perTime-1)/perTime
+	if end > int64(1)<<s.TimeBit {
+		return nil, errorf(CodeVaultOverflow)
+	}
+
+	block := &IDBlock{
+		Settings:  s,
+		MachineID: issuer.machineID,
+		Timeline:  issuer.timeline,
+		StartTime: start,
+		EndTime:   end,
+		Count:     n,
+		IssuedAt:  now,
+	}
+	block.Signature = block.sign(issuer.key)
+	issuer.nextTime = end
+	return block, nil
+}
@@ -0,0 +1,90 @@
+package generator
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestVault 离线id块签发、保存、校验及使用
+func TestVault(t *testing.T) {
+	key := []byte("secret")
+	issuer, err := NewVaultIssuer(*DefaultSettings, 511, 0, key, nil)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	first, err := issuer.Issue(10000)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	second, _ := issuer.Issue(1)
+	if err := VerifyBlocks([]*IDBlock{second, first}); err != nil {
+		t.Fatalf("【失败】-连续签发的块不应重叠-%v", err)
+	}
+
+	dir, _ := ioutil.TempDir("", "vault")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "block.json")
+	if err := SaveBlock(path, first); err != nil {
+		t.Fatal(err.Error())
+	}
+	loaded, err := LoadBlock(path, key)
+	if err != nil {
+		t.Fatalf("【失败】-读取块-%v", err)
+	}
+	if _, err := LoadBlock(path, []byte("wrong")); err == nil {
+		t.Fatalf("【失败】-密钥错误应校验失败")
+	}
+
+	// 块内id与在线节点生成的id不重复
+	live, _ := NewGenerator(0)
+	if err := loaded.VerifyDisjoint([]int64{0, 1, 2}); err != nil {
+		t.Fatal(err.Error())
+	}
+	if err := loaded.VerifyDisjoint([]int64{511}); err == nil {
+		t.Fatalf("【失败】-机器ID已分配给在线节点应校验失败")
+	}
+	ids := make(map[int64]bool)
+	for i := int64(0); i < loaded.Count; i++ {
+		id, err := loaded.ID(i)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if ids[id] {
+			t.Fatalf("【失败】-块内出现重复的id:%d", id)
+		}
+		ids[id] = true
+		if compose := live.Decompose(id); compose.MachineID != 511 {
+			t.Fatalf("【失败】-块内id的机器ID错误:%d", compose.MachineID)
+		}
+	}
+	for i := 0; i < 10000; i++ {
+		id, _ := live.Generate()
+		if ids[int64(id)] {
+			t.Fatalf("【失败】-在线生成的id与块内id重复:%d", id)
+		}
+	}
+	if _, err := loaded.ID(loaded.Count); err == nil {
+		t.Fatalf("【失败】-下标越界应返回错误")
+	}
+
+	// 重启后的签发者不会与已签发的块重叠
+	restarted, _ := NewVaultIssuer(*DefaultSettings, 511, 0, key, []*IDBlock{first, second})
+	third, _ := restarted.Issue(1)
+	if err := VerifyBlocks([]*IDBlock{first, second, third}); err != nil {
+		t.Fatalf("【失败】-重启后签发的块不应重叠-%v", err)
+	}
+	overlap := *first
+	if err := VerifyBlocks([]*IDBlock{first, &overlap}); err == nil {
+		t.Fatalf("【失败】-重叠的块应校验失败")
+	}
+
+	// 篡改块内容
+	tampered := *loaded
+	tampered.Count++
+	if err := tampered.Verify(key); err == nil {
+		t.Fatalf("【失败】-篡改后的块应校验失败")
+	}
+}
@@ -0,0 +1,112 @@
+package generator
+
+import (
+	"fmt"
+	"time"
+)
+
+// VerifyReport id校验结果
+type VerifyReport struct {
+	Total            int64 //校验的id总数
+	Duplicates       int64 //重复的id数
+	LayoutViolations int64 //不符合id结构的数量(如符号位为1，版本号、数据中心ID与配置不符，标记位为1)
+	FutureTimestamps int64 //时间晚于校验时间的数量
+	OrderAnomalies   int64 //同一机器同一时间线内出现逆序的数量
+	Machines         int   //出现的机器数
+}
+
+// OK 是否未发现任何问题
+func (r *VerifyReport) OK() bool {
+	return r.Duplicates == 0 && r.LayoutViolations == 0 && r.FutureTimestamps == 0 && r.OrderAnomalies == 0
+}
+
+// String 汇总信息
+func (r *VerifyReport) String() string {
+	return fmt.Sprintf("total=%d machines=%d duplicates=%d layout_violations=%d future_timestamps=%d order_anomalies=%d",
+		r.Total, r.Machines, r.Duplicates, r.LayoutViolations, r.FutureTimestamps, r.OrderAnomalies)
+}
+
+// Verifier 按生成顺序流式校验一批id
+//   - 按创建时生成器的id结构解析，版本号、数据中心ID须与配置一致，生成的id标记位总是0；机器ID、时间线占满各自的位，任何取值均合法
+//   - 需要记录全部已校验的id用于查重，内存占用与id数量成正比
+type Verifier struct {
+	decomposer *Decomposer
+	now        int64 //校验时间(偏移量)，晚于该时间的id视为未来时间
+	seen       map[int64]struct{}
+	last       map[[2]int64]int64 //机器ID+时间线 -> 最近一个id
+	machines   map[int64]struct{}
+	report     VerifyReport
+}
+
+// NewVerifier 创建校验器，tolerance为允许的时钟误差
+//   - 校验时间取自生成器的时钟(SetClock)，与生成id使用同一时间源
+func (idGen *IDGenerator) NewVerifier(tolerance time.Duration) *Verifier {
+	idGen.mutex.Lock()
+	now := idGen.now()
+	decomposer := newDecomposer(*idGen.settings)
+	idGen.mutex.Unlock()
+
+	return &Verifier{
+		decomposer: decomposer,
+		now:        decomposer.idGen.toOffsetTime(now.Add(tolerance).UnixNano()),
+		seen:       make(map[int64]struct{}),
+		last:       make(map[[2]int64]int64),
+		machines:   make(map[int64]struct{}),
+	}
+}
+
+// Add 校验一个id
+func (v *Verifier) Add(id int64) {
+	v.report.Total++
+	compose := v.decomposer.Decompose(id)
+	if !v.validLayout(id, compose) {
+		v.report.LayoutViolations++
+		return
+	}
+
+	if _, exist := v.seen[id]; exist {
+		v.report.Duplicates++
+		return
+	}
+	v.seen[id] = struct{}{}
+
+	if compose.Time > v.now {
+		v.report.FutureTimestamps++
+	}
+
+	v.machines[compose.MachineID] = struct{}{}
+	key := [2]int64{compose.MachineID, compose.TimeLine}
+	if last, exist := v.last[key]; exist && id < last {
+		v.report.OrderAnomalies++
+	}
+	v.last[key] = id
+}
+
+// validLayout id是否符合配置的id结构
+func (v *Verifier) validLayout(id int64, compose *IDCompose) bool {
+	settings := &v.decomposer.settings
+	switch {
+	case id < 0:
+		return false
+	case compose.Version != settings.Version:
+		return false
+	case compose.DatacenterID != settings.DatacenterID:
+		return false
+	case settings.FlagBit && id&1 != 0:
+		return false
+	}
+	return true
+}
+
+// AddViolation 记录一个无法解析的id
+func (v *Verifier) AddViolation() {
+	v.report.Total++
+	v.report.LayoutViolations++
+}
+
+// Report 校验结果
+func (v *Verifier) Report() VerifyReport {
+	report := v.report
+	report.Machines = len(v.machines)
+	return report
+}
@@ -0,0 +1,77 @@
+package generator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jayecc/mtl-snowflake/fakeclock"
+)
+
+// TestVerifier id流式校验
+func TestVerifier(t *testing.T) {
+	clock := fakeclock.New(time.Now())
+	settings := Settings{TimeBit: 39, VersionBit: 2, Version: 1, DatacenterBit: 2, DatacenterID: 1, MachineIDBit: 8, TimelineBit: 1, SeqBit: 10, FlagBit: true,
+		Epoch: DefaultSettings.Epoch, Clock: clock}
+	newGenerator := func(machineID int64, change func(s *Settings)) *IDGenerator {
+		s := settings
+		change(&s)
+		idGen, err := NewGeneratorWithSettings(machineID, s)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		return idGen
+	}
+	idGen := newGenerator(1, func(s *Settings) {})
+	other := newGenerator(2, func(s *Settings) {})
+	otherDatacenter := newGenerator(1, func(s *Settings) { s.DatacenterID = 2 })
+	otherVersion := newGenerator(1, func(s *Settings) { s.Version = 2 })
+
+	ids := make([]int64, 0, 10)
+	for i := 0; i < 5; i++ {
+		id, _ := idGen.Generate()
+		ids = append(ids, int64(id))
+		id, _ = other.Generate()
+		ids = append(ids, int64(id))
+	}
+	datacenterID, _ := otherDatacenter.Generate()
+	versionID, _ := otherVersion.Generate()
+	verifier := func() *Verifier { return idGen.NewVerifier(time.Second) }
+	clock.Advance(time.Hour)
+	future, _ := idGen.Generate()
+
+	testCases := []struct {
+		name string
+		ids  []int64
+		want VerifyReport
+	}{
+		{name: "校验通过", ids: ids, want: VerifyReport{Total: 10, Machines: 2}},
+		{name: "重复id", ids: append(ids[:4:4], ids[0]), want: VerifyReport{Total: 5, Machines: 2, Duplicates: 1}},
+		{name: "符号位为1", ids: []int64{ids[0], -1}, want: VerifyReport{Total: 2, Machines: 1, LayoutViolations: 1}},
+		{name: "数据中心ID不符", ids: []int64{ids[0], int64(datacenterID)}, want: VerifyReport{Total: 2, Machines: 1, LayoutViolations: 1}},
+		{name: "版本号不符", ids: []int64{ids[0], int64(versionID)}, want: VerifyReport{Total: 2, Machines: 1, LayoutViolations: 1}},
+		{name: "标记位为1", ids: []int64{ids[0], idGen.WithFlag(ids[2])}, want: VerifyReport{Total: 2, Machines: 1, LayoutViolations: 1}},
+		{name: "未来时间", ids: []int64{ids[0], int64(future)}, want: VerifyReport{Total: 2, Machines: 1, FutureTimestamps: 1}},
+		{name: "同一机器逆序", ids: []int64{ids[2], ids[0], ids[1]}, want: VerifyReport{Total: 3, Machines: 2, OrderAnomalies: 1}},
+	}
+	clock.Advance(-time.Hour) //校验时间取自生成器的时钟
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			v := verifier()
+			for _, id := range tc.ids {
+				v.Add(id)
+			}
+			got := v.Report()
+			if got != tc.want || got.OK() != (tc.name == "校验通过") {
+				t.Fatalf("【失败】-%s-got:%s-want:%s", tc.name, got.String(), tc.want.String())
+			}
+		})
+	}
+
+	//时钟前进后，此前的未来时间不再是未来时间
+	clock.Advance(time.Hour)
+	v := verifier()
+	v.Add(int64(future))
+	if got := v.Report(); !got.OK() {
+		t.Fatalf("【失败】-%s-got:%s-want:%v", "时钟前进", got.String(), "OK")
+	}
+}
@@ -0,0 +1,162 @@
+package generator
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// WatermarkSink 水位发布目标(Redis key、Kafka topic、文件等)
+//   - 每次发布的水位不小于上一次发布的水位
+type WatermarkSink interface {
+	Publish(watermark int64) error
+}
+
+// WatermarkSinkFunc 函数形式的WatermarkSink，用于适配Redis、Kafka等客户端，如：
+//
+//	generator.WatermarkSinkFunc(func(watermark int64) error {
+//		return client.Set(ctx, "order:idgen:watermark:1", watermark, 0).Err()
+//	})
+type WatermarkSinkFunc func(watermark int64) error
+
+// Publish 发布水位
+func (f WatermarkSinkFunc) Publish(watermark int64) error {
+	return f(watermark)
+}
+
+// WatermarkConfig 水位发布配置
+type WatermarkConfig struct {
+	Sink     WatermarkSink   //发布目标
+	Interval time.Duration   //发布间隔，缺省一个时间单位
+	OnError  func(err error) //后台发布失败时的回调，在发布goroutine中执行，nil表示忽略
+}
+
+// WatermarkPublisher 水位发布：后台定期将已生成的最大id发布到WatermarkSink，
+// 供下游复制/CDC消费方实现"等待id≤X的数据落地"的屏障
+//   - 水位只在增大时发布，发布失败时下个周期重试
+//   - 水位表示已生成的最大id；时钟回退切换时间线后生成的id可能小于已发布的水位，
+//     屏障要求此后不再出现≤水位的id时须开启严格递增模式(SetStrictMonotonic)
+type WatermarkPublisher struct {
+	idGen  *IDGenerator
+	config WatermarkConfig
+
+	mutex     sync.Mutex //保证发布顺序
+	published int64      //已发布的水位，0表示尚未发布
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewWatermarkPublisher 创建水位发布并启动后台发布
+func (idGen *IDGenerator) NewWatermarkPublisher(config WatermarkConfig) (*WatermarkPublisher, error) {
+	if config.Sink == nil {
+		return nil, errorf(CodeNil, "Sink")
+	}
+	if config.Interval < 0 {
+		return nil, errorf(CodeNegative, "Interval")
+	}
+	if config.Interval == 0 {
+		settings := idGen.GetSettings()
+		config.Interval = time.Duration(settings.unit())
+	}
+
+	p := &WatermarkPublisher{
+		idGen:  idGen,
+		config: config,
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	goLabeled(idGen, p.run)
+	return p, nil
+}
+
+// MaxIssuedID 已生成的最大id，尚未生成时返回0
+func (idGen *IDGenerator) MaxIssuedID() int64 {
+	idGen.mutex.Lock()
+	defer idGen.mutex.Unlock()
+	return idGen.maxID
+}
+
+// Flush 立即发布当前水位，水位未增大时不发布
+func (p *WatermarkPublisher) Flush() error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	watermark := p.idGen.MaxIssuedID()
+	if watermark <= p.published {
+		return nil
+	}
+	if err := p.config.Sink.Publish(watermark); err != nil {
+		return err
+	}
+	p.published = watermark
+	return nil
+}
+
+// Published 已发布的水位，尚未发布时返回0
+func (p *WatermarkPublisher) Published() int64 {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.published
+}
+
+// Close 停止后台发布并发布最终水位
+func (p *WatermarkPublisher) Close() error {
+	p.closeOnce.Do(func() { close(p.stop) })
+	<-p.done
+	return p.Flush()
+}
+
+// run 后台定期发布
+func (p *WatermarkPublisher) run() {
+	defer close(p.done)
+	ticker := time.NewTicker(p.config.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			if err := p.Flush(); err != nil && p.config.OnError != nil {
+				p.config.OnError(err)
+			}
+		}
+	}
+}
+
+// FileWatermarkSink 将水位以十进制写入文件，先写临时文件再重命名，读取方不会读到不完整的内容
+type FileWatermarkSink struct {
+	path string
+}
+
+// NewFileWatermarkSink 创建写入path的水位发布目标
+func NewFileWatermarkSink(path string) (*FileWatermarkSink, error) {
+	if path == "" {
+		return nil, errorf(CodeEmptyPath)
+	}
+	return &FileWatermarkSink{path: path}, nil
+}
+
+// Publish 写入水位
+func (sink *FileWatermarkSink) Publish(watermark int64) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(sink.path), filepath.Base(sink.path)+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err = tmp.WriteString(strconv.FormatInt(watermark, 10) + "\n"); err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), sink.path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+	return err
+}
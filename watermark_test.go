@@ -0,0 +1,144 @@
+package generator
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jayecc/mtl-snowflake/fakeclock"
+)
+
+// memorySink 记录发布的水位
+type memorySink struct {
+	mutex      sync.Mutex
+	watermarks []int64
+	err        error
+}
+
+func (sink *memorySink) Publish(watermark int64) error {
+	sink.mutex.Lock()
+	defer sink.mutex.Unlock()
+	if sink.err != nil {
+		return sink.err
+	}
+	sink.watermarks = append(sink.watermarks, watermark)
+	return nil
+}
+
+func (sink *memorySink) last() int64 {
+	sink.mutex.Lock()
+	defer sink.mutex.Unlock()
+	if len(sink.watermarks) == 0 {
+		return 0
+	}
+	return sink.watermarks[len(sink.watermarks)-1]
+}
+
+// TestWatermarkPublisher 水位发布
+func TestWatermarkPublisher(t *testing.T) {
+	clock := fakeclock.New(time.Now())
+	settings := *DefaultSettings
+	settings.Clock = clock
+	idGen, _ := NewGeneratorWithSettings(1, settings)
+	sink := &memorySink{}
+	p, err := idGen.NewWatermarkPublisher(WatermarkConfig{Sink: sink, Interval: time.Hour})
+	if err != nil {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "创建", err, nil)
+	}
+	defer p.Close()
+
+	if err := p.Flush(); err != nil || len(sink.watermarks) != 0 {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "尚未生成不发布", sink.watermarks, "[]")
+	}
+
+	first, _ := idGen.Generate()
+	clock.Advance(10 * time.Millisecond)
+	max, _ := idGen.Generate()
+	clock.Advance(-5 * time.Millisecond)
+	lower, _ := idGen.Generate() //时钟回退切换时间线后生成更小的id
+
+	testCases := []struct {
+		name string
+		err  error //发布目标返回的错误
+		want int64 //期望发布的水位
+		n    int   //期望发布次数
+	}{
+		{name: "发布最大id", err: nil, want: int64(max), n: 1},
+		{name: "水位未增大不重复发布", err: nil, want: int64(max), n: 1},
+	}
+	for _, tc := range testCases {
+		sink.err = tc.err
+		if err := p.Flush(); err != tc.err {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, err, tc.err)
+		}
+		if got := sink.last(); got != tc.want || len(sink.watermarks) != tc.n {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, sink.watermarks, tc.want)
+		}
+	}
+	if lower >= max || first >= max {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "时钟回退后的id", lower, "<"+strconv.FormatInt(int64(max), 10))
+	}
+
+	//发布失败后重试
+	clock.Advance(time.Second)
+	next, _ := idGen.Generate()
+	sink.err = errors.New("unavailable")
+	if err := p.Flush(); err != sink.err || p.Published() != int64(max) {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "发布失败", p.Published(), max)
+	}
+	sink.err = nil
+	if err := p.Close(); err != nil || sink.last() != int64(next) {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "关闭时发布", sink.last(), next)
+	}
+}
+
+// TestWatermarkPublisherInterval 后台定期发布
+func TestWatermarkPublisherInterval(t *testing.T) {
+	idGen, _ := NewGenerator(1)
+	sink := &memorySink{}
+	p, _ := idGen.NewWatermarkPublisher(WatermarkConfig{Sink: sink, Interval: time.Millisecond})
+	defer p.Close()
+
+	id, _ := idGen.Generate()
+	deadline := time.Now().Add(time.Second)
+	for sink.last() != int64(id) && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if got := sink.last(); got != int64(id) {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "定期发布", got, id)
+	}
+
+	if _, err := idGen.NewWatermarkPublisher(WatermarkConfig{}); err == nil {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "发布目标为空", err, "error")
+	}
+}
+
+// TestFileWatermarkSink 水位写入文件
+func TestFileWatermarkSink(t *testing.T) {
+	dir, err := ioutil.TempDir("", "watermark")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "watermark")
+	sink, _ := NewFileWatermarkSink(path)
+	for _, want := range []int64{100, 200} {
+		if err := sink.Publish(want); err != nil {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", "写入", err, nil)
+		}
+		data, _ := ioutil.ReadFile(path)
+		if got, _ := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64); got != want {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", "读取", got, want)
+		}
+	}
+	if files, _ := ioutil.ReadDir(dir); len(files) != 1 {
+		t.Fatalf("【失败】-%s-got:%v-want:%v", "临时文件", len(files), 1)
+	}
+}
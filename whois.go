@@ -0,0 +1,205 @@
+package generator
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MachineInfo 机器登记信息
+type MachineInfo struct {
+	MachineID int64  `json:"machine_id"`
+	Host      string `json:"host,omitempty"`  //主机名或实例名
+	Owner     string `json:"owner,omitempty"` //负责团队
+}
+
+// Attribution id归属
+type Attribution struct {
+	System  string      //所属系统
+	Machine MachineInfo //生成id的机器
+	Compose *IDCompose  //按该系统id结构解析的结果
+	Time    time.Time   //生成时间
+}
+
+// MachineRegistry 机器登记表：按系统登记id结构及机器信息，用于追溯id由哪个系统的哪台机器生成
+//   - 可登记不由本库分配机器ID的外部系统(如收购的业务、遗留平台)，机器信息可从CSV/JSON导入
+type MachineRegistry struct {
+	systems map[string]*registrySystem
+}
+
+// registrySystem 登记的系统
+type registrySystem struct {
+	decomposer *Decomposer
+	machines   map[int64]MachineInfo
+}
+
+// registryFile 登记表JSON格式
+type registryFile struct {
+	Systems []struct {
+		Name          string        `json:"name"`
+		TimeBit       uint64        `json:"time_bit"`
+		DatacenterBit uint64        `json:"datacenter_bit,omitempty"` //数据中心位数，缺省0
+		MachineIDBit  uint64        `json:"machine_id_bit"`
+		TimelineBit   uint64        `json:"timeline_bit"`
+		SeqBit        uint64        `json:"seq_bit"`
+		Epoch         time.Time     `json:"epoch"`
+		TimeUnit      string        `json:"time_unit,omitempty"`          //时间单位，如1s，缺省毫秒
+		Placement     string        `json:"timeline_placement,omitempty"` //时间线位置，缺省below-machine
+		FlagBit       bool          `json:"flag_bit,omitempty"`           //是否预留标记位
+		Machines      []MachineInfo `json:"machines"`
+	} `json:"systems"`
+}
+
+// NewMachineRegistry 创建机器登记表
+func NewMachineRegistry() *MachineRegistry {
+	return &MachineRegistry{systems: make(map[string]*registrySystem)}
+}
+
+// AddSystem 登记系统及其id结构
+func (r *MachineRegistry) AddSystem(name string, settings Settings) error {
+	if name == "" {
+		return errorf(CodeSystemNameEmpty)
+	}
+	if _, exist := r.systems[name]; exist {
+		return errorf(CodeSystemRegistered, name)
+	}
+	decomposer, err := NewDecomposer(settings)
+	if err != nil {
+		return err
+	}
+	r.systems[name] = &registrySystem{decomposer: decomposer, machines: make(map[int64]MachineInfo)}
+	return nil
+}
+
+// Register 登记机器
+func (r *MachineRegistry) Register(system string, info MachineInfo) error {
+	s, exist := r.systems[system]
+	if !exist {
+		return errorf(CodeSystemUnknown, system)
+	}
+	maxMachineID := s.decomposer.settings.presets.maxMachineID
+	if info.MachineID < 0 || info.MachineID > maxMachineID {
+		return &MachineIDError{MachineID: info.MachineID, Max: maxMachineID}
+	}
+	s.machines[info.MachineID] = info
+	return nil
+}
+
+// LoadJSON 导入JSON格式的登记表，如：
+//
+//	{"systems":[{"name":"legacy","time_bit":41,"machine_id_bit":10,"timeline_bit":0,"seq_bit":12,
+//	  "epoch":"2015-06-10T00:00:00Z","time_unit":"1ms","machines":[{"machine_id":1,"host":"app-01","owner":"order"}]}]}
+func (r *MachineRegistry) LoadJSON(reader io.Reader) error {
+	var file registryFile
+	if err := json.NewDecoder(reader).Decode(&file); err != nil {
+		return err
+	}
+	for _, s := range file.Systems {
+		var unit time.Duration
+		if s.TimeUnit != "" {
+			var err error
+			if unit, err = time.ParseDuration(s.TimeUnit); err != nil {
+				return errorf(CodeSystemField, "time_unit", s.Name, err)
+			}
+		}
+		var placement Placement
+		if s.Placement != "" {
+			var err error
+			if placement, err = ParsePlacement(s.Placement); err != nil {
+				return errorf(CodeSystemField, "timeline_placement", s.Name, err)
+			}
+		}
+		settings := Settings{
+			TimeBit:       s.TimeBit,
+			DatacenterBit: s.DatacenterBit,
+			MachineIDBit:  s.MachineIDBit,
+			TimelineBit:   s.TimelineBit,
+			SeqBit:        s.SeqBit,
+			Epoch:         s.Epoch.UnixNano(),
+			TimeUnit:      unit,
+			Placement:     placement,
+			FlagBit:       s.FlagBit,
+		}
+		if err := r.AddSystem(s.Name, settings); err != nil {
+			return err
+		}
+		for _, info := range s.Machines {
+			if err := r.Register(s.Name, info); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// LoadCSV 导入CSV格式的机器信息到已登记的系统，首行为表头：machine_id(必须)、host、owner
+func (r *MachineRegistry) LoadCSV(system string, reader io.Reader) error {
+	records, err := csv.NewReader(reader).ReadAll()
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		return errorf(CodeCSVEmpty)
+	}
+
+	columns := make(map[string]int)
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	idColumn, exist := columns["machine_id"]
+	if !exist {
+		return errorf(CodeCSVMachineID)
+	}
+	field := func(record []string, name string) string {
+		if i, exist := columns[name]; exist && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	for line, record := range records[1:] {
+		machineID, err := strconv.ParseInt(strings.TrimSpace(record[idColumn]), 10, 64)
+		if err != nil {
+			return errorf(CodeCSVMachineIDValue, line+2, err)
+		}
+		info := MachineInfo{MachineID: machineID, Host: field(record, "host"), Owner: field(record, "owner")}
+		if err := r.Register(system, info); err != nil {
+			return errorf(CodeCSVLine, line+2, err)
+		}
+	}
+	return nil
+}
+
+// WhoIs 查找可能生成该id的系统及机器
+//   - 按各系统的id结构解析，机器已登记且生成时间不晚于当前时间的系统均视为候选，按系统名称排序
+func (r *MachineRegistry) WhoIs(id int64) []Attribution {
+	if id < 0 {
+		return nil
+	}
+	names := make([]string, 0, len(r.systems))
+	for name := range r.systems {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	now := time.Now()
+	var result []Attribution
+	for _, name := range names {
+		s := r.systems[name]
+		compose := s.decomposer.Decompose(id)
+		info, exist := s.machines[compose.MachineID]
+		if !exist {
+			continue
+		}
+		t := s.decomposer.Time(id)
+		if t.After(now) {
+			continue
+		}
+		result = append(result, Attribution{System: name, Machine: info, Compose: compose, Time: t})
+	}
+	return result
+}
@@ -0,0 +1,77 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestMachineRegistry 机器登记表及id归属
+func TestMachineRegistry(t *testing.T) {
+	registry := NewMachineRegistry()
+	err := registry.LoadJSON(strings.NewReader(`{"systems":[{"name":"order","time_bit":41,"machine_id_bit":9,"timeline_bit":1,"seq_bit":12,
+		"epoch":"2020-01-01T00:00:00Z","machines":[{"machine_id":3,"host":"order-03","owner":"交易"}]}]}`))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	legacy := Settings{TimeBit: 41, MachineIDBit: 10, TimelineBit: 0, SeqBit: 12, Epoch: time.Date(2015, 6, 10, 0, 0, 0, 0, time.UTC).UnixNano()}
+	if err := registry.AddSystem("legacy", legacy); err != nil {
+		t.Fatal(err.Error())
+	}
+	if err := registry.LoadCSV("legacy", strings.NewReader("machine_id,host,owner\n7,legacy-07,支付\n")); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	orderGen, _ := NewGenerator(3)
+	legacyGen, _ := NewGeneratorWithSettings(7, legacy)
+	unknownGen, _ := NewGenerator(100)
+	orderID, _ := orderGen.Generate()
+	legacyID, _ := legacyGen.Generate()
+	unknownID, _ := unknownGen.Generate()
+
+	testCases := []struct {
+		name       string
+		id         int64
+		wantSystem string
+		wantHost   string
+	}{
+		{name: "JSON登记的系统", id: int64(orderID), wantSystem: "order", wantHost: "order-03"},
+		{name: "CSV登记的外部系统", id: int64(legacyID), wantSystem: "legacy", wantHost: "legacy-07"},
+		{name: "未登记的机器", id: int64(unknownID)},
+	}
+
+	for _, tc := range testCases {
+		var got *Attribution
+		for _, a := range registry.WhoIs(tc.id) {
+			if a.System == tc.wantSystem {
+				a := a
+				got = &a
+			}
+		}
+		if tc.wantSystem == "" {
+			if len(registry.WhoIs(tc.id)) != 0 {
+				t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, registry.WhoIs(tc.id), "无归属")
+			}
+			continue
+		}
+		if got == nil || got.Machine.Host != tc.wantHost || time.Since(got.Time) > time.Minute {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, got, tc.wantHost)
+		}
+	}
+
+	errCases := []struct {
+		name string
+		err  error
+	}{
+		{name: "重复登记系统", err: registry.AddSystem("order", *DefaultSettings)},
+		{name: "系统未登记", err: registry.Register("unknown", MachineInfo{MachineID: 1})},
+		{name: "机器ID超限", err: registry.Register("order", MachineInfo{MachineID: 512})},
+		{name: "CSV缺少machine_id列", err: registry.LoadCSV("order", strings.NewReader("host\na\n"))},
+		{name: "CSV machine_id错误", err: registry.LoadCSV("order", strings.NewReader("machine_id\nx\n"))},
+	}
+	for _, tc := range errCases {
+		if tc.err == nil {
+			t.Fatalf("【失败】-%s-got:%v-want:%v", tc.name, tc.err, "error")
+		}
+	}
+}